@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrCircuitOpen is returned (as CodeUnavailable) for calls rejected by an
+// open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+type breakerMetrics struct {
+	state    *prometheus.GaugeVec
+	rejected *prometheus.CounterVec
+}
+
+func newBreakerMetrics(registerer prometheus.Registerer) *breakerMetrics {
+	m := &breakerMetrics{
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "client_circuit_breaker_state",
+			Help: "Circuit breaker state per target: 0 closed, 1 half-open, 2 open.",
+		}, []string{"target"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "client_circuit_breaker_rejected_total",
+			Help: "Calls rejected because the target's circuit was open.",
+		}, []string{"target"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.state, m.rejected)
+	}
+
+	return m
+}
+
+// BreakerConfig controls when calls to a target are short-circuited.
+type BreakerConfig struct {
+	// The circuit opens when at least MinRequests calls were made in Window
+	// and FailureRate of them failed.
+	FailureRate float64
+	MinRequests int
+	Window      time.Duration
+	// OpenTimeout is how long the circuit stays open before letting
+	// HalfOpenProbes calls through; if all succeed it closes, otherwise it
+	// opens again.
+	OpenTimeout    time.Duration
+	HalfOpenProbes int
+}
+
+// DefaultBreakerConfig is used for targets without their own configuration.
+var DefaultBreakerConfig = BreakerConfig{
+	FailureRate:    0.5,
+	MinRequests:    20,
+	Window:         10 * time.Second,
+	OpenTimeout:    5 * time.Second,
+	HalfOpenProbes: 3,
+}
+
+type circuitBreaker struct {
+	target  string
+	cfg     BreakerConfig
+	metrics *breakerMetrics
+	now     func() time.Time
+
+	mu          sync.Mutex
+	state       circuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	// halfOpens counts the times the circuit went half-open, so a probe
+	// finishing after its round ended is not taken for one of the next.
+	halfOpens int
+	probes    int
+	probeOK   int
+}
+
+// breakerCall is a call let through by allow, to be passed back to record.
+type breakerCall struct {
+	// probe is set for a call admitted while half-open, in round halfOpen.
+	probe    bool
+	halfOpen int
+}
+
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// callAbandoned is a call the caller gave up on, which tells nothing
+	// about the target.
+	callAbandoned
+)
+
+func newCircuitBreaker(target string, cfg BreakerConfig, metrics *breakerMetrics) *circuitBreaker {
+	metrics.state.WithLabelValues(target).Set(float64(circuitClosed))
+	return &circuitBreaker{
+		target:      target,
+		cfg:         cfg,
+		metrics:     metrics,
+		now:         time.Now,
+		windowStart: time.Now(),
+	}
+}
+
+// allow reports whether a call may proceed.
+func (b *circuitBreaker) allow() (breakerCall, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return breakerCall{}, false
+		}
+		b.setState(circuitHalfOpen)
+		b.halfOpens++
+		b.probes, b.probeOK = 0, 0
+		fallthrough
+	case circuitHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			return breakerCall{}, false
+		}
+		b.probes++
+		return breakerCall{probe: true, halfOpen: b.halfOpens}, true
+	default:
+		if now.Sub(b.windowStart) > b.cfg.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		return breakerCall{}, true
+	}
+}
+
+// record counts the outcome of call. While half-open only the probes of
+// the current round count; calls admitted before the circuit opened are
+// ignored.
+func (b *circuitBreaker) record(call breakerCall, outcome callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitHalfOpen:
+		if !call.probe || call.halfOpen != b.halfOpens {
+			return
+		}
+		switch outcome {
+		case callAbandoned:
+			// free the slot for another probe
+			b.probes--
+		case callFailed:
+			b.open()
+		default:
+			b.probeOK++
+			if b.probeOK >= b.cfg.HalfOpenProbes {
+				b.setState(circuitClosed)
+				b.windowStart, b.requests, b.failures = b.now(), 0, 0
+			}
+		}
+	case circuitClosed:
+		if call.probe || outcome == callAbandoned {
+			return
+		}
+		b.requests++
+		if outcome == callFailed {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRate {
+			b.open()
+		}
+	}
+}
+
+func (b *circuitBreaker) open() {
+	b.setState(circuitOpen)
+	b.openedAt = b.now()
+}
+
+func (b *circuitBreaker) setState(state circuitState) {
+	b.state = state
+	b.metrics.state.WithLabelValues(b.target).Set(float64(state))
+}
+
+// outcomeOf classifies the result of a call made with ctx. Errors that
+// indicate an unhealthy target fail it, rather than a rejected request; a
+// caller giving up is not the target's fault either way. A call that ran out
+// of its deadline did not give up: a slow target is what the breaker is for.
+func outcomeOf(ctx context.Context, err error) callOutcome {
+	switch {
+	case err == nil:
+		return callSucceeded
+	case errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled) || connect.CodeOf(err) == connect.CodeCanceled:
+		return callAbandoned
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded):
+		return callFailed
+	case isBreakerFailure(err):
+		return callFailed
+	default:
+		return callSucceeded
+	}
+}
+
+// isBreakerFailure reports whether err indicates an unhealthy target rather
+// than a rejected request.
+func isBreakerFailure(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeInternal, connect.CodeUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func newCircuitBreakerInterceptor(target string, cfg BreakerConfig, metrics *breakerMetrics) connect.UnaryInterceptorFunc {
+	breaker := newCircuitBreaker(target, cfg, metrics)
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			call, ok := breaker.allow()
+			if !ok {
+				metrics.rejected.WithLabelValues(target).Inc()
+				return nil, connect.NewError(connect.CodeUnavailable, ErrCircuitOpen)
+			}
+
+			res, err := next(ctx, req)
+			breaker.record(call, outcomeOf(ctx, err))
+
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+var testBreakerConfig = BreakerConfig{
+	FailureRate:    0.5,
+	MinRequests:    4,
+	Window:         time.Minute,
+	OpenTimeout:    5 * time.Second,
+	HalfOpenProbes: 2,
+}
+
+// newTestBreaker returns a breaker on a clock moved by advance.
+func newTestBreaker(t *testing.T) (b *circuitBreaker, advance func(time.Duration)) {
+	t.Helper()
+
+	now := time.Unix(1_700_000_000, 0)
+	b = newCircuitBreaker(t.Name(), testBreakerConfig, newBreakerMetrics(nil))
+	b.now = func() time.Time { return now }
+	b.windowStart = now
+
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+// call runs one call through b, reporting whether it was let through.
+func call(b *circuitBreaker, outcome callOutcome) bool {
+	c, ok := b.allow()
+	if ok {
+		b.record(c, outcome)
+	}
+	return ok
+}
+
+// trip opens b with failed calls.
+func trip(t *testing.T, b *circuitBreaker) {
+	t.Helper()
+
+	for range testBreakerConfig.MinRequests {
+		call(b, callFailed)
+	}
+	if b.state != circuitOpen {
+		t.Fatalf("state after %d failures = %d, want open", testBreakerConfig.MinRequests, b.state)
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	b, _ := newTestBreaker(t)
+
+	call(b, callSucceeded)
+	call(b, callSucceeded)
+	call(b, callFailed)
+	if b.state != circuitClosed {
+		t.Fatalf("state below MinRequests = %d, want closed", b.state)
+	}
+
+	call(b, callFailed)
+	if b.state != circuitOpen {
+		t.Fatalf("state at FailureRate = %d, want open", b.state)
+	}
+	if call(b, callSucceeded) {
+		t.Error("open circuit let a call through")
+	}
+}
+
+func TestCircuitBreakerIgnoresAbandonedCalls(t *testing.T) {
+	b, _ := newTestBreaker(t)
+
+	for range 10 {
+		call(b, callAbandoned)
+	}
+	if b.requests != 0 {
+		t.Errorf("abandoned calls counted %d requests, want 0", b.requests)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Run("goes half-open after OpenTimeout", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		trip(t, b)
+
+		advance(testBreakerConfig.OpenTimeout - time.Second)
+		if _, ok := b.allow(); ok {
+			t.Fatal("circuit let a call through before OpenTimeout")
+		}
+
+		advance(time.Second)
+		for i := range testBreakerConfig.HalfOpenProbes {
+			if _, ok := b.allow(); !ok {
+				t.Fatalf("half-open circuit rejected probe %d", i+1)
+			}
+		}
+		if b.state != circuitHalfOpen {
+			t.Fatalf("state after OpenTimeout = %d, want half-open", b.state)
+		}
+		if _, ok := b.allow(); ok {
+			t.Error("half-open circuit let more than HalfOpenProbes calls through")
+		}
+	})
+
+	t.Run("closes when every probe succeeds", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		trip(t, b)
+		advance(testBreakerConfig.OpenTimeout)
+
+		for range testBreakerConfig.HalfOpenProbes {
+			call(b, callSucceeded)
+		}
+		if b.state != circuitClosed {
+			t.Errorf("state after successful probes = %d, want closed", b.state)
+		}
+	})
+
+	t.Run("opens again when a probe fails", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		trip(t, b)
+		advance(testBreakerConfig.OpenTimeout)
+
+		call(b, callSucceeded)
+		call(b, callFailed)
+		if b.state != circuitOpen {
+			t.Errorf("state after a failed probe = %d, want open", b.state)
+		}
+	})
+
+	t.Run("does not take an abandoned probe for a success", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		trip(t, b)
+		advance(testBreakerConfig.OpenTimeout)
+
+		for range testBreakerConfig.HalfOpenProbes {
+			call(b, callAbandoned)
+		}
+		if b.state != circuitHalfOpen {
+			t.Fatalf("state after abandoned probes = %d, want half-open", b.state)
+		}
+		// the abandoned probes gave their slots back
+		call(b, callSucceeded)
+		call(b, callFailed)
+		if b.state != circuitOpen {
+			t.Errorf("state after a failed probe = %d, want open", b.state)
+		}
+	})
+
+	t.Run("ignores calls admitted before the circuit opened", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		inFlight, _ := b.allow()
+		trip(t, b)
+		advance(testBreakerConfig.OpenTimeout)
+
+		probe, _ := b.allow()
+		b.record(inFlight, callSucceeded)
+		b.record(inFlight, callSucceeded)
+		if b.state != circuitHalfOpen {
+			t.Fatalf("state after calls from before the trip = %d, want half-open", b.state)
+		}
+
+		b.record(probe, callFailed)
+		if b.state != circuitOpen {
+			t.Errorf("state after a failed probe = %d, want open", b.state)
+		}
+	})
+
+	t.Run("ignores probes of an earlier round", func(t *testing.T) {
+		b, advance := newTestBreaker(t)
+		trip(t, b)
+		advance(testBreakerConfig.OpenTimeout)
+
+		late, _ := b.allow()
+		call(b, callFailed)
+		advance(testBreakerConfig.OpenTimeout)
+		b.allow()
+
+		b.record(late, callSucceeded)
+		if b.probeOK != 0 {
+			t.Errorf("a probe of an earlier round counted as %d successes, want 0", b.probeOK)
+		}
+	})
+}
+
+func TestCircuitBreakerOpensOnTimeouts(t *testing.T) {
+	// a target that answers only after every caller gave up
+	slow := func(ctx context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		<-ctx.Done()
+		return nil, connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+	}
+	call := newCircuitBreakerInterceptor(t.Name(), testBreakerConfig, newBreakerMetrics(nil))(slow)
+
+	for range testBreakerConfig.MinRequests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		_, err := call(ctx, nil)
+		cancel()
+		if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+			t.Fatalf("call to the slow target = %v, want %s", err, connect.CodeDeadlineExceeded)
+		}
+	}
+
+	_, err := call(context.Background(), nil)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("call after %d timeouts = %v, want %v", testBreakerConfig.MinRequests, err, ErrCircuitOpen)
+	}
+}
+
+func TestOutcomeOf(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want callOutcome
+	}{
+		{name: "success", ctx: context.Background(), want: callSucceeded},
+		{name: "unavailable", ctx: context.Background(), err: connect.NewError(connect.CodeUnavailable, errors.New("down")), want: callFailed},
+		{name: "rejected request", ctx: context.Background(), err: connect.NewError(connect.CodeInvalidArgument, errors.New("bad")), want: callSucceeded},
+		{name: "caller canceled", ctx: canceled, err: connect.NewError(connect.CodeUnavailable, errors.New("down")), want: callAbandoned},
+		{name: "canceled error", ctx: context.Background(), err: context.Canceled, want: callAbandoned},
+		{name: "canceled code", ctx: context.Background(), err: connect.NewError(connect.CodeCanceled, errors.New("canceled")), want: callAbandoned},
+		{name: "caller deadline exceeded", ctx: expired, err: connect.NewError(connect.CodeDeadlineExceeded, context.DeadlineExceeded), want: callFailed},
+		{name: "deadline error", ctx: expired, err: context.DeadlineExceeded, want: callFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outcomeOf(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("outcomeOf(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// ErrDeadlineExhausted is returned (as CodeDeadlineExceeded) without calling
+// the server when the caller's deadline leaves no time for the call.
+var ErrDeadlineExhausted = errors.New("deadline exhausted before call")
+
+// TimeoutConfig sets the deadline applied to calls whose context has none.
+// A caller's existing deadline always wins, and Connect forwards the
+// remaining time to the server (Connect-Timeout-Ms / grpc-timeout), which
+// applies it to the handler's context.
+type TimeoutConfig struct {
+	Default time.Duration
+	// PerProcedure is keyed by full procedure name, e.g.
+	// "/user.v1.UserService/GetProfile".
+	PerProcedure map[string]time.Duration
+}
+
+func newDeadlineInterceptor(cfg TimeoutConfig) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if deadline, ok := ctx.Deadline(); ok {
+				if time.Until(deadline) <= 0 {
+					return nil, connect.NewError(connect.CodeDeadlineExceeded, ErrDeadlineExhausted)
+				}
+				return next(ctx, req)
+			}
+
+			timeout, ok := cfg.PerProcedure[req.Spec().Procedure]
+			if !ok {
+				timeout = cfg.Default
+			}
+			if timeout <= 0 {
+				return next(ctx, req)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return next(ctx, req)
+		}
+	}
+}
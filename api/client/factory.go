@@ -0,0 +1,336 @@
+// Package client builds Connect clients for calling go-shop services with a
+// consistent set of interceptors: bearer token injection, retries on
+// transient failures, circuit breaking and OpenTelemetry tracing, optionally
+// with region failover.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/otelconnect"
+	"github.com/phongloihong/go-shop/api/client/region"
+	"github.com/phongloihong/go-shop/api/client/resolver"
+	"github.com/phongloihong/go-shop/api/compression"
+	"github.com/phongloihong/go-shop/api/gen/jobs/v1/jobsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/operations/v1/operationsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v1/userv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// TokenSource returns the bearer token attached to outgoing requests. An
+// empty token sends the request without an Authorization header.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Factory creates service clients that share an HTTP client and interceptor
+// chain.
+type Factory struct {
+	httpClient  connect.HTTPClient
+	tokenSource TokenSource
+	retry       RetryPolicy
+	timeouts    TimeoutConfig
+	resolver    *resolver.Resolver
+	failover    *region.Failover
+	tlsConfig   *tls.Config
+	tracing     bool
+	breakersOn  bool
+	breakers    map[string]BreakerConfig
+	registerer  prometheus.Registerer
+	options     []connect.ClientOption
+	compression []connect.ClientOption
+
+	maxMessageBytes int
+
+	tracer       connect.Interceptor
+	interceptors []connect.Interceptor
+
+	breakerMetrics  *breakerMetrics
+	breakerMu       sync.Mutex
+	breakerByTarget map[string]connect.Interceptor
+}
+
+type Option func(*Factory)
+
+// WithHTTPClient replaces the default HTTP client (10s timeout).
+func WithHTTPClient(httpClient connect.HTTPClient) Option {
+	return func(f *Factory) {
+		f.httpClient = httpClient
+	}
+}
+
+// WithTokenSource injects "Authorization: Bearer <token>" on every call.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(f *Factory) {
+		f.tokenSource = tokenSource
+	}
+}
+
+// WithStaticToken injects the same bearer token on every call.
+func WithStaticToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithRetry replaces DefaultRetryPolicy. A zero MaxRetries disables retries.
+func WithRetry(policy RetryPolicy) Option {
+	return func(f *Factory) {
+		f.retry = policy
+	}
+}
+
+// WithTLS sets the TLS config used for https base URLs, e.g. the service's
+// mTLS client identity from pkg/mtls. It replaces the transport of the
+// factory's *http.Client.
+func WithTLS(tlsConfig *tls.Config) Option {
+	return func(f *Factory) {
+		f.tlsConfig = tlsConfig
+	}
+}
+
+// WithResolver lets base URLs name a logical service (e.g.
+// "http://user-service"); each request goes to an instance picked by r.
+// It wraps the transport of the factory's *http.Client.
+func WithResolver(r *resolver.Resolver) Option {
+	return func(f *Factory) {
+		f.resolver = r
+	}
+}
+
+// WithRegionFailover sends calls to the region currently serving each
+// service, failing over to the secondary region when the primary is
+// unhealthy (see region.Failover.Run). With WithTracing, client spans carry
+// the caller's and the callee's region. It wraps the transport of the
+// factory's *http.Client, outside any resolver.
+func WithRegionFailover(failover *region.Failover) Option {
+	return func(f *Factory) {
+		f.failover = failover
+	}
+}
+
+// WithTimeouts applies default deadlines to calls made without one and
+// rejects calls whose deadline has already passed.
+func WithTimeouts(cfg TimeoutConfig) Option {
+	return func(f *Factory) {
+		f.timeouts = cfg
+	}
+}
+
+// WithTracing records a client span per call using the global OpenTelemetry
+// providers and propagates the trace context to the callee.
+func WithTracing() Option {
+	return func(f *Factory) {
+		f.tracing = true
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker per target service. Targets
+// are keyed by the fully-qualified service name (e.g. "user.v1.UserService");
+// targets missing from perTarget use DefaultBreakerConfig.
+func WithCircuitBreaker(perTarget map[string]BreakerConfig) Option {
+	return func(f *Factory) {
+		f.breakersOn = true
+		f.breakers = perTarget
+	}
+}
+
+// WithMetrics registers the factory's circuit breaker metrics with
+// registerer. Without it they are kept but not exported.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(f *Factory) {
+		f.registerer = registerer
+	}
+}
+
+// WithCompression compresses requests of at least minBytes with algorithm
+// (compression.Gzip or compression.Zstd) and accepts gzip and zstd
+// responses. Zero minBytes uses compression.DefaultMinBytes.
+func WithCompression(algorithm string, minBytes int) Option {
+	return func(f *Factory) {
+		f.compression = compression.ClientOptions(algorithm, minBytes)
+	}
+}
+
+// WithMaxMessageBytes fails calls whose request or response message is
+// larger than n bytes instead of sending or buffering it.
+func WithMaxMessageBytes(n int) Option {
+	return func(f *Factory) {
+		f.maxMessageBytes = n
+	}
+}
+
+// WithClientOptions appends raw Connect client options, e.g. connect.WithGRPC().
+func WithClientOptions(options ...connect.ClientOption) Option {
+	return func(f *Factory) {
+		f.options = append(f.options, options...)
+	}
+}
+
+func NewFactory(opts ...Option) (*Factory, error) {
+	f := &Factory{
+		httpClient:      &http.Client{Timeout: defaultTimeout},
+		retry:           DefaultRetryPolicy,
+		breakerByTarget: make(map[string]connect.Interceptor),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if f.tlsConfig != nil {
+		httpClient, ok := f.httpClient.(*http.Client)
+		if !ok {
+			return nil, errors.New("WithTLS requires an *http.Client")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = f.tlsConfig
+		secured := *httpClient
+		secured.Transport = transport
+		f.httpClient = &secured
+	}
+
+	if f.resolver != nil {
+		httpClient, ok := f.httpClient.(*http.Client)
+		if !ok {
+			return nil, errors.New("WithResolver requires an *http.Client")
+		}
+		resolved := *httpClient
+		resolved.Transport = f.resolver.Transport(httpClient.Transport)
+		f.httpClient = &resolved
+	}
+
+	if f.failover != nil {
+		httpClient, ok := f.httpClient.(*http.Client)
+		if !ok {
+			return nil, errors.New("WithRegionFailover requires an *http.Client")
+		}
+		regional := *httpClient
+		regional.Transport = f.failover.Transport(httpClient.Transport)
+		f.httpClient = &regional
+	}
+
+	if f.tracing {
+		otelInterceptor, err := otelconnect.NewInterceptor()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tracing interceptor: %w", err)
+		}
+		f.tracer = otelInterceptor
+	}
+	if f.breakersOn {
+		f.breakerMetrics = newBreakerMetrics(f.registerer)
+	}
+	// The deadline covers every retry of the call.
+	f.interceptors = append(f.interceptors, newDeadlineInterceptor(f.timeouts))
+	if f.retry.MaxRetries > 0 {
+		f.interceptors = append(f.interceptors, newRetryInterceptor(f.retry))
+	}
+	if f.tokenSource != nil {
+		f.interceptors = append(f.interceptors, newAuthInterceptor(f.tokenSource))
+	}
+
+	return f, nil
+}
+
+// clientOptions builds the interceptor chain for target, outermost first:
+// tracing (so retries share one span), circuit breaker (so a call that
+// exhausted its retries counts as one failure), deadline, retries, auth.
+func (f *Factory) clientOptions(target string) []connect.ClientOption {
+	interceptors := make([]connect.Interceptor, 0, len(f.interceptors)+3)
+	if f.tracer != nil {
+		interceptors = append(interceptors, f.tracer)
+		if f.failover != nil {
+			interceptors = append(interceptors, newRegionInterceptor(f.failover))
+		}
+	}
+	if f.breakersOn {
+		interceptors = append(interceptors, f.breaker(target))
+	}
+	interceptors = append(interceptors, f.interceptors...)
+
+	options := []connect.ClientOption{connect.WithInterceptors(interceptors...)}
+	options = append(options, f.compression...)
+	if f.maxMessageBytes > 0 {
+		options = append(options,
+			connect.WithReadMaxBytes(f.maxMessageBytes),
+			connect.WithSendMaxBytes(f.maxMessageBytes),
+		)
+	}
+	return append(options, f.options...)
+}
+
+// breaker returns the target's circuit breaker, shared by every client the
+// factory creates for that target.
+func (f *Factory) breaker(target string) connect.Interceptor {
+	f.breakerMu.Lock()
+	defer f.breakerMu.Unlock()
+
+	if breaker, ok := f.breakerByTarget[target]; ok {
+		return breaker
+	}
+
+	cfg, ok := f.breakers[target]
+	if !ok {
+		cfg = DefaultBreakerConfig
+	}
+	breaker := newCircuitBreakerInterceptor(target, cfg, f.breakerMetrics)
+	f.breakerByTarget[target] = breaker
+
+	return breaker
+}
+
+// UserService returns a client for user.v1.UserService served at baseURL.
+//
+// Deprecated: user.v1.UserService is sunset; use UserServiceV2.
+func (f *Factory) UserService(baseURL string) userv1connect.UserServiceClient {
+	return userv1connect.NewUserServiceClient(f.httpClient, baseURL, f.clientOptions(userv1connect.UserServiceName)...)
+}
+
+// UserServiceV2 returns a client for user.v2.UserService served at baseURL.
+func (f *Factory) UserServiceV2(baseURL string) userv2connect.UserServiceClient {
+	return userv2connect.NewUserServiceClient(f.httpClient, baseURL, f.clientOptions(userv2connect.UserServiceName)...)
+}
+
+// UserAdminService returns a client for user.v2.UserAdminService served at
+// baseURL. It is served only on internal mTLS listeners, see WithTLS.
+func (f *Factory) UserAdminService(baseURL string) userv2connect.UserAdminServiceClient {
+	return userv2connect.NewUserAdminServiceClient(f.httpClient, baseURL, f.clientOptions(userv2connect.UserAdminServiceName)...)
+}
+
+// WebhookAdminService returns a client for user.v2.WebhookAdminService
+// served at baseURL. It is served only on internal mTLS listeners, see
+// WithTLS.
+func (f *Factory) WebhookAdminService(baseURL string) userv2connect.WebhookAdminServiceClient {
+	return userv2connect.NewWebhookAdminServiceClient(f.httpClient, baseURL, f.clientOptions(userv2connect.WebhookAdminServiceName)...)
+}
+
+// WebhookService returns a client for user.v1.WebhookService served at baseURL.
+func (f *Factory) WebhookService(baseURL string) userv1connect.WebhookServiceClient {
+	return userv1connect.NewWebhookServiceClient(f.httpClient, baseURL, f.clientOptions(userv1connect.WebhookServiceName)...)
+}
+
+// ExportService returns a client for user.v1.ExportService served at
+// baseURL. It is served only on internal mTLS listeners, see WithTLS.
+func (f *Factory) ExportService(baseURL string) userv1connect.ExportServiceClient {
+	return userv1connect.NewExportServiceClient(f.httpClient, baseURL, f.clientOptions(userv1connect.ExportServiceName)...)
+}
+
+// JobService returns a client for the jobs.v1.JobService of the service at
+// baseURL. It is served only on internal mTLS listeners, see WithTLS.
+func (f *Factory) JobService(baseURL string) jobsv1connect.JobServiceClient {
+	return jobsv1connect.NewJobServiceClient(f.httpClient, baseURL, f.clientOptions(jobsv1connect.JobServiceName)...)
+}
+
+// OperationService returns a client for the operations.v1.OperationService
+// of the service at baseURL, to poll and cancel the operations its methods
+// start. It is served only on internal mTLS listeners, see WithTLS.
+func (f *Factory) OperationService(baseURL string) operationsv1connect.OperationServiceClient {
+	return operationsv1connect.NewOperationServiceClient(f.httpClient, baseURL, f.clientOptions(operationsv1connect.OperationServiceName)...)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"net"
+
+	"connectrpc.com/connect"
+	"github.com/phongloihong/go-shop/api/client/region"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newAuthInterceptor(tokenSource TokenSource) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if req.Header().Get("Authorization") == "" {
+				token, err := tokenSource(ctx)
+				if err != nil {
+					return nil, connect.NewError(connect.CodeUnauthenticated, err)
+				}
+				if token != "" {
+					req.Header().Set("Authorization", "Bearer "+token)
+				}
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// newRegionInterceptor tags the call's span with the caller's region and
+// the region serving the callee. It runs inside the tracing interceptor.
+func newRegionInterceptor(failover *region.Failover) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			attrs := []attribute.KeyValue{attribute.String("cloud.region", failover.Local())}
+			if active := failover.Active(peerHost(req.Peer().Addr)); active != "" {
+				attrs = append(attrs, attribute.String("peer.cloud.region", active))
+			}
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// peerHost strips the port, if any, from a client peer address.
+func peerHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
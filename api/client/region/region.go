@@ -0,0 +1,234 @@
+// Package region sends calls to services in an active-passive pair of
+// regions. Each service is probed in its primary region; after repeated
+// failed probes its calls go to the secondary region until the primary has
+// been healthy again for a while. Base URLs keep naming the service (e.g.
+// "http://user-service"), and the transport rewrites the host to the address
+// of the region currently serving it.
+package region
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	activeRegionGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "client_region_active",
+		Help: "1 for the region currently serving calls to a service, 0 otherwise.",
+	}, []string{"service", "region"})
+	failoversTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "client_region_failovers_total",
+		Help: "Switches of a service between its primary and secondary region.",
+	}, []string{"service"})
+)
+
+func init() {
+	prometheus.MustRegister(activeRegionGauge, failoversTotal)
+}
+
+type Config struct {
+	// Local is the region this process runs in, recorded on spans and events.
+	Local     string `mapstructure:"local"`
+	Primary   string `mapstructure:"primary"`
+	Secondary string `mapstructure:"secondary"`
+	// Endpoints maps a service host used in base URLs to its "host:port" in
+	// each region. Hosts missing here are sent unchanged.
+	Endpoints map[string]map[string]string `mapstructure:"endpoints"`
+	// Scheme and HealthPath build the probe URL; defaults "http" and
+	// "/ready".
+	Scheme     string `mapstructure:"scheme"`
+	HealthPath string `mapstructure:"health_path"`
+	// CheckInterval is the time between probes of each primary endpoint.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// FailureThreshold consecutive failed probes move a service to the
+	// secondary region; RecoveryThreshold consecutive healthy ones move it
+	// back.
+	FailureThreshold  int `mapstructure:"failure_threshold"`
+	RecoveryThreshold int `mapstructure:"recovery_threshold"`
+}
+
+type serviceState struct {
+	onSecondary bool
+	failures    int
+	successes   int
+}
+
+// Failover tracks which region serves each service.
+type Failover struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu       sync.RWMutex
+	services map[string]*serviceState
+}
+
+// New returns a Failover probing with httpClient, which should carry the
+// same TLS identity as service calls. Every service starts on the primary
+// region; call Run to start probing.
+func New(cfg Config, httpClient *http.Client) *Failover {
+	if cfg.Scheme == "" {
+		cfg.Scheme = "http"
+	}
+	if cfg.HealthPath == "" {
+		cfg.HealthPath = "/ready"
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Second
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.RecoveryThreshold <= 0 {
+		cfg.RecoveryThreshold = 5
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	f := &Failover{
+		cfg:        cfg,
+		httpClient: httpClient,
+		services:   make(map[string]*serviceState, len(cfg.Endpoints)),
+	}
+	for service := range cfg.Endpoints {
+		f.services[service] = &serviceState{}
+		f.recordActive(service, cfg.Primary)
+	}
+
+	return f
+}
+
+// Local returns the region this process runs in.
+func (f *Failover) Local() string {
+	return f.cfg.Local
+}
+
+// Active returns the region currently serving service, or "" for a service
+// without regional endpoints.
+func (f *Failover) Active(service string) string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	state, ok := f.services[service]
+	if !ok {
+		return ""
+	}
+	if state.onSecondary {
+		return f.cfg.Secondary
+	}
+	return f.cfg.Primary
+}
+
+// Run probes the primary endpoint of every service each CheckInterval until
+// ctx is cancelled.
+func (f *Failover) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for service, endpoints := range f.cfg.Endpoints {
+			addr, ok := endpoints[f.cfg.Primary]
+			if !ok {
+				continue
+			}
+			f.observe(service, f.probe(ctx, addr))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Failover) probe(ctx context.Context, addr string) bool {
+	ctx, cancel := context.WithTimeout(ctx, f.cfg.CheckInterval)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.cfg.Scheme+"://"+addr+f.cfg.HealthPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// observe counts a probe of service's primary region and switches regions
+// once a threshold is reached.
+func (f *Failover) observe(service string, healthy bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state := f.services[service]
+	if healthy {
+		state.failures = 0
+		state.successes++
+	} else {
+		state.successes = 0
+		state.failures++
+	}
+
+	switch {
+	case !state.onSecondary && state.failures >= f.cfg.FailureThreshold:
+		if _, ok := f.cfg.Endpoints[service][f.cfg.Secondary]; !ok {
+			return
+		}
+		state.onSecondary = true
+		f.recordActive(service, f.cfg.Secondary)
+	case state.onSecondary && state.successes >= f.cfg.RecoveryThreshold:
+		state.onSecondary = false
+		f.recordActive(service, f.cfg.Primary)
+	default:
+		return
+	}
+	failoversTotal.WithLabelValues(service).Inc()
+}
+
+func (f *Failover) recordActive(service, active string) {
+	for _, region := range []string{f.cfg.Primary, f.cfg.Secondary} {
+		value := 0.0
+		if region == active {
+			value = 1
+		}
+		activeRegionGauge.WithLabelValues(service, region).Set(value)
+	}
+}
+
+// Transport sends requests for services with regional endpoints to the
+// active region's address. Other hosts are sent as-is, so it composes with
+// resolver.Transport when endpoints are themselves logical names.
+func (f *Failover) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{failover: f, base: base}
+}
+
+type transport struct {
+	failover *Failover
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := req.URL.Hostname()
+	addr, ok := t.failover.cfg.Endpoints[service][t.failover.Active(service)]
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	out := req.Clone(req.Context())
+	out.URL.Host = addr
+	out.Host = addr
+
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,158 @@
+// Package resolver maps logical service names to instance addresses so
+// clients can use base URLs like "http://user-service" instead of hardcoded
+// hosts. Addresses come from static config, DNS SRV records or Consul, and
+// requests are spread round-robin across instances that have not recently
+// failed.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUnknownService is returned by a Source that has no entry for a name;
+// requests to such hosts are sent unchanged.
+var ErrUnknownService = errors.New("unknown service")
+
+// Source lists the "host:port" addresses of a service's instances.
+type Source interface {
+	Resolve(ctx context.Context, service string) ([]string, error)
+}
+
+const (
+	ModeStatic = "static"
+	ModeDNS    = "dns"
+	ModeConsul = "consul"
+)
+
+type Config struct {
+	Mode   string              `mapstructure:"mode"`
+	Static map[string][]string `mapstructure:"static"`
+	DNS    DNSConfig           `mapstructure:"dns"`
+	Consul ConsulConfig        `mapstructure:"consul"`
+	// RefreshInterval is how long resolved addresses are cached.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	// FailureCooldown is how long an instance is skipped after a connection
+	// error or 503 response.
+	FailureCooldown time.Duration `mapstructure:"failure_cooldown"`
+}
+
+// NewFromConfig builds a Resolver using the source selected by cfg.Mode.
+func NewFromConfig(cfg Config) (*Resolver, error) {
+	var source Source
+	switch cfg.Mode {
+	case ModeStatic:
+		source = StaticSource(cfg.Static)
+	case ModeDNS:
+		source = NewDNSSource(cfg.DNS)
+	case ModeConsul:
+		source = NewConsulSource(cfg.Consul)
+	default:
+		return nil, fmt.Errorf("unknown resolver mode: %q", cfg.Mode)
+	}
+
+	return New(source, cfg.RefreshInterval, cfg.FailureCooldown), nil
+}
+
+type entry struct {
+	addrs     []string
+	next      int
+	fetchedAt time.Time
+}
+
+type Resolver struct {
+	source          Source
+	refreshInterval time.Duration
+	failureCooldown time.Duration
+
+	mu       sync.Mutex
+	services map[string]*entry
+	downTill map[string]time.Time
+}
+
+func New(source Source, refreshInterval, failureCooldown time.Duration) *Resolver {
+	if refreshInterval <= 0 {
+		refreshInterval = 30 * time.Second
+	}
+	if failureCooldown <= 0 {
+		failureCooldown = 10 * time.Second
+	}
+
+	return &Resolver{
+		source:          source,
+		refreshInterval: refreshInterval,
+		failureCooldown: failureCooldown,
+		services:        make(map[string]*entry),
+		downTill:        make(map[string]time.Time),
+	}
+}
+
+// Pick returns the next healthy address for service. When every instance is
+// cooling down it still returns one, since failing fast on a guess is no
+// better than trying.
+func (r *Resolver) Pick(ctx context.Context, service string) (string, error) {
+	e, err := r.lookup(ctx, service)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(e.addrs); i++ {
+		addr := e.addrs[(e.next+i)%len(e.addrs)]
+		if now.After(r.downTill[addr]) {
+			e.next = (e.next + i + 1) % len(e.addrs)
+			return addr, nil
+		}
+	}
+
+	addr := e.addrs[e.next%len(e.addrs)]
+	e.next = (e.next + 1) % len(e.addrs)
+	return addr, nil
+}
+
+// MarkDown skips addr for the failure cooldown.
+func (r *Resolver) MarkDown(addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.downTill[addr] = time.Now().Add(r.failureCooldown)
+}
+
+func (r *Resolver) lookup(ctx context.Context, service string) (*entry, error) {
+	r.mu.Lock()
+	e, ok := r.services[service]
+	r.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < r.refreshInterval {
+		return e, nil
+	}
+
+	addrs, err := r.source.Resolve(ctx, service)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no instances of %s", service)
+	}
+	if err != nil {
+		// Keep serving the last known instances while the source is down.
+		if ok && !errors.Is(err, ErrUnknownService) {
+			return e, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !ok {
+		e = &entry{}
+		r.services[service] = e
+	}
+	e.addrs = addrs
+	e.fetchedAt = time.Now()
+
+	return e, nil
+}
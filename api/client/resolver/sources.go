@@ -0,0 +1,130 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaticSource maps service names to fixed addresses from config.
+type StaticSource map[string][]string
+
+func (s StaticSource) Resolve(_ context.Context, service string) ([]string, error) {
+	addrs, ok := s[service]
+	if !ok {
+		return nil, ErrUnknownService
+	}
+	return addrs, nil
+}
+
+type DNSConfig struct {
+	// PortName and Domain build the SRV query _<port_name>._tcp.<service>.<domain>,
+	// e.g. _http._tcp.user-service.default.svc.cluster.local on Kubernetes.
+	PortName string `mapstructure:"port_name"`
+	Domain   string `mapstructure:"domain"`
+}
+
+type DNSSource struct {
+	cfg      DNSConfig
+	resolver *net.Resolver
+}
+
+func NewDNSSource(cfg DNSConfig) *DNSSource {
+	return &DNSSource{cfg: cfg, resolver: net.DefaultResolver}
+}
+
+func (s *DNSSource) Resolve(ctx context.Context, service string) ([]string, error) {
+	name := service
+	if s.cfg.Domain != "" {
+		name = service + "." + s.cfg.Domain
+	}
+
+	_, records, err := s.resolver.LookupSRV(ctx, s.cfg.PortName, "tcp", name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, ErrUnknownService
+		}
+		return nil, fmt.Errorf("srv lookup for %s: %w", name, err)
+	}
+
+	addrs := make([]string, 0, len(records))
+	for _, record := range records {
+		addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+	}
+
+	return addrs, nil
+}
+
+type ConsulConfig struct {
+	// Addr is the Consul agent's HTTP address, e.g. http://consul:8500.
+	Addr string `mapstructure:"addr"`
+}
+
+// ConsulSource returns instances whose health checks are passing.
+type ConsulSource struct {
+	addr       string
+	httpClient *http.Client
+}
+
+func NewConsulSource(cfg ConsulConfig) *ConsulSource {
+	return &ConsulSource{
+		addr:       strings.TrimSuffix(cfg.Addr, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type consulServiceEntry struct {
+	Node struct {
+		Address string `json:"Address"`
+	} `json:"Node"`
+	Service struct {
+		Address string `json:"Address"`
+		Port    int    `json:"Port"`
+	} `json:"Service"`
+}
+
+func (s *ConsulSource) Resolve(ctx context.Context, service string) ([]string, error) {
+	endpoint := s.addr + "/v1/health/service/" + url.PathEscape(service) + "?passing=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul lookup for %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul lookup for %s: status %d", service, resp.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("consul lookup for %s: %w", service, err)
+	}
+	if len(entries) == 0 {
+		return nil, ErrUnknownService
+	}
+
+	addrs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		// The service address falls back to the node address when unset.
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(e.Service.Port)))
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Transport sends requests for logical hosts to a picked instance. Hosts
+// with an explicit port, and names the source does not know, are sent as-is.
+func (r *Resolver) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{resolver: r, base: base}
+}
+
+type transport struct {
+	resolver *Resolver
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Port() != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	addr, err := t.resolver.Pick(req.Context(), req.URL.Hostname())
+	if errors.Is(err, ErrUnknownService) {
+		return t.base.RoundTrip(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := req.Clone(req.Context())
+	out.URL.Host = addr
+	out.Host = addr
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil || resp.StatusCode == http.StatusServiceUnavailable {
+		t.resolver.MarkDown(addr)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// RetryPolicy controls retries of idempotent procedures, i.e. those declared
+// with idempotency_level NO_SIDE_EFFECTS or IDEMPOTENT in their proto.
+// Other procedures are never retried, since the server may have applied them.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// The wait before retry n is InitialBackoff * 2^(n-1), capped at
+	// MaxBackoff, with up to 20% jitter either way.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Budget caps the total time a call may spend waiting between retries.
+	// A Retry-After that does not fit in the remaining budget ends the call.
+	Budget time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     2,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Budget:         3 * time.Second,
+}
+
+// maxRetriesContextKey holds the MaxRetries override set by WithMaxRetries.
+type maxRetriesContextKey struct{}
+
+// WithMaxRetries overrides the policy's MaxRetries for calls made with ctx,
+// e.g. zero for a latency-sensitive call that should fail fast.
+func WithMaxRetries(ctx context.Context, maxRetries int) context.Context {
+	return context.WithValue(ctx, maxRetriesContextKey{}, maxRetries)
+}
+
+func isRetryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted, connect.CodeAborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter reads the server's Retry-After metadata (in seconds).
+func retryAfter(err error) (time.Duration, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0, false
+	}
+
+	seconds, convErr := strconv.Atoi(connectErr.Meta().Get("Retry-After"))
+	if convErr != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff << (retry - 1)
+	if wait > p.MaxBackoff || wait <= 0 {
+		wait = p.MaxBackoff
+	}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(wait))
+
+	return wait + jitter
+}
+
+func newRetryInterceptor(policy RetryPolicy) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if req.Spec().IdempotencyLevel == connect.IdempotencyUnknown {
+				return next(ctx, req)
+			}
+
+			maxRetries := policy.MaxRetries
+			if override, ok := ctx.Value(maxRetriesContextKey{}).(int); ok {
+				maxRetries = override
+			}
+			budget := policy.Budget
+
+			for retry := 0; ; retry++ {
+				res, err := next(ctx, req)
+				if err == nil || retry >= maxRetries || !isRetryable(err) {
+					return res, err
+				}
+
+				wait := policy.backoff(retry + 1)
+				if serverWait, ok := retryAfter(err); ok {
+					wait = serverWait
+				}
+				if wait > budget {
+					return nil, err
+				}
+				budget -= wait
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, contextError(ctx, err)
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+// contextError reports the caller's cancellation or deadline, keeping the
+// last attempt's error for context.
+func contextError(ctx context.Context, lastErr error) error {
+	code := connect.CodeCanceled
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		code = connect.CodeDeadlineExceeded
+	}
+
+	return connect.NewError(code, errors.Join(ctx.Err(), lastErr))
+}
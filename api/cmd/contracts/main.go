@@ -0,0 +1,63 @@
+// Command contracts verifies every consumer contract under api/contracts
+// against the generated service descriptors. It exits non-zero when a
+// provider change would break a consumer.
+//
+//	cd api && go run ./cmd/contracts
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/phongloihong/go-shop/api/contract"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// register provider descriptors
+	_ "github.com/phongloihong/go-shop/api/gen/user/v1"
+)
+
+func main() {
+	dir := flag.String("dir", "contracts", "directory holding <consumer>/<service>.json contracts")
+	flag.Parse()
+
+	var paths []string
+	err := filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".json" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("failed to list contracts in %s: %v", *dir, err)
+	}
+
+	failed := false
+	for _, path := range paths {
+		violations, err := contract.VerifyFile(path, protoregistry.GlobalFiles)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(violations) == 0 {
+			fmt.Printf("ok   %s\n", path)
+			continue
+		}
+
+		failed = true
+		fmt.Printf("FAIL %s\n", path)
+		for _, v := range violations {
+			fmt.Printf("     %s\n", v)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
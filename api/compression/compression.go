@@ -0,0 +1,81 @@
+// Package compression configures message compression for Connect servers
+// and clients. gzip is built into Connect; this package adds zstd, which
+// compresses large listings and exports better at a lower CPU cost.
+package compression
+
+import (
+	"io"
+
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// DefaultMinBytes leaves small messages uncompressed; below about 1 KiB
+// compression costs more CPU than it saves bandwidth.
+const DefaultMinBytes = 1024
+
+// HandlerOptions enables gzip and zstd on a handler: it accepts requests in
+// either and answers in the one the client prefers. Responses smaller than
+// minBytes are sent uncompressed; zero uses DefaultMinBytes.
+func HandlerOptions(minBytes int) []connect.HandlerOption {
+	return []connect.HandlerOption{
+		connect.WithCompression(Zstd, newZstdDecompressor, newZstdCompressor),
+		connect.WithCompressMinBytes(orDefault(minBytes)),
+	}
+}
+
+// ClientOptions compresses requests of at least minBytes with algorithm
+// (Gzip or Zstd) and accepts gzip and zstd responses. Zero minBytes uses
+// DefaultMinBytes.
+func ClientOptions(algorithm string, minBytes int) []connect.ClientOption {
+	return []connect.ClientOption{
+		connect.WithAcceptCompression(Zstd, newZstdDecompressor, newZstdCompressor),
+		connect.WithSendCompression(algorithm),
+		connect.WithCompressMinBytes(orDefault(minBytes)),
+	}
+}
+
+func orDefault(minBytes int) int {
+	if minBytes <= 0 {
+		return DefaultMinBytes
+	}
+
+	return minBytes
+}
+
+// Connect pools decoders and encoders, so each runs on one goroutine.
+
+func newZstdDecompressor() connect.Decompressor {
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	return &zstdDecompressor{decoder: decoder}
+}
+
+func newZstdCompressor() connect.Compressor {
+	encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return encoder
+}
+
+// zstdDecompressor adapts zstd.Decoder, whose Close frees it for good, to
+// Connect's pooled Decompressor, which is closed after every message.
+type zstdDecompressor struct {
+	decoder *zstd.Decoder
+}
+
+func (d *zstdDecompressor) Read(p []byte) (int, error) {
+	return d.decoder.Read(p)
+}
+
+func (d *zstdDecompressor) Reset(r io.Reader) error {
+	return d.decoder.Reset(r)
+}
+
+// Close drops the reference to the input so the pooled decoder does not keep
+// it alive.
+func (d *zstdDecompressor) Close() error {
+	return d.decoder.Reset(nil)
+}
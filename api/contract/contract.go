@@ -0,0 +1,192 @@
+// Package contract implements consumer-driven contracts for go-shop's
+// protobuf APIs. A consumer lists the methods and fields it relies on, with
+// their field numbers and types, in a JSON file under api/contracts/<consumer>/.
+// Verify checks a contract against the provider's current descriptors, so a
+// provider change that would break a consumer (a removed method, a renumbered
+// or retyped field) fails CI before it ships. Consumers run the same check in
+// their own test suites:
+//
+//	violations, err := contract.VerifyFile("../../api/contracts/order-service/user.v1.UserService.json", protoregistry.GlobalFiles)
+//
+// Adding fields or methods never breaks a contract; only what the consumer
+// listed is checked.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type Contract struct {
+	Consumer string   `json:"consumer"`
+	Service  string   `json:"service"`
+	Methods  []Method `json:"methods"`
+}
+
+type Method struct {
+	Name string `json:"name"`
+	// Streaming is "client", "server", "bidi" or empty for unary.
+	Streaming string  `json:"streaming,omitempty"`
+	Request   []Field `json:"request,omitempty"`
+	Response  []Field `json:"response,omitempty"`
+}
+
+// Field is a field the consumer reads or writes. Path is dot-separated
+// through nested messages, e.g. "profiles.first_name". Type is a scalar kind
+// ("string", "int64", ...) or the full name of a message or enum.
+type Field struct {
+	Path     string `json:"path"`
+	Number   int32  `json:"number"`
+	Type     string `json:"type"`
+	Repeated bool   `json:"repeated,omitempty"`
+}
+
+// Violation is one way the provider no longer satisfies a contract.
+type Violation struct {
+	Consumer string
+	Method   string
+	Problem  string
+}
+
+func (v Violation) String() string {
+	if v.Method == "" {
+		return fmt.Sprintf("%s: %s", v.Consumer, v.Problem)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", v.Consumer, v.Method, v.Problem)
+}
+
+// Load reads a contract file.
+func Load(path string) (*Contract, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract %s: %w", path, err)
+	}
+
+	var c Contract
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse contract %s: %w", path, err)
+	}
+	if c.Consumer == "" || c.Service == "" {
+		return nil, fmt.Errorf("contract %s must name its consumer and service", path)
+	}
+
+	return &c, nil
+}
+
+// VerifyFile loads the contract at path and verifies it against files.
+func VerifyFile(path string, files *protoregistry.Files) ([]Violation, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Verify(c, files), nil
+}
+
+// Verify returns every violation of c by the service descriptors in files,
+// usually protoregistry.GlobalFiles with the provider's generated package
+// imported.
+func Verify(c *Contract, files *protoregistry.Files) []Violation {
+	var violations []Violation
+	report := func(method, format string, args ...any) {
+		violations = append(violations, Violation{
+			Consumer: c.Consumer,
+			Method:   method,
+			Problem:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(c.Service))
+	if err != nil {
+		report("", "service %s not found", c.Service)
+		return violations
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		report("", "%s is not a service", c.Service)
+		return violations
+	}
+
+	for _, m := range c.Methods {
+		method := service.Methods().ByName(protoreflect.Name(m.Name))
+		if method == nil {
+			report(m.Name, "method not found")
+			continue
+		}
+
+		if got := streaming(method); got != m.Streaming {
+			report(m.Name, "streaming is %q, contract expects %q", got, m.Streaming)
+		}
+		for _, field := range m.Request {
+			if problem := checkField(method.Input(), field); problem != "" {
+				report(m.Name, "request %s", problem)
+			}
+		}
+		for _, field := range m.Response {
+			if problem := checkField(method.Output(), field); problem != "" {
+				report(m.Name, "response %s", problem)
+			}
+		}
+	}
+
+	return violations
+}
+
+func streaming(method protoreflect.MethodDescriptor) string {
+	switch {
+	case method.IsStreamingClient() && method.IsStreamingServer():
+		return "bidi"
+	case method.IsStreamingClient():
+		return "client"
+	case method.IsStreamingServer():
+		return "server"
+	default:
+		return ""
+	}
+}
+
+func checkField(msg protoreflect.MessageDescriptor, want Field) string {
+	parts := strings.Split(want.Path, ".")
+	var field protoreflect.FieldDescriptor
+	for i, part := range parts {
+		field = msg.Fields().ByName(protoreflect.Name(part))
+		if field == nil {
+			return fmt.Sprintf("field %s not found in %s", want.Path, msg.FullName())
+		}
+		if i < len(parts)-1 {
+			if field.Message() == nil {
+				return fmt.Sprintf("field %s: %s is not a message", want.Path, part)
+			}
+			msg = field.Message()
+		}
+	}
+
+	if int32(field.Number()) != want.Number {
+		return fmt.Sprintf("field %s has number %d, contract expects %d", want.Path, field.Number(), want.Number)
+	}
+	if got := fieldType(field); got != want.Type {
+		return fmt.Sprintf("field %s has type %s, contract expects %s", want.Path, got, want.Type)
+	}
+	if repeated := field.Cardinality() == protoreflect.Repeated; repeated != want.Repeated {
+		return fmt.Sprintf("field %s repeated is %t, contract expects %t", want.Path, repeated, want.Repeated)
+	}
+
+	return ""
+}
+
+func fieldType(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.Message() != nil:
+		return string(field.Message().FullName())
+	case field.Enum() != nil:
+		return string(field.Enum().FullName())
+	default:
+		return field.Kind().String()
+	}
+}
@@ -8,7 +8,7 @@ import (
 	connect "connectrpc.com/connect"
 	context "context"
 	errors "errors"
-	v1 "github.com/phongloihong/go-shop/services/user-service/external/gen/user/v1"
+	v1 "github.com/phongloihong/go-shop/api/gen/user/v1"
 	http "net/http"
 	strings "strings"
 )
@@ -45,15 +45,29 @@ const (
 	// UserServiceGetPublicProfileProcedure is the fully-qualified name of the UserService's
 	// GetPublicProfile RPC.
 	UserServiceGetPublicProfileProcedure = "/user.v1.UserService/GetPublicProfile"
+	// UserServiceGetNotificationPreferencesProcedure is the fully-qualified name of the UserService's
+	// GetNotificationPreferences RPC.
+	UserServiceGetNotificationPreferencesProcedure = "/user.v1.UserService/GetNotificationPreferences"
+	// UserServiceUpdateNotificationPreferencesProcedure is the fully-qualified name of the
+	// UserService's UpdateNotificationPreferences RPC.
+	UserServiceUpdateNotificationPreferencesProcedure = "/user.v1.UserService/UpdateNotificationPreferences"
+	// UserServiceCheckNotificationAllowedProcedure is the fully-qualified name of the UserService's
+	// CheckNotificationAllowed RPC.
+	UserServiceCheckNotificationAllowedProcedure = "/user.v1.UserService/CheckNotificationAllowed"
 )
 
 // UserServiceClient is a client for the user.v1.UserService service.
+//
+// Deprecated: do not use.
 type UserServiceClient interface {
 	Register(context.Context, *connect.Request[v1.RegisterRequest]) (*connect.Response[v1.RegisterResponse], error)
 	Login(context.Context, *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error)
 	ChangePassword(context.Context, *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error)
 	GetProfile(context.Context, *connect.Request[v1.GetProfileRequest]) (*connect.Response[v1.GetProfileResponse], error)
 	GetPublicProfile(context.Context, *connect.Request[v1.GetPublicProfileRequest]) (*connect.Response[v1.GetPublicProfileResponse], error)
+	GetNotificationPreferences(context.Context, *connect.Request[v1.GetNotificationPreferencesRequest]) (*connect.Response[v1.GetNotificationPreferencesResponse], error)
+	UpdateNotificationPreferences(context.Context, *connect.Request[v1.UpdateNotificationPreferencesRequest]) (*connect.Response[v1.UpdateNotificationPreferencesResponse], error)
+	CheckNotificationAllowed(context.Context, *connect.Request[v1.CheckNotificationAllowedRequest]) (*connect.Response[v1.CheckNotificationAllowedResponse], error)
 }
 
 // NewUserServiceClient constructs a client for the user.v1.UserService service. By default, it uses
@@ -63,6 +77,8 @@ type UserServiceClient interface {
 //
 // The URL supplied here should be the base URL for the Connect or gRPC server (for example,
 // http://api.acme.com or https://acme.com/grpc).
+//
+// Deprecated: do not use.
 func NewUserServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) UserServiceClient {
 	baseURL = strings.TrimRight(baseURL, "/")
 	userServiceMethods := v1.File_user_v1_user_proto.Services().ByName("UserService").Methods()
@@ -89,12 +105,35 @@ func NewUserServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			httpClient,
 			baseURL+UserServiceGetProfileProcedure,
 			connect.WithSchema(userServiceMethods.ByName("GetProfile")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		getPublicProfile: connect.NewClient[v1.GetPublicProfileRequest, v1.GetPublicProfileResponse](
 			httpClient,
 			baseURL+UserServiceGetPublicProfileProcedure,
 			connect.WithSchema(userServiceMethods.ByName("GetPublicProfile")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getNotificationPreferences: connect.NewClient[v1.GetNotificationPreferencesRequest, v1.GetNotificationPreferencesResponse](
+			httpClient,
+			baseURL+UserServiceGetNotificationPreferencesProcedure,
+			connect.WithSchema(userServiceMethods.ByName("GetNotificationPreferences")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		updateNotificationPreferences: connect.NewClient[v1.UpdateNotificationPreferencesRequest, v1.UpdateNotificationPreferencesResponse](
+			httpClient,
+			baseURL+UserServiceUpdateNotificationPreferencesProcedure,
+			connect.WithSchema(userServiceMethods.ByName("UpdateNotificationPreferences")),
+			connect.WithIdempotency(connect.IdempotencyIdempotent),
+			connect.WithClientOptions(opts...),
+		),
+		checkNotificationAllowed: connect.NewClient[v1.CheckNotificationAllowedRequest, v1.CheckNotificationAllowedResponse](
+			httpClient,
+			baseURL+UserServiceCheckNotificationAllowedProcedure,
+			connect.WithSchema(userServiceMethods.ByName("CheckNotificationAllowed")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 	}
@@ -102,11 +141,14 @@ func NewUserServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 
 // userServiceClient implements UserServiceClient.
 type userServiceClient struct {
-	register         *connect.Client[v1.RegisterRequest, v1.RegisterResponse]
-	login            *connect.Client[v1.LoginRequest, v1.LoginResponse]
-	changePassword   *connect.Client[v1.ChangePasswordRequest, v1.ChangePasswordResponse]
-	getProfile       *connect.Client[v1.GetProfileRequest, v1.GetProfileResponse]
-	getPublicProfile *connect.Client[v1.GetPublicProfileRequest, v1.GetPublicProfileResponse]
+	register                      *connect.Client[v1.RegisterRequest, v1.RegisterResponse]
+	login                         *connect.Client[v1.LoginRequest, v1.LoginResponse]
+	changePassword                *connect.Client[v1.ChangePasswordRequest, v1.ChangePasswordResponse]
+	getProfile                    *connect.Client[v1.GetProfileRequest, v1.GetProfileResponse]
+	getPublicProfile              *connect.Client[v1.GetPublicProfileRequest, v1.GetPublicProfileResponse]
+	getNotificationPreferences    *connect.Client[v1.GetNotificationPreferencesRequest, v1.GetNotificationPreferencesResponse]
+	updateNotificationPreferences *connect.Client[v1.UpdateNotificationPreferencesRequest, v1.UpdateNotificationPreferencesResponse]
+	checkNotificationAllowed      *connect.Client[v1.CheckNotificationAllowedRequest, v1.CheckNotificationAllowedResponse]
 }
 
 // Register calls user.v1.UserService.Register.
@@ -134,13 +176,33 @@ func (c *userServiceClient) GetPublicProfile(ctx context.Context, req *connect.R
 	return c.getPublicProfile.CallUnary(ctx, req)
 }
 
+// GetNotificationPreferences calls user.v1.UserService.GetNotificationPreferences.
+func (c *userServiceClient) GetNotificationPreferences(ctx context.Context, req *connect.Request[v1.GetNotificationPreferencesRequest]) (*connect.Response[v1.GetNotificationPreferencesResponse], error) {
+	return c.getNotificationPreferences.CallUnary(ctx, req)
+}
+
+// UpdateNotificationPreferences calls user.v1.UserService.UpdateNotificationPreferences.
+func (c *userServiceClient) UpdateNotificationPreferences(ctx context.Context, req *connect.Request[v1.UpdateNotificationPreferencesRequest]) (*connect.Response[v1.UpdateNotificationPreferencesResponse], error) {
+	return c.updateNotificationPreferences.CallUnary(ctx, req)
+}
+
+// CheckNotificationAllowed calls user.v1.UserService.CheckNotificationAllowed.
+func (c *userServiceClient) CheckNotificationAllowed(ctx context.Context, req *connect.Request[v1.CheckNotificationAllowedRequest]) (*connect.Response[v1.CheckNotificationAllowedResponse], error) {
+	return c.checkNotificationAllowed.CallUnary(ctx, req)
+}
+
 // UserServiceHandler is an implementation of the user.v1.UserService service.
+//
+// Deprecated: do not use.
 type UserServiceHandler interface {
 	Register(context.Context, *connect.Request[v1.RegisterRequest]) (*connect.Response[v1.RegisterResponse], error)
 	Login(context.Context, *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error)
 	ChangePassword(context.Context, *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error)
 	GetProfile(context.Context, *connect.Request[v1.GetProfileRequest]) (*connect.Response[v1.GetProfileResponse], error)
 	GetPublicProfile(context.Context, *connect.Request[v1.GetPublicProfileRequest]) (*connect.Response[v1.GetPublicProfileResponse], error)
+	GetNotificationPreferences(context.Context, *connect.Request[v1.GetNotificationPreferencesRequest]) (*connect.Response[v1.GetNotificationPreferencesResponse], error)
+	UpdateNotificationPreferences(context.Context, *connect.Request[v1.UpdateNotificationPreferencesRequest]) (*connect.Response[v1.UpdateNotificationPreferencesResponse], error)
+	CheckNotificationAllowed(context.Context, *connect.Request[v1.CheckNotificationAllowedRequest]) (*connect.Response[v1.CheckNotificationAllowedResponse], error)
 }
 
 // NewUserServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -148,6 +210,8 @@ type UserServiceHandler interface {
 //
 // By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
 // and JSON codecs. They also support gzip compression.
+//
+// Deprecated: do not use.
 func NewUserServiceHandler(svc UserServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
 	userServiceMethods := v1.File_user_v1_user_proto.Services().ByName("UserService").Methods()
 	userServiceRegisterHandler := connect.NewUnaryHandler(
@@ -172,12 +236,35 @@ func NewUserServiceHandler(svc UserServiceHandler, opts ...connect.HandlerOption
 		UserServiceGetProfileProcedure,
 		svc.GetProfile,
 		connect.WithSchema(userServiceMethods.ByName("GetProfile")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	userServiceGetPublicProfileHandler := connect.NewUnaryHandler(
 		UserServiceGetPublicProfileProcedure,
 		svc.GetPublicProfile,
 		connect.WithSchema(userServiceMethods.ByName("GetPublicProfile")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceGetNotificationPreferencesHandler := connect.NewUnaryHandler(
+		UserServiceGetNotificationPreferencesProcedure,
+		svc.GetNotificationPreferences,
+		connect.WithSchema(userServiceMethods.ByName("GetNotificationPreferences")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceUpdateNotificationPreferencesHandler := connect.NewUnaryHandler(
+		UserServiceUpdateNotificationPreferencesProcedure,
+		svc.UpdateNotificationPreferences,
+		connect.WithSchema(userServiceMethods.ByName("UpdateNotificationPreferences")),
+		connect.WithIdempotency(connect.IdempotencyIdempotent),
+		connect.WithHandlerOptions(opts...),
+	)
+	userServiceCheckNotificationAllowedHandler := connect.NewUnaryHandler(
+		UserServiceCheckNotificationAllowedProcedure,
+		svc.CheckNotificationAllowed,
+		connect.WithSchema(userServiceMethods.ByName("CheckNotificationAllowed")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	return "/user.v1.UserService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -192,6 +279,12 @@ func NewUserServiceHandler(svc UserServiceHandler, opts ...connect.HandlerOption
 			userServiceGetProfileHandler.ServeHTTP(w, r)
 		case UserServiceGetPublicProfileProcedure:
 			userServiceGetPublicProfileHandler.ServeHTTP(w, r)
+		case UserServiceGetNotificationPreferencesProcedure:
+			userServiceGetNotificationPreferencesHandler.ServeHTTP(w, r)
+		case UserServiceUpdateNotificationPreferencesProcedure:
+			userServiceUpdateNotificationPreferencesHandler.ServeHTTP(w, r)
+		case UserServiceCheckNotificationAllowedProcedure:
+			userServiceCheckNotificationAllowedHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -220,3 +313,15 @@ func (UnimplementedUserServiceHandler) GetProfile(context.Context, *connect.Requ
 func (UnimplementedUserServiceHandler) GetPublicProfile(context.Context, *connect.Request[v1.GetPublicProfileRequest]) (*connect.Response[v1.GetPublicProfileResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.v1.UserService.GetPublicProfile is not implemented"))
 }
+
+func (UnimplementedUserServiceHandler) GetNotificationPreferences(context.Context, *connect.Request[v1.GetNotificationPreferencesRequest]) (*connect.Response[v1.GetNotificationPreferencesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.v1.UserService.GetNotificationPreferences is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) UpdateNotificationPreferences(context.Context, *connect.Request[v1.UpdateNotificationPreferencesRequest]) (*connect.Response[v1.UpdateNotificationPreferencesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.v1.UserService.UpdateNotificationPreferences is not implemented"))
+}
+
+func (UnimplementedUserServiceHandler) CheckNotificationAllowed(context.Context, *connect.Request[v1.CheckNotificationAllowedRequest]) (*connect.Response[v1.CheckNotificationAllowedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("user.v1.UserService.CheckNotificationAllowed is not implemented"))
+}
@@ -0,0 +1,77 @@
+// Package redact masks fields annotated with (options.v1.sensitive) so
+// payloads can be logged without leaking credentials or personal data.
+package redact
+
+import (
+	"sync"
+
+	optionsv1 "github.com/phongloihong/go-shop/api/gen/options/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Placeholder replaces the value of sensitive string fields. Sensitive fields
+// of other types are cleared.
+const Placeholder = "[REDACTED]"
+
+// sensitive caches the annotation per field descriptor.
+var sensitive sync.Map
+
+// Message returns a copy of msg with every sensitive field masked, including
+// those of nested, repeated and map-valued messages. msg is not modified.
+func Message(msg proto.Message) proto.Message {
+	if msg == nil {
+		return nil
+	}
+
+	clone := proto.Clone(msg)
+	redact(clone.ProtoReflect())
+
+	return clone
+}
+
+func redact(m protoreflect.Message) {
+	var masked []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitive(fd):
+			masked = append(masked, fd)
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redact(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				redact(value.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			redact(v.Message())
+		}
+		return true
+	})
+
+	// fields are replaced after Range, which must not see its message change
+	for _, fd := range masked {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(Placeholder))
+			continue
+		}
+		m.Clear(fd)
+	}
+}
+
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	if v, ok := sensitive.Load(fd); ok {
+		return v.(bool)
+	}
+
+	ret := false
+	if opts := fd.Options(); opts != nil {
+		ret, _ = proto.GetExtension(opts, optionsv1.E_Sensitive).(bool)
+	}
+	sensitive.Store(fd, ret)
+
+	return ret
+}
@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// errorDomain is the google.rpc.ErrorInfo domain of go-shop errors.
+const errorDomain = "go-shop"
+
+// Reason is the stable, machine-readable cause of an error. Branch on it
+// rather than on messages, which are localized and may change.
+type Reason string
+
+// The reasons go-shop services return, as catalogued in pkg/domain_errors.
+const (
+	ReasonInvalidArgument  Reason = "INVALID_ARGUMENT"
+	ReasonNotFound         Reason = "NOT_FOUND"
+	ReasonAlreadyExists    Reason = "ALREADY_EXISTS"
+	ReasonUnauthenticated  Reason = "UNAUTHENTICATED"
+	ReasonPermissionDenied Reason = "PERMISSION_DENIED"
+	ReasonInternal         Reason = "INTERNAL"
+	ReasonCanceled         Reason = "CANCELED"
+	ReasonDeadlineExceeded Reason = "DEADLINE_EXCEEDED"
+
+	ReasonValidationFailed     Reason = "VALIDATION_FAILED"
+	ReasonRateLimited          Reason = "RATE_LIMITED"
+	ReasonPayloadTooLarge      Reason = "PAYLOAD_TOO_LARGE"
+	ReasonInvalidPageToken     Reason = "INVALID_PAGE_TOKEN"
+	ReasonInvalidFilter        Reason = "INVALID_FILTER"
+	ReasonUserNotFound         Reason = "USER_NOT_FOUND"
+	ReasonUserVersionMismatch  Reason = "USER_VERSION_MISMATCH"
+	ReasonUserVersionRequired  Reason = "USER_VERSION_REQUIRED"
+	ReasonEmailAlreadyExists   Reason = "EMAIL_ALREADY_EXISTS"
+	ReasonInvalidCredentials   Reason = "INVALID_CREDENTIALS"
+	ReasonInvalidAccessToken   Reason = "INVALID_ACCESS_TOKEN"
+	ReasonWebhookNotFound      Reason = "WEBHOOK_NOT_FOUND"
+	ReasonDeliveryNotRetryable Reason = "WEBHOOK_DELIVERY_NOT_RETRYABLE"
+	ReasonJobNotFound          Reason = "JOB_NOT_FOUND"
+	ReasonJobInvalidState      Reason = "JOB_INVALID_STATE"
+	ReasonOperationNotFound    Reason = "OPERATION_NOT_FOUND"
+	ReasonUserUnderLegalHold   Reason = "USER_UNDER_LEGAL_HOLD"
+	// ReasonLoginVerificationRequired has the challenge to answer with
+	// VerifyLogin in Metadata["challenge_id"].
+	ReasonLoginVerificationRequired Reason = "LOGIN_VERIFICATION_REQUIRED"
+	ReasonInvalidVerificationCode   Reason = "INVALID_VERIFICATION_CODE"
+	ReasonInvalidMagicLink          Reason = "INVALID_MAGIC_LINK"
+	ReasonMagicLinkDisabled         Reason = "MAGIC_LINK_DISABLED"
+	// ReasonSSORequired asks to sign in with StartSsoLogin instead.
+	ReasonSSORequired           Reason = "SSO_REQUIRED"
+	ReasonSSOLoginFailed        Reason = "SSO_LOGIN_FAILED"
+	ReasonSSOConnectionNotFound Reason = "SSO_CONNECTION_NOT_FOUND"
+	// ReasonCaptchaRequired asks to retry with a solved CAPTCHA in
+	// captcha_token.
+	ReasonCaptchaRequired Reason = "CAPTCHA_REQUIRED"
+)
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Error is a failed call, with the details go-shop services attach to
+// errors decoded.
+type Error struct {
+	Code connect.Code
+	// Reason is empty for errors that did not come from a go-shop handler,
+	// e.g. network failures or errors of a proxy.
+	Reason  Reason
+	Message string
+	// Metadata is the ErrorInfo metadata of the reason, if any.
+	Metadata map[string]string
+	// FieldViolations lists the invalid request fields of VALIDATION_FAILED.
+	FieldViolations []FieldViolation
+	// RetryAfter is how long the server asked the caller to wait before
+	// retrying, or zero.
+	RetryAfter time.Duration
+
+	err *connect.Error
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// AsError decodes the error of a call made with a Client. It reports false
+// for nil and for errors that are not Connect errors.
+func AsError(err error) (*Error, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil, false
+	}
+
+	e := &Error{
+		Code:    connectErr.Code(),
+		Message: connectErr.Message(),
+		err:     connectErr,
+	}
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+
+		switch value := value.(type) {
+		case *errdetails.ErrorInfo:
+			if value.Domain == errorDomain {
+				e.Reason = Reason(value.Reason)
+				e.Metadata = value.Metadata
+			}
+		case *errdetails.BadRequest:
+			for _, v := range value.FieldViolations {
+				e.FieldViolations = append(e.FieldViolations, FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+		case *errdetails.RetryInfo:
+			e.RetryAfter = value.RetryDelay.AsDuration()
+		}
+	}
+	if e.RetryAfter == 0 {
+		if seconds, err := strconv.Atoi(connectErr.Meta().Get("Retry-After")); err == nil && seconds > 0 {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return e, true
+}
+
+// HasReason reports whether err is an error of a call that failed for
+// reason.
+func HasReason(err error, reason Reason) bool {
+	e, ok := AsError(err)
+	return ok && e.Reason == reason
+}
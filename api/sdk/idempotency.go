@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"connectrpc.com/connect"
+)
+
+// IdempotencyKeyHeader carries the key of a write. A server that
+// deduplicates by it applies writes with the same key once and answers
+// repeats with the first result, so callers can resend a write whose outcome
+// they never saw.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyKey struct{}
+
+// NewIdempotencyKey returns a random key (a UUIDv4) for one logical write.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// WithIdempotencyKey sends key as the Idempotency-Key of the calls made with
+// ctx. Reuse the key when resending the same write, and use a new one per
+// write. Calls are retried automatically only if their method is idempotent
+// in its proto, key or not; see client.RetryPolicy.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// IdempotencyKey returns the key set by WithIdempotencyKey, if any.
+func IdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok && key != ""
+}
+
+func newIdempotencyInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if key, ok := IdempotencyKey(ctx); ok && req.Header().Get(IdempotencyKeyHeader) == "" {
+				req.Header().Set(IdempotencyKeyHeader, key)
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package sdk is the Go client for go-shop, for internal tools and partners.
+// It wraps the generated Connect clients with the plumbing every caller
+// otherwise writes by hand: a Session that logs in and keeps the access
+// token fresh, retries of idempotent calls (see client.RetryPolicy),
+// idempotency keys for writes, and typed errors carrying the server's
+// reason, field violations and retry delay.
+//
+//	c, err := sdk.New("https://api.go-shop.example", sdk.WithCredentials(email, password))
+//	if err != nil {
+//		return err
+//	}
+//	res, err := c.Users.GetProfile(ctx, connect.NewRequest(&userv2.GetProfileRequest{}))
+//	if sdkErr, ok := sdk.AsError(err); ok && sdkErr.Reason == sdk.ReasonUserNotFound {
+//		...
+//	}
+package sdk
+
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/phongloihong/go-shop/api/client"
+	"github.com/phongloihong/go-shop/api/gen/operations/v1/operationsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+)
+
+// Client holds the go-shop service clients. The internal ones are nil unless
+// New is given WithInternalURL.
+type Client struct {
+	Users userv2connect.UserServiceClient
+
+	UserAdmin    userv2connect.UserAdminServiceClient
+	WebhookAdmin userv2connect.WebhookAdminServiceClient
+	Operations   operationsv1connect.OperationServiceClient
+
+	// Session is nil unless New is given WithCredentials.
+	Session *Session
+}
+
+type options struct {
+	email, password string
+	internalURL     string
+	clientOptions   []client.Option
+}
+
+type Option func(*options)
+
+// WithCredentials logs in as the given user on the first call and sends its
+// access token on every call, logging in again before the token expires.
+func WithCredentials(email, password string) Option {
+	return func(o *options) {
+		o.email, o.password = email, password
+	}
+}
+
+// WithInternalURL creates the clients of the services served only on the
+// internal mTLS listener at baseURL. The caller's client certificate is set
+// with WithClientOptions(client.WithTLS(...)).
+func WithInternalURL(baseURL string) Option {
+	return func(o *options) {
+		o.internalURL = baseURL
+	}
+}
+
+// WithClientOptions passes options to the underlying client.Factory, e.g.
+// client.WithRetry, client.WithTimeouts or client.WithTracing.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(o *options) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// New returns a Client for the public API served at baseURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Client{}
+	interceptors := []connect.Interceptor{newIdempotencyInterceptor()}
+	factoryOptions := o.clientOptions
+
+	if o.email != "" {
+		// Login needs no token, so the session's own client goes without one
+		loginFactory, err := client.NewFactory(o.clientOptions...)
+		if err != nil {
+			return nil, err
+		}
+		c.Session = NewSession(PasswordLogin(loginFactory.UserServiceV2(baseURL), o.email, o.password))
+
+		// innermost, so a retried call gets a fresh token but keeps its span,
+		// deadline and circuit breaker
+		interceptors = append(interceptors, c.Session.interceptor())
+		factoryOptions = append(factoryOptions, client.WithTokenSource(c.Session.Token))
+	} else if o.password != "" {
+		return nil, errors.New("WithCredentials requires an email")
+	}
+
+	factoryOptions = append(factoryOptions, client.WithClientOptions(connect.WithInterceptors(interceptors...)))
+	factory, err := client.NewFactory(factoryOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Users = factory.UserServiceV2(baseURL)
+	if o.internalURL != "" {
+		c.UserAdmin = factory.UserAdminService(o.internalURL)
+		c.WebhookAdmin = factory.WebhookAdminService(o.internalURL)
+		c.Operations = factory.OperationService(o.internalURL)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	userv2 "github.com/phongloihong/go-shop/api/gen/user/v2"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+)
+
+// maxRefreshMargin is how long before expiry a Session replaces its token at
+// most; shorter-lived tokens are replaced after 90% of their lifetime.
+const maxRefreshMargin = time.Minute
+
+// LoginFunc obtains a new access token.
+type LoginFunc func(ctx context.Context) (*userv2.LoginResponse, error)
+
+// PasswordLogin logs in to users with email and password.
+func PasswordLogin(users userv2connect.UserServiceClient, email, password string) LoginFunc {
+	return func(ctx context.Context) (*userv2.LoginResponse, error) {
+		res, err := users.Login(ctx, connect.NewRequest(&userv2.LoginRequest{
+			Email:    email,
+			Password: password,
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		return res.Msg, nil
+	}
+}
+
+// Session keeps an access token for the calls of a Client. It logs in on
+// first use and again shortly before the token expires, or when a call fails
+// with CodeUnauthenticated, e.g. because the token was revoked. The user
+// service has no refresh-token method yet, so a new token always comes from
+// logging in again. A Session is safe for concurrent use; concurrent callers
+// share one login.
+type Session struct {
+	login LoginFunc
+	now   func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	previous string
+	refresh  time.Time
+}
+
+func NewSession(login LoginFunc) *Session {
+	return &Session{
+		login: login,
+		now:   time.Now,
+	}
+}
+
+// Token returns the current access token, logging in first if there is
+// none or it is about to expire. It is a client.TokenSource.
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Before(s.refresh) {
+		return s.token, nil
+	}
+
+	res, err := s.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	if res.AccessToken == "" {
+		return "", errors.New("login returned no access token")
+	}
+
+	lifetime := res.ExpiresIn.AsDuration()
+	margin := min(lifetime/10, maxRefreshMargin)
+	s.previous, s.token = s.token, res.AccessToken
+	s.refresh = s.now().Add(lifetime - margin)
+
+	return s.token, nil
+}
+
+// Invalidate makes the next Token log in again.
+func (s *Session) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+}
+
+// rejected drops token after the server refused it, and reports whether it
+// was issued by s, so the call may be retried with a new one.
+func (s *Session) rejected(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch token {
+	case "":
+		return false
+	case s.token:
+		s.token = ""
+		return true
+	default:
+		// another call has already replaced it
+		return token == s.previous
+	}
+}
+
+// interceptor retries a call once with a new token when the server rejects
+// the session's token.
+func (s *Session) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if connect.CodeOf(err) != connect.CodeUnauthenticated {
+				return res, err
+			}
+
+			token, ok := strings.CutPrefix(req.Header().Get("Authorization"), "Bearer ")
+			if !ok || !s.rejected(token) {
+				return res, err
+			}
+
+			fresh, tokenErr := s.Token(ctx)
+			if tokenErr != nil {
+				return nil, err
+			}
+			req.Header().Set("Authorization", "Bearer "+fresh)
+
+			return next(ctx, req)
+		}
+	}
+}
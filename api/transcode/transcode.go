@@ -0,0 +1,331 @@
+// Package transcode serves Connect methods annotated with (options.v1.http)
+// as conventional REST endpoints, for integrators that would rather not use
+// a Connect or gRPC client. A REST request is turned into a Connect unary
+// JSON request for the method and handed to the service's own handler, so
+// interceptors (auth, rate limits, validation, logging) apply unchanged. The
+// response is the Connect one: the method's response message as JSON on
+// success, a Connect JSON error otherwise, with the HTTP status Connect maps
+// its code to (404 for not_found, 409 for already_exists, ...).
+package transcode
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	optionsv1 "github.com/phongloihong/go-shop/api/gen/options/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DefaultReadMaxBytes bounds request bodies unless WithReadMaxBytes is given.
+const DefaultReadMaxBytes = 4 << 20
+
+var pathVariable = regexp.MustCompile(`\{([^}]*)\}`)
+
+type options struct {
+	readMaxBytes int64
+}
+
+type Option func(*options)
+
+// WithReadMaxBytes rejects request bodies larger than n bytes with 429, as
+// connect.WithReadMaxBytes does; zero or less means no limit.
+func WithReadMaxBytes(n int64) Option {
+	return func(opts *options) {
+		opts.readMaxBytes = n
+	}
+}
+
+// New returns a handler serving the REST endpoints of services, given by
+// full name (e.g. userv2connect.UserServiceName), whose Connect handlers
+// next serves. The services' descriptors must be registered, which importing
+// their generated package does. Methods without an (options.v1.http) rule
+// get no endpoint; requests matching no endpoint get 404, or 405 when only
+// the verb is wrong.
+func New(next http.Handler, services []string, opts ...Option) (http.Handler, error) {
+	o := &options{readMaxBytes: DefaultReadMaxBytes}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	mux := http.NewServeMux()
+	for _, name := range services {
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find service %s: %w", name, err)
+		}
+		service, ok := desc.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", name)
+		}
+
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			rule, _ := proto.GetExtension(method.Options(), optionsv1.E_Http).(*optionsv1.HttpRule)
+			if rule == nil || rule.Pattern == nil {
+				continue
+			}
+
+			r, err := newRoute(next, method, rule, o)
+			if err != nil {
+				return nil, fmt.Errorf("invalid http rule of %s: %w", method.FullName(), err)
+			}
+			mux.Handle(r.pattern, r)
+		}
+	}
+
+	return mux, nil
+}
+
+type route struct {
+	next      http.Handler
+	pattern   string
+	procedure string
+	input     protoreflect.MessageDescriptor
+	// pathFields are bound by the path template, keyed by variable name
+	pathFields map[string]protoreflect.FieldDescriptor
+	body       string
+	opts       *options
+}
+
+func newRoute(next http.Handler, method protoreflect.MethodDescriptor, rule *optionsv1.HttpRule, opts *options) (*route, error) {
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, errors.New("streaming methods cannot be transcoded")
+	}
+
+	var verb, path string
+	switch pattern := rule.Pattern.(type) {
+	case *optionsv1.HttpRule_Get:
+		verb, path = http.MethodGet, pattern.Get
+	case *optionsv1.HttpRule_Put:
+		verb, path = http.MethodPut, pattern.Put
+	case *optionsv1.HttpRule_Post:
+		verb, path = http.MethodPost, pattern.Post
+	case *optionsv1.HttpRule_Delete:
+		verb, path = http.MethodDelete, pattern.Delete
+	case *optionsv1.HttpRule_Patch:
+		verb, path = http.MethodPatch, pattern.Patch
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path %q must start with /", path)
+	}
+
+	input := method.Input()
+	r := &route{
+		next:       next,
+		pattern:    verb + " " + path,
+		procedure:  "/" + string(method.Parent().FullName()) + "/" + string(method.Name()),
+		input:      input,
+		pathFields: make(map[string]protoreflect.FieldDescriptor),
+		body:       rule.Body,
+		opts:       opts,
+	}
+
+	for _, match := range pathVariable.FindAllStringSubmatch(path, -1) {
+		fd := input.Fields().ByName(protoreflect.Name(match[1]))
+		if fd == nil || fd.IsList() || fd.IsMap() || fd.Message() != nil {
+			return nil, fmt.Errorf("path variable %q is not a scalar field of %s", match[1], input.FullName())
+		}
+		r.pathFields[match[1]] = fd
+	}
+
+	if r.body != "" && r.body != "*" && input.Fields().ByName(protoreflect.Name(r.body)) == nil {
+		return nil, fmt.Errorf("body %q is not a field of %s", r.body, input.FullName())
+	}
+
+	return r, nil
+}
+
+func (rt *route) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := rt.request(w, r)
+	if err != nil {
+		connect.NewErrorWriter().Write(w, r, err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, rt.procedure, bytes.NewReader(body))
+	if err != nil {
+		connect.NewErrorWriter().Write(w, r, connect.NewError(connect.CodeInternal, err))
+		return
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	req.TLS = r.TLS
+
+	rt.next.ServeHTTP(w, req)
+}
+
+// request builds the JSON request message of the method from the path, the
+// query string and the body of r.
+func (rt *route) request(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	fields := make(map[string]any)
+
+	if rt.body != "" {
+		reader := r.Body
+		if rt.opts.readMaxBytes > 0 {
+			reader = http.MaxBytesReader(w, r.Body, rt.opts.readMaxBytes)
+		}
+		raw, err := io.ReadAll(reader)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit))
+			}
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("failed to read request body: %w", err))
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			raw = []byte("{}")
+		}
+
+		if rt.body == "*" {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.UseNumber()
+			if err := decoder.Decode(&fields); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("request body must be a JSON object: %w", err))
+			}
+		} else {
+			fields[rt.body] = json.RawMessage(raw)
+		}
+	}
+
+	if rt.body != "*" {
+		for key, values := range r.URL.Query() {
+			if err := setQueryField(fields, rt.input, key, values); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			}
+		}
+	}
+
+	for name, fd := range rt.pathFields {
+		value, err := scalarValue(fd, r.PathValue(name))
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		fields[string(fd.Name())] = value
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// decode with the method's types so malformed values fail here, with a
+	// message naming the field
+	msg := newMessage(rt.input)
+	if err := protojson.Unmarshal(encoded, msg); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid request: %w", err))
+	}
+
+	return protojson.Marshal(msg)
+}
+
+// setQueryField sets the field at the dotted path key, named by proto or JSON
+// field names, to values.
+func setQueryField(fields map[string]any, desc protoreflect.MessageDescriptor, key string, values []string) error {
+	names := strings.Split(key, ".")
+	for i, name := range names {
+		fd := desc.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = desc.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return fmt.Errorf("unknown query parameter %q", key)
+		}
+		name = string(fd.Name())
+
+		if i < len(names)-1 {
+			if fd.IsList() || fd.IsMap() || fd.Message() == nil {
+				return fmt.Errorf("query parameter %q does not name a field", key)
+			}
+			nested, ok := fields[name].(map[string]any)
+			if !ok {
+				nested = make(map[string]any)
+				fields[name] = nested
+			}
+			fields, desc = nested, fd.Message()
+			continue
+		}
+
+		if fd.IsMap() {
+			return fmt.Errorf("query parameter %q cannot be set from the query string", key)
+		}
+		if !fd.IsList() {
+			if len(values) > 1 {
+				return fmt.Errorf("query parameter %q is given more than once", key)
+			}
+			value, err := scalarValue(fd, values[0])
+			if err != nil {
+				return err
+			}
+			fields[name] = value
+			return nil
+		}
+
+		list := make([]any, 0, len(values))
+		for _, v := range values {
+			value, err := scalarValue(fd, v)
+			if err != nil {
+				return err
+			}
+			list = append(list, value)
+		}
+		fields[name] = list
+	}
+
+	return nil
+}
+
+// scalarValue is the JSON value of s for fd, as protojson reads it. Messages
+// are limited to the well-known types written as JSON strings, such as
+// FieldMask, Timestamp and Duration.
+func scalarValue(fd protoreflect.FieldDescriptor, s string) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", fd.Name())
+		}
+		return b, nil
+	case protoreflect.EnumKind:
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			return n, nil
+		}
+		return s, nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.FieldMask", "google.protobuf.Timestamp", "google.protobuf.Duration":
+			return s, nil
+		}
+		return nil, fmt.Errorf("%s cannot be set from the query string", fd.Name())
+	default:
+		// protojson reads numbers and bytes from strings too
+		return s, nil
+	}
+}
+
+// newMessage returns an empty message of desc, of its generated type when
+// one is registered.
+func newMessage(desc protoreflect.MessageDescriptor) proto.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName()); err == nil {
+		return mt.New().Interface()
+	}
+
+	return dynamicpb.NewMessage(desc)
+}
@@ -0,0 +1,80 @@
+// Package admin serves runtime diagnostics (pprof profiles, expvar and
+// Prometheus metrics) on a separate port, so CPU and heap profiles can be
+// captured and metrics scraped in production without exposing them on the
+// public listener.
+//
+//	curl -H "Authorization: Bearer $ADMIN_TOKEN" -o heap.pprof http://host:8102/debug/pprof/heap
+//	go tool pprof -http=: heap.pprof
+package admin
+
+import (
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	Port    int  `mapstructure:"port"`
+	// Token must be sent as a bearer token on every request.
+	Token string `mapstructure:"token"`
+}
+
+var publishOnce sync.Once
+
+// NewServer returns the admin server for cfg; the caller starts it. It
+// fails when no token is configured, so diagnostics are never served
+// unauthenticated.
+func NewServer(cfg Config) (*http.Server, error) {
+	if cfg.Token == "" {
+		return nil, errors.New("admin server requires a token")
+	}
+
+	publishOnce.Do(func() {
+		expvar.Publish("goroutines", expvar.Func(func() any {
+			return runtime.NumGoroutine()
+		}))
+		expvar.Publish("go_version", expvar.Func(func() any {
+			return runtime.Version()
+		}))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           requireToken(cfg.Token, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
+
+func requireToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(strings.TrimSpace(r.Header.Get("Authorization")))
+		if subtle.ConstantTimeCompare(got, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
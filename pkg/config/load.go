@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// placeholderPattern matches ${VAR} and ${VAR:default}.
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?\}`)
+
+type loadOptions struct {
+	name  string
+	paths []string
+}
+
+type LoadOption func(*loadOptions)
+
+func WithName(name string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.name = name
+	}
+}
+
+func WithPath(path string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.paths = append(opts.paths, path)
+	}
+}
+
+// Load reads a yaml config file into out. ${VAR} and ${VAR:default}
+// placeholders in the file are expanded from the environment, and any key can
+// still be overridden by its upper-cased env name (database.host -> DATABASE_HOST).
+func Load(out any, options ...LoadOption) error {
+	_, err := load(out, newLoadOptions(options))
+	return err
+}
+
+func newLoadOptions(options []LoadOption) *loadOptions {
+	opts := &loadOptions{
+		name:  "config",
+		paths: nil,
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	if len(opts.paths) == 0 {
+		opts.paths = []string{"./internal/config"}
+	}
+
+	return opts
+}
+
+// load decodes the config file into out and returns the file's path.
+func load(out any, opts *loadOptions) (string, error) {
+	raw, path, err := readFile(opts)
+	if err != nil {
+		return "", err
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	// Enable automatic environment vars
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	if err := v.ReadConfig(bytes.NewReader(ExpandEnv(raw))); err != nil {
+		return path, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	if err := v.Unmarshal(out); err != nil {
+		return path, fmt.Errorf("error unmarshalling config: %w", err)
+	}
+
+	return path, nil
+}
+
+// ExpandEnv replaces ${VAR} and ${VAR:default} placeholders with environment
+// values. Unset variables without a default expand to an empty string.
+func ExpandEnv(raw []byte) []byte {
+	return placeholderPattern.ReplaceAllFunc(raw, func(match []byte) []byte {
+		groups := placeholderPattern.FindSubmatch(match)
+		if value, ok := os.LookupEnv(string(groups[1])); ok {
+			return []byte(value)
+		}
+
+		return groups[2]
+	})
+}
+
+func readFile(opts *loadOptions) ([]byte, string, error) {
+	for _, dir := range opts.paths {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := strings.TrimRight(dir, "/") + "/" + opts.name + ext
+			raw, err := os.ReadFile(path)
+			if err == nil {
+				return raw, path, nil
+			}
+
+			if !os.IsNotExist(err) {
+				return nil, path, fmt.Errorf("error reading config file %s: %w", path, err)
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("config file %q not found in %v", opts.name, opts.paths)
+}
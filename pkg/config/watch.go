@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Watcher keeps the latest successfully loaded config and reloads it when
+// the file changes or the process receives SIGHUP. Settings that can only be
+// applied at startup (listeners, database, secrets) should be read once;
+// components that support live changes read Current on use or register
+// OnChange.
+type Watcher[T any] struct {
+	opts    *loadOptions
+	path    string
+	current atomic.Pointer[T]
+
+	mu        sync.Mutex
+	listeners []func(*T)
+}
+
+// Watch loads the config once and returns a Watcher for it; call Run to
+// start watching.
+func Watch[T any](options ...LoadOption) (*Watcher[T], error) {
+	w := &Watcher[T]{opts: newLoadOptions(options)}
+
+	var cfg T
+	path, err := load(&cfg, w.opts)
+	if err != nil {
+		return nil, err
+	}
+	w.path = path
+	w.current.Store(&cfg)
+
+	return w, nil
+}
+
+// Current returns the latest config. It must be treated as read-only.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// OnChange registers fn to be called with the new config after each reload.
+func (w *Watcher[T]) OnChange(fn func(*T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.listeners = append(w.listeners, fn)
+}
+
+// Reload re-reads the file. An invalid file is logged and ignored so a bad
+// edit never replaces a working config.
+func (w *Watcher[T]) Reload() {
+	var cfg T
+	if _, err := load(&cfg, w.opts); err != nil {
+		log.Printf("config reload failed, keeping previous config: %v", err)
+		return
+	}
+	w.current.Store(&cfg)
+
+	w.mu.Lock()
+	listeners := append([]func(*T){}, w.listeners...)
+	w.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(&cfg)
+	}
+	log.Printf("config reloaded from %s", w.path)
+}
+
+// Run reloads on file changes and SIGHUP until ctx is cancelled.
+func (w *Watcher[T]) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	changed := make(chan struct{}, 1)
+	v := viper.New()
+	v.SetConfigFile(w.path)
+	v.OnConfigChange(func(fsnotify.Event) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	v.WatchConfig()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.Reload()
+		case <-changed:
+			w.Reload()
+		}
+	}
+}
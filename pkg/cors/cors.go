@@ -0,0 +1,97 @@
+// Package cors lets browser apps on other origins call Connect handlers over
+// the Connect and gRPC-Web protocols, and their REST transcoding. It answers
+// preflight requests and exposes the protocol headers that carry errors and
+// trailers.
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// allowedHeaders are the request headers of the Connect and gRPC-Web
+// protocols, plus the ones go-shop handlers read.
+var allowedHeaders = []string{
+	"Content-Type",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+	"Connect-Accept-Encoding",
+	"Connect-Content-Encoding",
+	"Grpc-Timeout",
+	"Grpc-Accept-Encoding",
+	"Grpc-Encoding",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"Authorization",
+	"Accept-Language",
+	"If-Match",
+}
+
+// exposedHeaders carry gRPC-Web status and compression details, the
+// deprecation notices of old API versions and resource ETags, that browser
+// clients must be able to read.
+var exposedHeaders = []string{
+	"Grpc-Status",
+	"Grpc-Message",
+	"Grpc-Status-Details-Bin",
+	"Grpc-Encoding",
+	"Content-Encoding",
+	"Connect-Content-Encoding",
+	"Deprecation",
+	"Sunset",
+	"Link",
+	"ETag",
+}
+
+type Config struct {
+	// AllowedOrigins lists origins such as "https://shop.example.com"; "*"
+	// allows any origin. Empty disables CORS.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// Handler adds CORS headers for allowed origins and answers their preflight
+// requests. Requests from other origins pass through without CORS headers,
+// so browsers block them.
+func Handler(cfg Config, next http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return next
+	}
+
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	allowHeaders := strings.Join(allowedHeaders, ", ")
+	exposeHeaders := strings.Join(exposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!anyOrigin && !slices.Contains(cfg.AllowedOrigins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		header.Set("Access-Control-Allow-Origin", origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			// Connect uses GET and POST, REST endpoints the other verbs too
+			header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
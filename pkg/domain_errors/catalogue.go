@@ -0,0 +1,159 @@
+package domain_error
+
+import (
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// Reason is a stable, machine-readable error code sent to clients as
+// google.rpc.ErrorInfo.reason. Clients branch on it instead of parsing
+// messages, so existing values must never change meaning.
+type Reason string
+
+const (
+	// Generic reasons, used by the NewXxx constructors.
+	ReasonInvalidArgument  Reason = "INVALID_ARGUMENT"
+	ReasonNotFound         Reason = "NOT_FOUND"
+	ReasonAlreadyExists    Reason = "ALREADY_EXISTS"
+	ReasonUnauthenticated  Reason = "UNAUTHENTICATED"
+	ReasonPermissionDenied Reason = "PERMISSION_DENIED"
+	ReasonInternal         Reason = "INTERNAL"
+	ReasonCanceled         Reason = "CANCELED"
+	ReasonDeadlineExceeded Reason = "DEADLINE_EXCEEDED"
+
+	ReasonValidationFailed     Reason = "VALIDATION_FAILED"
+	ReasonRateLimited          Reason = "RATE_LIMITED"
+	ReasonPayloadTooLarge      Reason = "PAYLOAD_TOO_LARGE"
+	ReasonInvalidPageToken     Reason = "INVALID_PAGE_TOKEN"
+	ReasonInvalidFilter        Reason = "INVALID_FILTER"
+	ReasonUserNotFound         Reason = "USER_NOT_FOUND"
+	ReasonUserVersionMismatch  Reason = "USER_VERSION_MISMATCH"
+	ReasonUserVersionRequired  Reason = "USER_VERSION_REQUIRED"
+	ReasonEmailAlreadyExists   Reason = "EMAIL_ALREADY_EXISTS"
+	ReasonInvalidCredentials   Reason = "INVALID_CREDENTIALS"
+	ReasonInvalidAccessToken   Reason = "INVALID_ACCESS_TOKEN"
+	ReasonWebhookNotFound      Reason = "WEBHOOK_NOT_FOUND"
+	ReasonDeliveryNotRetryable Reason = "WEBHOOK_DELIVERY_NOT_RETRYABLE"
+	ReasonJobNotFound          Reason = "JOB_NOT_FOUND"
+	ReasonJobInvalidState      Reason = "JOB_INVALID_STATE"
+	ReasonOperationNotFound    Reason = "OPERATION_NOT_FOUND"
+	ReasonUserUnderLegalHold   Reason = "USER_UNDER_LEGAL_HOLD"
+	// ReasonLoginVerificationRequired carries the challenge_id to answer
+	// with the emailed code.
+	ReasonLoginVerificationRequired Reason = "LOGIN_VERIFICATION_REQUIRED"
+	ReasonInvalidVerificationCode   Reason = "INVALID_VERIFICATION_CODE"
+	ReasonInvalidMagicLink          Reason = "INVALID_MAGIC_LINK"
+	ReasonMagicLinkDisabled         Reason = "MAGIC_LINK_DISABLED"
+	// ReasonSSORequired refuses other ways of signing in to users whose
+	// organization signs in with single sign-on.
+	ReasonSSORequired           Reason = "SSO_REQUIRED"
+	ReasonSSOLoginFailed        Reason = "SSO_LOGIN_FAILED"
+	ReasonSSOConnectionNotFound Reason = "SSO_CONNECTION_NOT_FOUND"
+	// ReasonCaptchaRequired refuses logins and registrations from callers
+	// that look like an attack until they send a solved CAPTCHA.
+	ReasonCaptchaRequired Reason = "CAPTCHA_REQUIRED"
+)
+
+type catalogueEntry struct {
+	code    connect.Code
+	message string
+}
+
+// catalogue holds the Connect code and default message of every reason.
+var catalogue = map[Reason]catalogueEntry{
+	ReasonInvalidArgument:  {connect.CodeInvalidArgument, "The request is invalid."},
+	ReasonNotFound:         {connect.CodeNotFound, "The requested resource was not found."},
+	ReasonAlreadyExists:    {connect.CodeAlreadyExists, "The resource already exists."},
+	ReasonUnauthenticated:  {connect.CodeUnauthenticated, "Authentication is required."},
+	ReasonPermissionDenied: {connect.CodePermissionDenied, "You do not have permission to do this."},
+	ReasonInternal:         {connect.CodeInternal, "Something went wrong. Please try again later."},
+	ReasonCanceled:         {connect.CodeCanceled, "The request was canceled."},
+	ReasonDeadlineExceeded: {connect.CodeDeadlineExceeded, "The request took too long. Please try again."},
+
+	ReasonValidationFailed:          {connect.CodeInvalidArgument, "Some fields are invalid."},
+	ReasonRateLimited:               {connect.CodeResourceExhausted, "Too many requests. Please try again later."},
+	ReasonPayloadTooLarge:           {connect.CodeInvalidArgument, "The request is too large."},
+	ReasonInvalidPageToken:          {connect.CodeInvalidArgument, "The page token is invalid. Start again from the first page."},
+	ReasonInvalidFilter:             {connect.CodeInvalidArgument, "The filter is invalid."},
+	ReasonUserNotFound:              {connect.CodeNotFound, "The user was not found."},
+	ReasonUserVersionMismatch:       {connect.CodeFailedPrecondition, "The profile was changed since it was read. Get it again and retry."},
+	ReasonUserVersionRequired:       {connect.CodeFailedPrecondition, "Send the version of the profile the change is based on. Get the profile first."},
+	ReasonEmailAlreadyExists:        {connect.CodeAlreadyExists, "An account with this email already exists."},
+	ReasonInvalidCredentials:        {connect.CodeUnauthenticated, "The email or password is incorrect."},
+	ReasonInvalidAccessToken:        {connect.CodeUnauthenticated, "The access token is missing, invalid or expired."},
+	ReasonWebhookNotFound:           {connect.CodeNotFound, "The webhook subscription was not found."},
+	ReasonDeliveryNotRetryable:      {connect.CodeFailedPrecondition, "Only failed webhook deliveries can be retried."},
+	ReasonJobNotFound:               {connect.CodeNotFound, "The job was not found."},
+	ReasonJobInvalidState:           {connect.CodeFailedPrecondition, "The job's current status does not allow this."},
+	ReasonOperationNotFound:         {connect.CodeNotFound, "The operation was not found."},
+	ReasonUserUnderLegalHold:        {connect.CodeFailedPrecondition, "The account is under a legal hold and cannot be deleted."},
+	ReasonLoginVerificationRequired: {connect.CodeUnauthenticated, "We sent a verification code to your email. Enter it to finish signing in."},
+	ReasonInvalidVerificationCode:   {connect.CodeUnauthenticated, "The verification code is incorrect or has expired."},
+	ReasonInvalidMagicLink:          {connect.CodeUnauthenticated, "The sign-in link is invalid, expired or already used. Request a new one."},
+	ReasonMagicLinkDisabled:         {connect.CodeFailedPrecondition, "Signing in with an email link is not available. Sign in with your password."},
+	ReasonSSORequired:               {connect.CodeFailedPrecondition, "Your organization uses single sign-on. Continue with SSO to sign in."},
+	ReasonSSOLoginFailed:            {connect.CodeUnauthenticated, "Single sign-on failed or took too long. Please start again."},
+	ReasonSSOConnectionNotFound:     {connect.CodeNotFound, "Single sign-on is not set up for this organization."},
+	ReasonCaptchaRequired:           {connect.CodeFailedPrecondition, "Please complete the CAPTCHA to continue."},
+}
+
+type Option func(*domainError)
+
+// WithMessage replaces the catalogue message, e.g. with specifics for logs
+// and developers.
+func WithMessage(msg string) Option {
+	return func(e *domainError) {
+		e.message = msg
+	}
+}
+
+// WithFieldViolation reports an invalid request field as a BadRequest detail.
+func WithFieldViolation(field, description string) Option {
+	return func(e *domainError) {
+		e.violations = append(e.violations, FieldViolation{Field: field, Description: description})
+	}
+}
+
+// WithRetryAfter tells the client when to retry, as a RetryInfo detail.
+func WithRetryAfter(d time.Duration) Option {
+	return func(e *domainError) {
+		e.retryAfter = d
+	}
+}
+
+// WithMetadata adds a key to the ErrorInfo metadata, for values the client
+// needs to act on the error.
+func WithMetadata(key, value string) Option {
+	return func(e *domainError) {
+		if e.metadata == nil {
+			e.metadata = make(map[string]string)
+		}
+		e.metadata[key] = value
+	}
+}
+
+// New builds an error for a catalogue reason. Unknown reasons are internal
+// errors, so a typo never leaks as a client error.
+func New(reason Reason, opts ...Option) DomainError {
+	entry, ok := catalogue[reason]
+	if !ok {
+		entry = catalogue[ReasonInternal]
+	}
+
+	e := &domainError{
+		message: entry.message,
+		code:    entry.code,
+		reason:  reason,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// DefaultMessage returns the catalogue message for reason.
+func DefaultMessage(reason Reason) string {
+	return catalogue[reason].message
+}
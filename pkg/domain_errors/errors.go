@@ -0,0 +1,172 @@
+package domain_error
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain identifies go-shop in google.rpc.ErrorInfo details.
+const ErrorDomain = "go-shop"
+
+type DomainError interface {
+	error
+	Code() connect.Code
+	Reason() Reason
+}
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+type domainError struct {
+	message    string
+	code       connect.Code
+	reason     Reason
+	violations []FieldViolation
+	retryAfter time.Duration
+	metadata   map[string]string
+}
+
+func (e *domainError) Error() string {
+	return e.message
+}
+
+func (e *domainError) Code() connect.Code {
+	return e.code
+}
+
+func (e *domainError) Reason() Reason {
+	return e.reason
+}
+
+// MapError converts err to a Connect error. Domain errors carry their
+// reason and metadata as google.rpc.ErrorInfo plus BadRequest field
+// violations and RetryInfo when set; context errors become CANCELED or DEADLINE_EXCEEDED and
+// anything else becomes CodeInternal.
+func MapError(err error) *connect.Error {
+	var domainErr *domainError
+	if !errors.As(err, &domainErr) {
+		ctxErr, ok := FromContext(err)
+		if !ok {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+		domainErr = ctxErr.(*domainError)
+	}
+
+	connectErr := connect.NewError(domainErr.code, domainErr)
+	addDetail(connectErr, &errdetails.ErrorInfo{
+		Reason:   string(domainErr.reason),
+		Domain:   ErrorDomain,
+		Metadata: domainErr.metadata,
+	})
+
+	if len(domainErr.violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range domainErr.violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		addDetail(connectErr, badRequest)
+	}
+
+	if domainErr.retryAfter > 0 {
+		addDetail(connectErr, &errdetails.RetryInfo{RetryDelay: durationpb.New(domainErr.retryAfter)})
+	}
+
+	return connectErr
+}
+
+func addDetail(connectErr *connect.Error, msg proto.Message) {
+	detail, err := connect.NewErrorDetail(msg)
+	if err == nil {
+		connectErr.AddDetail(detail)
+	}
+}
+
+// ReasonOf returns the catalogue reason of a domain error, or of a Connect
+// error received from another service.
+func ReasonOf(err error) (Reason, bool) {
+	var domainErr *domainError
+	if errors.As(err, &domainErr) {
+		return domainErr.reason, true
+	}
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return "", false
+	}
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if info, ok := value.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			return Reason(info.Reason), true
+		}
+	}
+
+	return "", false
+}
+
+// FieldViolationsOf returns the invalid request fields reported by a domain
+// error, e.g. to describe a failed item of a batch.
+func FieldViolationsOf(err error) []FieldViolation {
+	var domainErr *domainError
+	if errors.As(err, &domainErr) {
+		return domainErr.violations
+	}
+
+	return nil
+}
+
+// FromContext returns a CANCELED or DEADLINE_EXCEEDED error, with err's
+// message, when err was caused by a canceled context or an expired deadline.
+// Wrap err first to say what was interrupted.
+func FromContext(err error) (DomainError, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(ReasonDeadlineExceeded, WithMessage(err.Error())), true
+	case errors.Is(err, context.Canceled):
+		return New(ReasonCanceled, WithMessage(err.Error())), true
+	default:
+		return nil, false
+	}
+}
+
+func IsNotFound(err error) bool {
+	if domainErr, ok := err.(DomainError); ok {
+		return domainErr.Code() == connect.CodeNotFound
+	}
+
+	return false
+}
+
+// Constructors
+func NewNotFoundError(msg string) DomainError {
+	return New(ReasonNotFound, WithMessage(msg))
+}
+
+func NewAlreadyExistsError(msg string) DomainError {
+	return New(ReasonAlreadyExists, WithMessage(msg))
+}
+
+func NewUnauthorizedError(msg string) DomainError {
+	return New(ReasonUnauthenticated, WithMessage(msg))
+}
+
+func NewInvalidData(msg string) DomainError {
+	return New(ReasonInvalidArgument, WithMessage(msg))
+}
+
+func NewInternalError(msg string) DomainError {
+	return New(ReasonInternal, WithMessage(msg))
+}
@@ -0,0 +1,158 @@
+package domain_error
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// DefaultLocale is the locale of the catalogue messages, used when the
+// caller accepts none of the translated locales.
+const DefaultLocale = "en"
+
+// locales/<locale>.json maps reasons to translated messages. Adding a
+// locale only takes a new file; reasons missing from it fall back to
+// DefaultLocale.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var translations = mustLoadTranslations()
+
+func mustLoadTranslations() map[string]map[Reason]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("failed to read error message locales: %v", err))
+	}
+
+	out := make(map[string]map[Reason]string, len(entries))
+	for _, entry := range entries {
+		raw, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("failed to read error messages %s: %v", entry.Name(), err))
+		}
+
+		messages := make(map[Reason]string)
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("failed to parse error messages %s: %v", entry.Name(), err))
+		}
+		out[strings.ToLower(strings.TrimSuffix(entry.Name(), ".json"))] = messages
+	}
+
+	return out
+}
+
+// Locales returns every locale error messages are available in.
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales[1:])
+
+	return locales
+}
+
+// MatchLocale picks the best supported locale for an Accept-Language header,
+// e.g. "vi-VN,vi;q=0.9,en;q=0.8" -> "vi". A regional tag also matches its
+// base language.
+func MatchLocale(acceptLanguage string) string {
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == DefaultLocale {
+			return DefaultLocale
+		}
+		if _, ok := translations[tag]; ok {
+			return tag
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+		if base == DefaultLocale {
+			return DefaultLocale
+		}
+		if _, ok := translations[base]; ok {
+			return base
+		}
+	}
+
+	return DefaultLocale
+}
+
+// LocalizedMessage returns the message for reason in locale, falling back to
+// the catalogue message.
+func LocalizedMessage(reason Reason, locale string) string {
+	if msg, ok := translations[locale][reason]; ok {
+		return msg
+	}
+
+	return DefaultMessage(reason)
+}
+
+// Localize adds a google.rpc.LocalizedMessage detail to a Connect error that
+// carries a catalogue reason, in the best locale for acceptLanguage, and
+// returns the locale used. The error message itself is left untouched for
+// logs and developers.
+func Localize(connectErr *connect.Error, acceptLanguage string) (string, bool) {
+	reason, ok := ReasonOf(connectErr)
+	if !ok {
+		return "", false
+	}
+
+	locale := MatchLocale(acceptLanguage)
+	addDetail(connectErr, &errdetails.LocalizedMessage{
+		Locale:  locale,
+		Message: LocalizedMessage(reason, locale),
+	})
+
+	return locale, true
+}
+
+// parseAcceptLanguage returns the lower-cased language tags of an
+// Accept-Language header, most preferred first. Tags with q=0 and the "*"
+// wildcard are dropped.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	out := make([]string, len(tags))
+	for i, tag := range tags {
+		out[i] = tag.tag
+	}
+
+	return out
+}
@@ -0,0 +1,106 @@
+// Package fieldmask applies the read_mask of Get and List methods
+// (AIP-157), so callers receive only the fields they asked for. Services
+// that need just an ID and an email then never see a phone number they
+// would have to handle as PII.
+package fieldmask
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Mask is a validated read mask.
+type Mask struct {
+	// nil keeps every field
+	fields tree
+}
+
+// Parse checks mask against resource, the message type it applies to.
+// Paths are relative to the resource, e.g. "id" or "name.given_name", and
+// may not traverse repeated or map fields. An empty mask, or one holding
+// "*", keeps every field. An invalid path fails with VALIDATION_FAILED on
+// field, the request's name for the mask (e.g. "read_mask").
+func Parse(field string, mask *fieldmaskpb.FieldMask, resource proto.Message) (*Mask, error) {
+	paths := mask.GetPaths()
+	if len(paths) == 0 || slices.Contains(paths, "*") {
+		return &Mask{}, nil
+	}
+
+	var opts []domain_error.Option
+	for _, path := range paths {
+		if _, err := fieldmaskpb.New(resource, path); err != nil {
+			opts = append(opts, domain_error.WithFieldViolation(field, fmt.Sprintf("%q is not a field of %s", path, resource.ProtoReflect().Descriptor().Name())))
+		}
+	}
+	if len(opts) > 0 {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	return &Mask{fields: newTree(paths)}, nil
+}
+
+// Prune clears every field of msg not named by the mask. msg must be of the
+// type the mask was parsed for.
+func (m *Mask) Prune(msg proto.Message) {
+	if m.fields == nil {
+		return
+	}
+
+	m.fields.prune(msg.ProtoReflect())
+}
+
+// tree holds the masked fields of a message; a nil subtree keeps the whole
+// field.
+type tree map[protoreflect.Name]tree
+
+func newTree(paths []string) tree {
+	root := tree{}
+	for _, path := range paths {
+		node := root
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			child, ok := node[protoreflect.Name(name)]
+			last := i == len(names)-1
+			switch {
+			case ok && child == nil:
+				// a shorter path already keeps the whole field
+			case last:
+				node[protoreflect.Name(name)] = nil
+			case !ok:
+				child = tree{}
+				node[protoreflect.Name(name)] = child
+			}
+			if child == nil {
+				break
+			}
+			node = child
+		}
+	}
+
+	return root
+}
+
+func (t tree) prune(msg protoreflect.Message) {
+	var unmasked []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		child, ok := t[fd.Name()]
+		switch {
+		case !ok:
+			unmasked = append(unmasked, fd)
+		case child != nil:
+			child.prune(value.Message())
+		}
+		return true
+	})
+
+	// cleared after Range, which must not see the message change
+	for _, fd := range unmasked {
+		msg.Clear(fd)
+	}
+}
@@ -0,0 +1,304 @@
+// Package filter parses the filter expressions of go-shop list methods, a
+// subset of AIP-160:
+//
+//	status = "active" AND create_time > "2024-01-01"
+//	email = "*@example.com" OR email = "*@example.org"
+//	NOT name.given_name : "an"
+//
+// Restrictions compare a field with a literal using =, !=, <, <=, >, >= or
+// ":" (contains, case-insensitive). A "*" in a string compared with = or !=
+// matches any run of characters. OR binds tighter than AND, as in AIP-160;
+// NOT or a leading "-" negates a term, and parentheses group. Juxtaposed
+// terms are ANDed.
+//
+// Parse checks fields and literals against the list method's Schema, so the
+// result only names known fields with values of their type. SQL turns it
+// into a parameterised WHERE clause and Match evaluates it in memory.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+const (
+	maxLength       = 2048
+	maxRestrictions = 50
+)
+
+// Type is the type of a filterable field.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+	// Timestamp literals are RFC 3339 timestamps or dates, taken as UTC
+	// midnight.
+	Timestamp
+)
+
+// Schema maps the fields a list method can be filtered on to their types.
+// Field names are those of the API, e.g. "name.given_name".
+type Schema map[string]Type
+
+// Expr is a parsed filter. A nil Expr matches everything.
+type Expr interface {
+	isExpr()
+}
+
+type and struct{ left, right Expr }
+
+type or struct{ left, right Expr }
+
+type not struct{ expr Expr }
+
+// restriction compares a field with a value of the field's type: string,
+// int64, bool or time.Time.
+type restriction struct {
+	field    string
+	typ      Type
+	op       string
+	value    any
+	wildcard bool
+}
+
+func (and) isExpr()         {}
+func (or) isExpr()          {}
+func (not) isExpr()         {}
+func (restriction) isExpr() {}
+
+// Parse parses and type-checks input against schema. An empty input returns
+// a nil Expr. Errors have reason INVALID_FILTER and say where input went
+// wrong.
+func Parse(input string, schema Schema) (Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	if len(input) > maxLength {
+		return nil, invalid(fmt.Sprintf("filter is longer than %d bytes", maxLength))
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, schema: schema}
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, invalid(fmt.Sprintf("unexpected %q at position %d", tok.text, tok.pos))
+	}
+
+	return expr, nil
+}
+
+type parser struct {
+	tokens       []token
+	next         int
+	schema       Schema
+	restrictions int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// expression := sequence { "AND" sequence }
+func (p *parser) expression() (Expr, error) {
+	left, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("AND") {
+		p.advance()
+		right, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+
+	return left, nil
+}
+
+// sequence := factor { factor }
+func (p *parser) sequence() (Expr, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.startsTerm() {
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+
+	return left, nil
+}
+
+// factor := term { "OR" term }
+func (p *parser) factor() (Expr, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.advance()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) startsTerm() bool {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenLParen, tokenMinus:
+		return true
+	case tokenWord:
+		return !tok.isKeyword("AND") && !tok.isKeyword("OR")
+	default:
+		return false
+	}
+}
+
+// term := [ "NOT" | "-" ] ( "(" expression ")" | restriction )
+func (p *parser) term() (Expr, error) {
+	if tok := p.peek(); tok.kind == tokenMinus || tok.isKeyword("NOT") {
+		p.advance()
+		expr, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		return not{expr}, nil
+	}
+
+	return p.simple()
+}
+
+func (p *parser) simple() (Expr, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenLParen:
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, invalid(fmt.Sprintf("missing ) for ( at position %d", tok.pos))
+		}
+		return expr, nil
+	case tokenWord:
+		return p.restriction(tok)
+	case tokenEOF:
+		return nil, invalid("filter ends where a restriction was expected")
+	default:
+		return nil, invalid(fmt.Sprintf("unexpected %q at position %d", tok.text, tok.pos))
+	}
+}
+
+// restriction := field comparator value
+func (p *parser) restriction(field token) (Expr, error) {
+	typ, ok := p.schema[field.text]
+	if !ok {
+		return nil, invalid(fmt.Sprintf("unknown field %q at position %d", field.text, field.pos))
+	}
+
+	op := p.advance()
+	if op.kind != tokenComparator {
+		return nil, invalid(fmt.Sprintf("expected a comparator after %q at position %d", field.text, op.pos))
+	}
+	literal := p.advance()
+	if literal.kind != tokenWord && literal.kind != tokenString {
+		return nil, invalid(fmt.Sprintf("expected a value after %q at position %d", op.text, literal.pos))
+	}
+
+	p.restrictions++
+	if p.restrictions > maxRestrictions {
+		return nil, invalid(fmt.Sprintf("filter has more than %d restrictions", maxRestrictions))
+	}
+
+	r := restriction{field: field.text, typ: typ, op: op.text}
+	if err := r.setValue(literal); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *restriction) setValue(literal token) error {
+	badOp := invalid(fmt.Sprintf("%s does not support %q at position %d", r.field, r.op, literal.pos))
+
+	switch r.typ {
+	case String:
+		r.value = literal.text
+		r.wildcard = strings.Contains(literal.text, "*") && (r.op == "=" || r.op == "!=")
+		return nil
+	case Int:
+		if r.op == ":" {
+			return badOp
+		}
+		n, err := strconv.ParseInt(literal.text, 10, 64)
+		if err != nil {
+			return invalid(fmt.Sprintf("%s needs an integer, not %q at position %d", r.field, literal.text, literal.pos))
+		}
+		r.value = n
+		return nil
+	case Bool:
+		if r.op != "=" && r.op != "!=" {
+			return badOp
+		}
+		b, err := strconv.ParseBool(literal.text)
+		if err != nil {
+			return invalid(fmt.Sprintf("%s needs true or false, not %q at position %d", r.field, literal.text, literal.pos))
+		}
+		r.value = b
+		return nil
+	case Timestamp:
+		if r.op == ":" {
+			return badOp
+		}
+		t, err := parseTimestamp(literal.text)
+		if err != nil {
+			return invalid(fmt.Sprintf("%s needs an RFC 3339 timestamp or a date, not %q at position %d", r.field, literal.text, literal.pos))
+		}
+		r.value = t
+		return nil
+	default:
+		return invalid(fmt.Sprintf("%s cannot be filtered on", r.field))
+	}
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+
+	return time.Parse(time.DateOnly, s)
+}
+
+func invalid(msg string) error {
+	return domain_error.New(domain_error.ReasonInvalidFilter, domain_error.WithFieldViolation("filter", msg))
+}
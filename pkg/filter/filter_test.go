@@ -0,0 +1,112 @@
+package filter_test
+
+import (
+	"testing"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+)
+
+var testSchema = filter.Schema{
+	"status":          filter.String,
+	"email":           filter.String,
+	"name.given_name": filter.String,
+	"age":             filter.Int,
+	"verified":        filter.Bool,
+	"create_time":     filter.Timestamp,
+}
+
+var testColumns = map[string]string{
+	"status":          "status",
+	"email":           "email",
+	"name.given_name": "first_name",
+	"age":             "age",
+	"verified":        "verified",
+	"create_time":     "created_at",
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "OR binds tighter than AND",
+			input: `status = "a" AND status = "b" OR status = "c"`,
+			want:  "(status = $1 AND (status = $2 OR status = $3))",
+		},
+		{
+			name:  "OR binds tighter than AND on the left",
+			input: `status = "a" OR status = "b" AND status = "c"`,
+			want:  "((status = $1 OR status = $2) AND status = $3)",
+		},
+		{
+			name:  "juxtaposed terms are ANDed",
+			input: `status = "a" status = "b" OR status = "c"`,
+			want:  "(status = $1 AND (status = $2 OR status = $3))",
+		},
+		{
+			name:  "NOT binds tighter than OR",
+			input: `NOT status = "a" OR status = "b"`,
+			want:  "(NOT (status = $1) OR status = $2)",
+		},
+		{
+			name:  "minus negates like NOT",
+			input: `-status = "a"`,
+			want:  "NOT (status = $1)",
+		},
+		{
+			name:  "parentheses group",
+			input: `NOT (status = "a" AND age > 3) OR verified = true`,
+			want:  "(NOT (status = $1 AND age > $2) OR verified = $3)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := filter.Parse(tt.input, testSchema)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			got, _, err := filter.SQL(expr, testColumns, 1)
+			if err != nil {
+				t.Fatalf("SQL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unknown field", input: `password = "x"`},
+		{name: "contains on an int", input: `age : "3"`},
+		{name: "non-integer int", input: `age = "three"`},
+		{name: "ordering on a bool", input: `verified < true`},
+		{name: "bad timestamp", input: `create_time > "yesterday"`},
+		{name: "missing value", input: `status =`},
+		{name: "missing comparator", input: `status "a"`},
+		{name: "unclosed parenthesis", input: `(status = "a"`},
+		{name: "trailing operator", input: `status = "a" AND`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := filter.Parse(tt.input, testSchema)
+			if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonInvalidFilter {
+				t.Errorf("Parse(%q) = %v, want %s", tt.input, err, domain_error.ReasonInvalidFilter)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	expr, err := filter.Parse("  ", testSchema)
+	if err != nil || expr != nil {
+		t.Errorf("Parse of a blank filter = %v, %v, want nil, nil", expr, err)
+	}
+}
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenComparator
+	tokenLParen
+	tokenRParen
+	tokenMinus
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the 1-based byte offset in the filter, for error messages.
+	pos int
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && t.text == keyword
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i + 1})
+			i++
+		case c == '=' || c == ':':
+			tokens = append(tokens, token{tokenComparator, string(c), i + 1})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			if i+1 < len(input) && input[i+1] == '=' {
+				tokens = append(tokens, token{tokenComparator, input[i : i+2], i + 1})
+				i += 2
+				continue
+			}
+			if c == '!' {
+				return nil, invalid(fmt.Sprintf("expected != at position %d", i+1))
+			}
+			tokens = append(tokens, token{tokenComparator, string(c), i + 1})
+			i++
+		case c == '"' || c == '\'':
+			text, end, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, i + 1})
+			i = end
+		case c == '-' && (i+1 == len(input) || !isDigit(input[i+1])):
+			tokens = append(tokens, token{tokenMinus, "-", i + 1})
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(input) && isWordByte(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, input[start:i], start + 1})
+		default:
+			return nil, invalid(fmt.Sprintf("unexpected %q at position %d", c, i+1))
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(input) + 1}), nil
+}
+
+// lexString reads the quoted string starting at input[start], returning its
+// unescaped text and the offset after the closing quote.
+func lexString(input string, start int) (string, int, error) {
+	quote := input[start]
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch {
+		case input[i] == '\\' && i+1 < len(input):
+			i++
+			b.WriteByte(input[i])
+		case input[i] == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(input[i])
+		}
+	}
+
+	return "", 0, invalid(fmt.Sprintf("unterminated string at position %d", start+1))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isWordByte accepts field paths and unquoted literals such as numbers,
+// dates and true. Non-ASCII bytes are accepted so unquoted words may hold
+// any letter.
+func isWordByte(c byte) bool {
+	return c >= 0x80 || unicode.IsLetter(rune(c)) || isDigit(c) || strings.IndexByte("_.-*@+", c) >= 0
+}
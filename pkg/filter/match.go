@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"cmp"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Match reports whether the item whose field values are returned by value
+// satisfies expr, with the semantics of SQL for in-memory lists. value must
+// return a string, int64, bool or time.Time for each field of the schema,
+// as declared there. A nil expr matches everything.
+func Match(expr Expr, value func(field string) any) bool {
+	switch e := expr.(type) {
+	case nil:
+		return true
+	case and:
+		return Match(e.left, value) && Match(e.right, value)
+	case or:
+		return Match(e.left, value) || Match(e.right, value)
+	case not:
+		return !Match(e.expr, value)
+	case restriction:
+		return e.match(value(e.field))
+	default:
+		return false
+	}
+}
+
+func (r restriction) match(actual any) bool {
+	if r.op == ":" {
+		s, ok := actual.(string)
+		return ok && strings.Contains(strings.ToLower(s), strings.ToLower(r.value.(string)))
+	}
+	if r.wildcard {
+		s, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(r.value.(string)), `\*`, ".*") + "$"
+		matched := regexp.MustCompile(pattern).MatchString(s)
+		return matched == (r.op == "=")
+	}
+
+	var order int
+	switch want := r.value.(type) {
+	case string:
+		s, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		order = strings.Compare(s, want)
+	case int64:
+		n, ok := actual.(int64)
+		if !ok {
+			return false
+		}
+		order = cmp.Compare(n, want)
+	case bool:
+		b, ok := actual.(bool)
+		if !ok {
+			return false
+		}
+		if b != want {
+			order = 1
+		}
+	case time.Time:
+		t, ok := actual.(time.Time)
+		if !ok {
+			return false
+		}
+		order = t.Compare(want)
+	default:
+		return false
+	}
+
+	switch r.op {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SQL translates expr into a Postgres condition for a WHERE clause, with
+// values passed as the returned arguments numbered from $firstArg. columns
+// maps every field of the schema to the SQL expression it is compared with,
+// e.g. "create_time" to "created_at"; it must come from code, never from
+// the request. A nil expr is "TRUE".
+func SQL(expr Expr, columns map[string]string, firstArg int, opts ...SQLOption) (string, []any, error) {
+	if expr == nil {
+		return "TRUE", nil, nil
+	}
+
+	t := &sqlTranslator{columns: columns, next: firstArg, hashed: map[string]func(string) string{}}
+	for _, opt := range opts {
+		opt(t)
+	}
+	var b strings.Builder
+	if err := t.write(&b, expr); err != nil {
+		return "", nil, err
+	}
+
+	return b.String(), t.args, nil
+}
+
+// SQLOption changes how SQL translates a field.
+type SQLOption func(*sqlTranslator)
+
+// HashedField compares the string field by hash(value), for a column that
+// stores a hash of the field instead of the field, e.g. an encrypted one.
+// Hashes only tell equal values apart, so field accepts = and != without
+// "*"; other restrictions of it fail with INVALID_FILTER.
+func HashedField(field string, hash func(string) string) SQLOption {
+	return func(t *sqlTranslator) {
+		t.hashed[field] = hash
+	}
+}
+
+type sqlTranslator struct {
+	columns map[string]string
+	hashed  map[string]func(string) string
+	next    int
+	args    []any
+}
+
+func (t *sqlTranslator) write(b *strings.Builder, expr Expr) error {
+	switch e := expr.(type) {
+	case and:
+		return t.binary(b, e.left, "AND", e.right)
+	case or:
+		return t.binary(b, e.left, "OR", e.right)
+	case not:
+		b.WriteString("NOT ")
+		if _, ok := e.expr.(restriction); ok {
+			b.WriteString("(")
+			defer b.WriteString(")")
+		}
+		return t.write(b, e.expr)
+	case restriction:
+		column, ok := t.columns[e.field]
+		if !ok {
+			return fmt.Errorf("no column for filter field %q", e.field)
+		}
+		op, value := e.op, e.value
+		if hash, ok := t.hashed[e.field]; ok {
+			if (e.op != "=" && e.op != "!=") || e.wildcard {
+				return invalid(fmt.Sprintf("%s can only be compared with = or != and no *", e.field))
+			}
+			value = hash(e.value.(string))
+		}
+		switch {
+		case e.op == ":":
+			op, value = "ILIKE", "%"+likeEscaper.Replace(e.value.(string))+"%"
+		case e.wildcard:
+			op = "LIKE"
+			if e.op == "!=" {
+				op = "NOT LIKE"
+			}
+			value = strings.ReplaceAll(likeEscaper.Replace(e.value.(string)), "*", "%")
+		case e.op == "!=":
+			op = "<>"
+		}
+		t.args = append(t.args, value)
+		fmt.Fprintf(b, "%s %s $%d", column, op, t.next)
+		t.next++
+		return nil
+	default:
+		return fmt.Errorf("unknown filter expression %T", expr)
+	}
+}
+
+func (t *sqlTranslator) binary(b *strings.Builder, left Expr, op string, right Expr) error {
+	b.WriteString("(")
+	if err := t.write(b, left); err != nil {
+		return err
+	}
+	b.WriteString(" " + op + " ")
+	if err := t.write(b, right); err != nil {
+		return err
+	}
+	b.WriteString(")")
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package filter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+)
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		firstArg int
+		want     string
+		wantArgs []any
+	}{
+		{
+			name:     "contains is a case-insensitive LIKE",
+			input:    `name.given_name : "an"`,
+			firstArg: 1,
+			want:     "first_name ILIKE $1",
+			wantArgs: []any{"%an%"},
+		},
+		{
+			name:     "contains escapes LIKE metacharacters",
+			input:    `email : "50%_off\\"`,
+			firstArg: 1,
+			want:     "email ILIKE $1",
+			wantArgs: []any{`%50\%\_off\\%`},
+		},
+		{
+			name:     "wildcard with =",
+			input:    `email = "*@example.com"`,
+			firstArg: 1,
+			want:     "email LIKE $1",
+			wantArgs: []any{"%@example.com"},
+		},
+		{
+			name:     "wildcard with !=",
+			input:    `email != "*_test@*"`,
+			firstArg: 1,
+			want:     "email NOT LIKE $1",
+			wantArgs: []any{`%\_test@%`},
+		},
+		{
+			name:     "* is literal with other comparators",
+			input:    `email > "a*"`,
+			firstArg: 1,
+			want:     "email > $1",
+			wantArgs: []any{"a*"},
+		},
+		{
+			name:     "!= without wildcard",
+			input:    `status != "active"`,
+			firstArg: 1,
+			want:     "status <> $1",
+			wantArgs: []any{"active"},
+		},
+		{
+			name:     "arguments are numbered from firstArg",
+			input:    `status = "active" AND age >= 18 AND verified = true`,
+			firstArg: 3,
+			want:     "((status = $3 AND age >= $4) AND verified = $5)",
+			wantArgs: []any{"active", int64(18), true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := filter.Parse(tt.input, testSchema)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			got, args, err := filter.SQL(expr, testColumns, tt.firstArg)
+			if err != nil {
+				t.Fatalf("SQL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SQL(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQL(%q) args = %#v, want %#v", tt.input, args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSQLNilExpr(t *testing.T) {
+	got, args, err := filter.SQL(nil, testColumns, 1)
+	if got != "TRUE" || args != nil || err != nil {
+		t.Errorf("SQL(nil) = %q, %v, %v, want TRUE", got, args, err)
+	}
+}
+
+func TestSQLNoColumn(t *testing.T) {
+	expr, err := filter.Parse(`age = 3`, testSchema)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	_, _, err = filter.SQL(expr, map[string]string{"status": "status"}, 1)
+	if err == nil || !strings.Contains(err.Error(), `"age"`) {
+		t.Errorf("SQL of a field without a column = %v, want an error naming it", err)
+	}
+}
+
+func TestSQLHashedField(t *testing.T) {
+	hash := func(s string) string { return "hash(" + s + ")" }
+	schema := filter.Schema{"phone": filter.String}
+	columns := map[string]string{"phone": "phone_hash"}
+
+	t.Run("compares hashes with = and !=", func(t *testing.T) {
+		expr, err := filter.Parse(`phone = "0901234567" OR phone != "0907654321"`, schema)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		got, args, err := filter.SQL(expr, columns, 1, filter.HashedField("phone", hash))
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if want := "(phone_hash = $1 OR phone_hash <> $2)"; got != want {
+			t.Errorf("SQL = %s, want %s", got, want)
+		}
+		if want := []any{"hash(0901234567)", "hash(0907654321)"}; !reflect.DeepEqual(args, want) {
+			t.Errorf("SQL args = %v, want %v", args, want)
+		}
+	})
+
+	for _, input := range []string{`phone : "0901"`, `phone < "0901234567"`, `phone = "0901*"`, `phone != "*4567"`} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			expr, err := filter.Parse(input, schema)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", input, err)
+			}
+			_, _, err = filter.SQL(expr, columns, 1, filter.HashedField("phone", hash))
+			if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonInvalidFilter {
+				t.Errorf("SQL(%q) = %v, want %s", input, err, domain_error.ReasonInvalidFilter)
+			}
+		})
+	}
+}
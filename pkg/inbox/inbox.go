@@ -0,0 +1,83 @@
+// Package inbox makes broker consumers idempotent. Each processed message ID
+// is recorded in the same transaction as the handler's effects, so a message
+// redelivered by the broker (at-least-once) is acknowledged without being
+// applied twice.
+//
+// The table is owned by the embedding service's migrations:
+//
+//	CREATE TABLE inbox (
+//	  consumer TEXT NOT NULL,
+//	  message_id TEXT NOT NULL,
+//	  processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//	  PRIMARY KEY (consumer, message_id)
+//	);
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+)
+
+// TxHandler applies a message's effects through tx. Returning an error rolls
+// back both the effects and the inbox record, so the message is redelivered.
+type TxHandler func(ctx context.Context, tx pgx.Tx, msg messaging.Message) error
+
+type Inbox struct {
+	pool     *pgxpool.Pool
+	table    string
+	consumer string
+}
+
+// New returns an inbox stored in table. consumer scopes the recorded IDs so
+// several handlers of the same message keep separate records.
+func New(pool *pgxpool.Pool, table, consumer string) *Inbox {
+	return &Inbox{
+		pool:     pool,
+		table:    pgx.Identifier{table}.Sanitize(),
+		consumer: consumer,
+	}
+}
+
+// Wrap adapts handler into a messaging.Handler that runs at most once per
+// message ID.
+func (i *Inbox) Wrap(handler TxHandler) messaging.Handler {
+	return func(ctx context.Context, msg messaging.Message) error {
+		if msg.ID == "" {
+			return errors.New("inbox: message has no ID to deduplicate on")
+		}
+
+		tx, err := i.pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("inbox: failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback(ctx)
+
+		// A concurrent delivery of the same message blocks on the primary key
+		// until this transaction finishes, then sees the conflict.
+		tag, err := tx.Exec(ctx,
+			"INSERT INTO "+i.table+" (consumer, message_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			i.consumer, msg.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("inbox: failed to record message %s: %w", msg.ID, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+
+		if err := handler(ctx, tx, msg); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("inbox: failed to commit message %s: %w", msg.ID, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package inbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/inbox"
+	"github.com/phongloihong/go-shop/pkg/internal/testutil"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+)
+
+const testSchema = `
+CREATE TABLE inbox (
+  consumer TEXT NOT NULL,
+  message_id TEXT NOT NULL,
+  processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (consumer, message_id)
+);
+CREATE TABLE stock (sku TEXT PRIMARY KEY, quantity INT NOT NULL);
+INSERT INTO stock VALUES ('sku-1', 10);`
+
+// decrement takes one sku-1 off the stock, and then fails with err if set.
+func decrement(err error) inbox.TxHandler {
+	return func(ctx context.Context, tx pgx.Tx, _ messaging.Message) error {
+		if _, execErr := tx.Exec(ctx, "UPDATE stock SET quantity = quantity - 1 WHERE sku = 'sku-1'"); execErr != nil {
+			return execErr
+		}
+		return err
+	}
+}
+
+func quantity(t *testing.T, pool *pgxpool.Pool) int {
+	t.Helper()
+
+	var n int
+	if err := pool.QueryRow(context.Background(), "SELECT quantity FROM stock WHERE sku = 'sku-1'").Scan(&n); err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	return n
+}
+
+func TestInbox(t *testing.T) {
+	pool := testutil.StartPostgres(t, testSchema)
+	ctx := context.Background()
+
+	t.Run("skips a duplicate message", func(t *testing.T) {
+		handler := inbox.New(pool, "inbox", "stock").Wrap(decrement(nil))
+		msg := messaging.Message{ID: "msg-1", Topic: "orders"}
+
+		for range 2 {
+			if err := handler(ctx, msg); err != nil {
+				t.Fatalf("handler: %v", err)
+			}
+		}
+		if n := quantity(t, pool); n != 9 {
+			t.Errorf("stock after a redelivered message = %d, want 9", n)
+		}
+
+		// another consumer keeps its own record of the message
+		if err := inbox.New(pool, "inbox", "audit").Wrap(decrement(nil))(ctx, msg); err != nil {
+			t.Fatalf("handler of another consumer: %v", err)
+		}
+		if n := quantity(t, pool); n != 8 {
+			t.Errorf("stock after another consumer = %d, want 8", n)
+		}
+	})
+
+	t.Run("rolls back the record with a failing handler", func(t *testing.T) {
+		failed := errors.New("handler failed")
+		msg := messaging.Message{ID: "msg-2", Topic: "orders"}
+		before := quantity(t, pool)
+
+		err := inbox.New(pool, "inbox", "stock").Wrap(decrement(failed))(ctx, msg)
+		if !errors.Is(err, failed) {
+			t.Fatalf("handler = %v, want %v", err, failed)
+		}
+		if n := quantity(t, pool); n != before {
+			t.Errorf("stock after a failed handler = %d, want %d", n, before)
+		}
+
+		// the redelivery is applied
+		if err := inbox.New(pool, "inbox", "stock").Wrap(decrement(nil))(ctx, msg); err != nil {
+			t.Fatalf("handler of the redelivery: %v", err)
+		}
+		if n := quantity(t, pool); n != before-1 {
+			t.Errorf("stock after the redelivery = %d, want %d", n, before-1)
+		}
+	})
+
+	t.Run("rejects a message without ID", func(t *testing.T) {
+		if err := inbox.New(pool, "inbox", "stock").Wrap(decrement(nil))(ctx, messaging.Message{}); err == nil {
+			t.Error("handler of a message without ID succeeded, want an error")
+		}
+	})
+}
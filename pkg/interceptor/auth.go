@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+// TokenValidator parses a bearer token and returns the claims it carries.
+type TokenValidator[C any] func(token string) (C, error)
+
+type claimsContextKey struct{}
+
+// NewAuthInterceptor requires a valid "Authorization: Bearer <token>" header
+// on every procedure except publicProcedures, and stores the validated claims
+// in the request context for ClaimsFromContext.
+func NewAuthInterceptor[C any](validate TokenValidator[C], publicProcedures ...string) connect.UnaryInterceptorFunc {
+	public := make(map[string]bool, len(publicProcedures))
+	for _, procedure := range publicProcedures {
+		public[procedure] = true
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if public[req.Spec().Procedure] {
+				return next(ctx, req)
+			}
+
+			token, ok := strings.CutPrefix(req.Header().Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				return nil, domain_error.MapError(domain_error.New(domain_error.ReasonInvalidAccessToken, domain_error.WithMessage("missing access token")))
+			}
+
+			claims, err := validate(token)
+			if err != nil {
+				return nil, domain_error.MapError(domain_error.New(domain_error.ReasonInvalidAccessToken, domain_error.WithMessage("invalid access token")))
+			}
+
+			return next(context.WithValue(ctx, claimsContextKey{}, claims), req)
+		}
+	}
+}
+
+// ClaimsFromContext returns the claims stored by NewAuthInterceptor.
+func ClaimsFromContext[C any](ctx context.Context) (C, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(C)
+	return claims, ok
+}
@@ -0,0 +1,106 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var faultsInjectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "chaos_faults_injected_total",
+	Help: "Faults injected by the chaos interceptor, by procedure and fault (latency, error, drop).",
+}, []string{"procedure", "fault"})
+
+func init() {
+	prometheus.MustRegister(faultsInjectedTotal)
+}
+
+// ChaosConfig sets which faults the chaos interceptor injects. Rates are
+// fractions of requests between 0 and 1, drawn independently per fault.
+type ChaosConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Procedures limits faults to these procedures, e.g.
+	// "/user.v1.UserService/Login". Empty means every procedure.
+	Procedures []string `mapstructure:"procedures"`
+
+	// LatencyRate of requests wait Latency before the handler runs, or until
+	// their deadline if that comes first.
+	LatencyRate float64       `mapstructure:"latency_rate"`
+	Latency     time.Duration `mapstructure:"latency"`
+	// ErrorRate of requests fail with ErrorCode (default "unavailable")
+	// without reaching the handler.
+	ErrorRate float64 `mapstructure:"error_rate"`
+	ErrorCode string  `mapstructure:"error_code"`
+	// DropRate of requests run the handler, but the client gets
+	// CodeUnavailable instead of the response, as if the connection broke
+	// after the work was done. Use it to check that retries are safe.
+	DropRate float64 `mapstructure:"drop_rate"`
+}
+
+// NewChaosInterceptor injects latency, errors and dropped responses as set
+// by the current config, which is read per request so faults can be switched
+// on and off live. It is for resilience testing of clients' retries and
+// circuit breakers and must never be enabled in production.
+func NewChaosInterceptor(currentConfig func() *ChaosConfig) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			cfg := currentConfig()
+			procedure := req.Spec().Procedure
+			if cfg == nil || !cfg.Enabled || (len(cfg.Procedures) > 0 && !slices.Contains(cfg.Procedures, procedure)) {
+				return next(ctx, req)
+			}
+
+			if hit(cfg.LatencyRate) && cfg.Latency > 0 {
+				faultsInjectedTotal.WithLabelValues(procedure, "latency").Inc()
+				timer := time.NewTimer(cfg.Latency)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, contextError(ctx)
+				}
+			}
+
+			if hit(cfg.ErrorRate) {
+				faultsInjectedTotal.WithLabelValues(procedure, "error").Inc()
+				return nil, connect.NewError(chaosErrorCode(cfg.ErrorCode), errors.New("chaos: injected error"))
+			}
+
+			res, err := next(ctx, req)
+			if err == nil && hit(cfg.DropRate) {
+				faultsInjectedTotal.WithLabelValues(procedure, "drop").Inc()
+				return nil, connect.NewError(connect.CodeUnavailable, errors.New("chaos: response dropped"))
+			}
+
+			return res, err
+		}
+	}
+}
+
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+func chaosErrorCode(name string) connect.Code {
+	code := connect.CodeUnavailable
+	if name != "" {
+		if err := code.UnmarshalText([]byte(name)); err != nil {
+			return connect.CodeUnavailable
+		}
+	}
+
+	return code
+}
+
+func contextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+	}
+
+	return connect.NewError(connect.CodeCanceled, ctx.Err())
+}
@@ -0,0 +1,102 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+type clientIPContextKey struct{}
+
+// NewClientIPInterceptor stores the caller's address for
+// ClientIPFromContext. X-Forwarded-For is only believed from trustedProxies;
+// other callers are identified by their peer address, whatever they send.
+func NewClientIPInterceptor(trustedProxies []netip.Prefix) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			ip := ClientIP(req.Peer().Addr, req.Header().Values("X-Forwarded-For"), trustedProxies)
+			return next(context.WithValue(ctx, clientIPContextKey{}, ip), req)
+		}
+	}
+}
+
+// ClientIPFromContext returns the address stored by NewClientIPInterceptor,
+// or "" without one.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey{}).(string)
+	return ip
+}
+
+// ClientIP returns the address of the client behind peerAddr. When the peer
+// is a trusted proxy, it is the right-most forwardedFor hop that is not one:
+// each trusted proxy appends the address it was called from, while the hops
+// left of it are whatever the client sent. A hop that is not an address ends
+// the walk at the last trusted proxy, so garbage never becomes a client.
+func ClientIP(peerAddr string, forwardedFor []string, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(peerAddr)
+	if err != nil {
+		host = peerAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !trusted(peer, trustedProxies) {
+		return host
+	}
+
+	var hops []string
+	for _, value := range forwardedFor {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	client := peer.Unmap()
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = hop.Unmap()
+		if !trusted(client, trustedProxies) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+func trusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseTrustedProxies parses CIDRs such as "10.0.0.0/8"; a bare address
+// stands for itself.
+func ParseTrustedProxies(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
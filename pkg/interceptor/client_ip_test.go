@@ -0,0 +1,108 @@
+package interceptor_test
+
+import (
+	"testing"
+
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := interceptor.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{
+			name: "untrusted peer without X-Forwarded-For",
+			peer: "203.0.113.7:5000",
+			want: "203.0.113.7",
+		},
+		{
+			name:         "untrusted peer with X-Forwarded-For",
+			peer:         "203.0.113.7:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "chain of trusted proxies",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"198.51.100.1, 10.0.0.3", "192.0.2.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "spoofed hops left of the client",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"1.2.3.4, 5.6.7.8, 198.51.100.1, 10.0.0.3"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "malformed hop",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"198.51.100.1, not-an-ip, 10.0.0.3"},
+			want:         "10.0.0.3",
+		},
+		{
+			name:         "malformed header",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"garbage"},
+			want:         "10.0.0.2",
+		},
+		{
+			name:         "every hop trusted",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"10.0.0.4, 10.0.0.3"},
+			want:         "10.0.0.4",
+		},
+		{
+			name:         "IPv4-mapped trusted proxy",
+			peer:         "[::ffff:10.0.0.2]:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			name:         "IPv6 client",
+			peer:         "10.0.0.2:5000",
+			forwardedFor: []string{"2001:db8::1"},
+			want:         "2001:db8::1",
+		},
+		{
+			name: "peer without a port",
+			peer: "203.0.113.7",
+			want: "203.0.113.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interceptor.ClientIP(tt.peer, tt.forwardedFor, proxies); got != tt.want {
+				t.Errorf("ClientIP(%q, %q) = %q, want %q", tt.peer, tt.forwardedFor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	got := interceptor.ClientIP("10.0.0.2:5000", []string{"198.51.100.1"}, nil)
+	if got != "10.0.0.2" {
+		t.Errorf("ClientIP without trusted proxies = %q, want the peer", got)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	for _, cidr := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0"} {
+		if _, err := interceptor.ParseTrustedProxies([]string{cidr}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want an error", cidr)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+)
+
+// NewDeadlineInterceptor rejects requests whose propagated deadline has
+// already passed with CodeDeadlineExceeded, before any handler work is done.
+// Connect applies the caller's Connect-Timeout-Ms / grpc-timeout to ctx, so
+// handlers that pass ctx downstream propagate the remaining budget.
+func NewDeadlineInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if err := ctx.Err(); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					return nil, connect.NewError(connect.CodeDeadlineExceeded, err)
+				}
+				return nil, connect.NewError(connect.CodeCanceled, err)
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
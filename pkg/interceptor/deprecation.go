@@ -0,0 +1,76 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var deprecatedCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "connect_deprecated_calls_total",
+	Help: "Calls to deprecated procedures, by procedure, to track client migration.",
+}, []string{"procedure"})
+
+func init() {
+	prometheus.MustRegister(deprecatedCallsTotal)
+}
+
+// Deprecation describes a deprecated API version.
+type Deprecation struct {
+	// Since is when the version was deprecated.
+	Since time.Time
+	// Sunset is when it stops being served; zero if not yet decided.
+	Sunset time.Time
+	// Successor is the fully-qualified name of the replacing service, e.g.
+	// "user.v2.UserService".
+	Successor string
+}
+
+// NewDeprecationInterceptor marks every response and error of the handlers
+// it wraps with a Deprecation header (RFC 9745), a Sunset header (RFC 8594)
+// when one is set, and a Link to the successor service, and counts the
+// calls per procedure. Install it only on deprecated handlers.
+func NewDeprecationInterceptor(d Deprecation) connect.UnaryInterceptorFunc {
+	deprecation := fmt.Sprintf("@%d", d.Since.Unix())
+	sunset := ""
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	link := ""
+	if d.Successor != "" {
+		link = fmt.Sprintf(`</%s/>; rel="successor-version"`, d.Successor)
+	}
+
+	setHeaders := func(header http.Header) {
+		header.Set("Deprecation", deprecation)
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		if link != "" {
+			header.Set("Link", link)
+		}
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			deprecatedCallsTotal.WithLabelValues(req.Spec().Procedure).Inc()
+
+			res, err := next(ctx, req)
+			if err != nil {
+				var connectErr *connect.Error
+				if errors.As(err, &connectErr) {
+					setHeaders(connectErr.Meta())
+				}
+				return nil, err
+			}
+			setHeaders(res.Header())
+
+			return res, nil
+		}
+	}
+}
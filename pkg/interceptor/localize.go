@@ -0,0 +1,35 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+// NewLocalizeInterceptor translates errors with a catalogue reason into the
+// caller's language, picked from the Accept-Language header. The translation
+// is added as a google.rpc.LocalizedMessage detail and the chosen locale is
+// sent back as Content-Language. Register it first so it also sees errors
+// from the other interceptors.
+func NewLocalizeInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if err == nil {
+				return res, nil
+			}
+
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) {
+				return res, err
+			}
+			if locale, ok := domain_error.Localize(connectErr, req.Header().Get("Accept-Language")); ok {
+				connectErr.Meta().Set("Content-Language", locale)
+			}
+
+			return res, connectErr
+		}
+	}
+}
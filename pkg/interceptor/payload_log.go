@@ -0,0 +1,61 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewPayloadLogInterceptor logs every request and its response or error at
+// debug level, for troubleshooting. redact masks sensitive fields before a
+// payload is encoded (see api/redact). Nothing is encoded unless logger has
+// debug enabled, so the interceptor is cheap to leave in the chain.
+func NewPayloadLogInterceptor(logger *slog.Logger, redact func(proto.Message) proto.Message) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if !logger.Enabled(ctx, slog.LevelDebug) {
+				return next(ctx, req)
+			}
+
+			start := time.Now()
+			res, err := next(ctx, req)
+
+			attrs := []slog.Attr{
+				slog.String("procedure", req.Spec().Procedure),
+				slog.String("protocol", req.Peer().Protocol),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("request", encodePayload(req.Any(), redact)),
+			}
+			if err != nil {
+				attrs = append(attrs,
+					slog.String("code", connect.CodeOf(err).String()),
+					slog.String("error", err.Error()),
+				)
+			} else {
+				attrs = append(attrs, slog.String("response", encodePayload(res.Any(), redact)))
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "rpc payload", attrs...)
+
+			return res, err
+		}
+	}
+}
+
+func encodePayload(payload any, redact func(proto.Message) proto.Message) string {
+	msg, ok := payload.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("<%T>", payload)
+	}
+
+	b, err := protojson.Marshal(redact(msg))
+	if err != nil {
+		return fmt.Sprintf("<%T: %v>", payload, err)
+	}
+
+	return string(b)
+}
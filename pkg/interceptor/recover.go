@@ -0,0 +1,68 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var panicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "connect_handler_panics_total",
+	Help: "Panics recovered in Connect handlers, by procedure.",
+}, []string{"procedure"})
+
+func init() {
+	prometheus.MustRegister(panicsTotal)
+}
+
+// NewRecoverInterceptor turns a handler panic into a CodeInternal error, so
+// the client gets a proper response and the server keeps running. The panic
+// value and stack are logged with the procedure and peer, but never sent to
+// the client. It covers unary and streaming handlers alike; a stream that
+// panics midway ends with the error after the messages already sent.
+func NewRecoverInterceptor() connect.Interceptor {
+	return recoverInterceptor{}
+}
+
+type recoverInterceptor struct{}
+
+func (recoverInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (res connect.AnyResponse, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				res = nil
+				err = recovered(req.Spec().Procedure, req.Peer().Addr, r)
+			}
+		}()
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves clients alone; only handlers are recovered.
+func (recoverInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (recoverInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(conn.Spec().Procedure, conn.Peer().Addr, r)
+			}
+		}()
+
+		return next(ctx, conn)
+	}
+}
+
+func recovered(procedure, peer string, r any) error {
+	panicsTotal.WithLabelValues(procedure).Inc()
+	log.Printf("panic in %s (peer %s): %v\n%s", procedure, peer, r, debug.Stack())
+
+	return connect.NewError(connect.CodeInternal, errors.New("internal error"))
+}
@@ -0,0 +1,60 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// SizeLimitConfig caps the size of request messages, measured in protobuf
+// encoding whatever the wire format.
+type SizeLimitConfig struct {
+	// MaxBytes applies to every procedure; zero means no limit.
+	MaxBytes int `mapstructure:"max_bytes"`
+	// Procedures overrides MaxBytes per method, keyed by lower-case method
+	// name (e.g. "register").
+	Procedures map[string]int `mapstructure:"procedures"`
+}
+
+// NewSizeLimitInterceptor rejects requests larger than the current limit of
+// their procedure with reason PAYLOAD_TOO_LARGE, naming the size and the
+// limit. It enforces tight per-procedure limits below the handler's
+// connect.WithReadMaxBytes, which stops oversized bodies before they are
+// decoded.
+func NewSizeLimitInterceptor(currentConfig func() *SizeLimitConfig) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			cfg := currentConfig()
+			if cfg == nil {
+				return next(ctx, req)
+			}
+
+			limit := cfg.MaxBytes
+			// config keys are lower-cased by viper
+			if procedureLimit, ok := cfg.Procedures[strings.ToLower(path.Base(req.Spec().Procedure))]; ok {
+				limit = procedureLimit
+			}
+			if limit <= 0 {
+				return next(ctx, req)
+			}
+
+			msg, ok := req.Any().(proto.Message)
+			if !ok {
+				return next(ctx, req)
+			}
+			if size := proto.Size(msg); size > limit {
+				return nil, domain_error.MapError(domain_error.New(
+					domain_error.ReasonPayloadTooLarge,
+					domain_error.WithMessage(fmt.Sprintf("request is %d bytes, more than the %d bytes allowed for %s", size, limit, req.Spec().Procedure)),
+				))
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
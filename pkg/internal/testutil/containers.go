@@ -0,0 +1,130 @@
+// Package testutil starts ephemeral Postgres, Redis and NATS containers with
+// testcontainers for the integration tests of this module's packages. The
+// packages own no migrations, so tests create the tables their package docs
+// describe.
+//
+// Tests using it are skipped when no Docker daemon is reachable.
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Images match docker-compose.yml so tests see the same versions as dev.
+const (
+	PostgresImage = "postgres:16-alpine"
+	RedisImage    = "redis:7-alpine"
+	NATSImage     = "nats:2.10-alpine"
+)
+
+const startupTimeout = 2 * time.Minute
+
+// StartPostgres starts a Postgres container for the test, runs schema on it
+// and returns a pool connected to it. The container is removed when the test
+// ends.
+func StartPostgres(t *testing.T, schema string) *pgxpool.Pool {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	container, err := tcpostgres.Run(ctx, PostgresImage,
+		tcpostgres.WithDatabase("test_db"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("postgres"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get postgres connection string: %v", err)
+	}
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to postgres: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if _, err := pool.Exec(ctx, schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	return pool
+}
+
+// StartRedis starts a Redis container for the test and returns a client
+// connected to it. The container is removed when the test ends.
+func StartRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	container, err := tcredis.Run(ctx, RedisImage)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start redis container: %v", err)
+	}
+
+	url, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get redis connection string: %v", err)
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("failed to parse redis url %s: %v", url, err)
+	}
+
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+// StartNATS starts a NATS server with JetStream for the test and returns its
+// URL. The container is removed when the test ends.
+func StartNATS(t *testing.T) string {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        NATSImage,
+			Cmd:          []string{"--jetstream"},
+			ExposedPorts: []string{"4222/tcp"},
+			WaitingFor:   wait.ForLog("Server is ready"),
+		},
+		Started: true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start nats container: %v", err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "4222/tcp", "nats")
+	if err != nil {
+		t.Fatalf("failed to get nats endpoint: %v", err)
+	}
+
+	return endpoint
+}
@@ -0,0 +1,344 @@
+// Package jobs is a Postgres-backed queue for deferred work such as sending
+// emails, processing images or generating exports. Jobs are enqueued inside
+// the caller's unit of work, so they exist only if the state change that
+// produced them commits. A Worker runs them with retries and exponential
+// backoff; jobs that exhaust their attempts are dead-lettered and can be
+// inspected and retried through Queue. Handlers may leave a result for
+// whoever started the job, which makes a job usable as a long-running
+// operation: start it, poll it with Get, Cancel it and read its Result.
+//
+// The table is owned by the embedding service's migrations:
+//
+//	CREATE TABLE jobs (
+//	  id BIGSERIAL PRIMARY KEY,
+//	  kind TEXT NOT NULL,
+//	  args JSONB NOT NULL DEFAULT '{}',
+//	  status TEXT NOT NULL DEFAULT 'pending',
+//	  attempt INT NOT NULL DEFAULT 0,
+//	  max_attempts INT NOT NULL,
+//	  run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//	  locked_until TIMESTAMPTZ,
+//	  last_error TEXT NOT NULL DEFAULT '',
+//	  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//	  finished_at TIMESTAMPTZ,
+//	  result JSONB
+//	);
+//	CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE status IN ('pending', 'running');
+//	CREATE INDEX idx_jobs_status ON jobs(status, id);
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/pkg/uow"
+)
+
+type Status string
+
+const (
+	// StatusPending jobs wait for run_at, including failed jobs awaiting a retry.
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	// StatusDead jobs failed every attempt or failed permanently.
+	StatusDead      Status = "dead"
+	StatusCancelled Status = "cancelled"
+)
+
+const DefaultMaxAttempts = 10
+
+var (
+	ErrNotFound = errors.New("job not found")
+	// ErrInvalidState is returned when retrying or cancelling a job whose
+	// status does not allow it.
+	ErrInvalidState = errors.New("job is not in a state that allows this")
+)
+
+type Job struct {
+	ID          int64
+	Kind        string
+	Args        json.RawMessage
+	Status      Status
+	Attempt     int32
+	MaxAttempts int32
+	RunAt       time.Time
+	LastError   string
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+	// Result is the JSON-encoded result of a completed job, if its handler
+	// set one.
+	Result json.RawMessage
+}
+
+// UnmarshalArgs decodes the job's arguments into v.
+func (j *Job) UnmarshalArgs(v any) error {
+	if err := json.Unmarshal(j.Args, v); err != nil {
+		return fmt.Errorf("failed to decode args of %s job %d: %w", j.Kind, j.ID, err)
+	}
+
+	return nil
+}
+
+// SetResult records v, encoded as JSON, as the job's result. Handlers call it
+// before returning; the result is stored only if the attempt succeeds.
+func (j *Job) SetResult(v any) error {
+	result, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of %s job %d: %w", j.Kind, j.ID, err)
+	}
+	j.Result = result
+
+	return nil
+}
+
+type db interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type Queue struct {
+	pool  *pgxpool.Pool
+	table string
+}
+
+// New returns a queue stored in table.
+func New(pool *pgxpool.Pool, table string) *Queue {
+	return &Queue{
+		pool:  pool,
+		table: pgx.Identifier{table}.Sanitize(),
+	}
+}
+
+// db returns the unit of work transaction running ctx, or the pool.
+func (q *Queue) db(ctx context.Context) db {
+	if tx, ok := uow.Tx(ctx); ok {
+		return tx
+	}
+
+	return q.pool
+}
+
+type enqueueOptions struct {
+	runAt       time.Time
+	maxAttempts int32
+}
+
+type EnqueueOption func(*enqueueOptions)
+
+// WithRunAt defers the job until t.
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(opts *enqueueOptions) {
+		opts.runAt = t
+	}
+}
+
+// WithMaxAttempts replaces DefaultMaxAttempts.
+func WithMaxAttempts(n int32) EnqueueOption {
+	return func(opts *enqueueOptions) {
+		opts.maxAttempts = n
+	}
+}
+
+// Enqueue adds a job of kind with args encoded as JSON and returns its ID.
+// Inside a unit of work the job commits or rolls back with it.
+func (q *Queue) Enqueue(ctx context.Context, kind string, args any, options ...EnqueueOption) (int64, error) {
+	opts := &enqueueOptions{maxAttempts: DefaultMaxAttempts}
+	for _, option := range options {
+		option(opts)
+	}
+
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode args of %s job: %w", kind, err)
+	}
+
+	var runAt *time.Time
+	if !opts.runAt.IsZero() {
+		t := opts.runAt.UTC()
+		runAt = &t
+	}
+
+	var id int64
+	err = q.db(ctx).QueryRow(ctx,
+		"INSERT INTO "+q.table+" (kind, args, max_attempts, run_at) VALUES ($1, $2, $3, COALESCE($4::timestamptz, NOW())) RETURNING id",
+		kind, argsJSON, opts.maxAttempts, runAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue %s job: %w", kind, err)
+	}
+
+	return id, nil
+}
+
+const jobColumns = "id, kind, args, status, attempt, max_attempts, run_at, last_error, created_at, finished_at, result"
+
+func scanJob(row pgx.Row) (*Job, error) {
+	var job Job
+	err := row.Scan(&job.ID, &job.Kind, &job.Args, &job.Status, &job.Attempt, &job.MaxAttempts, &job.RunAt, &job.LastError, &job.CreatedAt, &job.FinishedAt, &job.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (q *Queue) Get(ctx context.Context, id int64) (*Job, error) {
+	job, err := scanJob(q.db(ctx).QueryRow(ctx, "SELECT "+jobColumns+" FROM "+q.table+" WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %d: %w", id, err)
+	}
+
+	return job, nil
+}
+
+// FilterSchema lists the fields ListFilter.Where can filter on.
+var FilterSchema = filter.Schema{
+	"kind":         filter.String,
+	"status":       filter.String,
+	"attempt":      filter.Int,
+	"max_attempts": filter.Int,
+	"last_error":   filter.String,
+	"run_at":       filter.Timestamp,
+	"created_at":   filter.Timestamp,
+	"finished_at":  filter.Timestamp,
+}
+
+// filterColumns are the columns of FilterSchema's fields; unfinished jobs
+// match no finished_at restriction.
+var filterColumns = map[string]string{
+	"kind":         "kind",
+	"status":       "status",
+	"attempt":      "attempt",
+	"max_attempts": "max_attempts",
+	"last_error":   "last_error",
+	"run_at":       "run_at",
+	"created_at":   "created_at",
+	"finished_at":  "finished_at",
+}
+
+// ListFilter selects jobs for List; zero fields match everything.
+type ListFilter struct {
+	Kind   string
+	Status Status
+	// Where is a filter parsed against FilterSchema.
+	Where filter.Expr
+	// BeforeID pages backwards: pass the smallest ID of the previous page.
+	BeforeID int64
+	Limit    int32
+}
+
+// List returns matching jobs, newest first.
+func (q *Queue) List(ctx context.Context, params ListFilter) ([]*Job, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	where, whereArgs, err := filter.SQL(params.Where, filterColumns, 5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	rows, err := q.db(ctx).Query(ctx,
+		"SELECT "+jobColumns+" FROM "+q.table+`
+		WHERE ($1::text = '' OR kind = $1) AND ($2::text = '' OR status = $2) AND ($3::bigint = 0 OR id < $3)
+		AND `+where+`
+		ORDER BY id DESC LIMIT $4`,
+		append([]any{params.Kind, string(params.Status), params.BeforeID, limit}, whereArgs...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	ret := make([]*Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		ret = append(ret, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	return ret, nil
+}
+
+// Retry runs a dead, cancelled or pending job again right away with a fresh
+// set of attempts.
+func (q *Queue) Retry(ctx context.Context, id int64) (*Job, error) {
+	return q.transition(ctx, id, `
+		UPDATE `+q.table+`
+		SET status = 'pending', attempt = 0, run_at = NOW(), finished_at = NULL
+		WHERE id = $1 AND status IN ('dead', 'cancelled', 'pending')
+		RETURNING `+jobColumns)
+}
+
+// Cancel stops a pending job from running. A running job is cancelled too:
+// the worker running it cancels its handler's context within a poll interval
+// and discards the outcome, so handlers of long jobs should stop once their
+// context is done.
+func (q *Queue) Cancel(ctx context.Context, id int64) (*Job, error) {
+	return q.transition(ctx, id, `
+		UPDATE `+q.table+`
+		SET status = 'cancelled', locked_until = NULL, finished_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'running')
+		RETURNING `+jobColumns)
+}
+
+// status returns the current status of job id.
+func (q *Queue) status(ctx context.Context, id int64) (Status, error) {
+	var status Status
+	err := q.pool.QueryRow(ctx, "SELECT status FROM "+q.table+" WHERE id = $1", id).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get status of job %d: %w", id, err)
+	}
+
+	return status, nil
+}
+
+// Prune deletes completed and cancelled jobs that finished before t and
+// returns how many it deleted. Dead jobs are kept for inspection.
+func (q *Queue) Prune(ctx context.Context, t time.Time) (int64, error) {
+	tag, err := q.db(ctx).Exec(ctx,
+		"DELETE FROM "+q.table+" WHERE status IN ('completed', 'cancelled') AND finished_at < $1",
+		t.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (q *Queue) transition(ctx context.Context, id int64, sql string) (*Job, error) {
+	job, err := scanJob(q.db(ctx).QueryRow(ctx, sql, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := q.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update job %d: %w", id, err)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,335 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Handler runs one attempt of a job. Returning an error schedules a retry,
+// unless it is wrapped with Permanent or the job has no attempts left.
+type Handler func(ctx context.Context, job *Job) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying; the job is dead-lettered at once.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type WorkerConfig struct {
+	// Concurrency is the number of jobs run at the same time.
+	Concurrency int `mapstructure:"concurrency"`
+	// PollInterval is how often an idle worker looks for due jobs.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// Lease is the time limit of one attempt. A job whose worker died is run
+	// again once its lease expires.
+	Lease          time.Duration `mapstructure:"lease"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+}
+
+var DefaultWorkerConfig = WorkerConfig{
+	Concurrency:    4,
+	PollInterval:   time.Second,
+	Lease:          5 * time.Minute,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     time.Hour,
+}
+
+type workerMetrics struct {
+	finished *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newWorkerMetrics(registerer prometheus.Registerer) *workerMetrics {
+	m := &workerMetrics{
+		finished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jobs_attempts_total",
+			Help: "Job attempts by kind and outcome (completed, retried, dead, cancelled).",
+		}, []string{"kind", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "jobs_attempt_duration_seconds",
+			Help: "Duration of job attempts by kind.",
+		}, []string{"kind"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.finished, m.duration)
+	}
+
+	return m
+}
+
+type Worker struct {
+	queue    *Queue
+	cfg      WorkerConfig
+	handlers map[string]Handler
+	metrics  *workerMetrics
+}
+
+// NewWorker runs jobs from queue. Zero fields of cfg take their
+// DefaultWorkerConfig value. Metrics are registered with registerer when it
+// is not nil.
+func NewWorker(queue *Queue, cfg WorkerConfig, registerer prometheus.Registerer) *Worker {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultWorkerConfig.Concurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultWorkerConfig.PollInterval
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultWorkerConfig.Lease
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = DefaultWorkerConfig.InitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultWorkerConfig.MaxBackoff
+	}
+
+	return &Worker{
+		queue:    queue,
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+		metrics:  newWorkerMetrics(registerer),
+	}
+}
+
+// Handle registers the handler of a job kind. Register every kind before Run;
+// jobs of unknown kinds are dead-lettered.
+func (w *Worker) Handle(kind string, handler Handler) {
+	w.handlers[kind] = handler
+}
+
+// Run claims and runs due jobs until ctx is cancelled, then waits for the
+// running ones to finish.
+func (w *Worker) Run(ctx context.Context) {
+	slots := make(chan struct{}, w.cfg.Concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		free := cap(slots) - len(slots)
+		claimed := 0
+		if free > 0 {
+			jobs, err := w.claim(ctx, free)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("jobs worker: %v", err)
+			}
+			claimed = len(jobs)
+
+			for _, job := range jobs {
+				slots <- struct{}{}
+				running.Add(1)
+				go func() {
+					defer func() {
+						<-slots
+						running.Done()
+					}()
+					w.run(ctx, job)
+				}()
+			}
+		}
+
+		// a full claim means more jobs may be due; look again right away
+		if claimed > 0 && claimed == free {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim marks up to limit due jobs, and running jobs whose lease expired, as
+// running for this worker.
+func (w *Worker) claim(ctx context.Context, limit int) ([]*Job, error) {
+	rows, err := w.queue.pool.Query(ctx, `
+		UPDATE `+w.queue.table+`
+		SET status = 'running', attempt = attempt + 1, locked_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM `+w.queue.table+`
+			WHERE (status = 'pending' AND run_at <= NOW()) OR (status = 'running' AND locked_until < NOW())
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		limit, w.cfg.Lease.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (w *Worker) run(ctx context.Context, job *Job) {
+	handler, ok := w.handlers[job.Kind]
+	if !ok {
+		w.finish(ctx, job, Permanent(fmt.Errorf("no handler for job kind %q", job.Kind)))
+		return
+	}
+
+	// a job whose lease expired on every attempt (e.g. it crashes the
+	// process) ends up here with no attempts left
+	if job.Attempt > job.MaxAttempts {
+		w.finish(ctx, job, Permanent(errors.New("lease expired on the last attempt")))
+		return
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, w.cfg.Lease)
+	defer cancel()
+
+	cancelled := make(chan struct{})
+	stopWatching := w.watch(attemptCtx, job, func() {
+		close(cancelled)
+		cancel()
+	})
+
+	start := time.Now()
+	err := runHandler(attemptCtx, handler, job)
+	w.metrics.duration.WithLabelValues(job.Kind).Observe(time.Since(start).Seconds())
+	stopWatching()
+
+	select {
+	case <-cancelled:
+		// Cancel already recorded the outcome
+		w.metrics.finished.WithLabelValues(job.Kind, "cancelled").Inc()
+	default:
+		w.finish(ctx, job, err)
+	}
+}
+
+// watch calls cancel, at most once, if job stops running before the
+// returned stop function is called, i.e. when it is cancelled through the
+// queue. Stop waits for the watch to end.
+func (w *Worker) watch(ctx context.Context, job *Job, cancel func()) (stop func()) {
+	done := make(chan struct{})
+	var watching sync.WaitGroup
+	watching.Add(1)
+	go func() {
+		defer watching.Done()
+
+		ticker := time.NewTicker(w.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			status, err := w.queue.status(ctx, job.ID)
+			if err != nil {
+				// keep running; the outcome is recorded against the job's
+				// status either way
+				continue
+			}
+			if status != StatusRunning {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		watching.Wait()
+	}
+}
+
+func runHandler(ctx context.Context, handler Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// finish records the outcome of an attempt. The attempt number guards
+// against a worker whose lease expired overwriting a newer attempt.
+func (w *Worker) finish(ctx context.Context, job *Job, err error) {
+	// record the outcome even when shutting down
+	ctx = context.WithoutCancel(ctx)
+
+	var (
+		sql     string
+		args    []any
+		outcome string
+	)
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		outcome = "completed"
+		sql = "SET status = 'completed', locked_until = NULL, last_error = '', finished_at = NOW(), result = $3"
+		args = []any{[]byte(job.Result)}
+	case errors.As(err, &permanent) || job.Attempt >= job.MaxAttempts:
+		outcome = "dead"
+		sql = "SET status = 'dead', locked_until = NULL, last_error = $3, finished_at = NOW()"
+		args = []any{err.Error()}
+		log.Printf("jobs worker: %s job %d dead after %d attempts: %v", job.Kind, job.ID, job.Attempt, err)
+	default:
+		outcome = "retried"
+		sql = "SET status = 'pending', locked_until = NULL, last_error = $3, run_at = NOW() + make_interval(secs => $4)"
+		args = []any{err.Error(), w.backoff(job.Attempt).Seconds()}
+	}
+	w.metrics.finished.WithLabelValues(job.Kind, outcome).Inc()
+
+	_, execErr := w.queue.pool.Exec(ctx,
+		"UPDATE "+w.queue.table+" "+sql+" WHERE id = $1 AND attempt = $2 AND status = 'running'",
+		append([]any{job.ID, job.Attempt}, args...)...,
+	)
+	if execErr != nil {
+		log.Printf("jobs worker: failed to record result of %s job %d: %v", job.Kind, job.ID, execErr)
+	}
+}
+
+// backoff doubles from InitialBackoff per attempt up to MaxBackoff, with up
+// to 10% jitter so failed jobs don't retry in lockstep.
+func (w *Worker) backoff(attempt int32) time.Duration {
+	delay := w.cfg.MaxBackoff
+	if attempt < 32 {
+		if d := w.cfg.InitialBackoff << (attempt - 1); d > 0 && d < w.cfg.MaxBackoff {
+			delay = d
+		}
+	}
+
+	return delay + time.Duration(rand.Int64N(int64(delay/10)+1))
+}
@@ -0,0 +1,132 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const headerMessageID = "message-id"
+
+type KafkaConfig struct {
+	Brokers []string `mapstructure:"brokers"`
+}
+
+type KafkaBroker struct {
+	brokers    []string
+	writer     *kafka.Writer
+	maxDeliver int
+	retryDelay time.Duration
+}
+
+func NewKafkaBroker(cfg KafkaConfig, maxDeliver int, retryDelay time.Duration) (*KafkaBroker, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka: at least one broker is required")
+	}
+
+	return &KafkaBroker{
+		brokers: cfg.Brokers,
+		writer: &kafka.Writer{
+			Addr: kafka.TCP(cfg.Brokers...),
+			// Hash on the key so one aggregate always lands on one partition.
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+		maxDeliver: maxDeliver,
+		retryDelay: retryDelay,
+	}, nil
+}
+
+func (b *KafkaBroker) Publish(ctx context.Context, msg Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+1)
+	headers = append(headers, kafka.Header{Key: headerMessageID, Value: []byte(msg.ID)})
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	err := b.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     []byte(msg.Key),
+		Value:   msg.Payload,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("kafka: failed to publish to %s: %w", msg.Topic, err)
+	}
+
+	return nil
+}
+
+func (b *KafkaBroker) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: b.brokers,
+		GroupID: group,
+		Topic:   topic,
+	})
+	defer reader.Close()
+
+	for {
+		km, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("kafka: failed to fetch from %s: %w", topic, err)
+		}
+
+		msg := Message{
+			Topic:   km.Topic,
+			Key:     string(km.Key),
+			Payload: km.Value,
+			Headers: make(map[string]string, len(km.Headers)),
+		}
+		for _, h := range km.Headers {
+			if h.Key == headerMessageID {
+				msg.ID = string(h.Value)
+				continue
+			}
+			msg.Headers[h.Key] = string(h.Value)
+		}
+
+		// Kafka commits offsets, not individual messages, so a failing message
+		// is retried in place to keep per-partition ordering.
+		if !b.handle(ctx, msg, handler) && ctx.Err() != nil {
+			return nil
+		}
+
+		// The handler has finished; commit even if ctx was cancelled meanwhile.
+		if err := reader.CommitMessages(context.WithoutCancel(ctx), km); err != nil {
+			return fmt.Errorf("kafka: failed to commit offset on %s: %w", topic, err)
+		}
+	}
+}
+
+// handle runs handler until it succeeds or maxDeliver attempts are used up.
+// It reports false when the message was not handled successfully.
+func (b *KafkaBroker) handle(ctx context.Context, msg Message, handler Handler) bool {
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, msg)
+		if err == nil {
+			return true
+		}
+		if attempt >= b.maxDeliver {
+			log.Printf("kafka: dropping message %s on %s after %d attempts: %v", msg.ID, msg.Topic, attempt, err)
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(b.retryDelay):
+		}
+	}
+}
+
+func (b *KafkaBroker) Close() error {
+	return b.writer.Close()
+}
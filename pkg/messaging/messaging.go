@@ -0,0 +1,111 @@
+// Package messaging defines a broker-agnostic event bus with Kafka and NATS
+// JetStream implementations.
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	DriverKafka = "kafka"
+	DriverNATS  = "nats"
+)
+
+// HeaderRegion carries the region a message was published from, so
+// consumers in an active-passive setup can tell the regions' events apart.
+const HeaderRegion = "region"
+
+// Message is a single event on the bus. Messages with the same Key are
+// delivered in publish order (same Kafka partition / same NATS subject).
+type Message struct {
+	ID      string
+	Topic   string
+	Key     string
+	Payload []byte
+	Headers map[string]string
+}
+
+// Handler processes one message. Returning an error schedules a redelivery
+// until the subscription's MaxDeliver is reached.
+type Handler func(ctx context.Context, msg Message) error
+
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+type Subscriber interface {
+	// Subscribe consumes topic as part of group, so each message is handled by
+	// one member of the group. It blocks until ctx is cancelled and returns
+	// after the in-flight message has been handled.
+	Subscribe(ctx context.Context, topic, group string, handler Handler) error
+	Close() error
+}
+
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+type Config struct {
+	Driver string      `mapstructure:"driver"`
+	Kafka  KafkaConfig `mapstructure:"kafka"`
+	NATS   NATSConfig  `mapstructure:"nats"`
+	// MaxDeliver bounds how often a failing message is handed to a handler
+	// before it is logged and skipped.
+	MaxDeliver int `mapstructure:"max_deliver"`
+	// RetryDelay is the wait between deliveries of a failing message.
+	RetryDelay time.Duration `mapstructure:"retry_delay"`
+	// Region, if set, is added as the HeaderRegion of every published
+	// message that does not have one.
+	Region string `mapstructure:"region"`
+}
+
+// NewBroker connects to the broker selected by cfg.Driver.
+func NewBroker(cfg Config) (Broker, error) {
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = 5
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+
+	var (
+		broker Broker
+		err    error
+	)
+	switch cfg.Driver {
+	case DriverKafka:
+		broker, err = NewKafkaBroker(cfg.Kafka, cfg.MaxDeliver, cfg.RetryDelay)
+	case DriverNATS:
+		broker, err = NewNATSBroker(cfg.NATS, cfg.MaxDeliver, cfg.RetryDelay)
+	default:
+		return nil, fmt.Errorf("unknown messaging driver: %q", cfg.Driver)
+	}
+	if err != nil || cfg.Region == "" {
+		return broker, err
+	}
+
+	return &regionBroker{Broker: broker, region: cfg.Region}, nil
+}
+
+// regionBroker tags published messages with the publishing region.
+type regionBroker struct {
+	Broker
+	region string
+}
+
+func (b *regionBroker) Publish(ctx context.Context, msg Message) error {
+	if _, ok := msg.Headers[HeaderRegion]; !ok {
+		headers := make(map[string]string, len(msg.Headers)+1)
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		headers[HeaderRegion] = b.region
+		msg.Headers = headers
+	}
+
+	return b.Broker.Publish(ctx, msg)
+}
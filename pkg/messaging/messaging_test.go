@@ -0,0 +1,102 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingBroker keeps the published messages.
+type recordingBroker struct {
+	Broker
+	published []Message
+}
+
+func (b *recordingBroker) Publish(_ context.Context, msg Message) error {
+	b.published = append(b.published, msg)
+	return nil
+}
+
+func TestRegionBroker(t *testing.T) {
+	inner := &recordingBroker{}
+	broker := &regionBroker{Broker: inner, region: "ap-southeast-1"}
+	ctx := context.Background()
+
+	headers := map[string]string{"trace": "t-1"}
+	if err := broker.Publish(ctx, Message{ID: "m-1", Headers: headers}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := broker.Publish(ctx, Message{ID: "m-2", Headers: map[string]string{HeaderRegion: "us-east-1"}}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if got := inner.published[0].Headers; got[HeaderRegion] != "ap-southeast-1" || got["trace"] != "t-1" {
+		t.Errorf("headers of a message without region = %v, want the broker's region added", got)
+	}
+	if _, ok := headers[HeaderRegion]; ok {
+		t.Error("Publish changed the caller's headers")
+	}
+	if got := inner.published[1].Headers[HeaderRegion]; got != "us-east-1" {
+		t.Errorf("region of a message with one = %q, want it kept", got)
+	}
+}
+
+func TestNewBrokerUnknownDriver(t *testing.T) {
+	if _, err := NewBroker(Config{Driver: "carrier-pigeon"}); err == nil {
+		t.Error("NewBroker of an unknown driver succeeded, want an error")
+	}
+}
+
+func TestKafkaHandleRedelivers(t *testing.T) {
+	broker := &KafkaBroker{maxDeliver: 3, retryDelay: time.Millisecond}
+	failing := errors.New("handler failed")
+
+	tests := []struct {
+		name     string
+		failures int
+		want     bool
+		attempts int
+	}{
+		{name: "handled at once", failures: 0, want: true, attempts: 1},
+		{name: "handled on a redelivery", failures: 2, want: true, attempts: 3},
+		{name: "dropped after maxDeliver", failures: 5, want: false, attempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			handler := func(context.Context, Message) error {
+				attempts++
+				if attempts <= tt.failures {
+					return failing
+				}
+				return nil
+			}
+
+			if got := broker.handle(context.Background(), Message{ID: "m-1"}, handler); got != tt.want {
+				t.Errorf("handle = %v, want %v", got, tt.want)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("handler ran %d times, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+
+	t.Run("stops when ctx is cancelled", func(t *testing.T) {
+		broker := &KafkaBroker{maxDeliver: 3, retryDelay: time.Hour}
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		handler := func(context.Context, Message) error {
+			attempts++
+			cancel()
+			return failing
+		}
+
+		if broker.handle(ctx, Message{ID: "m-1"}, handler) {
+			t.Error("handle = true, want false")
+		}
+		if attempts != 1 {
+			t.Errorf("handler ran %d times after ctx was cancelled, want 1", attempts)
+		}
+	})
+}
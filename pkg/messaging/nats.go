@@ -0,0 +1,148 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type NATSConfig struct {
+	URL string `mapstructure:"url"`
+	// Stream is created (or updated) on connect and must cover every subject
+	// the service publishes or subscribes to, e.g. "user.>".
+	Stream   string   `mapstructure:"stream"`
+	Subjects []string `mapstructure:"subjects"`
+}
+
+type NATSBroker struct {
+	conn       *nats.Conn
+	js         jetstream.JetStream
+	stream     string
+	maxDeliver int
+	retryDelay time.Duration
+}
+
+func NewNATSBroker(cfg NATSConfig, maxDeliver int, retryDelay time.Duration) (*NATSBroker, error) {
+	if cfg.Stream == "" || len(cfg.Subjects) == 0 {
+		return nil, errors.New("nats: stream and subjects are required")
+	}
+
+	conn, err := nats.Connect(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("nats: failed to connect: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: failed to create jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     cfg.Stream,
+		Subjects: cfg.Subjects,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: failed to create stream %s: %w", cfg.Stream, err)
+	}
+
+	return &NATSBroker{
+		conn:       conn,
+		js:         js,
+		stream:     cfg.Stream,
+		maxDeliver: maxDeliver,
+		retryDelay: retryDelay,
+	}, nil
+}
+
+// Publish sends msg to the subject named by msg.Topic. The message ID is used
+// for JetStream de-duplication; Key is only carried as a header since subjects
+// already give per-topic ordering.
+func (b *NATSBroker) Publish(ctx context.Context, msg Message) error {
+	nm := nats.NewMsg(msg.Topic)
+	nm.Data = msg.Payload
+	for k, v := range msg.Headers {
+		nm.Header.Set(k, v)
+	}
+	if msg.Key != "" {
+		nm.Header.Set("key", msg.Key)
+	}
+
+	opts := []jetstream.PublishOpt{}
+	if msg.ID != "" {
+		opts = append(opts, jetstream.WithMsgID(msg.ID))
+	}
+
+	if _, err := b.js.PublishMsg(ctx, nm, opts...); err != nil {
+		return fmt.Errorf("nats: failed to publish to %s: %w", msg.Topic, err)
+	}
+
+	return nil
+}
+
+// Subscribe uses a durable consumer named after group, so group members share
+// the work and resume from the last acknowledged message after a restart.
+func (b *NATSBroker) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, b.stream, jetstream.ConsumerConfig{
+		Durable:       group,
+		FilterSubject: topic,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxDeliver:    b.maxDeliver,
+		BackOff:       []time.Duration{b.retryDelay},
+	})
+	if err != nil {
+		return fmt.Errorf("nats: failed to create consumer %s: %w", group, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(nm jetstream.Msg) {
+		msg := Message{
+			Topic:   nm.Subject(),
+			Key:     nm.Headers().Get("key"),
+			Payload: nm.Data(),
+			Headers: make(map[string]string, len(nm.Headers())),
+		}
+		for k := range nm.Headers() {
+			switch k {
+			case "key":
+			case jetstream.MsgIDHeader:
+				msg.ID = nm.Headers().Get(k)
+			default:
+				msg.Headers[k] = nm.Headers().Get(k)
+			}
+		}
+
+		if err := handler(ctx, msg); err != nil {
+			if meta, metaErr := nm.Metadata(); metaErr == nil && int(meta.NumDelivered) >= b.maxDeliver {
+				log.Printf("nats: dropping message %s on %s after %d attempts: %v", msg.ID, msg.Topic, meta.NumDelivered, err)
+				_ = nm.Term()
+				return
+			}
+			_ = nm.NakWithDelay(b.retryDelay)
+			return
+		}
+		_ = nm.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("nats: failed to consume %s: %w", topic, err)
+	}
+
+	<-ctx.Done()
+	// Drain lets the in-flight handler finish before the subscription closes.
+	consumeCtx.Drain()
+	<-consumeCtx.Closed()
+
+	return nil
+}
+
+func (b *NATSBroker) Close() error {
+	return b.conn.Drain()
+}
@@ -0,0 +1,79 @@
+package messaging_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/internal/testutil"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+)
+
+func TestNATSBrokerRedelivery(t *testing.T) {
+	broker, err := messaging.NewNATSBroker(messaging.NATSConfig{
+		URL:      testutil.StartNATS(t),
+		Stream:   "TEST",
+		Subjects: []string{"user.>"},
+	}, 3, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewNATSBroker: %v", err)
+	}
+	t.Cleanup(func() { _ = broker.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var (
+		mu         sync.Mutex
+		deliveries []messaging.Message
+		handled    = make(chan struct{})
+	)
+	handler := func(_ context.Context, msg messaging.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries = append(deliveries, msg)
+		if len(deliveries) == 1 {
+			return errors.New("first delivery fails")
+		}
+		close(handled)
+		return nil
+	}
+	subscribed := make(chan error, 1)
+	go func() { subscribed <- broker.Subscribe(ctx, "user.created", "test-group", handler) }()
+
+	msg := messaging.Message{
+		ID:      "m-1",
+		Topic:   "user.created",
+		Key:     "user-1",
+		Payload: []byte(`{"id":"user-1"}`),
+		Headers: map[string]string{"trace": "t-1"},
+	}
+	if err := broker.Publish(ctx, msg); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case <-handled:
+	case <-ctx.Done():
+		t.Fatal("the failed message was not redelivered")
+	}
+	// an acknowledged message is not delivered again
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	if err := <-subscribed; err != nil {
+		t.Errorf("Subscribe: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deliveries) != 2 {
+		t.Fatalf("message delivered %d times, want 2", len(deliveries))
+	}
+	for _, got := range deliveries {
+		if got.ID != msg.ID || got.Key != msg.Key || string(got.Payload) != string(msg.Payload) || got.Headers["trace"] != "t-1" {
+			t.Errorf("delivered %+v, want %+v", got, msg)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package metrics exports connection pool, client and query statistics of a
+// service's infrastructure to Prometheus, so pool exhaustion and slow
+// dependencies show up on dashboards before they cause outages. Every
+// metric carries a service label.
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type pgxPoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquires          *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	emptyAcquireWait  *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+	newConns          *prometheus.Desc
+}
+
+// RegisterPgxPool exports pool's statistics, read on every scrape.
+func RegisterPgxPool(registerer prometheus.Registerer, service string, pool *pgxpool.Pool) {
+	labels := prometheus.Labels{"service": service}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("pgxpool_"+name, help, nil, labels)
+	}
+
+	registerer.MustRegister(&pgxPoolCollector{
+		pool:              pool,
+		acquiredConns:     desc("acquired_conns", "Connections currently checked out of the pool."),
+		idleConns:         desc("idle_conns", "Idle connections in the pool."),
+		constructingConns: desc("constructing_conns", "Connections being opened."),
+		totalConns:        desc("total_conns", "Open connections in the pool."),
+		maxConns:          desc("max_conns", "Maximum size of the pool."),
+		acquires:          desc("acquires_total", "Successful connection acquires."),
+		acquireDuration:   desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		emptyAcquires:     desc("empty_acquires_total", "Acquires that had to wait because no connection was idle."),
+		emptyAcquireWait:  desc("empty_acquire_wait_seconds_total", "Total time acquires waited for a connection to free up."),
+		canceledAcquires:  desc("canceled_acquires_total", "Acquires cancelled by their context while waiting."),
+		newConns:          desc("new_conns_total", "Connections opened."),
+	})
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	gauge := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v)
+	}
+	counter := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquiredConns, float64(stat.AcquiredConns()))
+	gauge(c.idleConns, float64(stat.IdleConns()))
+	gauge(c.constructingConns, float64(stat.ConstructingConns()))
+	gauge(c.totalConns, float64(stat.TotalConns()))
+	gauge(c.maxConns, float64(stat.MaxConns()))
+	counter(c.acquires, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.emptyAcquires, float64(stat.EmptyAcquireCount()))
+	counter(c.emptyAcquireWait, stat.EmptyAcquireWaitTime().Seconds())
+	counter(c.canceledAcquires, float64(stat.CanceledAcquireCount()))
+	counter(c.newConns, float64(stat.NewConnsCount()))
+}
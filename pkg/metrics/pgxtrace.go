@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxLoggedSQL bounds the statement text in slow-query logs.
+const maxLoggedSQL = 1000
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+// QueryTracer is a pgx.QueryTracer recording the latency and errors of every
+// query by name, and logging queries slower than a threshold.
+type QueryTracer struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewQueryTracer builds a tracer for pgx.ConnConfig.Tracer. Queries are named
+// by their sqlc "-- name:" comment, or else by their first keyword (e.g.
+// "begin"). Queries taking at least slowThreshold are logged at warn level
+// with their parameters redacted; zero disables the log.
+func NewQueryTracer(registerer prometheus.Registerer, service string, logger *slog.Logger, slowThreshold time.Duration) *QueryTracer {
+	labels := prometheus.Labels{"service": service}
+
+	t := &QueryTracer{
+		logger:        logger,
+		slowThreshold: slowThreshold,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "pgx_query_duration_seconds",
+			Help:        "Duration of Postgres queries by query name.",
+			ConstLabels: labels,
+			Buckets:     []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"query"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "pgx_query_errors_total",
+			Help:        "Failed Postgres queries by query name.",
+			ConstLabels: labels,
+		}, []string{"query"}),
+	}
+	registerer.MustRegister(t.duration, t.errors)
+
+	return t
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+
+	name := queryName(start.sql)
+	t.duration.WithLabelValues(name).Observe(elapsed.Seconds())
+	if data.Err != nil {
+		t.errors.WithLabelValues(name).Inc()
+	}
+
+	if t.slowThreshold > 0 && elapsed >= t.slowThreshold {
+		attrs := []any{
+			"query", name,
+			"duration", elapsed,
+			"sql", compactSQL(start.sql),
+			"args", redactedArgs(start.args),
+		}
+		if data.Err != nil {
+			attrs = append(attrs, "error", data.Err.Error())
+		}
+		t.logger.WarnContext(ctx, "slow query", attrs...)
+	}
+}
+
+// queryName returns the sqlc name of sql, or its lower-cased first keyword.
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, "-- name:"); ok {
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+
+	fields := strings.Fields(stripComments(sql))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return strings.ToLower(fields[0])
+}
+
+// compactSQL drops comments and collapses whitespace so a statement fits on
+// one log line.
+func compactSQL(sql string) string {
+	compact := strings.Join(strings.Fields(stripComments(sql)), " ")
+	if len(compact) > maxLoggedSQL {
+		compact = compact[:maxLoggedSQL] + "..."
+	}
+	return compact
+}
+
+func stripComments(sql string) string {
+	lines := strings.Split(sql, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// redactedArgs describes each query parameter by its Go type only, as
+// parameters may hold emails, password hashes or tokens.
+func redactedArgs(args []any) []string {
+	ret := make([]string, len(args))
+	for i, arg := range args {
+		ret[i] = fmt.Sprintf("$%d=%T", i+1, arg)
+	}
+	return ret
+}
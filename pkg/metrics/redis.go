@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// cacheReadCommands answer redis.Nil on a missing key, which counts as a
+// cache miss.
+var cacheReadCommands = map[string]bool{
+	"get":    true,
+	"getex":  true,
+	"getdel": true,
+	"hget":   true,
+}
+
+type redisHook struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	cache    *prometheus.CounterVec
+}
+
+// InstrumentRedis records the latency and errors of client's commands, cache
+// hits and misses of reads, and its connection pool statistics.
+func InstrumentRedis(registerer prometheus.Registerer, service string, client *redis.Client) {
+	labels := prometheus.Labels{"service": service}
+
+	hook := &redisHook{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "redis_command_duration_seconds",
+			Help:        "Duration of Redis commands by command; pipelines are timed as a whole.",
+			ConstLabels: labels,
+			Buckets:     []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "redis_command_errors_total",
+			Help:        "Failed Redis commands by command, not counting missing keys.",
+			ConstLabels: labels,
+		}, []string{"command"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "redis_cache_requests_total",
+			Help:        "Redis reads by result (hit, miss).",
+			ConstLabels: labels,
+		}, []string{"result"}),
+	}
+	registerer.MustRegister(hook.duration, hook.errors, hook.cache, newRedisPoolCollector(client, labels))
+
+	client.AddHook(hook)
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.duration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+		h.record(cmd, err)
+
+		return err
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.duration.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+		for _, cmd := range cmds {
+			cmdErr := cmd.Err()
+			if cmdErr == nil {
+				cmdErr = err
+			}
+			h.record(cmd, cmdErr)
+		}
+
+		return err
+	}
+}
+
+// record takes the error from the hook chain, since cmd.Err() is not set yet
+// when a command fails before reaching Redis.
+func (h *redisHook) record(cmd redis.Cmder, err error) {
+	if cacheReadCommands[cmd.Name()] {
+		switch {
+		case err == nil:
+			h.cache.WithLabelValues("hit").Inc()
+		case errors.Is(err, redis.Nil):
+			h.cache.WithLabelValues("miss").Inc()
+		}
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		h.errors.WithLabelValues(cmd.Name()).Inc()
+	}
+}
+
+type redisPoolCollector struct {
+	client *redis.Client
+
+	totalConns   *prometheus.Desc
+	idleConns    *prometheus.Desc
+	waits        *prometheus.Desc
+	waitDuration *prometheus.Desc
+	timeouts     *prometheus.Desc
+	staleConns   *prometheus.Desc
+}
+
+func newRedisPoolCollector(client *redis.Client, labels prometheus.Labels) *redisPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("redis_pool_"+name, help, nil, labels)
+	}
+
+	return &redisPoolCollector{
+		client:       client,
+		totalConns:   desc("total_conns", "Open connections in the pool."),
+		idleConns:    desc("idle_conns", "Idle connections in the pool."),
+		waits:        desc("waits_total", "Commands that waited for a free connection."),
+		waitDuration: desc("wait_duration_seconds_total", "Total time commands waited for a free connection."),
+		timeouts:     desc("timeouts_total", "Waits for a free connection that timed out."),
+		staleConns:   desc("stale_conns_total", "Stale connections removed from the pool."),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.waits, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, time.Duration(stats.WaitDurationNs).Seconds())
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
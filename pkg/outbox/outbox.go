@@ -0,0 +1,79 @@
+// Package outbox implements the transactional outbox pattern: services write
+// events into an outbox table in the same transaction as their state change,
+// and a Relay publishes them to the message broker afterwards.
+//
+// The table is owned by the embedding service's migrations:
+//
+//	CREATE TABLE outbox (
+//	  id BIGSERIAL PRIMARY KEY,
+//	  message_id TEXT NOT NULL,
+//	  topic TEXT NOT NULL,
+//	  aggregate_key TEXT NOT NULL DEFAULT '',
+//	  payload BYTEA NOT NULL,
+//	  headers JSONB NOT NULL DEFAULT '{}',
+//	  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//	  sent_at TIMESTAMPTZ
+//	);
+//	CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+)
+
+// DBTX is satisfied by pgx.Tx, so Enqueue joins the caller's transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type Outbox struct {
+	name          string
+	table         string
+	notifyChannel string
+}
+
+// New returns an outbox stored in table. When notifyChannel is set, Enqueue
+// also issues pg_notify on it so a listening Relay publishes without waiting
+// for its next poll.
+func New(table, notifyChannel string) *Outbox {
+	return &Outbox{
+		name:          table,
+		table:         pgx.Identifier{table}.Sanitize(),
+		notifyChannel: notifyChannel,
+	}
+}
+
+// Enqueue stores msg for publishing. msg.Key is the aggregate key: messages
+// sharing a key are published in the order they were enqueued.
+func (o *Outbox) Enqueue(ctx context.Context, tx DBTX, msg messaging.Message) error {
+	headers := msg.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox headers: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO "+o.table+" (message_id, topic, aggregate_key, payload, headers) VALUES ($1, $2, $3, $4, $5)",
+		msg.ID, msg.Topic, msg.Key, msg.Payload, headersJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox message: %w", err)
+	}
+
+	if o.notifyChannel != "" {
+		if _, err := tx.Exec(ctx, "SELECT pg_notify($1, '')", o.notifyChannel); err != nil {
+			return fmt.Errorf("failed to notify outbox relay: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type relayMetrics struct {
+	pending   prometheus.Gauge
+	lag       prometheus.Gauge
+	published prometheus.Counter
+	failed    prometheus.Counter
+}
+
+func newRelayMetrics(registerer prometheus.Registerer, table string) *relayMetrics {
+	labels := prometheus.Labels{"table": table}
+	m := &relayMetrics{
+		pending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "outbox_pending_messages",
+			Help:        "Number of outbox messages not yet published.",
+			ConstLabels: labels,
+		}),
+		lag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "outbox_lag_seconds",
+			Help:        "Age of the oldest unpublished outbox message.",
+			ConstLabels: labels,
+		}),
+		published: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "outbox_published_total",
+			Help:        "Outbox messages published to the broker.",
+			ConstLabels: labels,
+		}),
+		failed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "outbox_publish_failures_total",
+			Help:        "Failed attempts to publish outbox messages.",
+			ConstLabels: labels,
+		}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.pending, m.lag, m.published, m.failed)
+	}
+
+	return m
+}
+
+type Relay struct {
+	outbox       *Outbox
+	pool         *pgxpool.Pool
+	publisher    messaging.Publisher
+	batchSize    int
+	pollInterval time.Duration
+	metrics      *relayMetrics
+}
+
+// NewRelay publishes messages from outbox using publisher. Metrics are
+// registered on registerer; pass nil to skip registration.
+func NewRelay(
+	outbox *Outbox,
+	pool *pgxpool.Pool,
+	publisher messaging.Publisher,
+	batchSize int,
+	pollInterval time.Duration,
+	registerer prometheus.Registerer,
+) *Relay {
+	return &Relay{
+		outbox:       outbox,
+		pool:         pool,
+		publisher:    publisher,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		metrics:      newRelayMetrics(registerer, outbox.name),
+	}
+}
+
+// Run relays until ctx is cancelled. Only one relay per table publishes at a
+// time (guarded by an advisory lock), which keeps per-key ordering when
+// several replicas run the relay.
+func (r *Relay) Run(ctx context.Context) {
+	wake := make(chan struct{}, 1)
+	if r.outbox.notifyChannel != "" {
+		go r.listen(ctx, wake)
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.relayBatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("outbox relay: %v", err)
+			}
+			// A fully sent batch means there is probably more waiting. A
+			// batch that failed to send waits for the next poll, so a broker
+			// outage is not retried in a tight loop.
+			if err != nil || n < r.batchSize {
+				break
+			}
+		}
+		r.updateLag(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+type outboxRow struct {
+	id        int64
+	messageID string
+	topic     string
+	key       string
+	payload   []byte
+	headers   []byte
+}
+
+// relayBatch publishes up to batchSize pending messages and reports how many
+// it published.
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", r.outbox.name).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to take relay lock: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.Query(ctx,
+		"SELECT id, message_id, topic, aggregate_key, payload, headers FROM "+r.outbox.table+
+			" WHERE sent_at IS NULL ORDER BY id LIMIT $1",
+		r.batchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	batch, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (outboxRow, error) {
+		var o outboxRow
+		err := row.Scan(&o.id, &o.messageID, &o.topic, &o.key, &o.payload, &o.headers)
+		return o, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	// Once a message fails, later messages with the same key wait for the
+	// next batch so they are never published ahead of it.
+	blocked := make(map[string]bool)
+	sent := make([]int64, 0, len(batch))
+	for _, row := range batch {
+		if blocked[row.key] {
+			continue
+		}
+
+		msg := messaging.Message{
+			ID:      row.messageID,
+			Topic:   row.topic,
+			Key:     row.key,
+			Payload: row.payload,
+		}
+		if err := json.Unmarshal(row.headers, &msg.Headers); err != nil {
+			log.Printf("outbox relay: invalid headers on message %s: %v", row.messageID, err)
+		}
+
+		if err := r.publisher.Publish(ctx, msg); err != nil {
+			r.metrics.failed.Inc()
+			blocked[row.key] = true
+			log.Printf("outbox relay: failed to publish message %s: %v", row.messageID, err)
+			continue
+		}
+		sent = append(sent, row.id)
+	}
+
+	if len(sent) > 0 {
+		_, err = tx.Exec(ctx, "UPDATE "+r.outbox.table+" SET sent_at = NOW() WHERE id = ANY($1)", sent)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark outbox messages sent: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+	r.metrics.published.Add(float64(len(sent)))
+
+	return len(sent), nil
+}
+
+func (r *Relay) updateLag(ctx context.Context) {
+	var pending int64
+	var lagSeconds float64
+	err := r.pool.QueryRow(ctx,
+		"SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)::float8 FROM "+
+			r.outbox.table+" WHERE sent_at IS NULL",
+	).Scan(&pending, &lagSeconds)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("outbox relay: failed to measure lag: %v", err)
+		}
+		return
+	}
+
+	r.metrics.pending.Set(float64(pending))
+	r.metrics.lag.Set(lagSeconds)
+}
+
+// listen wakes the relay on every notification. It reconnects after errors
+// so a database restart only degrades the relay to polling for a while.
+func (r *Relay) listen(ctx context.Context, wake chan<- struct{}) {
+	for ctx.Err() == nil {
+		if err := r.waitForNotifications(ctx, wake); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: listen failed: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(r.pollInterval):
+			}
+		}
+	}
+}
+
+func (r *Relay) waitForNotifications(ctx context.Context, wake chan<- struct{}) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{r.outbox.notifyChannel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			// Drop the connection rather than return it to the pool while
+			// it is still subscribed to the channel.
+			conn.Conn().Close(context.Background())
+			return err
+		}
+
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
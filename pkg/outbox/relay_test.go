@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/internal/testutil"
+	"github.com/phongloihong/go-shop/pkg/messaging"
+)
+
+const testSchema = `
+CREATE TABLE outbox (
+  id BIGSERIAL PRIMARY KEY,
+  message_id TEXT NOT NULL,
+  topic TEXT NOT NULL,
+  aggregate_key TEXT NOT NULL DEFAULT '',
+  payload BYTEA NOT NULL,
+  headers JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  sent_at TIMESTAMPTZ
+);
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;`
+
+var errBrokerDown = errors.New("broker down")
+
+// fakePublisher records published message IDs and fails messages of the
+// keys in down.
+type fakePublisher struct {
+	mu        sync.Mutex
+	down      map[string]bool
+	published []string
+	attempts  int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg messaging.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	if p.down[msg.Key] || p.down["*"] {
+		return errBrokerDown
+	}
+	p.published = append(p.published, msg.ID)
+
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+func (p *fakePublisher) setDown(keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = map[string]bool{}
+	for _, key := range keys {
+		p.down[key] = true
+	}
+}
+
+func (p *fakePublisher) sent() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.published...)
+}
+
+func newTestRelay(t *testing.T, batchSize int) (*Relay, *pgxpool.Pool, *fakePublisher) {
+	t.Helper()
+
+	pool := testutil.StartPostgres(t, testSchema)
+	publisher := &fakePublisher{}
+	relay := NewRelay(New("outbox", ""), pool, publisher, batchSize, time.Hour, nil)
+
+	return relay, pool, publisher
+}
+
+// enqueue stores messages with the given IDs and keys, in order.
+func enqueue(t *testing.T, relay *Relay, pool *pgxpool.Pool, idKeys ...string) {
+	t.Helper()
+
+	for i := 0; i < len(idKeys); i += 2 {
+		msg := messaging.Message{ID: idKeys[i], Topic: "users", Key: idKeys[i+1], Payload: []byte("{}")}
+		if err := relay.outbox.Enqueue(context.Background(), pool, msg); err != nil {
+			t.Fatalf("Enqueue %s: %v", msg.ID, err)
+		}
+	}
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	relay, pool, publisher := newTestRelay(t, 10)
+	enqueue(t, relay, pool, "a1", "a", "b1", "b", "a2", "a", "b2", "b")
+
+	n, err := relay.relayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("relayBatch: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("relayBatch sent %d messages, want 4", n)
+	}
+	if got, want := publisher.sent(), []string{"a1", "b1", "a2", "b2"}; !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+
+	// sent messages are not published again
+	if n, err := relay.relayBatch(context.Background()); err != nil || n != 0 {
+		t.Errorf("second relayBatch = %d, %v, want 0 sent", n, err)
+	}
+}
+
+func TestRelayBrokerFailure(t *testing.T) {
+	relay, pool, publisher := newTestRelay(t, 10)
+	enqueue(t, relay, pool, "a1", "a", "b1", "b", "a2", "a")
+	publisher.setDown("a")
+
+	n, err := relay.relayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("relayBatch: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("relayBatch sent %d messages, want 1", n)
+	}
+	// a2 waits behind the failed a1 while b goes on
+	if got, want := publisher.sent(), []string{"b1"}; !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+
+	t.Run("recovers in order", func(t *testing.T) {
+		publisher.setDown()
+
+		n, err := relay.relayBatch(context.Background())
+		if err != nil {
+			t.Fatalf("relayBatch: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("relayBatch sent %d messages, want 2", n)
+		}
+		if got, want := publisher.sent(), []string{"b1", "a1", "a2"}; !slices.Equal(got, want) {
+			t.Errorf("published %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRelayRunWaitsOutBrokerOutage(t *testing.T) {
+	const batchSize = 3
+	relay, pool, publisher := newTestRelay(t, batchSize)
+	enqueue(t, relay, pool, "a1", "a", "b1", "b", "c1", "c", "d1", "d")
+	publisher.setDown("*")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	relay.Run(ctx)
+
+	// one failed batch, then nothing until the next poll an hour away
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	if publisher.attempts != batchSize {
+		t.Errorf("relay made %d publish attempts while the broker was down, want %d", publisher.attempts, batchSize)
+	}
+}
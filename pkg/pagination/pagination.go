@@ -0,0 +1,124 @@
+// Package pagination implements the page_size, page_token and
+// next_page_token convention of go-shop list methods. A page token is the
+// keyset of the last item of the previous page, JSON encoded and base64url'd,
+// so the next page continues after that item even when rows were inserted or
+// deleted in between. Clients must treat tokens as opaque.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+const (
+	DefaultPageSize int32 = 50
+	MaxPageSize     int32 = 100
+)
+
+// ListResponse is one page of a list method's result. NextPageToken is empty
+// on the last page.
+type ListResponse[T any] struct {
+	Items         []T
+	NextPageToken string
+}
+
+// PageSize clamps a requested page size to [1, max], using def for zero or
+// negative requests.
+func PageSize(requested, def, max int32) int32 {
+	if requested <= 0 {
+		return min(def, max)
+	}
+
+	return min(requested, max)
+}
+
+// EncodeCursor returns the page token continuing after an item with the
+// given keyset values. The values must marshal to JSON.
+func EncodeCursor(keys ...any) (string, error) {
+	raw, err := json.Marshal(keys)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor reads the keyset values of token into keys, pointers in the
+// order EncodeCursor was given the values. An empty token leaves keys
+// unchanged, so their zero values should select the first page. A token that
+// was not made by EncodeCursor with the same number of keys fails with
+// reason INVALID_PAGE_TOKEN.
+func DecodeCursor(token string, keys ...any) error {
+	if token == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return invalidToken()
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || len(values) != len(keys) {
+		return invalidToken()
+	}
+	for i, value := range values {
+		if err := json.Unmarshal(value, keys[i]); err != nil {
+			return invalidToken()
+		}
+	}
+
+	return nil
+}
+
+// NewListResponse builds a page from items fetched with a limit of
+// pageSize+1: the extra item only shows that another page exists and is
+// dropped. pageSize must be positive, as returned by PageSize. keys returns
+// the keyset values of an item, in the order the list is sorted by.
+func NewListResponse[T any](items []T, pageSize int32, keys func(T) []any) (ListResponse[T], error) {
+	if int32(len(items)) <= pageSize {
+		return ListResponse[T]{Items: items}, nil
+	}
+
+	items = items[:pageSize]
+	token, err := EncodeCursor(keys(items[len(items)-1])...)
+	if err != nil {
+		return ListResponse[T]{}, err
+	}
+
+	return ListResponse[T]{Items: items, NextPageToken: token}, nil
+}
+
+// Paginate pages through a list held in memory that is always built in the
+// same order, using the offset as keyset. pageSize must be positive.
+func Paginate[T any](items []T, pageSize int32, pageToken string) (ListResponse[T], error) {
+	var offset int
+	if err := DecodeCursor(pageToken, &offset); err != nil {
+		return ListResponse[T]{}, err
+	}
+	if offset < 0 {
+		return ListResponse[T]{}, invalidToken()
+	}
+	if offset >= len(items) {
+		return ListResponse[T]{Items: []T{}}, nil
+	}
+
+	end := min(offset+int(pageSize), len(items))
+	ret := ListResponse[T]{Items: items[offset:end]}
+	if end < len(items) {
+		token, err := EncodeCursor(end)
+		if err != nil {
+			return ListResponse[T]{}, err
+		}
+		ret.NextPageToken = token
+	}
+
+	return ret, nil
+}
+
+func invalidToken() error {
+	return domain_error.New(domain_error.ReasonInvalidPageToken, domain_error.WithFieldViolation("page_token", "not a token returned by this list method"))
+}
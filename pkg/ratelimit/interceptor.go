@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+// KeyFunc picks the bucket key and quota for a request; ok=false exempts it.
+type KeyFunc func(ctx context.Context, req connect.AnyRequest) (key string, quota Quota, ok bool)
+
+// NewInterceptor rejects requests over quota with CodeResourceExhausted and
+// Retry-After metadata (whole seconds) plus a RetryInfo detail, which the shared client's retry
+// policy honours. If the limiter itself fails the request is let through, so
+// a Redis outage degrades to no rate limiting rather than no service.
+func NewInterceptor(limiter Limiter, keyFunc KeyFunc) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			key, quota, ok := keyFunc(ctx, req)
+			if !ok {
+				return next(ctx, req)
+			}
+
+			result, err := limiter.Allow(ctx, key, quota)
+			if err != nil {
+				log.Printf("rate limiter unavailable, allowing request: %v", err)
+				return next(ctx, req)
+			}
+			if !result.Allowed {
+				connectErr := domain_error.MapError(domain_error.New(
+					domain_error.ReasonRateLimited,
+					domain_error.WithMessage("rate limit exceeded"),
+					domain_error.WithRetryAfter(result.RetryAfter),
+				))
+				connectErr.Meta().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+				return nil, connectErr
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const pingProcedure = "/test.v1.TestService/Ping"
+
+// fakeLimiter answers every call with result and err, recording the keys and
+// quotas it was asked about.
+type fakeLimiter struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+	quotas []ratelimit.Quota
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, key string, quota ratelimit.Quota) (ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	l.quotas = append(l.quotas, quota)
+	return l.result, l.err
+}
+
+// startPing serves a ping procedure behind the rate limit interceptor and
+// returns a client of it and a count of the calls that reached the handler.
+func startPing(t *testing.T, limiter ratelimit.Limiter, keyFunc ratelimit.KeyFunc) (*connect.Client[emptypb.Empty, emptypb.Empty], *int) {
+	t.Helper()
+
+	handled := new(int)
+	mux := http.NewServeMux()
+	mux.Handle(pingProcedure, connect.NewUnaryHandler(pingProcedure,
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			*handled++
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(ratelimit.NewInterceptor(limiter, keyFunc)),
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+pingProcedure), handled
+}
+
+func keyByProcedure(quota ratelimit.Quota) ratelimit.KeyFunc {
+	return func(_ context.Context, req connect.AnyRequest) (string, ratelimit.Quota, bool) {
+		return req.Spec().Procedure, quota, true
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	ctx := context.Background()
+	quota := ratelimit.Quota{Limit: 5, Window: time.Minute}
+
+	t.Run("lets requests under quota through", func(t *testing.T) {
+		limiter := &fakeLimiter{result: ratelimit.Result{Allowed: true, Remaining: 4}}
+		client, handled := startPing(t, limiter, keyByProcedure(quota))
+
+		if _, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+		if *handled != 1 {
+			t.Errorf("handler ran %d times, want 1", *handled)
+		}
+		if len(limiter.keys) != 1 || limiter.keys[0] != pingProcedure || limiter.quotas[0] != quota {
+			t.Errorf("limiter asked about %v %v, want %s with %+v", limiter.keys, limiter.quotas, pingProcedure, quota)
+		}
+	})
+
+	t.Run("rejects requests over quota with a retry delay", func(t *testing.T) {
+		limiter := &fakeLimiter{result: ratelimit.Result{RetryAfter: 1500 * time.Millisecond}}
+		client, handled := startPing(t, limiter, keyByProcedure(quota))
+
+		_, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{}))
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeResourceExhausted {
+			t.Fatalf("Ping = %v, want %v", err, connect.CodeResourceExhausted)
+		}
+		if *handled != 0 {
+			t.Errorf("handler ran %d times, want 0", *handled)
+		}
+		if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonRateLimited {
+			t.Errorf("reason = %q, want %q", reason, domain_error.ReasonRateLimited)
+		}
+		// whole seconds, rounded up so a client waiting for it is not early
+		if got := connectErr.Meta().Get("Retry-After"); got != "2" {
+			t.Errorf("Retry-After = %q, want %q", got, "2")
+		}
+
+		var retryDelay time.Duration
+		for _, detail := range connectErr.Details() {
+			if msg, err := detail.Value(); err == nil {
+				if info, ok := msg.(*errdetails.RetryInfo); ok {
+					retryDelay = info.GetRetryDelay().AsDuration()
+				}
+			}
+		}
+		if retryDelay != 1500*time.Millisecond {
+			t.Errorf("RetryInfo delay = %v, want %v", retryDelay, 1500*time.Millisecond)
+		}
+	})
+
+	t.Run("lets requests through when the limiter fails", func(t *testing.T) {
+		limiter := &fakeLimiter{err: errors.New("redis: connection refused")}
+		client, handled := startPing(t, limiter, keyByProcedure(quota))
+
+		if _, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+		if *handled != 1 {
+			t.Errorf("handler ran %d times, want 1", *handled)
+		}
+	})
+
+	t.Run("skips requests the key func exempts", func(t *testing.T) {
+		limiter := &fakeLimiter{}
+		client, handled := startPing(t, limiter, func(context.Context, connect.AnyRequest) (string, ratelimit.Quota, bool) {
+			return "", ratelimit.Quota{}, false
+		})
+
+		if _, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+		if *handled != 1 || len(limiter.keys) != 0 {
+			t.Errorf("handler ran %d times and limiter was asked %d times, want 1 and 0", *handled, len(limiter.keys))
+		}
+	})
+}
@@ -0,0 +1,27 @@
+// Package ratelimit provides Redis-backed rate limiters shared by every
+// replica of a service, plus a Connect interceptor to enforce them.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Quota allows Limit requests per Window. For the token bucket, Burst is the
+// bucket size (defaults to Limit); the sliding window ignores it.
+type Quota struct {
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
+	Burst  int           `mapstructure:"burst"`
+}
+
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next request would be allowed.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, quota Quota) (Result, error)
+}
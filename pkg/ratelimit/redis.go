@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Both scripts use the Redis clock so replicas with skewed clocks agree.
+
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2]) -- tokens per millisecond
+local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + (now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return {allowed, math.floor(tokens), retry}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[3])
+  redis.call('PEXPIRE', KEYS[1], window)
+  return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, 0, tonumber(oldest[2]) + window - now}
+`)
+
+// TokenBucket refills Limit tokens per Window up to Burst, allowing short
+// bursts above the average rate.
+type TokenBucket struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewTokenBucket(client redis.Scripter, prefix string) *TokenBucket {
+	return &TokenBucket{client: client, prefix: prefix}
+}
+
+func (l *TokenBucket) Allow(ctx context.Context, key string, quota Quota) (Result, error) {
+	if err := validate(quota); err != nil {
+		return Result{}, err
+	}
+
+	capacity := quota.Burst
+	if capacity <= 0 {
+		capacity = quota.Limit
+	}
+	rate := float64(quota.Limit) / float64(quota.Window.Milliseconds())
+
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{l.prefix + key}, capacity, rate).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: token bucket for %s: %w", key, err)
+	}
+
+	return toResult(values), nil
+}
+
+// SlidingWindow allows at most Limit requests in any Window-long interval.
+type SlidingWindow struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewSlidingWindow(client redis.Scripter, prefix string) *SlidingWindow {
+	return &SlidingWindow{client: client, prefix: prefix}
+}
+
+func (l *SlidingWindow) Allow(ctx context.Context, key string, quota Quota) (Result, error) {
+	if err := validate(quota); err != nil {
+		return Result{}, err
+	}
+
+	member := make([]byte, 8)
+	_, _ = rand.Read(member)
+
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{l.prefix + key},
+		quota.Window.Milliseconds(), quota.Limit, hex.EncodeToString(member),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: sliding window for %s: %w", key, err)
+	}
+
+	return toResult(values), nil
+}
+
+func validate(quota Quota) error {
+	if quota.Limit <= 0 || quota.Window < time.Millisecond {
+		return errors.New("ratelimit: quota needs a positive limit and a window of at least 1ms")
+	}
+	return nil
+}
+
+func toResult(values []int64) Result {
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}
+}
@@ -0,0 +1,126 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/internal/testutil"
+	"github.com/phongloihong/go-shop/pkg/ratelimit"
+)
+
+func allowN(t *testing.T, limiter ratelimit.Limiter, key string, quota ratelimit.Quota, n int) []ratelimit.Result {
+	t.Helper()
+
+	results := make([]ratelimit.Result, n)
+	for i := range results {
+		result, err := limiter.Allow(context.Background(), key, quota)
+		if err != nil {
+			t.Fatalf("Allow #%d: %v", i+1, err)
+		}
+		results[i] = result
+	}
+
+	return results
+}
+
+func TestTokenBucket(t *testing.T) {
+	client := testutil.StartRedis(t)
+	limiter := ratelimit.NewTokenBucket(client, "test:tb:")
+
+	t.Run("allows a burst, then waits for a token", func(t *testing.T) {
+		quota := ratelimit.Quota{Limit: 1, Window: time.Hour, Burst: 3}
+
+		results := allowN(t, limiter, "burst", quota, 4)
+		for i, want := range []int{2, 1, 0} {
+			if !results[i].Allowed || results[i].Remaining != want {
+				t.Errorf("request %d = %+v, want allowed with %d remaining", i+1, results[i], want)
+			}
+		}
+		if denied := results[3]; denied.Allowed || denied.RetryAfter <= 59*time.Minute || denied.RetryAfter > time.Hour {
+			t.Errorf("request over the burst = %+v, want denied for about an hour", denied)
+		}
+	})
+
+	t.Run("burst defaults to the limit", func(t *testing.T) {
+		quota := ratelimit.Quota{Limit: 2, Window: time.Hour}
+
+		results := allowN(t, limiter, "default-burst", quota, 3)
+		if !results[0].Allowed || !results[1].Allowed || results[2].Allowed {
+			t.Errorf("results = %+v, want two allowed and the third denied", results)
+		}
+	})
+
+	t.Run("refills at the limit rate", func(t *testing.T) {
+		// a token every 100ms
+		quota := ratelimit.Quota{Limit: 10, Window: time.Second}
+
+		allowN(t, limiter, "refill", quota, 10)
+		denied := allowN(t, limiter, "refill", quota, 1)[0]
+		if denied.Allowed || denied.RetryAfter <= 0 || denied.RetryAfter > 100*time.Millisecond {
+			t.Fatalf("request of an empty bucket = %+v, want denied for at most 100ms", denied)
+		}
+
+		time.Sleep(150 * time.Millisecond)
+		if result := allowN(t, limiter, "refill", quota, 1)[0]; !result.Allowed {
+			t.Errorf("request after a refill = %+v, want allowed", result)
+		}
+	})
+
+	t.Run("keys have separate buckets under the prefix", func(t *testing.T) {
+		quota := ratelimit.Quota{Limit: 1, Window: time.Hour}
+
+		allowN(t, limiter, "user:a", quota, 1)
+		if result := allowN(t, limiter, "user:b", quota, 1)[0]; !result.Allowed {
+			t.Errorf("first request of another key = %+v, want allowed", result)
+		}
+		if n, err := client.Exists(context.Background(), "test:tb:user:a").Result(); err != nil || n != 1 {
+			t.Errorf("Exists(test:tb:user:a) = %d, %v, want the bucket stored under the prefix", n, err)
+		}
+	})
+
+	t.Run("rejects an invalid quota", func(t *testing.T) {
+		if _, err := limiter.Allow(context.Background(), "invalid", ratelimit.Quota{Limit: 1}); err == nil {
+			t.Error("Allow with no window succeeded, want an error")
+		}
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	client := testutil.StartRedis(t)
+	limiter := ratelimit.NewSlidingWindow(client, "test:sw:")
+
+	t.Run("allows the limit per window", func(t *testing.T) {
+		quota := ratelimit.Quota{Limit: 2, Window: time.Hour, Burst: 10}
+
+		results := allowN(t, limiter, "limit", quota, 3)
+		for i, want := range []int{1, 0} {
+			if !results[i].Allowed || results[i].Remaining != want {
+				t.Errorf("request %d = %+v, want allowed with %d remaining", i+1, results[i], want)
+			}
+		}
+		if denied := results[2]; denied.Allowed || denied.RetryAfter <= 59*time.Minute || denied.RetryAfter > time.Hour {
+			t.Errorf("request over the limit = %+v, want denied until the first leaves the window", denied)
+		}
+	})
+
+	t.Run("allows again once requests leave the window", func(t *testing.T) {
+		quota := ratelimit.Quota{Limit: 2, Window: 100 * time.Millisecond}
+
+		allowN(t, limiter, "slide", quota, 2)
+		if denied := allowN(t, limiter, "slide", quota, 1)[0]; denied.Allowed {
+			t.Fatalf("request over the limit = %+v, want denied", denied)
+		}
+
+		time.Sleep(120 * time.Millisecond)
+		if result := allowN(t, limiter, "slide", quota, 1)[0]; !result.Allowed {
+			t.Errorf("request after the window = %+v, want allowed", result)
+		}
+	})
+
+	t.Run("rejects an invalid quota", func(t *testing.T) {
+		if _, err := limiter.Allow(context.Background(), "invalid", ratelimit.Quota{Window: time.Second}); err == nil {
+			t.Error("Allow with no limit succeeded, want an error")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package saga
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore persists saga instances. The table is owned by the embedding
+// service's migrations:
+//
+//	CREATE TABLE sagas (
+//	  id TEXT PRIMARY KEY,
+//	  name TEXT NOT NULL,
+//	  status TEXT NOT NULL,
+//	  step INT NOT NULL,
+//	  data JSONB NOT NULL,
+//	  error TEXT NOT NULL DEFAULT '',
+//	  owner TEXT NOT NULL DEFAULT '',
+//	  lease_until TIMESTAMPTZ,
+//	  created_at TIMESTAMPTZ DEFAULT NOW(),
+//	  updated_at TIMESTAMPTZ DEFAULT NOW()
+//	);
+//	CREATE INDEX sagas_unfinished_idx ON sagas (name) WHERE status IN ('running', 'compensating');
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	table string
+	// owner identifies this store's runner in the instances it holds.
+	owner string
+	lease time.Duration
+}
+
+// NewPostgresStore returns a store whose runner holds its instances for
+// lease after every saved step, so lease must outlast the longest step.
+func NewPostgresStore(pool *pgxpool.Pool, table string, lease time.Duration) *PostgresStore {
+	return &PostgresStore{
+		pool:  pool,
+		table: pgx.Identifier{table}.Sanitize(),
+		owner: rand.Text(),
+		lease: lease,
+	}
+}
+
+func (s *PostgresStore) Create(ctx context.Context, instance *Instance) error {
+	_, err := s.pool.Exec(ctx,
+		"INSERT INTO "+s.table+" (id, name, status, step, data, error, owner, lease_until)"+
+			" VALUES ($1, $2, $3, $4, $5, $6, $7, NOW() + make_interval(secs => $8))",
+		instance.ID, instance.Name, instance.Status, instance.Step, instance.Data, instance.Error, s.owner, s.lease.Seconds(),
+	)
+	return err
+}
+
+func (s *PostgresStore) Save(ctx context.Context, instance *Instance) error {
+	tag, err := s.pool.Exec(ctx,
+		"UPDATE "+s.table+" SET status = $2, step = $3, data = $4, error = $5, lease_until = NOW() + make_interval(secs => $7), updated_at = NOW()"+
+			" WHERE id = $1 AND owner = $6",
+		instance.ID, instance.Status, instance.Step, instance.Data, instance.Error, s.owner, s.lease.Seconds(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) ClaimUnfinished(ctx context.Context, name string) ([]*Instance, error) {
+	rows, err := s.pool.Query(ctx, `
+		WITH claimed AS (
+			UPDATE `+s.table+` SET owner = $4, lease_until = NOW() + make_interval(secs => $5)
+			WHERE id IN (
+				SELECT id FROM `+s.table+`
+				WHERE name = $1 AND status IN ($2, $3) AND (lease_until IS NULL OR lease_until < NOW())
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, name, status, step, data, error, created_at
+		)
+		SELECT id, name, status, step, data, error FROM claimed ORDER BY created_at`,
+		name, StatusRunning, StatusCompensating, s.owner, s.lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*Instance, error) {
+		var instance Instance
+		err := row.Scan(&instance.ID, &instance.Name, &instance.Status, &instance.Step, &instance.Data, &instance.Error)
+		return &instance, err
+	})
+}
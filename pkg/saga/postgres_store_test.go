@@ -0,0 +1,70 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/internal/testutil"
+	"github.com/phongloihong/go-shop/pkg/saga"
+)
+
+const testSchema = `
+CREATE TABLE sagas (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  status TEXT NOT NULL,
+  step INT NOT NULL,
+  data JSONB NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  owner TEXT NOT NULL DEFAULT '',
+  lease_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE INDEX sagas_unfinished_idx ON sagas (name) WHERE status IN ('running', 'compensating');`
+
+func TestPostgresStoreLeases(t *testing.T) {
+	pool := testutil.StartPostgres(t, testSchema)
+	ctx := context.Background()
+	const lease = time.Second
+
+	first := saga.NewPostgresStore(pool, "sagas", lease)
+	second := saga.NewPostgresStore(pool, "sagas", lease)
+
+	instance := &saga.Instance{ID: "order-1", Name: "checkout", Status: saga.StatusRunning, Data: []byte("{}")}
+	if err := first.Create(ctx, instance); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	claimed, err := second.ClaimUnfinished(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("ClaimUnfinished: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Fatalf("claimed %d instances held by a live runner, want 0", len(claimed))
+	}
+
+	// the first runner dies
+	time.Sleep(lease + 100*time.Millisecond)
+
+	claimed, err = second.ClaimUnfinished(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("ClaimUnfinished: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != "order-1" {
+		t.Fatalf("claimed %+v after the lease expired, want order-1", claimed)
+	}
+	if again, _ := first.ClaimUnfinished(ctx, "checkout"); len(again) != 0 {
+		t.Errorf("claimed %d instances just taken over, want 0", len(again))
+	}
+
+	instance.Step = 1
+	if err := first.Save(ctx, instance); !errors.Is(err, saga.ErrLeaseLost) {
+		t.Errorf("Save by the previous runner = %v, want %v", err, saga.ErrLeaseLost)
+	}
+	if err := second.Save(ctx, claimed[0]); err != nil {
+		t.Errorf("Save by the new runner: %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package saga runs multi-step business transactions across services. Each
+// step has an action and a compensation; when an action fails, the completed
+// steps are compensated in reverse order. Progress is persisted after every
+// step so Resume can finish sagas interrupted by a crash or restart.
+//
+// Actions and compensations may run more than once (a crash after a step's
+// side effect but before its progress is saved), so they must be idempotent.
+//
+// An instance is held by the runner that started or resumed it until its
+// lease expires, so replicas resuming on startup never run an instance that
+// another live replica is still executing. A runner whose context is
+// cancelled, e.g. on shutdown, stops where it is and leaves the instance to
+// be resumed rather than compensating it.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusRunning      Status = "running"
+	StatusCompensating Status = "compensating"
+	StatusCompleted    Status = "completed"
+	StatusCompensated  Status = "compensated"
+)
+
+// Step is one stage of a saga. Data is shared by all steps and saved after
+// each one, so actions can record results (e.g. a reservation ID) that later
+// steps or compensations need.
+type Step[T any] struct {
+	Name       string
+	Action     func(ctx context.Context, data *T) error
+	Compensate func(ctx context.Context, data *T) error
+	// Timeout bounds Action and Compensate individually; zero means no limit.
+	Timeout time.Duration
+}
+
+// Instance is the persisted state of one saga execution.
+type Instance struct {
+	ID     string
+	Name   string
+	Status Status
+	// Step is the index of the next action while running, or the number of
+	// steps still to compensate while compensating.
+	Step  int
+	Data  []byte
+	Error string
+}
+
+// Store persists instances on behalf of one runner, which holds the
+// instances it created or claimed until their lease expires.
+type Store interface {
+	// Create stores a new instance held by the runner.
+	Create(ctx context.Context, instance *Instance) error
+	// Save stores the progress of an instance and renews its lease. It fails
+	// with ErrLeaseLost once another runner claimed the instance.
+	Save(ctx context.Context, instance *Instance) error
+	// ClaimUnfinished takes over the unfinished instances of saga name that
+	// no runner holds, oldest first.
+	ClaimUnfinished(ctx context.Context, name string) ([]*Instance, error)
+}
+
+var (
+	ErrCompensated = errors.New("saga was rolled back")
+	// ErrLeaseLost is returned when an instance was claimed by another
+	// runner after its lease expired; that runner carries on with it.
+	ErrLeaseLost = errors.New("saga instance is held by another runner")
+)
+
+type Saga[T any] struct {
+	name  string
+	steps []Step[T]
+	store Store
+}
+
+func New[T any](name string, store Store, steps ...Step[T]) *Saga[T] {
+	return &Saga[T]{
+		name:  name,
+		steps: steps,
+		store: store,
+	}
+}
+
+// Start runs a new saga with the given ID. It returns nil when every step
+// succeeded, an error wrapping ErrCompensated when the saga was rolled back,
+// or another error when it stopped midway and needs Resume.
+func (s *Saga[T]) Start(ctx context.Context, id string, data T) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("saga %s: failed to encode data: %w", s.name, err)
+	}
+
+	instance := &Instance{
+		ID:     id,
+		Name:   s.name,
+		Status: StatusRunning,
+		Data:   raw,
+	}
+	if err := s.store.Create(ctx, instance); err != nil {
+		return fmt.Errorf("saga %s: failed to create instance: %w", s.name, err)
+	}
+
+	return s.run(ctx, instance)
+}
+
+// Resume continues every unfinished instance of this saga that no runner
+// holds, typically called on startup. It returns the errors of instances
+// that still could not finish.
+func (s *Saga[T]) Resume(ctx context.Context) error {
+	instances, err := s.store.ClaimUnfinished(ctx, s.name)
+	if err != nil {
+		return fmt.Errorf("saga %s: failed to claim unfinished instances: %w", s.name, err)
+	}
+
+	var errs []error
+	for _, instance := range instances {
+		if err := s.run(ctx, instance); err != nil && !errors.Is(err, ErrCompensated) {
+			errs = append(errs, fmt.Errorf("instance %s: %w", instance.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Saga[T]) run(ctx context.Context, instance *Instance) error {
+	var data T
+	if err := json.Unmarshal(instance.Data, &data); err != nil {
+		return fmt.Errorf("saga %s: failed to decode data: %w", s.name, err)
+	}
+
+	for instance.Status == StatusRunning && instance.Step < len(s.steps) {
+		step := s.steps[instance.Step]
+		if err := s.call(ctx, step, step.Action, &data); err != nil {
+			// the runner is stopping, not the step failing: leave the
+			// instance to Resume
+			if ctx.Err() != nil {
+				return fmt.Errorf("saga %s: stopped at %s: %w", s.name, step.Name, ctx.Err())
+			}
+			instance.Status = StatusCompensating
+			instance.Error = fmt.Sprintf("%s: %v", step.Name, err)
+			if err := s.save(ctx, instance, &data); err != nil {
+				return err
+			}
+			break
+		}
+
+		instance.Step++
+		if instance.Step == len(s.steps) {
+			instance.Status = StatusCompleted
+		}
+		if err := s.save(ctx, instance, &data); err != nil {
+			return err
+		}
+	}
+
+	for instance.Status == StatusCompensating && instance.Step > 0 {
+		step := s.steps[instance.Step-1]
+		if step.Compensate != nil {
+			if err := s.call(ctx, step, step.Compensate, &data); err != nil {
+				return fmt.Errorf("saga %s: failed to compensate %s: %w", s.name, step.Name, err)
+			}
+		}
+
+		instance.Step--
+		if instance.Step == 0 {
+			instance.Status = StatusCompensated
+		}
+		if err := s.save(ctx, instance, &data); err != nil {
+			return err
+		}
+	}
+
+	if instance.Status == StatusCompensated {
+		return fmt.Errorf("saga %s: %s: %w", s.name, instance.Error, ErrCompensated)
+	}
+
+	return nil
+}
+
+func (s *Saga[T]) call(ctx context.Context, step Step[T], fn func(context.Context, *T) error, data *T) error {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	return fn(ctx, data)
+}
+
+func (s *Saga[T]) save(ctx context.Context, instance *Instance, data *T) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("saga %s: failed to encode data: %w", s.name, err)
+	}
+	instance.Data = raw
+
+	if err := s.store.Save(ctx, instance); err != nil {
+		return fmt.Errorf("saga %s: failed to save progress: %w", s.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/saga"
+)
+
+// memStore keeps instances in memory, with no leases.
+type memStore struct {
+	mu        sync.Mutex
+	instances map[string]saga.Instance
+	order     []string
+}
+
+func newMemStore() *memStore {
+	return &memStore{instances: map[string]saga.Instance{}}
+}
+
+func (s *memStore) Create(_ context.Context, instance *saga.Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances[instance.ID] = *instance
+	s.order = append(s.order, instance.ID)
+
+	return nil
+}
+
+func (s *memStore) Save(_ context.Context, instance *saga.Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances[instance.ID] = *instance
+
+	return nil
+}
+
+func (s *memStore) ClaimUnfinished(_ context.Context, name string) ([]*saga.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ret []*saga.Instance
+	for _, id := range s.order {
+		instance := s.instances[id]
+		if instance.Name == name && (instance.Status == saga.StatusRunning || instance.Status == saga.StatusCompensating) {
+			ret = append(ret, &instance)
+		}
+	}
+	return ret, nil
+}
+
+func (s *memStore) get(id string) saga.Instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.instances[id]
+}
+
+type order struct {
+	Reservation string `json:"reservation"`
+}
+
+// journal records the actions and compensations run, in order.
+type journal struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (j *journal) record(call string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.calls = append(j.calls, call)
+}
+
+func (j *journal) get() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]string(nil), j.calls...)
+}
+
+// step returns a step recording its calls in j, whose action runs act.
+func step(j *journal, name string, act func(ctx context.Context, data *order) error) saga.Step[order] {
+	return saga.Step[order]{
+		Name: name,
+		Action: func(ctx context.Context, data *order) error {
+			j.record(name)
+			if act != nil {
+				return act(ctx, data)
+			}
+			return nil
+		},
+		Compensate: func(context.Context, *order) error {
+			j.record("undo " + name)
+			return nil
+		},
+	}
+}
+
+func TestSagaCompletes(t *testing.T) {
+	store := newMemStore()
+	j := &journal{}
+	reserve := step(j, "reserve", func(_ context.Context, data *order) error {
+		data.Reservation = "r-1"
+		return nil
+	})
+	s := saga.New("checkout", store, reserve, step(j, "charge", nil))
+
+	if err := s.Start(context.Background(), "order-1", order{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if got, want := j.get(), []string{"reserve", "charge"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	instance := store.get("order-1")
+	if instance.Status != saga.StatusCompleted || string(instance.Data) != `{"reservation":"r-1"}` {
+		t.Errorf("instance = %s with %s, want completed with the reservation", instance.Status, instance.Data)
+	}
+}
+
+func TestSagaCompensatesInReverse(t *testing.T) {
+	store := newMemStore()
+	j := &journal{}
+	declined := errors.New("card declined")
+	s := saga.New("checkout", store,
+		step(j, "reserve", nil),
+		step(j, "hold", nil),
+		step(j, "charge", func(context.Context, *order) error { return declined }),
+	)
+
+	err := s.Start(context.Background(), "order-1", order{})
+	if !errors.Is(err, saga.ErrCompensated) {
+		t.Fatalf("Start = %v, want %v", err, saga.ErrCompensated)
+	}
+	if got, want := j.get(), []string{"reserve", "hold", "charge", "undo hold", "undo reserve"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if status := store.get("order-1").Status; status != saga.StatusCompensated {
+		t.Errorf("status = %s, want %s", status, saga.StatusCompensated)
+	}
+}
+
+func TestSagaCompensatesStepTimeout(t *testing.T) {
+	store := newMemStore()
+	j := &journal{}
+	slow := step(j, "charge", func(ctx context.Context, _ *order) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	slow.Timeout = time.Millisecond
+	s := saga.New("checkout", store, step(j, "reserve", nil), slow)
+
+	if err := s.Start(context.Background(), "order-1", order{}); !errors.Is(err, saga.ErrCompensated) {
+		t.Fatalf("Start = %v, want %v", err, saga.ErrCompensated)
+	}
+	if got, want := j.get(), []string{"reserve", "charge", "undo reserve"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestSagaStopsWithoutCompensating(t *testing.T) {
+	store := newMemStore()
+	j := &journal{}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopping := true
+	charge := step(j, "charge", func(ctx context.Context, _ *order) error {
+		if stopping {
+			// shut down while the step runs
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	})
+	s := saga.New("checkout", store, step(j, "reserve", nil), charge)
+
+	err := s.Start(ctx, "order-1", order{})
+	if err == nil || errors.Is(err, saga.ErrCompensated) {
+		t.Fatalf("Start = %v, want it stopped without compensating", err)
+	}
+	if got, want := j.get(), []string{"reserve", "charge"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if instance := store.get("order-1"); instance.Status != saga.StatusRunning || instance.Step != 1 {
+		t.Fatalf("instance = %s at step %d, want running at step 1", instance.Status, instance.Step)
+	}
+
+	stopping = false
+	if err := s.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if got, want := j.get(), []string{"reserve", "charge", "charge"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if status := store.get("order-1").Status; status != saga.StatusCompleted {
+		t.Errorf("status after Resume = %s, want %s", status, saga.StatusCompleted)
+	}
+}
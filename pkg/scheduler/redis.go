@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript deletes a lock only while it still holds the caller's token,
+// so a lock that expired and was taken by another replica is left alone.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker takes locks with SET NX under prefix. Each lock holds a token
+// of the locker, so only it can unlock them.
+type RedisLocker struct {
+	client *redis.Client
+	prefix string
+	token  string
+}
+
+func NewRedisLocker(client *redis.Client, prefix string) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+		prefix: prefix,
+		token:  rand.Text(),
+	}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := l.client.SetNX(ctx, l.prefix+key, l.token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to take lock %s: %w", key, err)
+	}
+
+	return ok, nil
+}
+
+func (l *RedisLocker) Unlock(ctx context.Context, key string) error {
+	if err := unlockScript.Run(ctx, l.client, []string{l.prefix + key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+
+	return nil
+}
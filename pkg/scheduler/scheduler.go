@@ -0,0 +1,237 @@
+// Package scheduler runs recurring maintenance tasks (cleanups, pruning,
+// reconciliation) on cron schedules. Every replica of a service runs the
+// scheduler, and a distributed lock taken per task and tick makes sure only
+// one of them runs each occurrence. A second lock, held per task while it
+// runs, keeps a run that outlasts its interval from overlapping the next.
+//
+// Tasks are registered in code and scheduled in config:
+//
+//	scheduler:
+//	  tasks:
+//	    prune_webhook_deliveries:
+//	      enabled: true
+//	      schedule: "0 3 * * *"
+//	      timeout: 10m
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+)
+
+// Task runs one occurrence of a scheduled task.
+type Task func(ctx context.Context) error
+
+type Config struct {
+	// Tasks is keyed by task name.
+	Tasks map[string]TaskConfig `mapstructure:"tasks"`
+}
+
+type TaskConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Schedule is a five-field cron expression ("0 3 * * *") or a
+	// descriptor ("@hourly", "@every 15m"), evaluated in UTC unless it
+	// starts with CRON_TZ=<zone>.
+	Schedule string `mapstructure:"schedule"`
+	// Timeout bounds one run; it defaults to the time until the next
+	// occurrence. Occurrences due while a longer run lasts are skipped.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Locker grants key to a single caller across every replica until ttl
+// expires or the caller unlocks it.
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock releases key if the caller still holds it.
+	Unlock(ctx context.Context, key string) error
+}
+
+type schedulerMetrics struct {
+	runs        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+func newSchedulerMetrics(registerer prometheus.Registerer) *schedulerMetrics {
+	m := &schedulerMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "scheduler_runs_total",
+			Help: "Scheduled task runs by task and outcome (succeeded, failed).",
+		}, []string{"task", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "scheduler_run_duration_seconds",
+			Help: "Duration of scheduled task runs by task.",
+		}, []string{"task"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "scheduler_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run of each task on this replica.",
+		}, []string{"task"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.runs, m.duration, m.lastSuccess)
+	}
+
+	return m
+}
+
+type entry struct {
+	name     string
+	schedule cron.Schedule
+	timeout  time.Duration
+	task     Task
+}
+
+type Scheduler struct {
+	cfg     Config
+	locker  Locker
+	entries []*entry
+	metrics *schedulerMetrics
+}
+
+// New returns a scheduler for the tasks in cfg. Metrics are registered with
+// registerer when it is not nil.
+func New(cfg Config, locker Locker, registerer prometheus.Registerer) *Scheduler {
+	return &Scheduler{
+		cfg:     cfg,
+		locker:  locker,
+		metrics: newSchedulerMetrics(registerer),
+	}
+}
+
+// Register schedules task as configured under name. A task that is missing
+// from the config or disabled is not scheduled. Register every task before
+// Run.
+func (s *Scheduler) Register(name string, task Task) error {
+	cfg, ok := s.cfg.Tasks[name]
+	if !ok || !cfg.Enabled {
+		log.Printf("scheduler: task %s is disabled", name)
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(cfg.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q of task %s: %w", cfg.Schedule, name, err)
+	}
+
+	s.entries = append(s.entries, &entry{
+		name:     name,
+		schedule: schedule,
+		timeout:  cfg.Timeout,
+		task:     task,
+	})
+
+	return nil
+}
+
+// Run runs the registered tasks on schedule until ctx is cancelled, then
+// waits for running ones to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	var running sync.WaitGroup
+	for _, e := range s.entries {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	running.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		tick := next(e.schedule, time.Now().UTC())
+		timer := time.NewTimer(time.Until(tick))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// the lock lasts until the next occurrence, which also covers
+		// replicas whose clocks are a little behind
+		interval := next(e.schedule, tick).Sub(tick)
+		locked, err := s.locker.TryLock(ctx, fmt.Sprintf("%s:%d", e.name, tick.Unix()), interval)
+		if err != nil {
+			log.Printf("scheduler: failed to lock task %s: %v", e.name, err)
+			continue
+		}
+		if !locked {
+			continue
+		}
+
+		timeout := e.timeout
+		if timeout <= 0 {
+			timeout = interval
+		}
+		s.runExclusive(ctx, e, timeout)
+	}
+}
+
+// runExclusive runs e unless a run of it is still going on any replica,
+// which happens when timeout is longer than the interval. The lock is held
+// until the run returns; its ttl only frees it after a crash.
+func (s *Scheduler) runExclusive(ctx context.Context, e *entry, timeout time.Duration) {
+	key := e.name + ":running"
+	locked, err := s.locker.TryLock(ctx, key, timeout)
+	if err != nil {
+		log.Printf("scheduler: failed to lock task %s: %v", e.name, err)
+		return
+	}
+	if !locked {
+		log.Printf("scheduler: skipping task %s, its previous run has not finished", e.name)
+		return
+	}
+	defer func() {
+		// released even when ctx is done, so a restart need not wait out
+		// the ttl
+		if err := s.locker.Unlock(context.WithoutCancel(ctx), key); err != nil {
+			log.Printf("scheduler: failed to unlock task %s: %v", e.name, err)
+		}
+	}()
+
+	s.run(ctx, e, timeout)
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry, timeout time.Duration) {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runTask(runCtx, e.task)
+	s.metrics.duration.WithLabelValues(e.name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		s.metrics.runs.WithLabelValues(e.name, "failed").Inc()
+		log.Printf("scheduler: task %s failed: %v", e.name, err)
+		return
+	}
+	s.metrics.runs.WithLabelValues(e.name, "succeeded").Inc()
+	s.metrics.lastSuccess.WithLabelValues(e.name).SetToCurrentTime()
+}
+
+func runTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return task(ctx)
+}
+
+// next returns the first occurrence after t. "@every" schedules are aligned
+// to multiples of their interval so every replica picks the same ticks.
+func next(schedule cron.Schedule, t time.Time) time.Time {
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return t.Truncate(every.Delay).Add(every.Delay)
+	}
+
+	return schedule.Next(t)
+}
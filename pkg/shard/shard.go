@@ -0,0 +1,30 @@
+// Package shard places keys on one of n shards with jump consistent hashing
+// (Lamping and Veach), so growing from n to n+1 shards moves only about
+// 1/(n+1) of the keys, all of them onto the new shard.
+package shard
+
+import "hash/fnv"
+
+// Index returns the shard of key among n shards, in [0, n). It is stable
+// across processes and releases: changing it relocates stored data.
+func Index(key string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	return jump(h.Sum64(), n)
+}
+
+func jump(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+
+	return int(b)
+}
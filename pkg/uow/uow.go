@@ -0,0 +1,83 @@
+// Package uow runs several repository calls atomically in one Postgres
+// transaction. The transaction travels in the context, so usecases stay
+// unaware of pgx and repositories join it with Tx:
+//
+//	err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+//		if _, err := userRepo.CreateUser(ctx, user); err != nil {
+//			return err
+//		}
+//		return events.Publish(ctx, event)
+//	})
+package uow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type txKey struct{}
+
+// Beginner starts transactions; *pgxpool.Pool and *pgx.Conn satisfy it.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type UnitOfWork struct {
+	db Beginner
+}
+
+func New(db Beginner) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction that is committed when fn returns nil and
+// rolled back when it returns an error or panics.
+//
+// A Do nested inside another runs in a savepoint of the outer transaction:
+// its failure rolls back only its own work, and the outer fn decides whether
+// to carry on or fail too. Nothing is committed before the outermost Do.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	var (
+		tx  pgx.Tx
+		err error
+	)
+	if outer, ok := Tx(ctx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = u.db.Begin(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// roll back even if ctx was cancelled, so the connection is released clean
+	rollbackCtx := context.WithoutCancel(ctx)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(rollbackCtx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(rollbackCtx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Tx returns the transaction of the innermost Do running ctx, if any.
+func Tx(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
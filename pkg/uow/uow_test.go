@@ -0,0 +1,166 @@
+package uow_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/phongloihong/go-shop/pkg/uow"
+)
+
+// fakeDB is a database of rows written through fakeTx, where a transaction
+// begun in a transaction is a savepoint, as with pgx: committing it hands
+// its rows to the outer transaction and rolling it back drops them.
+type fakeDB struct {
+	rows []string
+}
+
+func (db *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{db: db}, nil
+}
+
+type fakeTx struct {
+	pgx.Tx
+
+	db     *fakeDB
+	outer  *fakeTx
+	rows   []string
+	closed bool
+}
+
+func (tx *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{db: tx.db, outer: tx}, nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	if tx.closed {
+		return pgx.ErrTxClosed
+	}
+	tx.closed = true
+	if tx.outer != nil {
+		tx.outer.rows = append(tx.outer.rows, tx.rows...)
+	} else {
+		tx.db.rows = append(tx.db.rows, tx.rows...)
+	}
+
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if tx.closed {
+		return pgx.ErrTxClosed
+	}
+	tx.closed = true
+
+	return nil
+}
+
+// insert writes row in the transaction of ctx, as a repository joining it.
+func insert(t *testing.T, ctx context.Context, row string) {
+	t.Helper()
+
+	tx, ok := uow.Tx(ctx)
+	if !ok {
+		t.Fatalf("insert of %s outside a transaction", row)
+	}
+	tx.(*fakeTx).rows = append(tx.(*fakeTx).rows, row)
+}
+
+func TestDoCommits(t *testing.T) {
+	db := &fakeDB{}
+	unitOfWork := uow.New(db)
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		insert(t, ctx, "user")
+		return unitOfWork.Do(ctx, func(ctx context.Context) error {
+			insert(t, ctx, "event")
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if want := []string{"user", "event"}; !slices.Equal(db.rows, want) {
+		t.Errorf("rows = %v, want %v", db.rows, want)
+	}
+}
+
+func TestDoRollsBackOuterTransaction(t *testing.T) {
+	db := &fakeDB{}
+	unitOfWork := uow.New(db)
+	errFailed := errors.New("failed")
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		insert(t, ctx, "user")
+		// a savepoint that succeeded is still undone with its transaction
+		if err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			insert(t, ctx, "event")
+			return nil
+		}); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Do = %v, want %v", err, errFailed)
+	}
+	if len(db.rows) != 0 {
+		t.Errorf("rows = %v, want none", db.rows)
+	}
+}
+
+func TestDoRollsBackInnerSavepoint(t *testing.T) {
+	db := &fakeDB{}
+	unitOfWork := uow.New(db)
+	errFailed := errors.New("failed")
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		insert(t, ctx, "user")
+		err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			insert(t, ctx, "event")
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("inner Do = %v, want %v", err, errFailed)
+		}
+		// the outer transaction carries on without the savepoint's rows
+		insert(t, ctx, "audit")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if want := []string{"user", "audit"}; !slices.Equal(db.rows, want) {
+		t.Errorf("rows = %v, want %v", db.rows, want)
+	}
+}
+
+func TestDoRollsBackOnPanic(t *testing.T) {
+	db := &fakeDB{}
+	unitOfWork := uow.New(db)
+	var tx *fakeTx
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the panic to propagate", p)
+			}
+		}()
+
+		_ = unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			current, _ := uow.Tx(ctx)
+			tx = current.(*fakeTx)
+			insert(t, ctx, "user")
+			panic("boom")
+		})
+	}()
+
+	if tx == nil || !tx.closed {
+		t.Errorf("transaction left open after a panic")
+	}
+	if len(db.rows) != 0 {
+		t.Errorf("rows = %v, want none", db.rows)
+	}
+}
@@ -0,0 +1,100 @@
+package valueobject
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+type Email string
+
+// NewEmail trims and lower-cases email. Mail providers ignore case, so
+// addresses differing only in case belong to one person.
+func NewEmail(email string) Email {
+	return Email(strings.ToLower(strings.TrimSpace(email)))
+}
+
+func (e Email) String() string {
+	return string(e)
+}
+
+// Validate accepts a bare address only. mail.ParseAddress also takes
+// "Name <x@example.com>" and "<x@example.com>", whose raw string would end
+// its domain in ">" and get past the domain checks.
+func (e Email) Validate() error {
+	_, err := e.address()
+	return err
+}
+
+func (e Email) address() (string, error) {
+	parsed, err := mail.ParseAddress(string(e))
+	if err != nil {
+		return "", err
+	}
+	if parsed.Address != string(e) {
+		return "", errors.New("mail: expected a bare address")
+	}
+
+	return parsed.Address, nil
+}
+
+// Domain returns the part after the last "@" of the parsed address, or "" if
+// e is not a valid address.
+func (e Email) Domain() string {
+	address, err := e.address()
+	if err != nil {
+		return ""
+	}
+
+	return address[strings.LastIndexByte(address, '@')+1:]
+}
+
+// DedupKey returns the address without the "+tag" of its local part, e.g.
+// "lan@example.com" for "lan+shop@example.com". Most providers deliver both
+// to the same mailbox, so addresses with the same key likely belong to one
+// person. An invalid address is its own key.
+func (e Email) DedupKey() string {
+	address, err := e.address()
+	if err != nil {
+		return string(e)
+	}
+
+	at := strings.LastIndexByte(address, '@')
+	local, domain := address[:at], address[at:]
+	if plus := strings.IndexByte(local, '+'); plus >= 0 {
+		local = local[:plus]
+	}
+
+	return local + domain
+}
+
+// DomainList is a set of email domains, e.g. of disposable email providers.
+type DomainList map[string]struct{}
+
+func NewDomainList(domains []string) DomainList {
+	ret := make(DomainList, len(domains))
+	for _, domain := range domains {
+		ret[strings.ToLower(strings.TrimSpace(domain))] = struct{}{}
+	}
+
+	return ret
+}
+
+// Contains reports whether the domain of e or one of its parent domains is
+// in the list, so listing "example.com" also covers "mail.example.com".
+func (l DomainList) Contains(e Email) bool {
+	domain := e.Domain()
+	for domain != "" {
+		if _, ok := l[domain]; ok {
+			return true
+		}
+
+		dot := strings.IndexByte(domain, '.')
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+
+	return false
+}
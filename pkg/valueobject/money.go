@@ -0,0 +1,112 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Money is an amount in the smallest unit of its currency (e.g. cents) with
+// an ISO 4217 currency code. Amounts are integers so arithmetic never loses
+// precision.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: currency}
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+
+	return m, nil
+}
+
+func (m Money) Validate() error {
+	if !currencyPattern.MatchString(m.Currency) {
+		return fmt.Errorf("invalid currency code: %s", m.Currency)
+	}
+
+	return nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.New("cannot add money in different currencies")
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Subtract(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.New("cannot subtract money in different currencies")
+	}
+
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Multiply(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// Allocate splits the amount across the given ratios without losing units;
+// the remainder is distributed one unit at a time starting from the first share.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("allocation ratios must not be negative")
+		}
+		total += r
+	}
+
+	if total == 0 {
+		return nil, errors.New("allocation ratios must not all be zero")
+	}
+
+	ret := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, r := range ratios {
+		share := m.Amount * r / total
+		ret[i] = Money{Amount: share, Currency: m.Currency}
+		remainder -= share
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+
+	for i := 0; remainder != 0; i = (i + 1) % len(ret) {
+		if ratios[i] == 0 {
+			continue
+		}
+		ret[i].Amount += step
+		remainder -= step
+	}
+
+	return ret, nil
+}
@@ -25,6 +25,16 @@ func (p Password) Validate() error {
 	return nil
 }
 
+// ValidateHash checks that p is a bcrypt hash rather than a plain password,
+// e.g. one exported by a system whose users are imported.
+func (p Password) ValidateHash() error {
+	if _, err := bcrypt.Cost([]byte(p)); err != nil {
+		return errors.New("password hash must be a bcrypt hash")
+	}
+
+	return nil
+}
+
 func (p Password) Hash() (string, error) {
 	bytes, error := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
 	return string(bytes), error
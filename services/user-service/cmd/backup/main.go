@@ -0,0 +1,135 @@
+// Command backup takes and restores logical backups of the user service's
+// databases, the primary and every user shard, with pg_dump and pg_restore:
+//
+//	go run ./cmd/backup create -dir /var/backups/user-service
+//	go run ./cmd/backup verify -dir /var/backups/user-service
+//	go run ./cmd/backup prune -dir /var/backups/user-service -keep 7 -max-age 720h
+//	go run ./cmd/backup restore -dir /var/backups/user-service -backup 20261016T030000Z -database primary -into user_db_restored
+//
+// verify restores a backup, the newest by default, into scratch databases
+// and checks it; run it after every create. restore always restores into a
+// new database; point the service at it once it is done.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/backup"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: backup create|verify|restore|prune|list [flags]")
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	dir := flags.String("dir", "backups", "directory holding the backups")
+	name := flags.String("backup", "", "backup to verify or restore, by name; the newest by default")
+	database := flags.String("database", "primary", "database to restore: primary or shard-N")
+	into := flags.String("into", "", "new database to restore into, on the server of -database")
+	keep := flags.Int("keep", 7, "newest backups prune always keeps")
+	maxAge := flags.Duration("max-age", 30*24*time.Hour, "age after which prune deletes backups beyond -keep")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Error loading configuration:", err)
+	}
+	databases := backup.Databases(cfg.Database)
+
+	ctx := context.Background()
+	switch command {
+	case "create":
+		path, err := backup.Create(ctx, *dir, databases, time.Now())
+		if err != nil {
+			log.Fatal("Error creating backup:", err)
+		}
+		fmt.Printf("Backed up %d databases to %s\n", len(databases), path)
+
+	case "verify":
+		path, manifest := open(*dir, *name)
+		verifications, err := backup.Verify(ctx, path, manifest, databases)
+		for _, v := range verifications {
+			fmt.Printf("%s: restored at migration %d with %d users\n", v.Database, v.MigrationVersion, v.Users)
+		}
+		if err != nil {
+			log.Fatal("Error verifying backup:", err)
+		}
+		fmt.Printf("Verified %s\n", path)
+
+	case "restore":
+		if *into == "" {
+			log.Fatal("-into is required")
+		}
+		var target *backup.Database
+		for _, db := range databases {
+			if db.Name == *database {
+				target = &db
+			}
+		}
+		if target == nil {
+			log.Fatalf("Unknown database %s", *database)
+		}
+
+		path, manifest := open(*dir, *name)
+		if err := backup.Restore(ctx, path, manifest, target.Name, target.Config, *into); err != nil {
+			log.Fatal("Error restoring backup:", err)
+		}
+		fmt.Printf("Restored %s of %s into %s\n", target.Name, path, *into)
+
+	case "prune":
+		deleted, err := backup.Prune(*dir, *keep, *maxAge, time.Now())
+		for _, path := range deleted {
+			fmt.Printf("Deleted %s\n", path)
+		}
+		if err != nil {
+			log.Fatal("Error pruning backups:", err)
+		}
+		fmt.Printf("Pruned %d backups\n", len(deleted))
+
+	case "list":
+		backups, err := backup.List(*dir)
+		if err != nil {
+			log.Fatal("Error listing backups:", err)
+		}
+		for _, b := range backups {
+			fmt.Printf("%s\t%s\n", filepath.Base(b.Path), b.CreatedAt.Format(time.RFC3339))
+		}
+
+	default:
+		log.Fatalf("Unknown command %s", command)
+	}
+}
+
+// open returns the backup named name under dir, or the newest, with its
+// checked manifest.
+func open(dir, name string) (string, *backup.Manifest) {
+	path := filepath.Join(dir, name)
+	if name == "" {
+		backups, err := backup.List(dir)
+		if err != nil {
+			log.Fatal("Error listing backups:", err)
+		}
+		if len(backups) == 0 {
+			log.Fatalf("No backups in %s", dir)
+		}
+		path = backups[len(backups)-1].Path
+	}
+
+	manifest, err := backup.ReadManifest(path)
+	if err != nil {
+		log.Fatal("Error reading backup:", err)
+	}
+
+	return path, manifest
+}
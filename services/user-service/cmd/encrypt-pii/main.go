@@ -0,0 +1,75 @@
+// Command encrypt-pii encrypts the phone numbers stored in the clear before
+// the service encrypted them, on the primary database and every user shard.
+// Run it once after deploying migration 000019; it can run while the service
+// serves traffic, and an interrupted run can be repeated:
+//
+//	go run ./cmd/encrypt-pii
+//
+// After rotating the master key, the reencrypt_fields scheduled task moves
+// the numbers onto the new key; -reencrypt does the same at once. Before
+// migrating 000019 down, stop the service and run it with -decrypt to store
+// the numbers in the clear again.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", 500, "users read per query")
+	reencrypt := flag.Bool("reencrypt", false, "re-encrypt phone numbers under older master keys with the current one")
+	decrypt := flag.Bool("decrypt", false, "store encrypted phone numbers in the clear again")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Error loading configuration:", err)
+	}
+
+	fields, err := encryption.NewLocalFieldCipher(cfg.Encryption)
+	if err != nil {
+		log.Fatal("Error creating field cipher:", err)
+	}
+
+	ctx := context.Background()
+	conn, err := postgres.NewConnection(ctx, cfg.Database, nil)
+	if err != nil {
+		log.Fatal("Error creating database pool:", err)
+	}
+	defer conn.Close()
+
+	shardPools, err := postgres.NewShardConnections(ctx, cfg.Database, nil)
+	if err != nil {
+		log.Fatal("Error creating user shard pools:", err)
+	}
+	shards := []sqlc.DBTX{conn}
+	for _, pool := range shardPools {
+		defer pool.Close()
+		shards = append(shards, pool)
+	}
+	router := postgres.NewShardRouter(shards)
+
+	rewrite, verb := postgres.EncryptPlaintextPhones, "Encrypted"
+	switch {
+	case *reencrypt && *decrypt:
+		log.Fatal("-reencrypt and -decrypt cannot be combined")
+	case *reencrypt:
+		rewrite, verb = postgres.ReencryptPhones, "Re-encrypted"
+	case *decrypt:
+		rewrite, verb = postgres.DecryptPhones, "Decrypted"
+	}
+
+	stats, err := rewrite(ctx, router, fields, int32(*batchSize))
+	if err != nil {
+		log.Fatalf("Error rewriting phone numbers after %d users: %v", stats.Rewritten, err)
+	}
+	fmt.Printf("%s phone numbers of %d of %d users on %d shards; %d changed meanwhile\n", verb, stats.Rewritten, stats.Scanned, len(shards), stats.Skipped)
+}
@@ -4,62 +4,342 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/admin"
+	"github.com/phongloihong/go-shop/pkg/health"
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/pkg/metrics"
+	"github.com/phongloihong/go-shop/pkg/mtls"
+	"github.com/phongloihong/go-shop/pkg/scheduler"
+	"github.com/phongloihong/go-shop/pkg/uow"
 	"github.com/phongloihong/go-shop/services/user-service/internal/config"
 	"github.com/phongloihong/go-shop/services/user-service/internal/delivery/connect"
+	"github.com/phongloihong/go-shop/services/user-service/internal/delivery/worker"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/cache"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/captcha"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/dualwrite"
 	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/geoip"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/webhook"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 )
 
+// serviceName labels the service's infrastructure metrics.
+const serviceName = "user-service"
+
 func main() {
-	cfg, err := config.Load()
+	cfgWatcher, err := config.Watch()
 	if err != nil {
 		fmt.Println("Error loading configuration:", err)
 		return
 	}
+	cfg := cfgWatcher.Current()
+
+	// the level follows log_level on every config reload
+	logLevel := new(slog.LevelVar)
+	setLogLevel(logLevel, cfg.LogLevel)
+	cfgWatcher.OnChange(func(current *config.Config) {
+		setLogLevel(logLevel, current.LogLevel)
+	})
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+	if cfg.Region != "" {
+		logger = logger.With("region", cfg.Region)
+	}
 
-	conn, err := postgres.NewConnection(context.Background(), cfg.Database)
+	queryTracer := metrics.NewQueryTracer(prometheus.DefaultRegisterer, serviceName, logger, cfg.Database.SlowQueryThreshold)
+	conn, err := postgres.NewConnection(context.Background(), cfg.Database, queryTracer)
 	if err != nil {
-		log.Fatal("Error connecting to database:", err)
+		log.Fatal("Error creating database pool:", err)
+	}
+	defer conn.Close()
+
+	shardPools, err := postgres.NewShardConnections(context.Background(), cfg.Database, queryTracer)
+	if err != nil {
+		log.Fatal("Error creating user shard pools:", err)
+	}
+	userShards := make([]sqlc.DBTX, 0, len(shardPools))
+	for _, pool := range shardPools {
+		defer pool.Close()
+		userShards = append(userShards, pool)
+	}
+
+	fields, err := encryption.NewLocalFieldCipher(cfg.Encryption)
+	if err != nil {
+		log.Fatal("Error creating field cipher:", err)
 	}
 
-	defer func() {
-		conErr := conn.Close(context.Background())
-		if conErr != nil {
-			fmt.Println("Error when closing database connection:", conErr)
+	repos := postgres.NewUserRepositories(conn, userShards, fields)
+	var dualWritePools []*pgxpool.Pool
+	if cfg.DualWrite.Enabled {
+		dualWritePools, err = openDualWriteTarget(cfg.DualWrite, queryTracer, fields, logger, &repos)
+		if err != nil {
+			log.Fatal("Error creating dual write target pools:", err)
 		}
-	}()
+		for _, pool := range dualWritePools {
+			defer pool.Close()
+		}
+	}
+
+	redisClient := cache.NewRedisClient(cfg.Redis)
+	defer redisClient.Close()
+
+	metrics.RegisterPgxPool(prometheus.DefaultRegisterer, serviceName, conn)
+	metrics.InstrumentRedis(prometheus.DefaultRegisterer, serviceName, redisClient)
+
+	webhookUseCase := usecase.NewWebhookUseCase(
+		uow.New(conn),
+		postgres.NewWebhookRepository(conn),
+		webhook.NewHTTPSender(cfg.Webhook.RequestTimeout),
+		usecase.WebhookRetryPolicy{
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			InitialBackoff: cfg.Webhook.InitialBackoff,
+			MaxBackoff:     cfg.Webhook.MaxBackoff,
+		},
+		cfg.Region,
+	)
+
+	jobQueue := jobs.New(conn, "jobs")
+
+	// the API screens logins and registrations with it, and the scheduler
+	// looks for spikes
+	authAnomalies := usecase.NewAuthAnomalyDetector(
+		cache.NewAuthActivityRepository(redisClient, "user-service:auth-activity:", prometheus.DefaultRegisterer),
+		geoip.NewHTTPLocator(cfg.LoginRisk.GeoIPURL, cfg.LoginRisk.GeoIPTimeout),
+		captcha.NewHTTPVerifier(cfg.Captcha.VerifyURL, cfg.Captcha.Secret, cfg.Captcha.Timeout),
+		webhookUseCase,
+		usecase.AuthAnomalyPolicy{
+			StuffingWindow:   cfg.AuthAnomaly.StuffingWindow,
+			MaxEmailsPerIP:   cfg.AuthAnomaly.MaxEmailsPerIP,
+			MaxEmailsPerASN:  cfg.AuthAnomaly.MaxEmailsPerASN,
+			SpikeWindow:      cfg.AuthAnomaly.SpikeWindow,
+			BaselineWindows:  cfg.AuthAnomaly.BaselineWindows,
+			SpikeFactor:      cfg.AuthAnomaly.SpikeFactor,
+			MinFailedLogins:  cfg.AuthAnomaly.MinFailedLogins,
+			MinRegistrations: cfg.AuthAnomaly.MinRegistrations,
+			Cooldown:         cfg.AuthAnomaly.Cooldown,
+		},
+	)
+
+	// background workers stop before the server shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
 
+	// reload live settings (rate limits) on config file change or SIGHUP
+	go cfgWatcher.Run(workerCtx)
+
+	rateLimitConfig := func() *config.RateLimitConfig {
+		return cfgWatcher.Current().RateLimit
+	}
+
+	sizeLimitConfig := func() *interceptor.SizeLimitConfig {
+		return cfgWatcher.Current().RequestSize
+	}
+
+	chaosConfig := func() *interceptor.ChaosConfig {
+		current := cfgWatcher.Current()
+		if current.Environment == config.EnvironmentProduction {
+			return nil
+		}
+		return current.Chaos
+	}
+	if cfg.Chaos.Enabled {
+		if cfg.Environment == config.EnvironmentProduction {
+			log.Println("Chaos config is enabled but ignored in production")
+		} else {
+			log.Printf("Chaos fault injection is enabled (environment %q)", cfg.Environment)
+		}
+	}
+
+	// serve probes right away; RPCs are rejected until dependencies answer
+	readiness := &health.Readiness{}
+	servers := startConnectServer(cfg, logger, readiness, rateLimitConfig, chaosConfig, sizeLimitConfig, conn, repos, fields, redisClient, webhookUseCase, authAnomalies, jobQueue)
+
+	if err := health.WaitFor(workerCtx, "database", *cfg.Startup, conn.Ping); err != nil {
+		log.Fatal("Error connecting to database:", err)
+	}
+	for i, pool := range shardPools {
+		if err := health.WaitFor(workerCtx, fmt.Sprintf("user shard %d", i+1), *cfg.Startup, pool.Ping); err != nil {
+			log.Fatal("Error connecting to user shard:", err)
+		}
+	}
+	for i, pool := range dualWritePools {
+		if err := health.WaitFor(workerCtx, fmt.Sprintf("dual write target database %d", i), *cfg.Startup, pool.Ping); err != nil {
+			log.Fatal("Error connecting to dual write target database:", err)
+		}
+	}
 	fmt.Println("Connected to database successfully")
 
-	startConnectServer(cfg, conn)
+	pingRedis := func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}
+	if err := health.WaitFor(workerCtx, "redis", *cfg.Startup, pingRedis); err != nil {
+		log.Fatal("Error connecting to redis:", err)
+	}
+	fmt.Println("Connected to redis successfully")
+
+	readiness.SetReady(true)
+
+	dispatcher := worker.NewWebhookDispatcher(webhookUseCase, cfg.Webhook.PollInterval, cfg.Webhook.BatchSize, cfg.Webhook.RequestTimeout)
+	go dispatcher.Run(workerCtx)
+
+	jobWorker := jobs.NewWorker(jobQueue, *cfg.Jobs, prometheus.DefaultRegisterer)
+	worker.RegisterJobHandlers(jobWorker, usecase.NewImportUseCase(repos.Users, jobQueue, webhookUseCase))
+	go jobWorker.Run(workerCtx)
+
+	taskScheduler := scheduler.New(*cfg.Scheduler, scheduler.NewRedisLocker(redisClient, "user-service:scheduler:"), prometheus.DefaultRegisterer)
+	retentionUseCase := usecase.NewRetentionUseCase(repos.SecurityEvents, repos.LoginLocations, usecase.RetentionPolicy{
+		SecurityEvents: cfg.Retention.SecurityEvents,
+		LoginLocations: cfg.Retention.LoginLocations,
+	})
+	phoneKeyRotation := postgres.NewPhoneKeyRotation(
+		postgres.NewShardRouter(append([]sqlc.DBTX{conn}, userShards...)),
+		fields,
+		cfg.Encryption.ReencryptBatchSize,
+		prometheus.DefaultRegisterer,
+	)
+	if err := worker.RegisterScheduledTasks(taskScheduler, cfg.Retention, cfg.TagRules, webhookUseCase, jobQueue, usecase.NewTagUseCase(repos.Users, repos.Tags), retentionUseCase, phoneKeyRotation, authAnomalies); err != nil {
+		log.Fatal("Error scheduling tasks:", err)
+	}
+	go taskScheduler.Run(workerCtx)
+
+	waitForShutdown(servers, stopWorkers)
 }
 
-func startConnectServer(cfg *config.Config, conn *pgx.Conn) {
-	server := connect.StartConnect(cfg, conn)
+func startConnectServer(
+	cfg *config.Config,
+	logger *slog.Logger,
+	readiness *health.Readiness,
+	rateLimitConfig func() *config.RateLimitConfig,
+	chaosConfig func() *interceptor.ChaosConfig,
+	sizeLimitConfig func() *interceptor.SizeLimitConfig,
+	conn *pgxpool.Pool,
+	repos postgres.UserRepositories,
+	fields *encryption.FieldCipher,
+	redisClient *redis.Client,
+	webhookUseCase *usecase.WebhookUseCase,
+	authAnomalies *usecase.AuthAnomalyDetector,
+	jobQueue *jobs.Queue,
+) []*http.Server {
+	server, err := connect.StartConnect(cfg, logger, readiness, rateLimitConfig, chaosConfig, sizeLimitConfig, conn, repos, fields, redisClient, webhookUseCase, authAnomalies, jobQueue)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
 	server.Addr = fmt.Sprintf(":%d", cfg.Server.Port)
+	servers := []*http.Server{server}
 
-	// handle graceful shutdown
 	go func() {
 		fmt.Println("Starting server on", server.Addr)
 
-		if err := server.ListenAndServe(); err != nil {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
-	// wait for shutdown signal
+	// internal listener for other services, authenticated by client certificate
+	if cfg.MTLS.Enabled {
+		tlsConfig, err := mtls.ServerTLSConfig(*cfg.MTLS)
+		if err != nil {
+			log.Fatalf("Failed to load mTLS config: %v", err)
+		}
+
+		internalServer := &http.Server{
+			Addr:      fmt.Sprintf(":%d", cfg.Server.InternalPort),
+			Handler:   mtls.Authorize(cfg.MTLS.Policies, server.Handler),
+			TLSConfig: tlsConfig,
+		}
+		servers = append(servers, internalServer)
+
+		go func() {
+			fmt.Println("Starting internal mTLS server on", internalServer.Addr)
+
+			if err := internalServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start internal server: %v", err)
+			}
+		}()
+	}
+
+	// diagnostics listener, kept off the public port
+	if cfg.Admin.Enabled {
+		adminServer, err := admin.NewServer(*cfg.Admin)
+		if err != nil {
+			log.Fatalf("Failed to create admin server: %v", err)
+		}
+		servers = append(servers, adminServer)
+
+		go func() {
+			fmt.Println("Starting admin server on", adminServer.Addr)
+
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start admin server: %v", err)
+			}
+		}()
+	}
+
+	return servers
+}
+
+// openDualWriteTarget connects to the database users are migrating to and
+// makes repos.Users mirror its writes there, or the other way round once
+// reads come from the target. It returns the target's pools.
+func openDualWriteTarget(cfg *config.DualWriteConfig, tracer pgx.QueryTracer, fields *encryption.FieldCipher, logger *slog.Logger, repos *postgres.UserRepositories) ([]*pgxpool.Pool, error) {
+	conn, err := postgres.NewConnection(context.Background(), &cfg.Target, tracer)
+	if err != nil {
+		return nil, err
+	}
+	shardPools, err := postgres.NewShardConnections(context.Background(), &cfg.Target, tracer)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	pools := append([]*pgxpool.Pool{conn}, shardPools...)
+	shards := make([]sqlc.DBTX, 0, len(shardPools))
+	for _, pool := range shardPools {
+		shards = append(shards, pool)
+	}
+
+	primary, mirror := repos.Users, postgres.NewUserRepositories(conn, shards, fields).Users
+	if cfg.ReadFromTarget {
+		primary, mirror = mirror, primary
+	}
+	repos.Users = dualwrite.NewUserRepository(primary, mirror, cfg.CompareReads, logger, prometheus.DefaultRegisterer)
+
+	return pools, nil
+}
+
+func setLogLevel(level *slog.LevelVar, name string) {
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		log.Printf("Invalid log_level %q, keeping %s", name, level.Level())
+	}
+}
+
+// waitForShutdown blocks until SIGINT/SIGTERM, then stops workers and
+// drains the servers.
+func waitForShutdown(servers []*http.Server, stopWorkers context.CancelFunc) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
+	stopWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	server.Shutdown(ctx)
+	for _, server := range servers {
+		server.Shutdown(ctx)
+	}
 
 	fmt.Println("Server gracefully stopped")
 }
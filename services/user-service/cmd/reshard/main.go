@@ -0,0 +1,143 @@
+// Command reshard moves users onto newly added user shards. Append the new
+// databases to database.user_shards, apply the migrations to them, pause
+// writes, then run it with the number of shards the running service uses:
+//
+//	go run ./cmd/reshard -from 2 -dry-run
+//	go run ./cmd/reshard -from 2
+//
+// and deploy the new shard list. An interrupted run can be repeated. When
+// sharding is first enabled on an existing database, run it once with
+// -backfill-directory to record every user's email in the directory.
+//
+// Run it with -normalize-emails to lower-case the emails stored before they
+// were normalized, on every shard at once, so users whose emails differ
+// only in case are found across shards and left for merging by hand:
+//
+//	go run ./cmd/reshard -normalize-emails -dry-run
+//
+// During a dual-write migration, run it with -backfill-dual-write to copy
+// the users created before dual writes were enabled to dual_write.target.
+// Repeat it to refresh the users reported as dual write mismatches.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+func main() {
+	from := flag.Int("from", 0, "number of shards users are currently placed on, counting the primary database")
+	batchSize := flag.Int("batch-size", 500, "users read per query")
+	dryRun := flag.Bool("dry-run", false, "count the users that would move, or whose email would be lower-cased, without changing them")
+	backfill := flag.Bool("backfill-directory", false, "add missing email directory entries instead of moving users")
+	normalize := flag.Bool("normalize-emails", false, "lower-case stored emails instead of moving users")
+	backfillDualWrite := flag.Bool("backfill-dual-write", false, "copy users missing or outdated on dual_write.target instead of moving users")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Error loading configuration:", err)
+	}
+
+	ctx := context.Background()
+	conn, err := postgres.NewConnection(ctx, cfg.Database, nil)
+	if err != nil {
+		log.Fatal("Error creating database pool:", err)
+	}
+	defer conn.Close()
+
+	shardPools, err := postgres.NewShardConnections(ctx, cfg.Database, nil)
+	if err != nil {
+		log.Fatal("Error creating user shard pools:", err)
+	}
+	shards := []sqlc.DBTX{conn}
+	for _, pool := range shardPools {
+		defer pool.Close()
+		shards = append(shards, pool)
+	}
+	router := postgres.NewShardRouter(shards)
+
+	if *backfillDualWrite {
+		if cfg.DualWrite == nil || !cfg.DualWrite.Enabled {
+			log.Fatal("Backfilling a dual-write target needs dual_write.enabled, so users written meanwhile are mirrored")
+		}
+		target, closeTarget, err := openRouter(ctx, &cfg.DualWrite.Target)
+		if err != nil {
+			log.Fatal("Error connecting to the dual-write target:", err)
+		}
+		defer closeTarget()
+
+		stats, err := postgres.BackfillDualWriteTarget(ctx, router, target, int32(*batchSize))
+		if err != nil {
+			log.Fatalf("Error backfilling the dual-write target after %d users: %v", stats.Scanned, err)
+		}
+		fmt.Printf("Copied %d of %d users to the dual-write target's %d shards\n", stats.Copied, stats.Scanned, len(target.Shards()))
+		return
+	}
+
+	if *backfill {
+		scanned, err := postgres.BackfillUserDirectory(ctx, router, int32(*batchSize))
+		if err != nil {
+			log.Fatal("Error backfilling user directory:", err)
+		}
+		fmt.Printf("Checked directory entries of %d users on %d shards\n", scanned, len(shards))
+		return
+	}
+
+	if *normalize {
+		stats, err := postgres.NormalizeEmails(ctx, router, int32(*batchSize), *dryRun)
+		if err != nil {
+			log.Fatalf("Error normalizing emails after %d users: %v", stats.Normalized, err)
+		}
+		verb := "Lower-cased"
+		if *dryRun {
+			verb = "Would lower-case"
+		}
+		fmt.Printf("%s the emails of %d of %d users on %d shards; %d differ from another user's only in case and were left as they are\n", verb, stats.Normalized, stats.Scanned, len(shards), stats.Clashing)
+		return
+	}
+
+	stats, err := postgres.Reshard(ctx, router, *from, int32(*batchSize), *dryRun)
+	if err != nil {
+		log.Fatalf("Error resharding after %d moves: %v", stats.Moved, err)
+	}
+
+	verb := "Moved"
+	if *dryRun {
+		verb = "Would move"
+	}
+	fmt.Printf("%s %d of %d users from %d to %d shards\n", verb, stats.Moved, stats.Scanned, *from, len(shards))
+}
+
+// openRouter connects to the database of cfg and its user shards, and
+// returns a router over them and a func closing them.
+func openRouter(ctx context.Context, cfg *config.DatabaseConfig) (*postgres.ShardRouter, func(), error) {
+	conn, err := postgres.NewConnection(ctx, cfg, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	shardPools, err := postgres.NewShardConnections(ctx, cfg, nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	shards := []sqlc.DBTX{conn}
+	for _, pool := range shardPools {
+		shards = append(shards, pool)
+	}
+	closeAll := func() {
+		conn.Close()
+		for _, pool := range shardPools {
+			pool.Close()
+		}
+	}
+
+	return postgres.NewShardRouter(shards), closeAll, nil
+}
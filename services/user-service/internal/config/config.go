@@ -1,21 +1,90 @@
 package config
 
 import (
-	"fmt"
-	"strings"
+	"time"
 
-	"github.com/spf13/viper"
+	"github.com/phongloihong/go-shop/pkg/admin"
+	sharedconfig "github.com/phongloihong/go-shop/pkg/config"
+	"github.com/phongloihong/go-shop/pkg/cors"
+	"github.com/phongloihong/go-shop/pkg/health"
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/pkg/mtls"
+	"github.com/phongloihong/go-shop/pkg/ratelimit"
+	"github.com/phongloihong/go-shop/pkg/scheduler"
 )
 
+// EnvironmentProduction is the Environment value of production deployments.
+const EnvironmentProduction = "production"
+
 type Config struct {
+	// Environment is "development", "staging" or "production".
+	Environment string `mapstructure:"environment"`
+	// LogLevel is "debug", "info", "warn" or "error". At debug, RPC payloads
+	// are logged with sensitive fields redacted.
+	LogLevel string `mapstructure:"log_level"`
+	// Region is the deployment region, e.g. "ap-southeast-1". It tags
+	// published events and log lines; empty when running in one region.
+	Region string `mapstructure:"region"`
+
 	Server   *ServerConfig   `mapstructure:"server"`
 	Database *DatabaseConfig `mapstructure:"database"`
 	Redis    *RedisConfig    `mapstructure:"redis"`
 	Auth     *AuthConfig     `mapstructure:"auth"`
+	// Encryption encrypts personal data columns before they are stored.
+	Encryption *EncryptionConfig `mapstructure:"encryption"`
+	Email      *EmailConfig      `mapstructure:"email"`
+	LoginRisk  *LoginRiskConfig  `mapstructure:"login_risk"`
+	// AuthAnomaly sets when logins and registrations look like an attack,
+	// which then need a CAPTCHA checked as Captcha says.
+	AuthAnomaly *AuthAnomalyConfig `mapstructure:"auth_anomaly"`
+	Captcha     *CaptchaConfig     `mapstructure:"captcha"`
+	// Notification is where secrets for users, such as login codes and
+	// magic links, are sent to be emailed.
+	Notification *NotificationConfig `mapstructure:"notification"`
+	MagicLink    *MagicLinkConfig    `mapstructure:"magic_link"`
+	SSO          *SSOConfig          `mapstructure:"sso"`
+	ReadModel    *ReadModelConfig    `mapstructure:"read_model"`
+	Webhook      *WebhookConfig      `mapstructure:"webhook"`
+	RateLimit    *RateLimitConfig    `mapstructure:"rate_limit"`
+	// RequestSize sets tighter per-procedure request limits below
+	// Server.MaxMessageBytes.
+	RequestSize *interceptor.SizeLimitConfig `mapstructure:"request_size"`
+	MTLS        *mtls.Config                 `mapstructure:"mtls"`
+	// CORS lets browser apps on other origins call the public port.
+	CORS *cors.Config `mapstructure:"cors"`
+	// Admin serves pprof and expvar on a separate, token-protected port.
+	Admin *admin.Config `mapstructure:"admin"`
+	// Jobs configures the background job worker.
+	Jobs *jobs.WorkerConfig `mapstructure:"jobs"`
+	// Scheduler sets when recurring maintenance tasks run.
+	Scheduler *scheduler.Config `mapstructure:"scheduler"`
+	Retention *RetentionConfig  `mapstructure:"retention"`
+	// TagRules tag the users of a segment on every apply_tag_rules run.
+	TagRules []TagRuleConfig `mapstructure:"tag_rules"`
+	// DualWrite mirrors user writes to another database during a
+	// migration.
+	DualWrite *DualWriteConfig `mapstructure:"dual_write"`
+	// Chaos injects faults for resilience testing; ignored in production.
+	Chaos *interceptor.ChaosConfig `mapstructure:"chaos"`
+	// Startup bounds how long to wait for the database and redis on boot.
+	Startup *health.RetryPolicy `mapstructure:"startup"`
 }
 
 type ServerConfig struct {
 	Port int `mapstructure:"port"`
+	// InternalPort serves service-to-service calls over mTLS when enabled.
+	InternalPort int `mapstructure:"internal_port"`
+	// CompressMinBytes is the smallest response sent gzip or zstd
+	// compressed, when the client accepts it.
+	CompressMinBytes int `mapstructure:"compress_min_bytes"`
+	// MaxMessageBytes caps every request and response message; larger
+	// requests are rejected before they are decoded.
+	MaxMessageBytes int `mapstructure:"max_message_bytes"`
+	// TrustedProxies are the CIDRs of the load balancers in front of the
+	// service, whose X-Forwarded-For is believed. Empty trusts none, so
+	// callers are known by their peer address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -24,6 +93,30 @@ type DatabaseConfig struct {
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"db_name"`
+	// StatementTimeout makes the server cancel any single query running
+	// longer; zero leaves the server default.
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	// SlowQueryThreshold logs queries taking at least this long; zero
+	// disables the log.
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+	// UserShards are further databases users are spread over, by a hash of
+	// their ID; this database is shard 0. Append only: adding a shard needs
+	// a cmd/reshard run, and removing or reordering one loses users.
+	UserShards []DatabaseConfig `mapstructure:"user_shards"`
+}
+
+// DualWriteConfig mirrors the writes of users to Target while they are
+// migrated to it, e.g. a redesigned users table or another shard layout.
+type DualWriteConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Target is the database migrated to, with user shards of its own.
+	Target DatabaseConfig `mapstructure:"target"`
+	// ReadFromTarget serves reads from Target, and mirrors writes back to
+	// the current database, for the second half of a migration.
+	ReadFromTarget bool `mapstructure:"read_from_target"`
+	// CompareReads repeats reads of users on the mirror in the background
+	// and logs the ones that differ.
+	CompareReads bool `mapstructure:"compare_reads"`
 }
 
 type RedisConfig struct {
@@ -39,23 +132,163 @@ type AuthConfig struct {
 	RefreshSecret  string `mapstructure:"refresh_secret"`
 }
 
-func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./internal/config")
+// EncryptionConfig sets the keys personal data columns are encrypted with.
+type EncryptionConfig struct {
+	// MasterKeys wrap data keys. Keep a key listed until reencrypt_fields
+	// has moved every value off it.
+	MasterKeys []MasterKeyConfig `mapstructure:"master_keys"`
+	// KeyVersion is the version of the master key new values use.
+	KeyVersion int32 `mapstructure:"key_version"`
+	// HashKey keys the hashes encrypted columns are looked up by. Changing
+	// it breaks lookups until every row is re-hashed.
+	HashKey string `mapstructure:"hash_key"`
+	// DataKeyTTL is how long one data key encrypts new values before the
+	// next is generated.
+	DataKeyTTL time.Duration `mapstructure:"data_key_ttl"`
+	// ReencryptBatchSize is how many rows reencrypt_fields reads at a time.
+	ReencryptBatchSize int32 `mapstructure:"reencrypt_batch_size"`
+}
 
-	// Enable automatic environment vars
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+// MasterKeyConfig is the base64 of a 32-byte master key and its version.
+type MasterKeyConfig struct {
+	Version int32  `mapstructure:"version"`
+	Key     string `mapstructure:"key"`
+}
 
-	// Read config file
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
-	}
+// EmailConfig sets which emails may register.
+type EmailConfig struct {
+	// RejectPlusAliases refuses an email that differs from a registered one
+	// only in a "+tag".
+	RejectPlusAliases bool `mapstructure:"reject_plus_aliases"`
+	// BlockedDomains are refused with their subdomains.
+	BlockedDomains []string `mapstructure:"blocked_domains"`
+}
+
+// LoginRiskConfig sets how logins from unusual countries are challenged.
+type LoginRiskConfig struct {
+	// GeoIPURL is the lookup URL with "{ip}" in place of the address, e.g.
+	// "https://ipinfo.io/{ip}/json?token=...". Empty disables the checks.
+	GeoIPURL     string        `mapstructure:"geoip_url"`
+	GeoIPTimeout time.Duration `mapstructure:"geoip_timeout"`
+	// CodeTTL is how long an emailed verification code can be used.
+	CodeTTL time.Duration `mapstructure:"code_ttl"`
+}
+
+// AuthAnomalyConfig sets when failed logins and registrations are an
+// attack; see usecase.AuthAnomalyPolicy.
+type AuthAnomalyConfig struct {
+	StuffingWindow   time.Duration `mapstructure:"stuffing_window"`
+	MaxEmailsPerIP   int64         `mapstructure:"max_emails_per_ip"`
+	MaxEmailsPerASN  int64         `mapstructure:"max_emails_per_asn"`
+	SpikeWindow      time.Duration `mapstructure:"spike_window"`
+	BaselineWindows  int           `mapstructure:"baseline_windows"`
+	SpikeFactor      float64       `mapstructure:"spike_factor"`
+	MinFailedLogins  int64         `mapstructure:"min_failed_logins"`
+	MinRegistrations int64         `mapstructure:"min_registrations"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
+}
+
+// CaptchaConfig sets where CAPTCHA tokens are verified.
+type CaptchaConfig struct {
+	// VerifyURL is a siteverify endpoint, e.g.
+	// "https://challenges.cloudflare.com/turnstile/v0/siteverify". Empty
+	// VerifyURL or Secret turns the CAPTCHA requirement off.
+	VerifyURL string        `mapstructure:"verify_url"`
+	Secret    string        `mapstructure:"secret"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// NotificationConfig sets the notification service endpoint that login codes
+// and magic links are posted to, signed with Secret. Empty URL or Secret
+// makes challenged logins and magic link requests fail, as nothing can be
+// sent.
+type NotificationConfig struct {
+	URL     string        `mapstructure:"url"`
+	Secret  string        `mapstructure:"secret"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
 
+// MagicLinkConfig sets the emailed sign-in links of RequestMagicLink.
+type MagicLinkConfig struct {
+	// Secret signs the link tokens. Empty disables magic links.
+	Secret string `mapstructure:"secret"`
+	// URL is the storefront page that calls ConsumeMagicLink with the
+	// "token" query parameter of the link.
+	URL string        `mapstructure:"url"`
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// SSOConfig sets the sign-ins of StartSsoLogin and FinishSsoLogin.
+type SSOConfig struct {
+	// RedirectURL is the storefront page that calls FinishSsoLogin with the
+	// "state" and "code" query parameters the IdP redirects with.
+	RedirectURL string        `mapstructure:"redirect_url"`
+	StateTTL    time.Duration `mapstructure:"state_ttl"`
+	HTTPTimeout time.Duration `mapstructure:"http_timeout"`
+}
+
+// ReadModelConfig sets the denormalized copies that reads are served from.
+type ReadModelConfig struct {
+	// ProfileTTL is how long a profile is kept after its last write. It
+	// also bounds how stale a profile gets when an update fails to reach
+	// the read model.
+	ProfileTTL time.Duration `mapstructure:"profile_ttl"`
+}
+
+type WebhookConfig struct {
+	MaxAttempts    int32         `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	BatchSize      int32         `mapstructure:"batch_size"`
+}
+
+// RetentionConfig sets how long finished records and personal data are kept
+// before the scheduled prune and purge tasks delete them.
+type RetentionConfig struct {
+	WebhookDeliveries time.Duration `mapstructure:"webhook_deliveries"`
+	Jobs              time.Duration `mapstructure:"jobs"`
+	// SecurityEvents and LoginLocations are purged by purge_expired_data;
+	// zero keeps them forever.
+	SecurityEvents time.Duration `mapstructure:"security_events"`
+	LoginLocations time.Duration `mapstructure:"login_locations"`
+	// DryRun makes purge_expired_data report what it would delete instead.
+	DryRun bool `mapstructure:"dry_run"`
+}
+
+// TagRuleConfig gives Tag to the users matching Filter, a ListUsers filter,
+// and who have not changed their profile for InactiveFor, when set. Users
+// that stop matching lose the tag on the next run.
+type TagRuleConfig struct {
+	Tag         string        `mapstructure:"tag"`
+	Filter      string        `mapstructure:"filter"`
+	InactiveFor time.Duration `mapstructure:"inactive_for"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool            `mapstructure:"enabled"`
+	Default ratelimit.Quota `mapstructure:"default"`
+	// Procedures overrides Default per method, keyed by lower-case method
+	// name (e.g. "login").
+	Procedures map[string]ratelimit.Quota `mapstructure:"procedures"`
+	// Tightened replaces the quota of a method, keyed like Procedures, for
+	// callers from a flagged address and for everyone while logins or
+	// registrations spike.
+	Tightened map[string]ratelimit.Quota `mapstructure:"tightened"`
+}
+
+// Watch loads the config and returns a watcher that reloads it on file
+// change or SIGHUP. Only LogLevel, RateLimit, RequestSize and Chaos are applied
+// live; other sections are read once at startup.
+func Watch() (*sharedconfig.Watcher[Config], error) {
+	return sharedconfig.Watch[Config](sharedconfig.WithPath("./internal/config"))
+}
+
+func Load() (*Config, error) {
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+	if err := sharedconfig.Load(&config, sharedconfig.WithPath("./internal/config")); err != nil {
+		return nil, err
 	}
 
 	return &config, nil
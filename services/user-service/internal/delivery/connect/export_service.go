@@ -0,0 +1,57 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/phongloihong/go-shop/api/gen/user/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// exportServiceHandler streams bulk exports to internal callers. It is served
+// only on the internal mTLS listener.
+type exportServiceHandler struct {
+	exportUseCase *usecase.ExportUseCase
+}
+
+func NewExportServiceHandler(exportUseCase *usecase.ExportUseCase) *exportServiceHandler {
+	return &exportServiceHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+func (h *exportServiceHandler) ExportUsers(ctx context.Context, req *connect.Request[userv1.ExportUsersRequest], stream *connect.ServerStream[userv1.ExportUsersResponse]) error {
+	err := h.exportUseCase.ExportUsers(ctx, dto.ExportUsersRequest{
+		AfterID:   req.Msg.AfterId,
+		BatchSize: req.Msg.BatchSize,
+	}, func(users []*entity.User) error {
+		res := &userv1.ExportUsersResponse{
+			Users: make([]*userv1.ExportedUser, 0, len(users)),
+		}
+		for _, user := range users {
+			res.Users = append(res.Users, exportedUserToProto(user))
+		}
+
+		return stream.Send(res)
+	})
+	if err != nil {
+		return domain_error.MapError(err)
+	}
+
+	return nil
+}
+
+func exportedUserToProto(user *entity.User) *userv1.ExportedUser {
+	return &userv1.ExportedUser{
+		Id:        user.ID,
+		Email:     user.Email.String(),
+		Phone:     user.Phone.String(),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: user.CreatedAt.Unix(),
+		UpdatedAt: user.UpdatedAt.Unix(),
+	}
+}
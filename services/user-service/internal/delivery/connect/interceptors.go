@@ -2,21 +2,78 @@ package connect
 
 import (
 	"context"
-	"log"
 
 	"connectrpc.com/connect"
+	"github.com/phongloihong/go-shop/api/gen/jobs/v1/jobsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/operations/v1/operationsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v1/userv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
 )
 
-func newRecoverInterceptors() connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			defer func() {
-				if r := recover(); r != nil {
-					log.Printf("Recovered from panic: %v", r)
-				}
-			}()
+// publicProcedures can be called without an access token.
+var publicProcedures = []string{
+	userv1connect.UserServiceRegisterProcedure,
+	userv1connect.UserServiceLoginProcedure,
+	userv1connect.UserServiceGetPublicProfileProcedure,
+	userv2connect.UserServiceRegisterProcedure,
+	userv2connect.UserServiceCreateGuestTokenProcedure,
+	userv2connect.UserServiceLoginProcedure,
+	userv2connect.UserServiceVerifyLoginProcedure,
+	userv2connect.UserServiceRequestMagicLinkProcedure,
+	userv2connect.UserServiceConsumeMagicLinkProcedure,
+	userv2connect.UserServiceStartSsoLoginProcedure,
+	userv2connect.UserServiceFinishSsoLoginProcedure,
+	userv2connect.UserServiceBatchGetPublicProfilesProcedure,
+	// CheckNotificationAllowed and the admin, job and operation services carry
+	// no access token: they are served to mTLS callers only, see StartConnect
+	userv1connect.UserServiceCheckNotificationAllowedProcedure,
+	userv2connect.UserServiceCheckNotificationAllowedProcedure,
+	userv2connect.UserAdminServiceListUsersProcedure,
+	userv2connect.UserAdminServiceBatchGetUsersProcedure,
+	userv2connect.UserAdminServiceImportUsersProcedure,
+	userv2connect.UserAdminServiceDeleteUserProcedure,
+	userv2connect.UserAdminServiceGetUserTagsProcedure,
+	userv2connect.UserAdminServiceAddUserTagsProcedure,
+	userv2connect.UserAdminServiceRemoveUserTagsProcedure,
+	userv2connect.UserAdminServiceListConsentRecordsProcedure,
+	userv2connect.UserAdminServiceGetUserSecurityEventsProcedure,
+	userv2connect.UserAdminServiceGetLegalHoldProcedure,
+	userv2connect.UserAdminServicePlaceLegalHoldProcedure,
+	userv2connect.UserAdminServiceReleaseLegalHoldProcedure,
+	userv2connect.UserAdminServiceCreateSsoConnectionProcedure,
+	userv2connect.UserAdminServiceListSsoConnectionsProcedure,
+	userv2connect.UserAdminServiceDeleteSsoConnectionProcedure,
+	userv2connect.UserAdminServiceCreateScimTokenProcedure,
+	userv2connect.WebhookAdminServiceCreateWebhookSubscriptionProcedure,
+	userv2connect.WebhookAdminServiceListWebhookSubscriptionsProcedure,
+	userv2connect.WebhookAdminServiceDeleteWebhookSubscriptionProcedure,
+	userv2connect.WebhookAdminServiceListWebhookDeliveriesProcedure,
+	userv2connect.WebhookAdminServiceRetryWebhookDeliveryProcedure,
+	jobsv1connect.JobServiceGetJobProcedure,
+	jobsv1connect.JobServiceListJobsProcedure,
+	jobsv1connect.JobServiceRetryJobProcedure,
+	jobsv1connect.JobServiceCancelJobProcedure,
+	operationsv1connect.OperationServiceGetOperationProcedure,
+	operationsv1connect.OperationServiceCancelOperationProcedure,
+}
+
+func newAuthInterceptor(authService service.AuthService, accessSecret []byte) connect.UnaryInterceptorFunc {
+	validate := func(token string) (*service.TokenClaims, error) {
+		return authService.ValidateToken(token, accessSecret)
+	}
 
-			return next(ctx, req)
-		}
+	return interceptor.NewAuthInterceptor(validate, publicProcedures...)
+}
+
+// userIDFromContext returns the ID of the authenticated caller set by the auth interceptor.
+func userIDFromContext(ctx context.Context) (string, error) {
+	claims, ok := interceptor.ClaimsFromContext[*service.TokenClaims](ctx)
+	if !ok || claims.UserID == "" {
+		return "", domain_error.NewUnauthorizedError("unauthenticated")
 	}
+
+	return claims.UserID, nil
 }
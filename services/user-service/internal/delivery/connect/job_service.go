@@ -0,0 +1,118 @@
+package connect
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	jobsv1 "github.com/phongloihong/go-shop/api/gen/jobs/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+)
+
+// jobServiceHandler exposes the job queue to operators and the admin
+// service. It is served only on the internal mTLS listener.
+type jobServiceHandler struct {
+	queue *jobs.Queue
+}
+
+func NewJobServiceHandler(queue *jobs.Queue) *jobServiceHandler {
+	return &jobServiceHandler{
+		queue: queue,
+	}
+}
+
+func (h *jobServiceHandler) GetJob(ctx context.Context, req *connect.Request[jobsv1.GetJobRequest]) (*connect.Response[jobsv1.GetJobResponse], error) {
+	job, err := h.queue.Get(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, domain_error.MapError(jobError(err))
+	}
+
+	return connect.NewResponse(&jobsv1.GetJobResponse{
+		Job: jobToProto(job),
+	}), nil
+}
+
+func (h *jobServiceHandler) ListJobs(ctx context.Context, req *connect.Request[jobsv1.ListJobsRequest]) (*connect.Response[jobsv1.ListJobsResponse], error) {
+	where, err := filter.Parse(req.Msg.Filter, jobs.FilterSchema)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	list, err := h.queue.List(ctx, jobs.ListFilter{
+		Kind:     req.Msg.Kind,
+		Status:   jobs.Status(req.Msg.Status),
+		Where:    where,
+		BeforeID: req.Msg.BeforeId,
+		Limit:    req.Msg.Limit,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(jobError(err))
+	}
+
+	ret := &jobsv1.ListJobsResponse{
+		Jobs: make([]*jobsv1.Job, 0, len(list)),
+	}
+	for _, job := range list {
+		ret.Jobs = append(ret.Jobs, jobToProto(job))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *jobServiceHandler) RetryJob(ctx context.Context, req *connect.Request[jobsv1.RetryJobRequest]) (*connect.Response[jobsv1.RetryJobResponse], error) {
+	job, err := h.queue.Retry(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, domain_error.MapError(jobError(err))
+	}
+
+	return connect.NewResponse(&jobsv1.RetryJobResponse{
+		Job: jobToProto(job),
+	}), nil
+}
+
+func (h *jobServiceHandler) CancelJob(ctx context.Context, req *connect.Request[jobsv1.CancelJobRequest]) (*connect.Response[jobsv1.CancelJobResponse], error) {
+	job, err := h.queue.Cancel(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, domain_error.MapError(jobError(err))
+	}
+
+	return connect.NewResponse(&jobsv1.CancelJobResponse{
+		Job: jobToProto(job),
+	}), nil
+}
+
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return domain_error.New(domain_error.ReasonJobNotFound)
+	case errors.Is(err, jobs.ErrInvalidState):
+		return domain_error.New(domain_error.ReasonJobInvalidState)
+	default:
+		if ctxErr, ok := domain_error.FromContext(err); ok {
+			return ctxErr
+		}
+		return domain_error.NewInternalError(err.Error())
+	}
+}
+
+func jobToProto(job *jobs.Job) *jobsv1.Job {
+	ret := &jobsv1.Job{
+		Id:          job.ID,
+		Kind:        job.Kind,
+		Args:        string(job.Args),
+		Status:      string(job.Status),
+		Attempt:     job.Attempt,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt.Unix(),
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt.Unix(),
+		Result:      string(job.Result),
+	}
+	if job.FinishedAt != nil {
+		ret.FinishedAt = job.FinishedAt.Unix()
+	}
+
+	return ret
+}
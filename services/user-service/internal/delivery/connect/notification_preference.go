@@ -0,0 +1,116 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/phongloihong/go-shop/api/gen/user/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+var (
+	channelFromProto = map[userv1.NotificationChannel]valueobject.NotificationChannel{
+		userv1.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL: valueobject.ChannelEmail,
+		userv1.NotificationChannel_NOTIFICATION_CHANNEL_SMS:   valueobject.ChannelSMS,
+		userv1.NotificationChannel_NOTIFICATION_CHANNEL_PUSH:  valueobject.ChannelPush,
+	}
+	categoryFromProto = map[userv1.NotificationCategory]valueobject.NotificationCategory{
+		userv1.NotificationCategory_NOTIFICATION_CATEGORY_TRANSACTIONAL: valueobject.CategoryTransactional,
+		userv1.NotificationCategory_NOTIFICATION_CATEGORY_MARKETING:     valueobject.CategoryMarketing,
+	}
+)
+
+func (h *userServiceHandler) GetNotificationPreferences(ctx context.Context, req *connect.Request[userv1.GetNotificationPreferencesRequest]) (*connect.Response[userv1.GetNotificationPreferencesResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	prefs, err := h.notificationPreferenceUseCase.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.GetNotificationPreferencesResponse{
+		Preferences: preferencesToProto(prefs),
+	}), nil
+}
+
+func (h *userServiceHandler) UpdateNotificationPreferences(ctx context.Context, req *connect.Request[userv1.UpdateNotificationPreferencesRequest]) (*connect.Response[userv1.UpdateNotificationPreferencesResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	params := dto.UpdateNotificationPreferencesRequest{
+		UserID:      userID,
+		Preferences: make([]dto.NotificationPreference, 0, len(req.Msg.Preferences)),
+	}
+	for _, p := range req.Msg.Preferences {
+		params.Preferences = append(params.Preferences, dto.NotificationPreference{
+			Channel:  channelFromProto[p.Channel].String(),
+			Category: categoryFromProto[p.Category].String(),
+			Enabled:  p.Enabled,
+		})
+	}
+
+	prefs, err := h.notificationPreferenceUseCase.UpdatePreferences(ctx, params)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.UpdateNotificationPreferencesResponse{
+		Preferences: preferencesToProto(prefs),
+	}), nil
+}
+
+func (h *userServiceHandler) CheckNotificationAllowed(ctx context.Context, req *connect.Request[userv1.CheckNotificationAllowedRequest]) (*connect.Response[userv1.CheckNotificationAllowedResponse], error) {
+	allowed, err := h.notificationPreferenceUseCase.IsAllowed(ctx, dto.CheckNotificationAllowedRequest{
+		UserID:   req.Msg.UserId,
+		Channel:  channelFromProto[req.Msg.Channel].String(),
+		Category: categoryFromProto[req.Msg.Category].String(),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.CheckNotificationAllowedResponse{
+		Allowed: allowed,
+	}), nil
+}
+
+func preferencesToProto(prefs []*entity.NotificationPreference) []*userv1.NotificationPreference {
+	ret := make([]*userv1.NotificationPreference, 0, len(prefs))
+	for _, pref := range prefs {
+		ret = append(ret, &userv1.NotificationPreference{
+			Channel:  channelToProto(pref.Channel),
+			Category: categoryToProto(pref.Category),
+			Enabled:  pref.Enabled,
+		})
+	}
+
+	return ret
+}
+
+func channelToProto(channel valueobject.NotificationChannel) userv1.NotificationChannel {
+	for k, v := range channelFromProto {
+		if v == channel {
+			return k
+		}
+	}
+
+	return userv1.NotificationChannel_NOTIFICATION_CHANNEL_UNSPECIFIED
+}
+
+func categoryToProto(category valueobject.NotificationCategory) userv1.NotificationCategory {
+	for k, v := range categoryFromProto {
+		if v == category {
+			return k
+		}
+	}
+
+	return userv1.NotificationCategory_NOTIFICATION_CATEGORY_UNSPECIFIED
+}
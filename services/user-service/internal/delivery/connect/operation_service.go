@@ -0,0 +1,144 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	operationsv1 "github.com/phongloihong/go-shop/api/gen/operations/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// operationResponses turn the result of a completed job into the response
+// message of the method that started it. Jobs of other kinds are not
+// operations.
+var operationResponses = map[string]func(result json.RawMessage) (proto.Message, error){
+	usecase.ImportUsersJobKind: importUsersResponseToProto,
+}
+
+var operationStates = map[jobs.Status]operationsv1.Operation_State{
+	jobs.StatusPending:   operationsv1.Operation_STATE_PENDING,
+	jobs.StatusRunning:   operationsv1.Operation_STATE_RUNNING,
+	jobs.StatusCompleted: operationsv1.Operation_STATE_SUCCEEDED,
+	jobs.StatusDead:      operationsv1.Operation_STATE_FAILED,
+	jobs.StatusCancelled: operationsv1.Operation_STATE_CANCELLED,
+}
+
+// operationServiceHandler serves the operations started by this service's
+// methods, which run as jobs. It is served only on the internal mTLS
+// listener.
+type operationServiceHandler struct {
+	queue *jobs.Queue
+}
+
+func NewOperationServiceHandler(queue *jobs.Queue) *operationServiceHandler {
+	return &operationServiceHandler{
+		queue: queue,
+	}
+}
+
+func (h *operationServiceHandler) GetOperation(ctx context.Context, req *connect.Request[operationsv1.GetOperationRequest]) (*connect.Response[operationsv1.Operation], error) {
+	job, err := h.getOperationJob(ctx, req.Msg.Name)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret, err := operationToProto(job)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *operationServiceHandler) CancelOperation(ctx context.Context, req *connect.Request[operationsv1.CancelOperationRequest]) (*connect.Response[operationsv1.Operation], error) {
+	job, err := h.getOperationJob(ctx, req.Msg.Name)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	cancelled, err := h.queue.Cancel(ctx, job.ID)
+	switch {
+	case err == nil:
+		job = cancelled
+	case errors.Is(err, jobs.ErrInvalidState):
+		// already done; cancelling is best effort, so report how it ended
+		if job, err = h.queue.Get(ctx, job.ID); err != nil {
+			return nil, domain_error.MapError(jobError(err))
+		}
+	default:
+		return nil, domain_error.MapError(jobError(err))
+	}
+
+	ret, err := operationToProto(job)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+// getOperationJob returns the job of the operation called name, failing with
+// OPERATION_NOT_FOUND for unknown names and jobs that are not operations.
+func (h *operationServiceHandler) getOperationJob(ctx context.Context, name string) (*jobs.Job, error) {
+	idText, ok := strings.CutPrefix(name, "operations/")
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if !ok || err != nil || id <= 0 {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("name", `must be "operations/{id}"`))
+	}
+
+	job, err := h.queue.Get(ctx, id)
+	if errors.Is(err, jobs.ErrNotFound) {
+		return nil, domain_error.New(domain_error.ReasonOperationNotFound)
+	}
+	if err != nil {
+		return nil, jobError(err)
+	}
+	if _, ok := operationResponses[job.Kind]; !ok {
+		return nil, domain_error.New(domain_error.ReasonOperationNotFound)
+	}
+
+	return job, nil
+}
+
+func operationToProto(job *jobs.Job) (*operationsv1.Operation, error) {
+	ret := &operationsv1.Operation{
+		Name:       fmt.Sprintf("operations/%d", job.ID),
+		State:      operationStates[job.Status],
+		CreateTime: timestamppb.New(job.CreatedAt),
+	}
+	if job.FinishedAt != nil {
+		ret.Done = true
+		ret.EndTime = timestamppb.New(*job.FinishedAt)
+	}
+
+	switch job.Status {
+	case jobs.StatusDead:
+		ret.Result = &operationsv1.Operation_Error{Error: job.LastError}
+	case jobs.StatusCompleted:
+		toProto, ok := operationResponses[job.Kind]
+		if !ok || job.Result == nil {
+			break
+		}
+		response, err := toProto(job.Result)
+		if err != nil {
+			return nil, domain_error.NewInternalError(fmt.Sprintf("failed to decode result of operation %d: %s", job.ID, err.Error()))
+		}
+		packed, err := anypb.New(response)
+		if err != nil {
+			return nil, domain_error.NewInternalError(fmt.Sprintf("failed to pack result of operation %d: %s", job.ID, err.Error()))
+		}
+		ret.Result = &operationsv1.Operation_Response{Response: packed}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,29 @@
+package connect
+
+import (
+	"testing"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+func TestRateLimitSubject(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *service.TokenClaims
+		ip     string
+		want   string
+	}{
+		{name: "anonymous", ip: "203.0.113.7", want: "ip:203.0.113.7"},
+		{name: "user", claims: &service.TokenClaims{UserID: "u1", TokenID: "t1"}, ip: "203.0.113.7", want: "user:u1"},
+		{name: "guest", claims: &service.TokenClaims{GuestID: "g1", TokenID: "t1"}, ip: "203.0.113.7", want: "guest:g1:ip:203.0.113.7"},
+		{name: "user wins over guest", claims: &service.TokenClaims{UserID: "u1", GuestID: "g1"}, ip: "203.0.113.7", want: "user:u1"},
+		{name: "claims without a subject", claims: &service.TokenClaims{TokenID: "t1"}, ip: "203.0.113.7", want: "ip:203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitSubject(tt.claims, tt.ip); got != tt.want {
+				t.Errorf("rateLimitSubject(%+v, %q) = %q, want %q", tt.claims, tt.ip, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,37 +1,231 @@
 package connect
 
 import (
+	"log/slog"
 	"net/http"
 	"time"
 
 	"connectrpc.com/connect"
-	"github.com/phongloihong/go-shop/services/user-service/external/gen/user/v1/userv1connect"
+	"github.com/phongloihong/go-shop/api/compression"
+	"github.com/phongloihong/go-shop/api/gen/jobs/v1/jobsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/operations/v1/operationsv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v1/userv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+	"github.com/phongloihong/go-shop/api/redact"
+	"github.com/phongloihong/go-shop/api/transcode"
+	"github.com/phongloihong/go-shop/pkg/cors"
+	"github.com/phongloihong/go-shop/pkg/health"
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/pkg/mtls"
+	"github.com/phongloihong/go-shop/pkg/ratelimit"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
 	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/delivery/scim"
 	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/auth"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/cache"
 	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
 	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/geoip"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/notification"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/oidc"
 	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/redis/go-redis/v9"
 )
 
-func StartConnect(cfg *config.Config, dbConn sqlc.DBTX) *http.Server {
+// userV1Deprecation announces the sunset of user.v1.UserService to its
+// callers; see docs/apis/user-management.md for the migration.
+var userV1Deprecation = interceptor.Deprecation{
+	Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+	Sunset:    time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+	Successor: userv2connect.UserServiceName,
+}
+
+func StartConnect(
+	cfg *config.Config,
+	logger *slog.Logger,
+	readiness *health.Readiness,
+	rateLimitConfig func() *config.RateLimitConfig,
+	chaosConfig func() *interceptor.ChaosConfig,
+	sizeLimitConfig func() *interceptor.SizeLimitConfig,
+	dbConn sqlc.DBTX,
+	repos postgres.UserRepositories,
+	fields *encryption.FieldCipher,
+	redisClient *redis.Client,
+	webhookUseCase *usecase.WebhookUseCase,
+	authAnomalies *usecase.AuthAnomalyDetector,
+	jobQueue *jobs.Queue,
+) (*http.Server, error) {
 	mux := http.NewServeMux()
 
-	// create interceptors
-	interceptors := connect.WithInterceptors(
-		newRecoverInterceptors(),
-	)
+	trustedProxies, err := interceptor.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 
 	authService := auth.NewJWTService(
 		[]byte(cfg.Auth.AccessSecret),
 		[]byte(cfg.Auth.RefreshSecret),
-		time.Duration(30*time.Minute), // expires in 30 minutes
-		time.Duration(7*24*time.Hour), // expires in 7 days
+		time.Duration(30*time.Minute),  // expires in 30 minutes
+		time.Duration(7*24*time.Hour),  // expires in 7 days
+		time.Duration(30*24*time.Hour), // guest tokens expire in 30 days
+	)
+
+	userRepo := repos.Users
+	// user events update the profile read model as well as the webhooks; the
+	// prefix moved when phone numbers were encrypted in it, leaving the
+	// plaintext profiles to expire
+	profileReadModel := cache.NewProfileReadModel(redisClient, "user-service:profile:v2:", cfg.ReadModel.ProfileTTL, fields)
+	events := usecase.EventPublishers{webhookUseCase, usecase.NewProfileProjection(profileReadModel)}
+	securityEventUseCase := usecase.NewSecurityEventUseCase(userRepo, repos.SecurityEvents, events)
+	backupCodeUseCase := usecase.NewBackupCodeUseCase(userRepo, repos.BackupCodes, securityEventUseCase)
+	notifier := notification.NewHTTPNotifier(cfg.Notification.URL, cfg.Notification.Secret, cfg.Notification.Timeout)
+	loginGuard := usecase.NewLoginGuard(
+		geoip.NewHTTPLocator(cfg.LoginRisk.GeoIPURL, cfg.LoginRisk.GeoIPTimeout),
+		repos.LoginLocations,
+		cache.NewLoginChallengeRepository(redisClient, "user-service:login-challenge:"),
+		notifier,
+		securityEventUseCase,
+		backupCodeUseCase,
+		usecase.LoginRiskPolicy{CodeTTL: cfg.LoginRisk.CodeTTL},
+	)
+	ssoConnectionRepo := postgres.NewSSOConnectionRepository(dbConn)
+	scimRepo := postgres.NewSCIMRepository(dbConn)
+	ssoUseCase := usecase.NewSSOUseCase(
+		userRepo,
+		ssoConnectionRepo,
+		cache.NewSSOStateRepository(redisClient, "user-service:sso-state:"),
+		scimRepo,
+		oidc.NewProvider(cfg.SSO.HTTPTimeout),
+		authService,
+		events,
+		securityEventUseCase,
+		usecase.SSOPolicy{
+			RedirectURL: cfg.SSO.RedirectURL,
+			StateTTL:    cfg.SSO.StateTTL,
+		},
+	)
+	userUseCase := usecase.NewUserUseCase(userRepo, profileReadModel, repos.Consents, repos.LegalHolds, authService, events, usecase.EmailPolicy{
+		BlockedDomains:    valueobject.NewDomainList(cfg.Email.BlockedDomains),
+		RejectPlusAliases: cfg.Email.RejectPlusAliases,
+	}, loginGuard, authAnomalies, securityEventUseCase, ssoUseCase)
+	magicLinkUseCase := usecase.NewMagicLinkUseCase(
+		userRepo,
+		cache.NewMagicLinkRepository(redisClient, "user-service:magic-link:"),
+		authService,
+		notifier,
+		securityEventUseCase,
+		ssoUseCase,
+		usecase.MagicLinkPolicy{
+			Secret: []byte(cfg.MagicLink.Secret),
+			URL:    cfg.MagicLink.URL,
+			TTL:    cfg.MagicLink.TTL,
+		},
+	)
+	notificationPreferenceUseCase := usecase.NewNotificationPreferenceUseCase(repos.UnitOfWork, repos.NotificationPreferences, repos.Consents)
+	consentUseCase := usecase.NewConsentUseCase(userRepo, repos.Consents, events)
+
+	// create interceptors
+	interceptors := connect.WithInterceptors(
+		interceptor.NewLocalizeInterceptor(),
+		interceptor.NewRecoverInterceptor(),
+		interceptor.NewPayloadLogInterceptor(logger, redact.Message),
+		interceptor.NewClientIPInterceptor(trustedProxies),
+		interceptor.NewDeadlineInterceptor(),
+		interceptor.NewSizeLimitInterceptor(sizeLimitConfig),
+		interceptor.NewChaosInterceptor(chaosConfig),
+		newAuthInterceptor(authService, []byte(cfg.Auth.AccessSecret)),
+		// after auth so authenticated callers are limited per user
+		newRateLimitInterceptor(ratelimit.NewTokenBucket(redisClient, "user-service:ratelimit:"), rateLimitConfig, authAnomalies),
 	)
+	handlerOptions := append([]connect.HandlerOption{
+		interceptors,
+		connect.WithReadMaxBytes(cfg.Server.MaxMessageBytes),
+		connect.WithSendMaxBytes(cfg.Server.MaxMessageBytes),
+	}, compression.HandlerOptions(cfg.Server.CompressMinBytes)...)
+
+	// outermost, so errors from the shared interceptors carry the notice too
+	userV1Options := append([]connect.HandlerOption{
+		connect.WithInterceptors(interceptor.NewDeprecationInterceptor(userV1Deprecation)),
+	}, handlerOptions...)
+	userHandler := NewUserServiceHandler(userUseCase, notificationPreferenceUseCase)
+	userPath, userServiceHandler := userv1connect.NewUserServiceHandler(userHandler, userV1Options...)
+	mux.Handle(userPath, readiness.Gate(userServiceHandler))
+	// CheckNotificationAllowed reads any user's opt-ins; internal mTLS
+	// callers only
+	mux.Handle(userv1connect.UserServiceCheckNotificationAllowedProcedure, mtls.RequireCaller(readiness.Gate(userServiceHandler)))
+
+	userV2Handler := NewUserServiceV2Handler(userUseCase, notificationPreferenceUseCase, consentUseCase, securityEventUseCase, backupCodeUseCase, magicLinkUseCase, ssoUseCase)
+	userV2Path, userV2ServiceHandler := userv2connect.NewUserServiceHandler(userV2Handler, handlerOptions...)
+	mux.Handle(userV2Path, readiness.Gate(userV2ServiceHandler))
+	mux.Handle(userv2connect.UserServiceCheckNotificationAllowedProcedure, mtls.RequireCaller(readiness.Gate(userV2ServiceHandler)))
+
+	// REST endpoints of user.v2, from the (options.v1.http) rules in
+	// user/v2/user.proto; requests go through userV2ServiceHandler above
+	rest, err := transcode.New(mux, []string{userv2connect.UserServiceName}, transcode.WithReadMaxBytes(int64(cfg.Server.MaxMessageBytes)))
+	if err != nil {
+		// the rules are compiled in, so this is a bug rather than bad config
+		panic(err)
+	}
+	mux.Handle("/v2/", rest)
+
+	// the admin service lists, imports and deletes users; internal mTLS
+	// callers only
+	userAdminHandler := NewUserAdminServiceHandler(
+		userUseCase,
+		usecase.NewImportUseCase(userRepo, jobQueue, events),
+		usecase.NewTagUseCase(userRepo, repos.Tags),
+		consentUseCase,
+		securityEventUseCase,
+		usecase.NewLegalHoldUseCase(userRepo, repos.LegalHolds),
+		ssoUseCase,
+	)
+	userAdminPath, userAdminServiceHandler := userv2connect.NewUserAdminServiceHandler(userAdminHandler, handlerOptions...)
+	mux.Handle(userAdminPath, mtls.RequireCaller(readiness.Gate(userAdminServiceHandler)))
+
+	// IdPs provision the users of their SSO connection over SCIM; requests
+	// authenticate with the connection's token rather than an access token
+	scimUseCase := usecase.NewSCIMUseCase(userRepo, userUseCase, ssoConnectionRepo, scimRepo, events)
+	mux.Handle("/scim/v2/", readiness.Gate(scim.NewHandler(scimUseCase, cfg.Server.MaxMessageBytes, logger)))
+
+	webhookHandler := NewWebhookServiceHandler(webhookUseCase)
+	webhookPath, webhookServiceHandler := userv1connect.NewWebhookServiceHandler(webhookHandler, handlerOptions...)
+	mux.Handle(webhookPath, readiness.Gate(webhookServiceHandler))
+
+	// subscriptions to the events of every user belong to internal services
+	webhookAdminHandler := NewWebhookAdminServiceHandler(webhookUseCase)
+	webhookAdminPath, webhookAdminServiceHandler := userv2connect.NewWebhookAdminServiceHandler(webhookAdminHandler, handlerOptions...)
+	mux.Handle(webhookAdminPath, mtls.RequireCaller(readiness.Gate(webhookAdminServiceHandler)))
+
+	// bulk exports are for internal consumers only; streams bypass the
+	// unary auth interceptor, so the mTLS caller check is what guards them
+	exportHandler := NewExportServiceHandler(usecase.NewExportUseCase(userRepo))
+	exportPath, exportServiceHandler := userv1connect.NewExportServiceHandler(exportHandler, handlerOptions...)
+	mux.Handle(exportPath, mtls.RequireCaller(readiness.Gate(exportServiceHandler)))
+
+	// operators reach the job service through the internal mTLS listener only
+	jobHandler := NewJobServiceHandler(jobQueue)
+	jobPath, jobServiceHandler := jobsv1connect.NewJobServiceHandler(jobHandler, handlerOptions...)
+	mux.Handle(jobPath, mtls.RequireCaller(readiness.Gate(jobServiceHandler)))
+
+	// operations are started by admin methods, so they share its callers
+	operationHandler := NewOperationServiceHandler(jobQueue)
+	operationPath, operationServiceHandler := operationsv1connect.NewOperationServiceHandler(operationHandler, handlerOptions...)
+	mux.Handle(operationPath, mtls.RequireCaller(readiness.Gate(operationServiceHandler)))
+
+	mux.Handle("/health", health.LivenessHandler())
+	mux.Handle("/ready", readiness.Handler())
 
-	userRepo := postgres.NewUserRepository(dbConn)
-	userUseCase := usecase.NewUserUseCase(userRepo, authService)
-	userHandler := NewUserServiceHandler(userUseCase)
-	mux.Handle(userv1connect.NewUserServiceHandler(userHandler, interceptors))
+	// gRPC needs HTTP/2, which plaintext listeners only speak as h2c; Connect
+	// and gRPC-Web work over either version
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
 
-	return &http.Server{Handler: mux}
+	return &http.Server{
+		Handler:   cors.Handler(*cfg.CORS, mux),
+		Protocols: protocols,
+	}, nil
 }
@@ -0,0 +1,337 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+
+	"connectrpc.com/connect"
+	operationsv1 "github.com/phongloihong/go-shop/api/gen/operations/v1"
+	userv2 "github.com/phongloihong/go-shop/api/gen/user/v2"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/fieldmask"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type userAdminServiceHandler struct {
+	userUseCase      *usecase.UserUseCase
+	importUseCase    *usecase.ImportUseCase
+	tagUseCase       *usecase.TagUseCase
+	consentUseCase   *usecase.ConsentUseCase
+	securityUseCase  *usecase.SecurityEventUseCase
+	legalHoldUseCase *usecase.LegalHoldUseCase
+	ssoUseCase       *usecase.SSOUseCase
+}
+
+func NewUserAdminServiceHandler(
+	userUseCase *usecase.UserUseCase,
+	importUseCase *usecase.ImportUseCase,
+	tagUseCase *usecase.TagUseCase,
+	consentUseCase *usecase.ConsentUseCase,
+	securityUseCase *usecase.SecurityEventUseCase,
+	legalHoldUseCase *usecase.LegalHoldUseCase,
+	ssoUseCase *usecase.SSOUseCase,
+) *userAdminServiceHandler {
+	return &userAdminServiceHandler{
+		userUseCase:      userUseCase,
+		importUseCase:    importUseCase,
+		tagUseCase:       tagUseCase,
+		consentUseCase:   consentUseCase,
+		securityUseCase:  securityUseCase,
+		legalHoldUseCase: legalHoldUseCase,
+		ssoUseCase:       ssoUseCase,
+	}
+}
+
+func (h *userAdminServiceHandler) ListUsers(ctx context.Context, req *connect.Request[userv2.ListUsersRequest]) (*connect.Response[userv2.ListUsersResponse], error) {
+	readMask, err := fieldmask.Parse("read_mask", req.Msg.ReadMask, &userv2.User{})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	page, err := h.userUseCase.ListUsers(ctx, dto.ListUsersRequest{
+		PageSize:  req.Msg.PageSize,
+		PageToken: req.Msg.PageToken,
+		Filter:    req.Msg.Filter,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.ListUsersResponse{
+		Users:         make([]*userv2.User, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, user := range page.Items {
+		u := userToProtoV2(user)
+		readMask.Prune(u)
+		ret.Users = append(ret.Users, u)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userAdminServiceHandler) BatchGetUsers(ctx context.Context, req *connect.Request[userv2.BatchGetUsersRequest]) (*connect.Response[userv2.BatchGetUsersResponse], error) {
+	readMask, err := fieldmask.Parse("read_mask", req.Msg.ReadMask, &userv2.User{})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	results, err := h.userUseCase.BatchGetUsers(ctx, req.Msg.Ids)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.BatchGetUsersResponse{
+		Results: make([]*userv2.BatchGetUsersResult, 0, len(results)),
+	}
+	for _, result := range results {
+		item := &userv2.BatchGetUsersResult{Id: result.ID}
+		if result.Err != nil {
+			item.Result = &userv2.BatchGetUsersResult_Error{Error: itemErrorToProto(result.Err)}
+		} else {
+			u := userToProtoV2(result.User)
+			readMask.Prune(u)
+			item.Result = &userv2.BatchGetUsersResult_User{User: u}
+		}
+		ret.Results = append(ret.Results, item)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userAdminServiceHandler) ImportUsers(ctx context.Context, req *connect.Request[userv2.ImportUsersRequest]) (*connect.Response[operationsv1.Operation], error) {
+	users := make([]dto.ImportUser, 0, len(req.Msg.Users))
+	for _, user := range req.Msg.Users {
+		users = append(users, dto.ImportUser{
+			FirstName:    user.GetName().GetGivenName(),
+			LastName:     user.GetName().GetFamilyName(),
+			Email:        user.Email,
+			Phone:        user.Phone,
+			PasswordHash: user.PasswordHash,
+		})
+	}
+
+	job, err := h.importUseCase.StartImport(ctx, users)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret, err := operationToProto(job)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userAdminServiceHandler) DeleteUser(ctx context.Context, req *connect.Request[userv2.DeleteUserRequest]) (*connect.Response[userv2.DeleteUserResponse], error) {
+	if err := h.userUseCase.DeleteUser(ctx, req.Msg.Id); err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.DeleteUserResponse{}), nil
+}
+
+func (h *userAdminServiceHandler) GetUserTags(ctx context.Context, req *connect.Request[userv2.GetUserTagsRequest]) (*connect.Response[userv2.GetUserTagsResponse], error) {
+	tags, err := h.tagUseCase.GetTags(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GetUserTagsResponse{Tags: userTagsToProto(tags)}), nil
+}
+
+func (h *userAdminServiceHandler) AddUserTags(ctx context.Context, req *connect.Request[userv2.AddUserTagsRequest]) (*connect.Response[userv2.AddUserTagsResponse], error) {
+	tags, err := h.tagUseCase.AddTags(ctx, req.Msg.UserId, req.Msg.Tags)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.AddUserTagsResponse{Tags: userTagsToProto(tags)}), nil
+}
+
+func (h *userAdminServiceHandler) RemoveUserTags(ctx context.Context, req *connect.Request[userv2.RemoveUserTagsRequest]) (*connect.Response[userv2.RemoveUserTagsResponse], error) {
+	tags, err := h.tagUseCase.RemoveTags(ctx, req.Msg.UserId, req.Msg.Tags)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.RemoveUserTagsResponse{Tags: userTagsToProto(tags)}), nil
+}
+
+func (h *userAdminServiceHandler) ListConsentRecords(ctx context.Context, req *connect.Request[userv2.ListConsentRecordsRequest]) (*connect.Response[userv2.ListConsentRecordsResponse], error) {
+	records, err := h.consentUseCase.ListConsentRecords(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.ListConsentRecordsResponse{Records: consentsToProtoV2(records)}), nil
+}
+
+func (h *userAdminServiceHandler) GetUserSecurityEvents(ctx context.Context, req *connect.Request[userv2.GetUserSecurityEventsRequest]) (*connect.Response[userv2.GetUserSecurityEventsResponse], error) {
+	page, err := h.securityUseCase.ListSecurityEvents(ctx, dto.ListSecurityEventsRequest{
+		UserID:    req.Msg.UserId,
+		PageSize:  req.Msg.PageSize,
+		PageToken: req.Msg.PageToken,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GetUserSecurityEventsResponse{
+		Events:        securityEventsToProtoV2(page.Items),
+		NextPageToken: page.NextPageToken,
+	}), nil
+}
+
+func (h *userAdminServiceHandler) GetLegalHold(ctx context.Context, req *connect.Request[userv2.GetLegalHoldRequest]) (*connect.Response[userv2.GetLegalHoldResponse], error) {
+	hold, err := h.legalHoldUseCase.GetLegalHold(ctx, req.Msg.UserId)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.GetLegalHoldResponse{}
+	if hold != nil {
+		ret.Hold = legalHoldToProto(hold)
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userAdminServiceHandler) PlaceLegalHold(ctx context.Context, req *connect.Request[userv2.PlaceLegalHoldRequest]) (*connect.Response[userv2.PlaceLegalHoldResponse], error) {
+	hold, err := h.legalHoldUseCase.PlaceLegalHold(ctx, req.Msg.UserId, req.Msg.Reason)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.PlaceLegalHoldResponse{Hold: legalHoldToProto(hold)}), nil
+}
+
+func (h *userAdminServiceHandler) ReleaseLegalHold(ctx context.Context, req *connect.Request[userv2.ReleaseLegalHoldRequest]) (*connect.Response[userv2.ReleaseLegalHoldResponse], error) {
+	if err := h.legalHoldUseCase.ReleaseLegalHold(ctx, req.Msg.UserId); err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.ReleaseLegalHoldResponse{}), nil
+}
+
+func (h *userAdminServiceHandler) CreateSsoConnection(ctx context.Context, req *connect.Request[userv2.CreateSsoConnectionRequest]) (*connect.Response[userv2.CreateSsoConnectionResponse], error) {
+	conn, err := h.ssoUseCase.CreateConnection(ctx, dto.CreateSSOConnectionRequest{
+		Name:         req.Msg.Name,
+		Issuer:       req.Msg.Issuer,
+		ClientID:     req.Msg.ClientId,
+		ClientSecret: req.Msg.ClientSecret,
+		Domains:      req.Msg.Domains,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.CreateSsoConnectionResponse{Connection: ssoConnectionToProto(conn)}), nil
+}
+
+func (h *userAdminServiceHandler) ListSsoConnections(ctx context.Context, req *connect.Request[userv2.ListSsoConnectionsRequest]) (*connect.Response[userv2.ListSsoConnectionsResponse], error) {
+	conns, err := h.ssoUseCase.ListConnections(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.ListSsoConnectionsResponse{
+		Connections: make([]*userv2.SsoConnection, 0, len(conns)),
+	}
+	for _, conn := range conns {
+		ret.Connections = append(ret.Connections, ssoConnectionToProto(conn))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userAdminServiceHandler) DeleteSsoConnection(ctx context.Context, req *connect.Request[userv2.DeleteSsoConnectionRequest]) (*connect.Response[userv2.DeleteSsoConnectionResponse], error) {
+	if err := h.ssoUseCase.DeleteConnection(ctx, req.Msg.Id); err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.DeleteSsoConnectionResponse{}), nil
+}
+
+func (h *userAdminServiceHandler) CreateScimToken(ctx context.Context, req *connect.Request[userv2.CreateScimTokenRequest]) (*connect.Response[userv2.CreateScimTokenResponse], error) {
+	token, err := h.ssoUseCase.CreateSCIMToken(ctx, req.Msg.ConnectionId)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.CreateScimTokenResponse{Token: token}), nil
+}
+
+// importUsersResponseToProto is the response of a succeeded ImportUsers
+// operation.
+func importUsersResponseToProto(result json.RawMessage) (proto.Message, error) {
+	var imported usecase.ImportUsersResult
+	if err := json.Unmarshal(result, &imported); err != nil {
+		return nil, err
+	}
+
+	ret := &userv2.ImportUsersResponse{
+		ImportedCount: imported.Imported,
+		Failures:      make([]*userv2.ImportUsersFailure, 0, len(imported.Failures)),
+	}
+	for _, failure := range imported.Failures {
+		ret.Failures = append(ret.Failures, &userv2.ImportUsersFailure{
+			Index: failure.Index,
+			Error: itemErrorToProto(domain_error.New(failure.Reason, domain_error.WithMessage(failure.Message))),
+		})
+	}
+
+	return ret, nil
+}
+
+// itemErrorToProto reports the failure of one item of a batch the way
+// MapError would report it for a whole call.
+func itemErrorToProto(err error) *userv2.ItemError {
+	connectErr := domain_error.MapError(err)
+	reason, _ := domain_error.ReasonOf(connectErr)
+
+	return &userv2.ItemError{
+		Code:    connectErr.Code().String(),
+		Reason:  string(reason),
+		Message: connectErr.Message(),
+	}
+}
+
+func userTagsToProto(tags []*entity.UserTag) []*userv2.UserTag {
+	ret := make([]*userv2.UserTag, 0, len(tags))
+	for _, tag := range tags {
+		ret = append(ret, &userv2.UserTag{
+			Tag:        tag.Tag,
+			Source:     string(tag.Source),
+			CreateTime: timestamppb.New(tag.CreatedAt.Time()),
+		})
+	}
+
+	return ret
+}
+
+func legalHoldToProto(hold *entity.LegalHold) *userv2.LegalHold {
+	return &userv2.LegalHold{
+		Reason:     hold.Reason,
+		CreateTime: timestamppb.New(hold.CreatedAt.Time()),
+	}
+}
+
+// ssoConnectionToProto leaves out the client secret and SCIM token, which
+// are never returned.
+func ssoConnectionToProto(conn *entity.SSOConnection) *userv2.SsoConnection {
+	return &userv2.SsoConnection{
+		Id:          conn.ID,
+		Name:        conn.Name,
+		Issuer:      conn.Issuer,
+		ClientId:    conn.ClientID,
+		Domains:     conn.Domains,
+		CreateTime:  timestamppb.New(conn.CreatedAt.Time()),
+		ScimEnabled: conn.SCIMTokenHash != "",
+	}
+}
@@ -4,21 +4,24 @@ import (
 	"context"
 
 	"connectrpc.com/connect"
-	userv1 "github.com/phongloihong/go-shop/services/user-service/external/gen/user/v1"
-	domain_error "github.com/phongloihong/go-shop/services/user-service/internal/domain/domain_errors"
+	userv1 "github.com/phongloihong/go-shop/api/gen/user/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
 	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
 	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
 )
 
 type userServiceHandler struct {
-	userUseCase *usecase.UserUseCase
+	userUseCase                   *usecase.UserUseCase
+	notificationPreferenceUseCase *usecase.NotificationPreferenceUseCase
 }
 
 func NewUserServiceHandler(
 	userUseCase *usecase.UserUseCase,
+	notificationPreferenceUseCase *usecase.NotificationPreferenceUseCase,
 ) *userServiceHandler {
 	return &userServiceHandler{
-		userUseCase: userUseCase,
+		userUseCase:                   userUseCase,
+		notificationPreferenceUseCase: notificationPreferenceUseCase,
 	}
 }
 
@@ -29,6 +32,7 @@ func (h *userServiceHandler) Register(ctx context.Context, req *connect.Request[
 		Email:     req.Msg.Email,
 		Phone:     req.Msg.Phone,
 		Password:  req.Msg.Password,
+		IP:        clientIP(ctx),
 	}
 
 	_, err := h.userUseCase.RegisterUser(ctx, params)
@@ -45,8 +49,10 @@ func (h *userServiceHandler) Register(ctx context.Context, req *connect.Request[
 
 func (h *userServiceHandler) Login(ctx context.Context, req *connect.Request[userv1.LoginRequest]) (*connect.Response[userv1.LoginResponse], error) {
 	ret, err := h.userUseCase.Login(ctx, dto.LoginRequest{
-		Email:    req.Msg.Email,
-		Password: req.Msg.Password,
+		Email:     req.Msg.Email,
+		Password:  req.Msg.Password,
+		IP:        clientIP(ctx),
+		UserAgent: req.Header().Get("User-Agent"),
 	})
 	if err != nil {
 		return nil, domain_error.MapError(err)
@@ -60,13 +66,72 @@ func (h *userServiceHandler) Login(ctx context.Context, req *connect.Request[use
 }
 
 func (h *userServiceHandler) ChangePassword(ctx context.Context, req *connect.Request[userv1.ChangePasswordRequest]) (*connect.Response[userv1.ChangePasswordResponse], error) {
-	return nil, nil
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	// v1 names the account by email as well; it must be the caller's own
+	user, err := h.userUseCase.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+	if user.Email.String() != req.Msg.Email {
+		return nil, domain_error.MapError(domain_error.New(domain_error.ReasonInvalidCredentials))
+	}
+
+	err = h.userUseCase.ChangePassword(ctx, dto.ChangePasswordRequest{
+		UserID:      userID,
+		OldPassword: req.Msg.OldPassword,
+		NewPassword: req.Msg.NewPassword,
+		IP:          clientIP(ctx),
+		UserAgent:   req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.ChangePasswordResponse{
+		Success: true,
+	}), nil
 }
 
 func (h *userServiceHandler) GetProfile(ctx context.Context, req *connect.Request[userv1.GetProfileRequest]) (*connect.Response[userv1.GetProfileResponse], error) {
-	return nil, nil
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	user, err := h.userUseCase.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.GetProfileResponse{
+		Id:        user.ID,
+		Email:     user.Email.String(),
+		Phone:     user.Phone.String(),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}), nil
 }
 
 func (h *userServiceHandler) GetPublicProfile(ctx context.Context, req *connect.Request[userv1.GetPublicProfileRequest]) (*connect.Response[userv1.GetPublicProfileResponse], error) {
-	return nil, nil
+	profiles, err := h.userUseCase.GetPublicProfiles(ctx, req.Msg.Ids)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv1.GetPublicProfileResponse{
+		Profiles: make([]*userv1.PublicProfile, 0, len(profiles)),
+	}
+	for _, profile := range profiles {
+		ret.Profiles = append(ret.Profiles, &userv1.PublicProfile{
+			Id:        profile.ID,
+			FirstName: profile.FirstName,
+			LastName:  profile.LastName,
+		})
+	}
+
+	return connect.NewResponse(ret), nil
 }
@@ -0,0 +1,100 @@
+package connect_test
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/phongloihong/go-shop/api/gen/user/v1"
+	"github.com/phongloihong/go-shop/api/sdk"
+	"github.com/phongloihong/go-shop/services/user-service/internal/testutil"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	pool := testutil.StartPostgres(t)
+	srv := testutil.NewServer(t, pool, testutil.StartRedis(t))
+	users := srv.UserClient("")
+	ctx := context.Background()
+
+	register := func(t *testing.T, email string) error {
+		t.Helper()
+
+		_, err := users.Register(ctx, connect.NewRequest(&userv1.RegisterRequest{
+			Email:     email,
+			Phone:     "0901234567",
+			FirstName: "John",
+			LastName:  "Doe",
+			Password:  "Secret123!",
+		}))
+		return err
+	}
+
+	t.Run("registers, logs in and reads the profile", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		if err := register(t, "john@example.com"); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+
+		// emails are matched without regard to case
+		res, err := users.Login(ctx, connect.NewRequest(&userv1.LoginRequest{
+			Email:    "John@Example.com",
+			Password: "Secret123!",
+		}))
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if res.Msg.AccessToken == "" || res.Msg.RefreshToken == "" {
+			t.Fatalf("Login returned tokens %q and %q, want both", res.Msg.AccessToken, res.Msg.RefreshToken)
+		}
+
+		profile, err := srv.UserClient(res.Msg.AccessToken).GetProfile(ctx, connect.NewRequest(&userv1.GetProfileRequest{}))
+		if err != nil {
+			t.Fatalf("GetProfile: %v", err)
+		}
+		if profile.Msg.Email != "john@example.com" || profile.Msg.Phone != "0901234567" || profile.Msg.FirstName != "John" {
+			t.Errorf("GetProfile = %v, want the registered user", profile.Msg)
+		}
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		if err := register(t, "john@example.com"); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+
+		err := register(t, "JOHN@example.com")
+		if !sdk.HasReason(err, sdk.ReasonEmailAlreadyExists) {
+			t.Errorf("Register of a taken email in another case = %v, want %s", err, sdk.ReasonEmailAlreadyExists)
+		}
+	})
+
+	t.Run("rejects a wrong password", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		if err := register(t, "john@example.com"); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+
+		_, err := users.Login(ctx, connect.NewRequest(&userv1.LoginRequest{
+			Email:    "john@example.com",
+			Password: "Wrong123!",
+		}))
+		if !sdk.HasReason(err, sdk.ReasonInvalidCredentials) {
+			t.Errorf("Login with a wrong password = %v, want %s", err, sdk.ReasonInvalidCredentials)
+		}
+	})
+
+	t.Run("rejects an unknown email", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		_, err := users.Login(ctx, connect.NewRequest(&userv1.LoginRequest{
+			Email:    "nobody@example.com",
+			Password: "Secret123!",
+		}))
+		if !sdk.HasReason(err, sdk.ReasonInvalidCredentials) {
+			t.Errorf("Login of an unknown email = %v, want %s", err, sdk.ReasonInvalidCredentials)
+		}
+	})
+}
@@ -0,0 +1,639 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	userv2 "github.com/phongloihong/go-shop/api/gen/user/v2"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/fieldmask"
+	"github.com/phongloihong/go-shop/pkg/pagination"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	channelFromProtoV2 = map[userv2.NotificationChannel]valueobject.NotificationChannel{
+		userv2.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL: valueobject.ChannelEmail,
+		userv2.NotificationChannel_NOTIFICATION_CHANNEL_SMS:   valueobject.ChannelSMS,
+		userv2.NotificationChannel_NOTIFICATION_CHANNEL_PUSH:  valueobject.ChannelPush,
+	}
+	categoryFromProtoV2 = map[userv2.NotificationCategory]valueobject.NotificationCategory{
+		userv2.NotificationCategory_NOTIFICATION_CATEGORY_TRANSACTIONAL: valueobject.CategoryTransactional,
+		userv2.NotificationCategory_NOTIFICATION_CATEGORY_MARKETING:     valueobject.CategoryMarketing,
+	}
+	purposeFromProtoV2 = map[userv2.ConsentPurpose]valueobject.ConsentPurpose{
+		userv2.ConsentPurpose_CONSENT_PURPOSE_MARKETING_EMAIL:   valueobject.ConsentMarketingEmail,
+		userv2.ConsentPurpose_CONSENT_PURPOSE_MARKETING_SMS:     valueobject.ConsentMarketingSMS,
+		userv2.ConsentPurpose_CONSENT_PURPOSE_MARKETING_PUSH:    valueobject.ConsentMarketingPush,
+		userv2.ConsentPurpose_CONSENT_PURPOSE_ANALYTICS_COOKIES: valueobject.ConsentAnalyticsCookies,
+	}
+)
+
+// userServiceV2Handler serves user.v2.UserService from the same use cases as
+// the v1 handler; only the mapping to and from the wire types differs.
+type userServiceV2Handler struct {
+	userUseCase                   *usecase.UserUseCase
+	notificationPreferenceUseCase *usecase.NotificationPreferenceUseCase
+	consentUseCase                *usecase.ConsentUseCase
+	securityEventUseCase          *usecase.SecurityEventUseCase
+	backupCodeUseCase             *usecase.BackupCodeUseCase
+	magicLinkUseCase              *usecase.MagicLinkUseCase
+	ssoUseCase                    *usecase.SSOUseCase
+}
+
+func NewUserServiceV2Handler(
+	userUseCase *usecase.UserUseCase,
+	notificationPreferenceUseCase *usecase.NotificationPreferenceUseCase,
+	consentUseCase *usecase.ConsentUseCase,
+	securityEventUseCase *usecase.SecurityEventUseCase,
+	backupCodeUseCase *usecase.BackupCodeUseCase,
+	magicLinkUseCase *usecase.MagicLinkUseCase,
+	ssoUseCase *usecase.SSOUseCase,
+) *userServiceV2Handler {
+	return &userServiceV2Handler{
+		userUseCase:                   userUseCase,
+		notificationPreferenceUseCase: notificationPreferenceUseCase,
+		consentUseCase:                consentUseCase,
+		securityEventUseCase:          securityEventUseCase,
+		backupCodeUseCase:             backupCodeUseCase,
+		magicLinkUseCase:              magicLinkUseCase,
+		ssoUseCase:                    ssoUseCase,
+	}
+}
+
+func (h *userServiceV2Handler) Register(ctx context.Context, req *connect.Request[userv2.RegisterRequest]) (*connect.Response[userv2.RegisterResponse], error) {
+	user, err := h.userUseCase.RegisterUser(ctx, dto.RegisterRequest{
+		FirstName:    req.Msg.GetName().GetGivenName(),
+		LastName:     req.Msg.GetName().GetFamilyName(),
+		Email:        req.Msg.Email,
+		Phone:        req.Msg.Phone,
+		Password:     req.Msg.Password,
+		GuestToken:   req.Msg.GuestToken,
+		IP:           clientIP(ctx),
+		CaptchaToken: req.Msg.CaptchaToken,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.RegisterResponse{
+		User: userToProtoV2(user),
+	}), nil
+}
+
+func (h *userServiceV2Handler) CreateGuestToken(ctx context.Context, req *connect.Request[userv2.CreateGuestTokenRequest]) (*connect.Response[userv2.CreateGuestTokenResponse], error) {
+	guestID, ret, err := h.userUseCase.CreateGuestToken(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.CreateGuestTokenResponse{
+		GuestId:     guestID,
+		AccessToken: ret.AccessToken,
+		ExpiresIn:   durationpb.New(time.Duration(ret.ExpiresIn) * time.Second),
+	}), nil
+}
+
+func (h *userServiceV2Handler) Login(ctx context.Context, req *connect.Request[userv2.LoginRequest]) (*connect.Response[userv2.LoginResponse], error) {
+	ret, err := h.userUseCase.Login(ctx, dto.LoginRequest{
+		Email:        req.Msg.Email,
+		Password:     req.Msg.Password,
+		IP:           clientIP(ctx),
+		UserAgent:    req.Header().Get("User-Agent"),
+		CaptchaToken: req.Msg.CaptchaToken,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.LoginResponse{
+		AccessToken:  ret.AccessToken,
+		RefreshToken: ret.RefreshToken,
+		ExpiresIn:    durationpb.New(time.Duration(ret.ExpiresIn) * time.Second),
+	}), nil
+}
+
+func (h *userServiceV2Handler) VerifyLogin(ctx context.Context, req *connect.Request[userv2.VerifyLoginRequest]) (*connect.Response[userv2.LoginResponse], error) {
+	ret, err := h.userUseCase.VerifyLogin(ctx, dto.VerifyLoginRequest{
+		ChallengeID: req.Msg.ChallengeId,
+		Code:        req.Msg.Code,
+		BackupCode:  req.Msg.BackupCode,
+		UserAgent:   req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.LoginResponse{
+		AccessToken:  ret.AccessToken,
+		RefreshToken: ret.RefreshToken,
+		ExpiresIn:    durationpb.New(time.Duration(ret.ExpiresIn) * time.Second),
+	}), nil
+}
+
+func (h *userServiceV2Handler) RequestMagicLink(ctx context.Context, req *connect.Request[userv2.RequestMagicLinkRequest]) (*connect.Response[userv2.RequestMagicLinkResponse], error) {
+	err := h.magicLinkUseCase.RequestMagicLink(ctx, dto.RequestMagicLinkRequest{
+		Email: req.Msg.Email,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.RequestMagicLinkResponse{}), nil
+}
+
+func (h *userServiceV2Handler) ConsumeMagicLink(ctx context.Context, req *connect.Request[userv2.ConsumeMagicLinkRequest]) (*connect.Response[userv2.LoginResponse], error) {
+	ret, err := h.magicLinkUseCase.ConsumeMagicLink(ctx, dto.ConsumeMagicLinkRequest{
+		Token:     req.Msg.Token,
+		IP:        clientIP(ctx),
+		UserAgent: req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.LoginResponse{
+		AccessToken:  ret.AccessToken,
+		RefreshToken: ret.RefreshToken,
+		ExpiresIn:    durationpb.New(time.Duration(ret.ExpiresIn) * time.Second),
+	}), nil
+}
+
+func (h *userServiceV2Handler) StartSsoLogin(ctx context.Context, req *connect.Request[userv2.StartSsoLoginRequest]) (*connect.Response[userv2.StartSsoLoginResponse], error) {
+	authURL, err := h.ssoUseCase.StartLogin(ctx, dto.StartSSOLoginRequest{
+		Email: req.Msg.Email,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.StartSsoLoginResponse{AuthorizationUrl: authURL}), nil
+}
+
+func (h *userServiceV2Handler) FinishSsoLogin(ctx context.Context, req *connect.Request[userv2.FinishSsoLoginRequest]) (*connect.Response[userv2.LoginResponse], error) {
+	ret, err := h.ssoUseCase.FinishLogin(ctx, dto.FinishSSOLoginRequest{
+		State:     req.Msg.State,
+		Code:      req.Msg.Code,
+		IP:        clientIP(ctx),
+		UserAgent: req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.LoginResponse{
+		AccessToken:  ret.AccessToken,
+		RefreshToken: ret.RefreshToken,
+		ExpiresIn:    durationpb.New(time.Duration(ret.ExpiresIn) * time.Second),
+	}), nil
+}
+
+func (h *userServiceV2Handler) ChangePassword(ctx context.Context, req *connect.Request[userv2.ChangePasswordRequest]) (*connect.Response[userv2.ChangePasswordResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	err = h.userUseCase.ChangePassword(ctx, dto.ChangePasswordRequest{
+		UserID:      userID,
+		OldPassword: req.Msg.OldPassword,
+		NewPassword: req.Msg.NewPassword,
+		IP:          clientIP(ctx),
+		UserAgent:   req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.ChangePasswordResponse{}), nil
+}
+
+func (h *userServiceV2Handler) GetProfile(ctx context.Context, req *connect.Request[userv2.GetProfileRequest]) (*connect.Response[userv2.GetProfileResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	readMask, err := fieldmask.Parse("read_mask", req.Msg.ReadMask, &userv2.User{})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	// its version and ETag are what UpdateProfile is conditional on, so they
+	// come from the users table rather than the read model
+	user, err := h.userUseCase.GetProfileForUpdate(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := userToProtoV2(user)
+	readMask.Prune(ret)
+
+	res := connect.NewResponse(&userv2.GetProfileResponse{
+		User: ret,
+	})
+	res.Header().Set("ETag", etag(user.Version))
+
+	return res, nil
+}
+
+func (h *userServiceV2Handler) UpdateProfile(ctx context.Context, req *connect.Request[userv2.UpdateProfileRequest]) (*connect.Response[userv2.UpdateProfileResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	params, err := updateProfileFromProtoV2(userID, req.Msg.GetUser(), req.Msg.GetUpdateMask())
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+	params.ExpectedVersion, err = expectedVersion(req.Msg.GetExpectedVersion(), req.Header().Get("If-Match"))
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	user, err := h.userUseCase.UpdateProfile(ctx, params)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	res := connect.NewResponse(&userv2.UpdateProfileResponse{
+		User: userToProtoV2(user),
+	})
+	res.Header().Set("ETag", etag(user.Version))
+
+	return res, nil
+}
+
+func (h *userServiceV2Handler) BatchGetPublicProfiles(ctx context.Context, req *connect.Request[userv2.BatchGetPublicProfilesRequest]) (*connect.Response[userv2.BatchGetPublicProfilesResponse], error) {
+	profiles, err := h.userUseCase.GetPublicProfiles(ctx, req.Msg.Ids)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.BatchGetPublicProfilesResponse{
+		Profiles: make([]*userv2.PublicProfile, 0, len(profiles)),
+	}
+	for _, profile := range profiles {
+		ret.Profiles = append(ret.Profiles, &userv2.PublicProfile{
+			Id: profile.ID,
+			Name: &userv2.PersonName{
+				GivenName:  profile.FirstName,
+				FamilyName: profile.LastName,
+			},
+		})
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userServiceV2Handler) ListNotificationPreferences(ctx context.Context, req *connect.Request[userv2.ListNotificationPreferencesRequest]) (*connect.Response[userv2.ListNotificationPreferencesResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	prefs, err := h.notificationPreferenceUseCase.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	// every combination is always listed in the same order
+	pageSize := pagination.PageSize(req.Msg.PageSize, pagination.DefaultPageSize, pagination.MaxPageSize)
+	page, err := pagination.Paginate(prefs, pageSize, req.Msg.PageToken)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.ListNotificationPreferencesResponse{
+		Preferences:   preferencesToProtoV2(page.Items),
+		NextPageToken: page.NextPageToken,
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *userServiceV2Handler) UpdateNotificationPreferences(ctx context.Context, req *connect.Request[userv2.UpdateNotificationPreferencesRequest]) (*connect.Response[userv2.UpdateNotificationPreferencesResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	params := dto.UpdateNotificationPreferencesRequest{
+		UserID:      userID,
+		Preferences: make([]dto.NotificationPreference, 0, len(req.Msg.Preferences)),
+	}
+	for _, p := range req.Msg.Preferences {
+		params.Preferences = append(params.Preferences, dto.NotificationPreference{
+			Channel:  channelFromProtoV2[p.Channel].String(),
+			Category: categoryFromProtoV2[p.Category].String(),
+			Enabled:  p.Enabled,
+		})
+	}
+
+	prefs, err := h.notificationPreferenceUseCase.UpdatePreferences(ctx, params)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.UpdateNotificationPreferencesResponse{
+		Preferences: preferencesToProtoV2(prefs),
+	}), nil
+}
+
+func (h *userServiceV2Handler) GetConsents(ctx context.Context, req *connect.Request[userv2.GetConsentsRequest]) (*connect.Response[userv2.GetConsentsResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	consents, err := h.consentUseCase.GetConsents(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GetConsentsResponse{
+		Consents: consentsToProtoV2(consents),
+	}), nil
+}
+
+func (h *userServiceV2Handler) UpdateConsents(ctx context.Context, req *connect.Request[userv2.UpdateConsentsRequest]) (*connect.Response[userv2.UpdateConsentsResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	params := dto.UpdateConsentsRequest{
+		UserID:   userID,
+		Consents: make([]dto.ConsentDecision, 0, len(req.Msg.Consents)),
+	}
+	for _, c := range req.Msg.Consents {
+		params.Consents = append(params.Consents, dto.ConsentDecision{
+			Purpose:       purposeFromProtoV2[c.Purpose].String(),
+			Granted:       c.Granted,
+			PolicyVersion: c.PolicyVersion,
+		})
+	}
+
+	consents, err := h.consentUseCase.UpdateConsents(ctx, params)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.UpdateConsentsResponse{
+		Consents: consentsToProtoV2(consents),
+	}), nil
+}
+
+func (h *userServiceV2Handler) GetSecurityEvents(ctx context.Context, req *connect.Request[userv2.GetSecurityEventsRequest]) (*connect.Response[userv2.GetSecurityEventsResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	page, err := h.securityEventUseCase.ListSecurityEvents(ctx, dto.ListSecurityEventsRequest{
+		UserID:    userID,
+		PageSize:  req.Msg.PageSize,
+		PageToken: req.Msg.PageToken,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GetSecurityEventsResponse{
+		Events:        securityEventsToProtoV2(page.Items),
+		NextPageToken: page.NextPageToken,
+	}), nil
+}
+
+func (h *userServiceV2Handler) GenerateBackupCodes(ctx context.Context, req *connect.Request[userv2.GenerateBackupCodesRequest]) (*connect.Response[userv2.GenerateBackupCodesResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	codes, err := h.backupCodeUseCase.GenerateBackupCodes(ctx, dto.GenerateBackupCodesRequest{
+		UserID:    userID,
+		Password:  req.Msg.Password,
+		IP:        clientIP(ctx),
+		UserAgent: req.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GenerateBackupCodesResponse{
+		Codes: codes,
+	}), nil
+}
+
+func (h *userServiceV2Handler) GetBackupCodeStatus(ctx context.Context, req *connect.Request[userv2.GetBackupCodeStatusRequest]) (*connect.Response[userv2.GetBackupCodeStatusResponse], error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	count, err := h.backupCodeUseCase.CountBackupCodes(ctx, userID)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.GetBackupCodeStatusResponse{
+		RemainingCount: int32(count),
+	}), nil
+}
+
+func (h *userServiceV2Handler) CheckNotificationAllowed(ctx context.Context, req *connect.Request[userv2.CheckNotificationAllowedRequest]) (*connect.Response[userv2.CheckNotificationAllowedResponse], error) {
+	allowed, err := h.notificationPreferenceUseCase.IsAllowed(ctx, dto.CheckNotificationAllowedRequest{
+		UserID:   req.Msg.UserId,
+		Channel:  channelFromProtoV2[req.Msg.Channel].String(),
+		Category: categoryFromProtoV2[req.Msg.Category].String(),
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.CheckNotificationAllowedResponse{
+		Allowed: allowed,
+	}), nil
+}
+
+func userToProtoV2(user *entity.User) *userv2.User {
+	return &userv2.User{
+		Id: user.ID,
+		Name: &userv2.PersonName{
+			GivenName:  user.FirstName,
+			FamilyName: user.LastName,
+		},
+		Email:      user.Email.String(),
+		Phone:      user.Phone.String(),
+		CreateTime: timestamppb.New(user.CreatedAt.Time()),
+		UpdateTime: timestamppb.New(user.UpdatedAt.Time()),
+		Version:    user.Version,
+	}
+}
+
+// etag is the strong entity tag of a resource version.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// expectedVersion takes the version an update is based on from the request
+// field or, failing that, an If-Match header holding an ETag from etag. One
+// of them is required: an update without a precondition fails with
+// USER_VERSION_REQUIRED rather than overwriting whatever is stored.
+func expectedVersion(field int64, ifMatch string) (int64, error) {
+	if field > 0 {
+		return field, nil
+	}
+
+	if ifMatch == "" {
+		return 0, domain_error.New(domain_error.ReasonUserVersionRequired, domain_error.WithMessage("expected_version or an If-Match header is required"))
+	}
+	// a quoted version; weak tags and "*" cannot guard a write
+	version, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(ifMatch, `"`), `"`), 10, 64)
+	if err != nil || version <= 0 || ifMatch != etag(version) {
+		return 0, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("expected_version", "If-Match must be an ETag returned by GetProfile"))
+	}
+
+	return version, nil
+}
+
+// updateProfileFromProtoV2 picks the fields named by mask out of user. An
+// empty mask picks every updatable field that is set.
+func updateProfileFromProtoV2(userID string, user *userv2.User, mask *fieldmaskpb.FieldMask) (dto.UpdateProfileRequest, error) {
+	params := dto.UpdateProfileRequest{UserID: userID}
+	givenName, familyName, phone := user.GetName().GetGivenName(), user.GetName().GetFamilyName(), user.GetPhone()
+
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		if givenName != "" {
+			paths = append(paths, "name.given_name")
+		}
+		if familyName != "" {
+			paths = append(paths, "name.family_name")
+		}
+		if phone != "" {
+			paths = append(paths, "phone")
+		}
+	}
+
+	var opts []domain_error.Option
+	for _, path := range paths {
+		switch path {
+		case "name":
+			params.FirstName, params.LastName = &givenName, &familyName
+		case "name.given_name":
+			params.FirstName = &givenName
+		case "name.family_name":
+			params.LastName = &familyName
+		case "phone":
+			params.Phone = &phone
+		default:
+			opts = append(opts, domain_error.WithFieldViolation("update_mask", fmt.Sprintf("%q cannot be updated", path)))
+		}
+	}
+	if len(opts) > 0 {
+		return dto.UpdateProfileRequest{}, domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	return params, nil
+}
+
+func preferencesToProtoV2(prefs []*entity.NotificationPreference) []*userv2.NotificationPreference {
+	ret := make([]*userv2.NotificationPreference, 0, len(prefs))
+	for _, pref := range prefs {
+		ret = append(ret, &userv2.NotificationPreference{
+			Channel:  channelToProtoV2(pref.Channel),
+			Category: categoryToProtoV2(pref.Category),
+			Enabled:  pref.Enabled,
+		})
+	}
+
+	return ret
+}
+
+func channelToProtoV2(channel valueobject.NotificationChannel) userv2.NotificationChannel {
+	for k, v := range channelFromProtoV2 {
+		if v == channel {
+			return k
+		}
+	}
+
+	return userv2.NotificationChannel_NOTIFICATION_CHANNEL_UNSPECIFIED
+}
+
+func categoryToProtoV2(category valueobject.NotificationCategory) userv2.NotificationCategory {
+	for k, v := range categoryFromProtoV2 {
+		if v == category {
+			return k
+		}
+	}
+
+	return userv2.NotificationCategory_NOTIFICATION_CATEGORY_UNSPECIFIED
+}
+
+func consentsToProtoV2(consents []*entity.Consent) []*userv2.Consent {
+	ret := make([]*userv2.Consent, 0, len(consents))
+	for _, consent := range consents {
+		c := &userv2.Consent{
+			Purpose:       purposeToProtoV2(consent.Purpose),
+			Granted:       consent.Granted,
+			PolicyVersion: consent.PolicyVersion,
+		}
+		// never decided
+		if consent.UpdatedAt != 0 {
+			c.UpdateTime = timestamppb.New(consent.UpdatedAt.Time())
+		}
+		ret = append(ret, c)
+	}
+
+	return ret
+}
+
+var securityEventKindToProtoV2 = map[entity.SecurityEventKind]userv2.SecurityEventKind{
+	entity.SecurityEventPasswordChanged:      userv2.SecurityEventKind_SECURITY_EVENT_KIND_PASSWORD_CHANGED,
+	entity.SecurityEventNewDevice:            userv2.SecurityEventKind_SECURITY_EVENT_KIND_NEW_DEVICE,
+	entity.SecurityEventUnusualLoginLocation: userv2.SecurityEventKind_SECURITY_EVENT_KIND_UNUSUAL_LOGIN_LOCATION,
+	entity.SecurityEventBackupCodesGenerated: userv2.SecurityEventKind_SECURITY_EVENT_KIND_BACKUP_CODES_GENERATED,
+	entity.SecurityEventBackupCodeUsed:       userv2.SecurityEventKind_SECURITY_EVENT_KIND_BACKUP_CODE_USED,
+}
+
+func securityEventsToProtoV2(events []*entity.SecurityEvent) []*userv2.SecurityEvent {
+	ret := make([]*userv2.SecurityEvent, 0, len(events))
+	for _, event := range events {
+		e := &userv2.SecurityEvent{
+			Kind:       securityEventKindToProtoV2[event.Kind],
+			Ip:         event.IP,
+			UserAgent:  event.UserAgent,
+			CreateTime: timestamppb.New(event.CreatedAt.Time()),
+		}
+		if event.Location != nil {
+			e.Country = event.Location.Country
+			e.City = event.Location.City
+		}
+		ret = append(ret, e)
+	}
+
+	return ret
+}
+
+func purposeToProtoV2(purpose valueobject.ConsentPurpose) userv2.ConsentPurpose {
+	for k, v := range purposeFromProtoV2 {
+		if v == purpose {
+			return k
+		}
+	}
+
+	return userv2.ConsentPurpose_CONSENT_PURPOSE_UNSPECIFIED
+}
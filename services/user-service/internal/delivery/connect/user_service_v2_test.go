@@ -0,0 +1,47 @@
+package connect
+
+import (
+	"testing"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      int64
+		ifMatch    string
+		want       int64
+		wantReason domain_error.Reason
+	}{
+		{name: "field", field: 3, want: 3},
+		{name: "field wins over If-Match", field: 3, ifMatch: `"4"`, want: 3},
+		{name: "If-Match", ifMatch: `"4"`, want: 4},
+		{name: "neither", wantReason: domain_error.ReasonUserVersionRequired},
+		{name: "wildcard", ifMatch: "*", wantReason: domain_error.ReasonValidationFailed},
+		{name: "weak tag", ifMatch: `W/"4"`, wantReason: domain_error.ReasonValidationFailed},
+		{name: "unquoted", ifMatch: "4", wantReason: domain_error.ReasonValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expectedVersion(tt.field, tt.ifMatch)
+			if tt.wantReason == "" {
+				if err != nil || got != tt.want {
+					t.Fatalf("expectedVersion(%d, %q) = %d, %v, want %d", tt.field, tt.ifMatch, got, err, tt.want)
+				}
+				return
+			}
+			if reason, _ := domain_error.ReasonOf(err); reason != tt.wantReason {
+				t.Fatalf("expectedVersion(%d, %q) error reason = %q, want %q", tt.field, tt.ifMatch, reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestExpectedVersionRequiredIsFailedPrecondition(t *testing.T) {
+	_, err := expectedVersion(0, "")
+	if code := domain_error.MapError(err).Code(); code != connect.CodeFailedPrecondition {
+		t.Errorf("missing expected version maps to %v, want %v", code, connect.CodeFailedPrecondition)
+	}
+}
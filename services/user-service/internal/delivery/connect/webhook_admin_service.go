@@ -0,0 +1,158 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	userv2 "github.com/phongloihong/go-shop/api/gen/user/v2"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/mtls"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// webhookAdminServiceHandler manages the webhook subscriptions of internal
+// services, which receive the events of every user. It is served only on
+// the internal mTLS listener.
+type webhookAdminServiceHandler struct {
+	webhookUseCase *usecase.WebhookUseCase
+}
+
+func NewWebhookAdminServiceHandler(webhookUseCase *usecase.WebhookUseCase) *webhookAdminServiceHandler {
+	return &webhookAdminServiceHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (h *webhookAdminServiceHandler) CreateWebhookSubscription(ctx context.Context, req *connect.Request[userv2.CreateWebhookSubscriptionRequest]) (*connect.Response[userv2.WebhookSubscription], error) {
+	owner, err := serviceWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	sub, err := h.webhookUseCase.CreateSubscription(ctx, dto.CreateWebhookSubscriptionRequest{
+		Owner:      owner,
+		URL:        req.Msg.Url,
+		Secret:     req.Msg.Secret,
+		EventTypes: req.Msg.EventTypes,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(subscriptionToProtoV2(sub)), nil
+}
+
+func (h *webhookAdminServiceHandler) ListWebhookSubscriptions(ctx context.Context, req *connect.Request[userv2.ListWebhookSubscriptionsRequest]) (*connect.Response[userv2.ListWebhookSubscriptionsResponse], error) {
+	owner, err := serviceWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	subs, err := h.webhookUseCase.ListSubscriptions(ctx, owner)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.ListWebhookSubscriptionsResponse{
+		Subscriptions: make([]*userv2.WebhookSubscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		ret.Subscriptions = append(ret.Subscriptions, subscriptionToProtoV2(sub))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *webhookAdminServiceHandler) DeleteWebhookSubscription(ctx context.Context, req *connect.Request[userv2.DeleteWebhookSubscriptionRequest]) (*connect.Response[userv2.DeleteWebhookSubscriptionResponse], error) {
+	owner, err := serviceWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	if err := h.webhookUseCase.DeleteSubscription(ctx, req.Msg.Id, owner); err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.DeleteWebhookSubscriptionResponse{}), nil
+}
+
+func (h *webhookAdminServiceHandler) ListWebhookDeliveries(ctx context.Context, req *connect.Request[userv2.ListWebhookDeliveriesRequest]) (*connect.Response[userv2.ListWebhookDeliveriesResponse], error) {
+	owner, err := serviceWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	deliveries, err := h.webhookUseCase.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{
+		Owner:          owner,
+		SubscriptionID: req.Msg.SubscriptionId,
+		Limit:          req.Msg.Limit,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv2.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*userv2.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		ret.Deliveries = append(ret.Deliveries, deliveryToProtoV2(delivery))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *webhookAdminServiceHandler) RetryWebhookDelivery(ctx context.Context, req *connect.Request[userv2.RetryWebhookDeliveryRequest]) (*connect.Response[userv2.RetryWebhookDeliveryResponse], error) {
+	owner, err := serviceWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	delivery, err := h.webhookUseCase.RetryDelivery(ctx, req.Msg.Id, owner)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv2.RetryWebhookDeliveryResponse{
+		Delivery: deliveryToProtoV2(delivery),
+	}), nil
+}
+
+// serviceWebhookOwner returns the calling service, as identified by its
+// client certificate, which owns the subscriptions managed through this
+// service.
+func serviceWebhookOwner(ctx context.Context) (entity.WebhookOwner, error) {
+	caller, ok := mtls.CallerFromContext(ctx)
+	if !ok {
+		return entity.WebhookOwner{}, domain_error.NewUnauthorizedError("unauthenticated")
+	}
+
+	return entity.ServiceWebhookOwner(caller), nil
+}
+
+func subscriptionToProtoV2(sub *entity.WebhookSubscription) *userv2.WebhookSubscription {
+	return &userv2.WebhookSubscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Active:     sub.Active,
+		CreateTime: timestamppb.New(sub.CreatedAt.Time()),
+	}
+}
+
+func deliveryToProtoV2(delivery *entity.WebhookDelivery) *userv2.WebhookDelivery {
+	return &userv2.WebhookDelivery{
+		Id:              delivery.ID,
+		SubscriptionId:  delivery.SubscriptionID,
+		EventId:         delivery.EventID,
+		EventType:       delivery.EventType,
+		Status:          string(delivery.Status),
+		Attempts:        delivery.Attempts,
+		LastStatusCode:  delivery.LastStatusCode,
+		LastError:       delivery.LastError,
+		NextAttemptTime: timestamppb.New(delivery.NextAttemptAt.Time()),
+		CreateTime:      timestamppb.New(delivery.CreatedAt.Time()),
+	}
+}
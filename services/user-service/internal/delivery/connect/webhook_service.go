@@ -0,0 +1,158 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/phongloihong/go-shop/api/gen/user/v1"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+type webhookServiceHandler struct {
+	webhookUseCase *usecase.WebhookUseCase
+}
+
+func NewWebhookServiceHandler(
+	webhookUseCase *usecase.WebhookUseCase,
+) *webhookServiceHandler {
+	return &webhookServiceHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (h *webhookServiceHandler) CreateWebhookSubscription(ctx context.Context, req *connect.Request[userv1.CreateWebhookSubscriptionRequest]) (*connect.Response[userv1.CreateWebhookSubscriptionResponse], error) {
+	owner, err := userWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	sub, err := h.webhookUseCase.CreateSubscription(ctx, dto.CreateWebhookSubscriptionRequest{
+		Owner:      owner,
+		URL:        req.Msg.Url,
+		Secret:     req.Msg.Secret,
+		EventTypes: req.Msg.EventTypes,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.CreateWebhookSubscriptionResponse{
+		Subscription: subscriptionToProto(sub),
+	}), nil
+}
+
+func (h *webhookServiceHandler) ListWebhookSubscriptions(ctx context.Context, req *connect.Request[userv1.ListWebhookSubscriptionsRequest]) (*connect.Response[userv1.ListWebhookSubscriptionsResponse], error) {
+	owner, err := userWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	subs, err := h.webhookUseCase.ListSubscriptions(ctx, owner)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv1.ListWebhookSubscriptionsResponse{
+		Subscriptions: make([]*userv1.WebhookSubscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		ret.Subscriptions = append(ret.Subscriptions, subscriptionToProto(sub))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *webhookServiceHandler) DeleteWebhookSubscription(ctx context.Context, req *connect.Request[userv1.DeleteWebhookSubscriptionRequest]) (*connect.Response[userv1.DeleteWebhookSubscriptionResponse], error) {
+	owner, err := userWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	if err := h.webhookUseCase.DeleteSubscription(ctx, req.Msg.Id, owner); err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.DeleteWebhookSubscriptionResponse{
+		Success: true,
+	}), nil
+}
+
+func (h *webhookServiceHandler) ListWebhookDeliveries(ctx context.Context, req *connect.Request[userv1.ListWebhookDeliveriesRequest]) (*connect.Response[userv1.ListWebhookDeliveriesResponse], error) {
+	owner, err := userWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	deliveries, err := h.webhookUseCase.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{
+		Owner:          owner,
+		SubscriptionID: req.Msg.SubscriptionId,
+		Limit:          req.Msg.Limit,
+	})
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	ret := &userv1.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*userv1.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		ret.Deliveries = append(ret.Deliveries, deliveryToProto(delivery))
+	}
+
+	return connect.NewResponse(ret), nil
+}
+
+func (h *webhookServiceHandler) RetryWebhookDelivery(ctx context.Context, req *connect.Request[userv1.RetryWebhookDeliveryRequest]) (*connect.Response[userv1.RetryWebhookDeliveryResponse], error) {
+	owner, err := userWebhookOwner(ctx)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	delivery, err := h.webhookUseCase.RetryDelivery(ctx, req.Msg.Id, owner)
+	if err != nil {
+		return nil, domain_error.MapError(err)
+	}
+
+	return connect.NewResponse(&userv1.RetryWebhookDeliveryResponse{
+		Delivery: deliveryToProto(delivery),
+	}), nil
+}
+
+// userWebhookOwner returns the authenticated caller, who owns the
+// subscriptions managed through this service.
+func userWebhookOwner(ctx context.Context) (entity.WebhookOwner, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return entity.WebhookOwner{}, err
+	}
+
+	return entity.UserWebhookOwner(userID), nil
+}
+
+func subscriptionToProto(sub *entity.WebhookSubscription) *userv1.WebhookSubscription {
+	return &userv1.WebhookSubscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt.Unix(),
+	}
+}
+
+func deliveryToProto(delivery *entity.WebhookDelivery) *userv1.WebhookDelivery {
+	return &userv1.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventId:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt.Unix(),
+		CreatedAt:      delivery.CreatedAt.Unix(),
+	}
+}
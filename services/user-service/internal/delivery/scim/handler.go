@@ -0,0 +1,457 @@
+// Package scim serves the SCIM 2.0 API (RFC 7644) that identity providers
+// use to provision the users and groups of an SSO connection.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const contentType = "application/scim+json"
+
+// defaultPageSize is the page size of list requests without a count.
+const defaultPageSize = 100
+
+type handler struct {
+	useCase  *usecase.SCIMUseCase
+	maxBytes int64
+	logger   *slog.Logger
+}
+
+// connHandler serves a request authenticated as an SSO connection.
+type connHandler func(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error
+
+// NewHandler returns the handler of the /scim/v2/ routes. Requests
+// authenticate with the bearer token of their SSO connection, and only see
+// the users and groups provisioned for it.
+func NewHandler(useCase *usecase.SCIMUseCase, maxBytes int, logger *slog.Logger) http.Handler {
+	h := &handler{useCase: useCase, maxBytes: int64(maxBytes), logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /scim/v2/ServiceProviderConfig", h.auth(h.serviceProviderConfig))
+
+	mux.HandleFunc("GET /scim/v2/Users", h.auth(h.listUsers))
+	mux.HandleFunc("POST /scim/v2/Users", h.auth(h.createUser))
+	mux.HandleFunc("GET /scim/v2/Users/{id}", h.auth(h.getUser))
+	mux.HandleFunc("PUT /scim/v2/Users/{id}", h.auth(h.replaceUser))
+	mux.HandleFunc("PATCH /scim/v2/Users/{id}", h.auth(h.patchUser))
+	mux.HandleFunc("DELETE /scim/v2/Users/{id}", h.auth(h.deleteUser))
+
+	mux.HandleFunc("GET /scim/v2/Groups", h.auth(h.listGroups))
+	mux.HandleFunc("POST /scim/v2/Groups", h.auth(h.createGroup))
+	mux.HandleFunc("GET /scim/v2/Groups/{id}", h.auth(h.getGroup))
+	mux.HandleFunc("PUT /scim/v2/Groups/{id}", h.auth(h.replaceGroup))
+	mux.HandleFunc("PATCH /scim/v2/Groups/{id}", h.auth(h.patchGroup))
+	mux.HandleFunc("DELETE /scim/v2/Groups/{id}", h.auth(h.deleteGroup))
+
+	mux.HandleFunc("/scim/v2/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "", "resource not found")
+	})
+
+	return mux
+}
+
+func (h *handler) auth(next connHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			writeError(w, http.StatusUnauthorized, "", "missing bearer token")
+			return
+		}
+
+		conn, err := h.useCase.Authenticate(r.Context(), strings.TrimSpace(token))
+		if err == nil {
+			r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes)
+			err = next(w, r, conn)
+		}
+		if err != nil {
+			h.writeDomainError(w, r, err)
+		}
+	}
+}
+
+func (h *handler) serviceProviderConfig(w http.ResponseWriter, _ *http.Request, _ *entity.SSOConnection) error {
+	writeJSON(w, http.StatusOK, serviceProviderConfig)
+	return nil
+}
+
+func (h *handler) listUsers(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	params, err := listParams(r)
+	if err != nil {
+		return err
+	}
+
+	users, total, err := h.useCase.ListUsers(r.Context(), conn, params)
+	if err != nil {
+		return err
+	}
+
+	resources := make([]any, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, userToResource(user))
+	}
+	writeList(w, params, total, resources)
+
+	return nil
+}
+
+func (h *handler) createUser(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var resource userResource
+	if err := decode(r, &resource); err != nil {
+		return err
+	}
+
+	params := dto.CreateSCIMUserRequest{
+		UserName:   resource.UserName,
+		ExternalID: resource.ExternalID,
+		Active:     resource.Active == nil || *resource.Active,
+	}
+	if resource.Name != nil {
+		params.GivenName = resource.Name.GivenName
+		params.FamilyName = resource.Name.FamilyName
+	}
+
+	user, err := h.useCase.CreateUser(r.Context(), conn, params)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, userToResource(user))
+
+	return nil
+}
+
+func (h *handler) getUser(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	user, err := h.useCase.GetUser(r.Context(), conn, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, userToResource(user))
+
+	return nil
+}
+
+// replaceUser sets every supported attribute of a user; those left out of
+// the request are cleared, and active defaults to true.
+func (h *handler) replaceUser(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var resource userResource
+	if err := decode(r, &resource); err != nil {
+		return err
+	}
+
+	active := resource.Active == nil || *resource.Active
+	var name nameResource
+	if resource.Name != nil {
+		name = *resource.Name
+	}
+	params := dto.PatchSCIMUserRequest{
+		UserName:   &resource.UserName,
+		GivenName:  &name.GivenName,
+		FamilyName: &name.FamilyName,
+		ExternalID: &resource.ExternalID,
+		Active:     &active,
+	}
+
+	user, err := h.useCase.PatchUser(r.Context(), conn, r.PathValue("id"), params)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, userToResource(user))
+
+	return nil
+}
+
+func (h *handler) patchUser(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var request patchRequest
+	if err := decode(r, &request); err != nil {
+		return err
+	}
+
+	params, err := userPatch(request.Operations)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.useCase.PatchUser(r.Context(), conn, r.PathValue("id"), params)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, userToResource(user))
+
+	return nil
+}
+
+func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	if err := h.useCase.DeleteUser(r.Context(), conn, r.PathValue("id")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+func (h *handler) listGroups(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	params, err := listParams(r)
+	if err != nil {
+		return err
+	}
+
+	groups, total, err := h.useCase.ListGroups(r.Context(), conn, params)
+	if err != nil {
+		return err
+	}
+
+	resources := make([]any, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, groupToResource(group))
+	}
+	writeList(w, params, total, resources)
+
+	return nil
+}
+
+func (h *handler) createGroup(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var resource groupResource
+	if err := decode(r, &resource); err != nil {
+		return err
+	}
+
+	group, err := h.useCase.CreateGroup(r.Context(), conn, dto.CreateSCIMGroupRequest{
+		DisplayName: resource.DisplayName,
+		ExternalID:  resource.ExternalID,
+		Members:     memberIDs(resource.Members),
+	})
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, groupToResource(group))
+
+	return nil
+}
+
+func (h *handler) getGroup(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	group, err := h.useCase.GetGroup(r.Context(), conn, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, groupToResource(group))
+
+	return nil
+}
+
+func (h *handler) replaceGroup(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var resource groupResource
+	if err := decode(r, &resource); err != nil {
+		return err
+	}
+
+	group, err := h.useCase.PatchGroup(r.Context(), conn, r.PathValue("id"), dto.PatchSCIMGroupRequest{
+		DisplayName: &resource.DisplayName,
+		ExternalID:  &resource.ExternalID,
+		MemberOps:   []dto.SCIMMemberOp{{Op: "replace", UserIDs: memberIDs(resource.Members)}},
+	})
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, groupToResource(group))
+
+	return nil
+}
+
+func (h *handler) patchGroup(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	var request patchRequest
+	if err := decode(r, &request); err != nil {
+		return err
+	}
+
+	params, err := groupPatch(request.Operations)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.useCase.PatchGroup(r.Context(), conn, r.PathValue("id"), params)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, groupToResource(group))
+
+	return nil
+}
+
+func (h *handler) deleteGroup(w http.ResponseWriter, r *http.Request, conn *entity.SSOConnection) error {
+	if err := h.useCase.DeleteGroup(r.Context(), conn, r.PathValue("id")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// listParams reads the filter, startIndex and count query parameters of a
+// list request.
+func listParams(r *http.Request) (dto.ListSCIMResourcesRequest, error) {
+	query := r.URL.Query()
+	params := dto.ListSCIMResourcesRequest{StartIndex: 1, Count: defaultPageSize}
+
+	if value := query.Get("startIndex"); value != "" {
+		startIndex, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return params, invalidValue("startIndex", "must be an integer")
+		}
+		// RFC 7644 treats values below 1 as 1
+		params.StartIndex = max(int32(startIndex), 1)
+	}
+	if value := query.Get("count"); value != "" {
+		count, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return params, invalidValue("count", "must be an integer")
+		}
+		params.Count = max(int32(count), 0)
+	}
+	if value := query.Get("filter"); value != "" {
+		filter, err := parseFilter(value)
+		if err != nil {
+			return params, err
+		}
+		params.Filter = filter
+	}
+
+	return params, nil
+}
+
+// parseFilter parses the `attribute eq "value"` filters IdPs send to look a
+// resource up; other filters are rejected.
+func parseFilter(filter string) (*dto.SCIMFilter, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(filter), " ")
+	if ok {
+		var op string
+		op, rest, ok = strings.Cut(strings.TrimSpace(rest), " ")
+		ok = ok && strings.EqualFold(op, "eq")
+	}
+
+	var value string
+	if !ok || json.Unmarshal([]byte(strings.TrimSpace(rest)), &value) != nil {
+		return nil, domain_error.New(domain_error.ReasonInvalidFilter,
+			domain_error.WithMessage(`only filters of the form attribute eq "value" are supported`))
+	}
+
+	return &dto.SCIMFilter{Attribute: strings.ToLower(attribute), Value: value}, nil
+}
+
+func decode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return domain_error.New(domain_error.ReasonPayloadTooLarge,
+				domain_error.WithMessage(fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit)))
+		}
+		return errInvalidSyntax
+	}
+
+	return nil
+}
+
+// errInvalidSyntax is the error of request bodies that are not valid JSON
+// of the expected shape.
+var errInvalidSyntax = errors.New("request body is not a valid SCIM resource")
+
+func invalidValue(field, description string) error {
+	return domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation(field, description))
+}
+
+func writeList(w http.ResponseWriter, params dto.ListSCIMResourcesRequest, total int64, resources []any) {
+	writeJSON(w, http.StatusOK, listResponse{
+		Schemas:      []string{listResponseSchema},
+		TotalResults: total,
+		StartIndex:   params.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeJSON(w, status, errorResponse{
+		Schemas:  []string{errorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// writeDomainError responds with the SCIM error of err. Internal errors are
+// logged rather than shown to the IdP.
+func (h *handler) writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errInvalidSyntax) {
+		writeError(w, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	connectErr := domain_error.MapError(err)
+	reason, _ := domain_error.ReasonOf(err)
+	status := httpStatus(connectErr.Code())
+	switch {
+	case reason == domain_error.ReasonPayloadTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case status == http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scim", error="invalid_token"`)
+	case status == http.StatusInternalServerError:
+		h.logger.ErrorContext(r.Context(), "scim request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		writeError(w, status, "", "internal error")
+		return
+	}
+
+	detail := connectErr.Message()
+	var scimType string
+	switch {
+	case reason == domain_error.ReasonValidationFailed:
+		scimType = "invalidValue"
+		for _, v := range domain_error.FieldViolationsOf(err) {
+			detail += fmt.Sprintf("; %s: %s", v.Field, v.Description)
+		}
+	case reason == domain_error.ReasonInvalidFilter:
+		scimType = "invalidFilter"
+	case status == http.StatusConflict:
+		scimType = "uniqueness"
+	}
+	writeError(w, status, scimType, detail)
+}
+
+func httpStatus(code connect.Code) int {
+	switch code {
+	case connect.CodeInvalidArgument, connect.CodeFailedPrecondition, connect.CodeOutOfRange:
+		return http.StatusBadRequest
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	case connect.CodeAlreadyExists:
+		return http.StatusConflict
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case connect.CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case connect.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
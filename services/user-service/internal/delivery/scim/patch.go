@@ -0,0 +1,193 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// memberFilterPath matches the `members[value eq "id"]` path IdPs use to
+// remove a single member.
+var memberFilterPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+
+// userPatch turns the operations of a PATCH request into the changes of a
+// user. Attributes the service does not store are ignored, as RFC 7644
+// allows.
+func userPatch(ops []patchOperation) (dto.PatchSCIMUserRequest, error) {
+	var params dto.PatchSCIMUserRequest
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		path := strings.ToLower(strings.TrimSpace(op.Path))
+
+		switch kind {
+		case "add", "replace":
+		case "remove":
+			// the only optional attribute stored is externalId
+			if path == "externalid" {
+				empty := ""
+				params.ExternalID = &empty
+			}
+			continue
+		default:
+			return params, invalidValue("Operations", "unsupported operation "+op.Op)
+		}
+
+		values := map[string]json.RawMessage{path: op.Value}
+		if path == "" || path == "name" {
+			object, err := lowerKeys(op.Value)
+			if err != nil {
+				return params, errInvalidSyntax
+			}
+			values = object
+			if path == "name" {
+				values = prefixKeys(object, "name.")
+			} else if name, ok := object["name"]; ok {
+				nameValues, err := lowerKeys(name)
+				if err != nil {
+					return params, errInvalidSyntax
+				}
+				for key, value := range prefixKeys(nameValues, "name.") {
+					values[key] = value
+				}
+			}
+		}
+
+		for attribute, value := range values {
+			var err error
+			switch attribute {
+			case "username":
+				params.UserName, err = stringValue(value)
+			case "externalid":
+				params.ExternalID, err = stringValue(value)
+			case "name.givenname":
+				params.GivenName, err = stringValue(value)
+			case "name.familyname":
+				params.FamilyName, err = stringValue(value)
+			case "active":
+				params.Active, err = boolValue(value)
+			}
+			if err != nil {
+				return params, err
+			}
+		}
+	}
+
+	return params, nil
+}
+
+// groupPatch turns the operations of a PATCH request into the changes of a
+// group, keeping the order of member changes.
+func groupPatch(ops []patchOperation) (dto.PatchSCIMGroupRequest, error) {
+	var params dto.PatchSCIMGroupRequest
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		path := strings.TrimSpace(op.Path)
+
+		if kind != "add" && kind != "replace" && kind != "remove" {
+			return params, invalidValue("Operations", "unsupported operation "+op.Op)
+		}
+
+		if match := memberFilterPath.FindStringSubmatch(path); match != nil {
+			if kind != "remove" {
+				return params, invalidValue("Operations", "members can only be removed by filter")
+			}
+			var id string
+			if err := json.Unmarshal([]byte(match[1]), &id); err != nil {
+				return params, errInvalidSyntax
+			}
+			params.MemberOps = append(params.MemberOps, dto.SCIMMemberOp{Op: "remove", UserIDs: []string{id}})
+			continue
+		}
+
+		values := map[string]json.RawMessage{strings.ToLower(path): op.Value}
+		if path == "" {
+			if kind == "remove" {
+				return params, invalidValue("Operations", "remove requires a path")
+			}
+			object, err := lowerKeys(op.Value)
+			if err != nil {
+				return params, errInvalidSyntax
+			}
+			values = object
+		}
+
+		for attribute, value := range values {
+			switch attribute {
+			case "members":
+				memberOp := dto.SCIMMemberOp{Op: kind}
+				if len(value) > 0 && string(value) != "null" {
+					var members []memberResource
+					if err := json.Unmarshal(value, &members); err != nil {
+						return params, errInvalidSyntax
+					}
+					memberOp.UserIDs = memberIDs(members)
+				} else if kind == "remove" {
+					// removing the attribute removes every member
+					memberOp = dto.SCIMMemberOp{Op: "replace", UserIDs: []string{}}
+				}
+				params.MemberOps = append(params.MemberOps, memberOp)
+			case "displayname":
+				if kind == "remove" {
+					return params, invalidValue("displayName", "displayName is required")
+				}
+				displayName, err := stringValue(value)
+				if err != nil {
+					return params, err
+				}
+				params.DisplayName = displayName
+			case "externalid":
+				if kind == "remove" {
+					empty := ""
+					params.ExternalID = &empty
+					continue
+				}
+				externalID, err := stringValue(value)
+				if err != nil {
+					return params, err
+				}
+				params.ExternalID = externalID
+			}
+		}
+	}
+
+	return params, nil
+}
+
+func prefixKeys(values map[string]json.RawMessage, prefix string) map[string]json.RawMessage {
+	ret := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		ret[prefix+key] = value
+	}
+
+	return ret
+}
+
+func stringValue(raw json.RawMessage) (*string, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, errInvalidSyntax
+	}
+
+	return &value, nil
+}
+
+// boolValue also accepts "True" and "False" strings, which some IdPs send
+// for active.
+func boolValue(raw json.RawMessage) (*bool, error) {
+	var value bool
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return &value, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if value, err := strconv.ParseBool(text); err == nil {
+			return &value, nil
+		}
+	}
+
+	return nil, invalidValue("active", "must be a boolean")
+}
@@ -0,0 +1,182 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+)
+
+// The schema URNs of RFC 7643 and RFC 7644.
+const (
+	userSchema          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	groupSchema         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	listResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	errorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	serviceConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+type userResource struct {
+	Schemas    []string        `json:"schemas"`
+	ID         string          `json:"id,omitempty"`
+	ExternalID string          `json:"externalId,omitempty"`
+	UserName   string          `json:"userName"`
+	Name       *nameResource   `json:"name,omitempty"`
+	Emails     []emailResource `json:"emails,omitempty"`
+	// Active is nil in requests that leave it out, which means active.
+	Active *bool         `json:"active,omitempty"`
+	Meta   *metaResource `json:"meta,omitempty"`
+}
+
+type nameResource struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type emailResource struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type groupResource struct {
+	Schemas     []string         `json:"schemas"`
+	ID          string           `json:"id,omitempty"`
+	ExternalID  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []memberResource `json:"members"`
+	Meta        *metaResource    `json:"meta,omitempty"`
+}
+
+type memberResource struct {
+	Value string `json:"value"`
+}
+
+type metaResource struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created"`
+	LastModified string `json:"lastModified"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int32    `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type patchRequest struct {
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func userToResource(user *usecase.ProvisionedUser) *userResource {
+	active := user.SCIM.Active
+	lastModified := user.User.UpdatedAt.Time()
+	if scimModified := user.SCIM.UpdatedAt.Time(); scimModified.After(lastModified) {
+		lastModified = scimModified
+	}
+
+	return &userResource{
+		Schemas:    []string{userSchema},
+		ID:         user.User.ID,
+		ExternalID: user.SCIM.ExternalID,
+		UserName:   user.User.Email.String(),
+		Name: &nameResource{
+			GivenName:  user.User.FirstName,
+			FamilyName: user.User.LastName,
+		},
+		Emails: []emailResource{{
+			Value:   user.User.Email.String(),
+			Type:    "work",
+			Primary: true,
+		}},
+		Active: &active,
+		Meta: &metaResource{
+			ResourceType: "User",
+			Created:      formatTime(user.User.CreatedAt.Time()),
+			LastModified: formatTime(lastModified),
+		},
+	}
+}
+
+func groupToResource(group *entity.SCIMGroup) *groupResource {
+	members := make([]memberResource, 0, len(group.Members))
+	for _, id := range group.Members {
+		members = append(members, memberResource{Value: id})
+	}
+
+	return &groupResource{
+		Schemas:     []string{groupSchema},
+		ID:          group.ID,
+		ExternalID:  group.ExternalID,
+		DisplayName: group.DisplayName,
+		Members:     members,
+		Meta: &metaResource{
+			ResourceType: "Group",
+			Created:      formatTime(group.CreatedAt.Time()),
+			LastModified: formatTime(group.UpdatedAt.Time()),
+		},
+	}
+}
+
+func memberIDs(members []memberResource) []string {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.Value)
+	}
+
+	return ids
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// serviceProviderConfig tells IdPs which SCIM features the service supports.
+var serviceProviderConfig = map[string]any{
+	"schemas":        []string{serviceConfigSchema},
+	"patch":          map[string]bool{"supported": true},
+	"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter":         map[string]any{"supported": true, "maxResults": 100},
+	"changePassword": map[string]bool{"supported": false},
+	"sort":           map[string]bool{"supported": false},
+	"etag":           map[string]bool{"supported": false},
+	"authenticationSchemes": []map[string]any{{
+		"type":        "oauthbearertoken",
+		"name":        "Bearer token",
+		"description": "The token from UserAdminService.CreateScimToken",
+		"primary":     true,
+	}},
+}
+
+// lowerKeys returns the members of a JSON object keyed by their lower-cased
+// names, as SCIM attribute names are case-insensitive.
+func lowerKeys(raw json.RawMessage) (map[string]json.RawMessage, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]json.RawMessage, len(object))
+	for key, value := range object {
+		ret[strings.ToLower(key)] = value
+	}
+
+	return ret, nil
+}
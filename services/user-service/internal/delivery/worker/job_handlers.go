@@ -0,0 +1,12 @@
+package worker
+
+import (
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+)
+
+// RegisterJobHandlers registers a handler for every job kind the user
+// service enqueues.
+func RegisterJobHandlers(w *jobs.Worker, importUseCase *usecase.ImportUseCase) {
+	w.Handle(usecase.ImportUsersJobKind, importUseCase.ImportUsers)
+}
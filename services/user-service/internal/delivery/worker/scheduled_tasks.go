@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/pkg/scheduler"
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// FieldReencrypter moves encrypted fields onto the current master key.
+type FieldReencrypter interface {
+	// Reencrypt returns how many values it re-encrypted, and how many are
+	// still under other keys.
+	Reencrypt(ctx context.Context) (reencrypted, remaining int64, err error)
+}
+
+// RegisterScheduledTasks registers the user service's maintenance tasks.
+// Their schedules are set under scheduler.tasks in config.yaml.
+func RegisterScheduledTasks(
+	s *scheduler.Scheduler,
+	retention *config.RetentionConfig,
+	tagRules []config.TagRuleConfig,
+	webhookUseCase *usecase.WebhookUseCase,
+	jobQueue *jobs.Queue,
+	tagUseCase *usecase.TagUseCase,
+	retentionUseCase *usecase.RetentionUseCase,
+	reencrypter FieldReencrypter,
+	authAnomalies *usecase.AuthAnomalyDetector,
+) error {
+	err := s.Register("prune_webhook_deliveries", func(ctx context.Context) error {
+		n, err := webhookUseCase.PruneDeliveries(ctx, retention.WebhookDeliveries)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("pruned %d webhook deliveries", n)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.Register("prune_jobs", func(ctx context.Context) error {
+		n, err := jobQueue.Prune(ctx, time.Now().Add(-retention.Jobs))
+		if err != nil {
+			return err
+		}
+
+		log.Printf("pruned %d finished jobs", n)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.Register("purge_expired_data", func(ctx context.Context) error {
+		// report what was purged before a failure too
+		results, err := retentionUseCase.PurgeExpired(ctx, retention.DryRun)
+		for _, result := range results {
+			if result.DryRun {
+				log.Printf("dry run: would purge %d %s older than %s", result.Count, result.Kind, result.Before.Format(time.RFC3339))
+				continue
+			}
+			log.Printf("purged %d %s older than %s", result.Count, result.Kind, result.Before.Format(time.RFC3339))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.Register("reencrypt_fields", func(ctx context.Context) error {
+		reencrypted, remaining, err := reencrypter.Reencrypt(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("re-encrypted %d fields under the current key, %d left under older keys", reencrypted, remaining)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// credential stuffing is caught as logins fail; spikes need the
+	// complete windows, so they are checked here
+	err = s.Register("detect_auth_anomalies", func(ctx context.Context) error {
+		// the detector logs and alerts of the spikes it raises
+		_, err := authAnomalies.DetectSpikes(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	rules := make([]dto.TagRule, 0, len(tagRules))
+	for _, rule := range tagRules {
+		r := dto.TagRule{
+			Tag:         rule.Tag,
+			Filter:      rule.Filter,
+			InactiveFor: rule.InactiveFor,
+		}
+		if err := usecase.ValidateTagRule(r); err != nil {
+			return fmt.Errorf("tag rule %q: %w", rule.Tag, err)
+		}
+		rules = append(rules, r)
+	}
+
+	return s.Register("apply_tag_rules", func(ctx context.Context) error {
+		for _, rule := range rules {
+			result, err := tagUseCase.ApplyTagRule(ctx, rule)
+			if err != nil {
+				return fmt.Errorf("tag rule %q: %w", rule.Tag, err)
+			}
+
+			log.Printf("tag rule %q tagged %d users, untagged %d", rule.Tag, result.Tagged, result.Removed)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+)
+
+type WebhookDispatcher struct {
+	webhookUseCase *usecase.WebhookUseCase
+	pollInterval   time.Duration
+	batchSize      int32
+	lease          time.Duration
+}
+
+func NewWebhookDispatcher(webhookUseCase *usecase.WebhookUseCase, pollInterval time.Duration, batchSize int32, requestTimeout time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookUseCase: webhookUseCase,
+		pollInterval:   pollInterval,
+		batchSize:      batchSize,
+		// a claimed batch is sent sequentially, keep it hidden from other
+		// workers until every request in it could have timed out
+		lease: time.Duration(batchSize)*requestTimeout + time.Minute,
+	}
+}
+
+// Run polls for due deliveries until ctx is cancelled. A full batch is
+// followed immediately by the next one so a backlog drains quickly.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := d.webhookUseCase.DeliverDue(ctx, d.batchSize, d.lease)
+		if err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		if err == nil && n == int(d.batchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
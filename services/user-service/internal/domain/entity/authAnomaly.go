@@ -0,0 +1,54 @@
+package entity
+
+import (
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+)
+
+// AuthAttemptKind names the authentication attempts counted to detect
+// attacks.
+type AuthAttemptKind string
+
+const (
+	AuthAttemptLoginFailed AuthAttemptKind = "login_failed"
+	AuthAttemptRegistered  AuthAttemptKind = "registered"
+)
+
+// AuthAnomalyKind says what an AuthAnomaly is.
+type AuthAnomalyKind string
+
+const (
+	// AuthAnomalyCredentialStuffing is one address or network failing to
+	// log in as many different emails.
+	AuthAnomalyCredentialStuffing AuthAnomalyKind = "credential_stuffing"
+	// AuthAnomalyLoginFailureSpike is failed logins over the whole service
+	// far above their usual rate.
+	AuthAnomalyLoginFailureSpike AuthAnomalyKind = "login_failure_spike"
+	// AuthAnomalyRegistrationSpike is registrations over the whole service
+	// far above their usual rate.
+	AuthAnomalyRegistrationSpike AuthAnomalyKind = "registration_spike"
+)
+
+// AuthAnomaly is authentication activity that looks like an attack. Until
+// it expires, logins and registrations from Source, or from anyone for a
+// spike, get tighter rate limits and have to solve a CAPTCHA.
+type AuthAnomaly struct {
+	Kind AuthAnomalyKind `json:"kind"`
+	// Source is "ip:<address>" or "asn:<AS number>" for credential stuffing;
+	// spikes have none.
+	Source string `json:"source,omitempty"`
+	// Observed is what reached Threshold: the distinct emails Source failed
+	// to log in as, or the attempts of the last window for a spike.
+	Observed  int64             `json:"observed"`
+	Threshold int64             `json:"threshold"`
+	ExpiresAt sharedvo.DateTime `json:"expires_at"`
+}
+
+// Scope is what the anomaly covers: its Source, or its Kind for spikes,
+// which cover the whole service. One anomaly per scope is raised at a time.
+func (a *AuthAnomaly) Scope() string {
+	if a.Source != "" {
+		return a.Source
+	}
+
+	return string(a.Kind)
+}
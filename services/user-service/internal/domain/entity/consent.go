@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"fmt"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+const maxPolicyVersionLength = 50
+
+// Consent is a user's decision on a purpose, under the version of the
+// privacy policy they were shown.
+type Consent struct {
+	UserID        string                     `json:"user_id"`
+	Purpose       valueobject.ConsentPurpose `json:"purpose"`
+	Granted       bool                       `json:"granted"`
+	PolicyVersion string                     `json:"policy_version"`
+	// UpdatedAt is when the user decided; zero for purposes never decided.
+	UpdatedAt sharedvo.DateTime `json:"updated_at"`
+}
+
+func NewConsent(userID, purpose string, granted bool, policyVersion string) (*Consent, error) {
+	consent := &Consent{
+		UserID:        userID,
+		Purpose:       valueobject.ConsentPurpose(purpose),
+		Granted:       granted,
+		PolicyVersion: policyVersion,
+		UpdatedAt:     sharedvo.NewTime(utils.TimeNow()),
+	}
+
+	if err := consent.Validate(); err != nil {
+		return nil, err
+	}
+
+	return consent, nil
+}
+
+func ConsentFromDatabase(userID, purpose string, granted bool, policyVersion string, createdAt int64) *Consent {
+	return &Consent{
+		UserID:        userID,
+		Purpose:       valueobject.ConsentPurpose(purpose),
+		Granted:       granted,
+		PolicyVersion: policyVersion,
+		UpdatedAt:     sharedvo.NewTime(createdAt),
+	}
+}
+
+// DefaultConsents returns every purpose as not granted, used for the
+// purposes the user never decided on. Every purpose is opt-in.
+func DefaultConsents(userID string) []*Consent {
+	ret := make([]*Consent, 0, len(valueobject.ConsentPurposes()))
+	for _, purpose := range valueobject.ConsentPurposes() {
+		ret = append(ret, &Consent{
+			UserID:  userID,
+			Purpose: purpose,
+		})
+	}
+
+	return ret
+}
+
+func (c *Consent) Validate() error {
+	if err := c.Purpose.Validate(); err != nil {
+		return domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("consents.purpose", err.Error()))
+	}
+
+	if c.PolicyVersion == "" || len(c.PolicyVersion) > maxPolicyVersionLength {
+		return domain_error.New(
+			domain_error.ReasonValidationFailed,
+			domain_error.WithFieldViolation("consents.policy_version", fmt.Sprintf("must be 1 to %d characters", maxPolicyVersionLength)),
+		)
+	}
+
+	return nil
+}
+
+// ConsentState tells for every purpose whether the user granted it. User
+// events carry it so subscribers can honor consents without a lookup.
+type ConsentState map[valueobject.ConsentPurpose]bool
+
+// NewConsentState returns the state of consents, with the purposes missing
+// from them not granted.
+func NewConsentState(consents []*Consent) ConsentState {
+	ret := make(ConsentState, len(valueobject.ConsentPurposes()))
+	for _, purpose := range valueobject.ConsentPurposes() {
+		ret[purpose] = false
+	}
+	for _, consent := range consents {
+		ret[consent.Purpose] = consent.Granted
+	}
+
+	return ret
+}
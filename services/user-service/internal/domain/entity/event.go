@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"encoding/json"
+
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// Event types of the user lifecycle, whose subject is the user.
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+	// EventGuestUpgraded follows user.created when the user registered with
+	// a guest token; its data is a GuestUpgrade.
+	EventGuestUpgraded = "user.guest_upgraded"
+	// EventConsentsUpdated is published when the user decides on consents;
+	// its data is a ConsentChange.
+	EventConsentsUpdated = "user.consents_updated"
+	// EventSecurityAlert warns of activity the user should check; its data
+	// is a SecurityEvent.
+	EventSecurityAlert = "user.security_alert"
+	// EventAuthAnomalyDetected alerts of a likely attack on logins or
+	// registrations; its data is an AuthAnomaly. It has no subject, so only
+	// internal services receive it.
+	EventAuthAnomalyDetected = "user.auth_anomaly_detected"
+)
+
+// LoginCode is a code to email to a user to finish a challenged login. It is
+// sent through the service.Notifier, never as an event.
+type LoginCode struct {
+	UserID    string               `json:"user_id"`
+	Email     string               `json:"email"`
+	Code      string               `json:"code"`
+	ExpiresAt valueobject.DateTime `json:"expires_at"`
+}
+
+// MagicLinkEmail is a sign-in link to email to a user. It is sent through
+// the service.Notifier, never as an event.
+type MagicLinkEmail struct {
+	UserID    string               `json:"user_id"`
+	Email     string               `json:"email"`
+	URL       string               `json:"url"`
+	ExpiresAt valueobject.DateTime `json:"expires_at"`
+}
+
+// UserEventData is the data of the user.created, user.updated and
+// user.deleted events: the user with the state of their consents.
+type UserEventData struct {
+	*User
+	Consents ConsentState `json:"consents"`
+}
+
+// MarshalJSON leaves the phone number out. Encoded events are stored in the
+// webhook deliveries, and phone numbers are only ever stored encrypted.
+func (d UserEventData) MarshalJSON() ([]byte, error) {
+	type data UserEventData
+
+	return json.Marshal(struct {
+		data
+		// shadows User.Phone
+		Phone *struct{} `json:"phone,omitempty"`
+	}{data: data(d)})
+}
+
+// ConsentChange is the state of a user's consents after they changed.
+type ConsentChange struct {
+	UserID   string       `json:"user_id"`
+	Consents ConsentState `json:"consents"`
+}
+
+// GuestUpgrade tells the services holding guest activity which user it now
+// belongs to.
+type GuestUpgrade struct {
+	GuestID string `json:"guest_id"`
+	UserID  string `json:"user_id"`
+}
+
+// Event is a domain event published to integrators and other services.
+type Event struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	OccurredAt valueobject.DateTime `json:"occurred_at"`
+	// Region is where the event happened, set when the platform runs in
+	// several regions.
+	Region string `json:"region,omitempty"`
+	// Subject is the ID of the user the event is about.
+	Subject string `json:"subject"`
+	Data    any    `json:"data"`
+}
+
+func NewEvent(eventType, subject string, data any) *Event {
+	return &Event{
+		ID:         utils.NewUUID(),
+		Type:       eventType,
+		OccurredAt: valueobject.NewTime(utils.TimeNow()),
+		Subject:    subject,
+		Data:       data,
+	}
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+const maxLegalHoldReasonLength = 500
+
+// LegalHold preserves a user's data, e.g. during litigation: the retention
+// purge skips the user, who cannot be deleted until the hold is released.
+type LegalHold struct {
+	UserID string `json:"user_id"`
+	// Reason says why the data is held, e.g. a case number.
+	Reason    string            `json:"reason"`
+	CreatedAt sharedvo.DateTime `json:"created_at"`
+}
+
+// NewLegalHold fails with VALIDATION_FAILED on reason unless it is 1 to 500
+// characters.
+func NewLegalHold(userID, reason string) (*LegalHold, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxLegalHoldReasonLength {
+		return nil, domain_error.New(
+			domain_error.ReasonValidationFailed,
+			domain_error.WithFieldViolation("reason", "must be 1 to 500 characters"),
+		)
+	}
+
+	return &LegalHold{
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: sharedvo.NewTime(utils.TimeNow()),
+	}, nil
+}
+
+func LegalHoldFromDatabase(userID, reason string, createdAt int64) *LegalHold {
+	return &LegalHold{
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: sharedvo.NewTime(createdAt),
+	}
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// LoginLocation is a country a user has signed in from.
+type LoginLocation struct {
+	UserID string `json:"user_id"`
+	// Country is the ISO 3166-1 alpha-2 code, e.g. "VN".
+	Country     string            `json:"country"`
+	City        string            `json:"city"`
+	LastIP      string            `json:"last_ip"`
+	FirstSeenAt sharedvo.DateTime `json:"first_seen_at"`
+	LastSeenAt  sharedvo.DateTime `json:"last_seen_at"`
+}
+
+func NewLoginLocation(userID, ip string, location Location) *LoginLocation {
+	now := sharedvo.NewTime(utils.TimeNow())
+	return &LoginLocation{
+		UserID:      userID,
+		Country:     location.Country,
+		City:        location.City,
+		LastIP:      ip,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+}
+
+func LoginLocationFromDatabase(userID, country, city, lastIP string, firstSeenAt, lastSeenAt int64) *LoginLocation {
+	return &LoginLocation{
+		UserID:      userID,
+		Country:     country,
+		City:        city,
+		LastIP:      lastIP,
+		FirstSeenAt: sharedvo.NewTime(firstSeenAt),
+		LastSeenAt:  sharedvo.NewTime(lastSeenAt),
+	}
+}
+
+// Location is where an IP address is, as far as geolocation can tell.
+type Location struct {
+	Country string `json:"country"`
+	City    string `json:"city,omitempty"`
+	// ASN is the autonomous system the address belongs to, e.g. "AS15169",
+	// which names the network of a hosting provider or ISP.
+	ASN string `json:"asn,omitempty"`
+}
+
+// Known reports whether the country of the address could be told.
+func (l Location) Known() bool {
+	return l.Country != ""
+}
+
+// LoginChallenge holds a login from an unusual location until the user
+// enters the code emailed to them.
+type LoginChallenge struct {
+	ID     string
+	UserID string
+	// CodeHash is the SHA-256 of the code; the code itself is only emailed.
+	CodeHash string
+	IP       string
+	Location Location
+	// Attempts counts the codes entered so far, including the one being
+	// checked.
+	Attempts  int
+	ExpiresAt sharedvo.DateTime
+}
@@ -0,0 +1,13 @@
+package entity
+
+import (
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+)
+
+// MagicLink is a pending single-use sign-in link. The link itself carries a
+// signed token naming ID; only the ID and its user are stored.
+type MagicLink struct {
+	ID        string
+	UserID    string
+	ExpiresAt sharedvo.DateTime
+}
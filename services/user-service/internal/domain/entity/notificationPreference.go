@@ -0,0 +1,71 @@
+package entity
+
+import (
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+type NotificationPreference struct {
+	UserID    string                           `json:"user_id"`
+	Channel   valueobject.NotificationChannel  `json:"channel"`
+	Category  valueobject.NotificationCategory `json:"category"`
+	Enabled   bool                             `json:"enabled"`
+	UpdatedAt sharedvo.DateTime                `json:"updated_at"`
+}
+
+func NewNotificationPreference(userID, channel, category string, enabled bool) (*NotificationPreference, error) {
+	pref := &NotificationPreference{
+		UserID:    userID,
+		Channel:   valueobject.NotificationChannel(channel),
+		Category:  valueobject.NotificationCategory(category),
+		Enabled:   enabled,
+		UpdatedAt: sharedvo.NewTime(utils.TimeNow()),
+	}
+
+	if err := pref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return pref, nil
+}
+
+func NotificationPreferenceFromDatabase(userID, channel, category string, enabled bool, updatedAt int64) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:    userID,
+		Channel:   valueobject.NotificationChannel(channel),
+		Category:  valueobject.NotificationCategory(category),
+		Enabled:   enabled,
+		UpdatedAt: sharedvo.NewTime(updatedAt),
+	}
+}
+
+// DefaultNotificationPreferences returns the full channel/category matrix
+// with default values, used for users that never changed their settings.
+func DefaultNotificationPreferences(userID string) []*NotificationPreference {
+	ret := make([]*NotificationPreference, 0)
+	for _, channel := range valueobject.NotificationChannels() {
+		for _, category := range valueobject.NotificationCategories() {
+			ret = append(ret, &NotificationPreference{
+				UserID:   userID,
+				Channel:  channel,
+				Category: category,
+				Enabled:  category.DefaultEnabled(),
+			})
+		}
+	}
+
+	return ret
+}
+
+func (p *NotificationPreference) Validate() error {
+	if err := p.Channel.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Category.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// SCIMUser is a user an organization's IdP provisioned over SCIM. The IdP
+// deprovisions users by making them inactive, which stops their sign-ins.
+type SCIMUser struct {
+	UserID       string
+	ConnectionID string
+	// ExternalID is the IdP's own ID of the user, if it sent one.
+	ExternalID string
+	Active     bool
+	CreatedAt  valueobject.DateTime
+	UpdatedAt  valueobject.DateTime
+}
+
+// SCIMGroup is a group of an organization's IdP, holding users it
+// provisioned.
+type SCIMGroup struct {
+	ID           string
+	ConnectionID string
+	DisplayName  string
+	ExternalID   string
+	// Members are the user IDs in the group.
+	Members   []string
+	CreatedAt valueobject.DateTime
+	UpdatedAt valueobject.DateTime
+}
+
+func NewSCIMGroup(connectionID, displayName, externalID string, members []string) (*SCIMGroup, error) {
+	now := valueobject.NewTime(utils.TimeNow())
+	group := &SCIMGroup{
+		ID:           utils.NewUUID(),
+		ConnectionID: connectionID,
+		DisplayName:  strings.TrimSpace(displayName),
+		ExternalID:   externalID,
+		Members:      members,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (g *SCIMGroup) Validate() error {
+	if g.DisplayName == "" {
+		return domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("displayName", "display name is required"))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// SecurityEventKind says what happened in a SecurityEvent.
+type SecurityEventKind string
+
+const (
+	SecurityEventPasswordChanged SecurityEventKind = "password_changed"
+	// SecurityEventNewDevice is a login from a user agent the user never
+	// logged in with.
+	SecurityEventNewDevice SecurityEventKind = "new_device"
+	// SecurityEventUnusualLoginLocation is a right password entered from a
+	// country the user never signed in from; the login waits for an emailed
+	// code.
+	SecurityEventUnusualLoginLocation SecurityEventKind = "unusual_login_location"
+	// SecurityEventBackupCodesGenerated is a new set of backup codes, which
+	// voids the previous one.
+	SecurityEventBackupCodesGenerated SecurityEventKind = "backup_codes_generated"
+	// SecurityEventBackupCodeUsed is a challenged login finished with a
+	// backup code instead of the emailed one.
+	SecurityEventBackupCodeUsed SecurityEventKind = "backup_code_used"
+)
+
+// maxUserAgentLength bounds the stored user agent; longer ones are cut.
+const maxUserAgentLength = 512
+
+// SecurityEvent is security-relevant activity on a user's account, kept so
+// the user and support can review it.
+type SecurityEvent struct {
+	// ID orders the events of a user; zero until stored.
+	ID        int64             `json:"id"`
+	UserID    string            `json:"user_id"`
+	Kind      SecurityEventKind `json:"kind"`
+	IP        string            `json:"ip,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	// Location is where IP is, when it is known.
+	Location  *Location         `json:"location,omitempty"`
+	CreatedAt sharedvo.DateTime `json:"created_at"`
+}
+
+func NewSecurityEvent(userID string, kind SecurityEventKind, ip, userAgent string) *SecurityEvent {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return &SecurityEvent{
+		UserID:    userID,
+		Kind:      kind,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: sharedvo.NewTime(utils.TimeNow()),
+	}
+}
+
+func SecurityEventFromDatabase(id int64, userID, kind, ip, userAgent, country, city string, createdAt int64) *SecurityEvent {
+	event := &SecurityEvent{
+		ID:        id,
+		UserID:    userID,
+		Kind:      SecurityEventKind(kind),
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: sharedvo.NewTime(createdAt),
+	}
+	if country != "" {
+		event.Location = &Location{Country: country, City: city}
+	}
+
+	return event
+}
@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// SSOConnection is the OpenID Connect identity provider of an organization.
+// Users with an email in one of its Domains sign in through it only.
+type SSOConnection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Issuer is the IdP's issuer URL, from which its endpoints are
+	// discovered.
+	Issuer       string               `json:"issuer"`
+	ClientID     string               `json:"client_id"`
+	ClientSecret string               `json:"-"`
+	Domains      []string             `json:"domains"`
+	CreatedAt    valueobject.DateTime `json:"created_at"`
+	// SCIMTokenHash is the SHA-256 of the token the IdP provisions users
+	// with, or empty while it does not. Provisioned organizations sign in
+	// only the active users their IdP provisioned.
+	SCIMTokenHash string `json:"-"`
+}
+
+func NewSSOConnection(name, issuer, clientID, clientSecret string, domains []string) (*SSOConnection, error) {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !slices.Contains(normalized, domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+
+	conn := &SSOConnection{
+		ID:           utils.NewUUID(),
+		Name:         strings.TrimSpace(name),
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Domains:      normalized,
+		CreatedAt:    valueobject.NewTime(utils.TimeNow()),
+	}
+
+	if err := conn.Validate(); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func SSOConnectionFromDatabase(id, name, issuer, clientID, clientSecret string, domains []string, createdAt int64, scimTokenHash string) *SSOConnection {
+	return &SSOConnection{
+		ID:            id,
+		Name:          name,
+		Issuer:        issuer,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		Domains:       domains,
+		CreatedAt:     valueobject.NewTime(createdAt),
+		SCIMTokenHash: scimTokenHash,
+	}
+}
+
+// Validate reports every invalid field at once as a VALIDATION_FAILED error.
+func (c *SSOConnection) Validate() error {
+	var opts []domain_error.Option
+	if c.Name == "" {
+		opts = append(opts, domain_error.WithFieldViolation("name", "name is required"))
+	}
+
+	// issuers must be https; plain http is only accepted on localhost, for
+	// development IdPs
+	u, err := url.Parse(c.Issuer)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(u.Scheme == "http" && u.Hostname() == "localhost")) || u.RawQuery != "" || u.Fragment != "" {
+		opts = append(opts, domain_error.WithFieldViolation("issuer", "issuer must be an https url without query or fragment"))
+	}
+
+	if c.ClientID == "" {
+		opts = append(opts, domain_error.WithFieldViolation("client_id", "client ID is required"))
+	}
+	if c.ClientSecret == "" {
+		opts = append(opts, domain_error.WithFieldViolation("client_secret", "client secret is required"))
+	}
+
+	if len(c.Domains) == 0 {
+		opts = append(opts, domain_error.WithFieldViolation("domains", "at least one email domain is required"))
+	}
+	for _, domain := range c.Domains {
+		if !strings.Contains(domain, ".") || strings.ContainsAny(domain, "@/ ") || strings.HasPrefix(domain, ".") {
+			opts = append(opts, domain_error.WithFieldViolation("domains", "invalid email domain: "+domain))
+		}
+	}
+
+	if len(opts) > 0 {
+		return domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	return nil
+}
+
+// SSOIdentity links an account at an IdP, known by its subject, to a user.
+type SSOIdentity struct {
+	ConnectionID string
+	Subject      string
+	UserID       string
+}
+
+// SSOState is a sign-in in progress at an IdP, kept until the IdP redirects
+// back with its ID as the OAuth state.
+type SSOState struct {
+	ID           string
+	ConnectionID string
+	// Nonce is expected back in the ID token.
+	Nonce string
+	// CodeVerifier is the PKCE secret of the authorization request.
+	CodeVerifier string
+	ExpiresAt    valueobject.DateTime
+}
+
+// SSOClaims are the verified claims of an IdP's ID token.
+type SSOClaims struct {
+	Subject string
+	Email   string
+	// EmailVerified is false only if the IdP said so; IdPs that omit the
+	// claim vouch for the emails of their own domains.
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Nonce         string
+}
@@ -1,7 +1,10 @@
 package entity
 
 import (
-	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"fmt"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
 	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
 )
 
@@ -14,8 +17,13 @@ type User struct {
 	Password  valueobject.Password `json:"-"`
 	CreatedAt valueobject.DateTime `json:"created_at"`
 	UpdatedAt valueobject.DateTime `json:"updated_at"`
+	// Version starts at 1 and goes up with every profile update, so writers
+	// can tell whether the user changed since they read it.
+	Version int64 `json:"version"`
 }
 
+// NewUser validates a new user and hashes its password, so the entity only
+// ever holds the hash.
 func NewUser(firstName, lastName, email, phone, password string) (*User, error) {
 	passwordVO := valueobject.NewPassword(password)
 	emailVO := valueobject.NewEmail(email)
@@ -31,16 +39,56 @@ func NewUser(firstName, lastName, email, phone, password string) (*User, error)
 		Password:  passwordVO,
 		CreatedAt: nowVO,
 		UpdatedAt: nowVO,
+		Version:   1,
 	}
 
 	if err := user.Validate(); err != nil {
 		return nil, err
 	}
 
+	hash, err := passwordVO.Hash()
+	if err != nil {
+		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to hash password: %s", err.Error()))
+	}
+	user.Password = valueobject.NewPassword(hash)
+
 	return user, nil
 }
 
-func UserFromDatabase(id, firstName, lastName, email, phone, password string, createdAt, updatedAt int64) *User {
+// NewImportedUser builds a user moved over from another system, keeping the
+// bcrypt hash of its password there so the user can log in as before.
+func NewImportedUser(firstName, lastName, email, phone, passwordHash string) (*User, error) {
+	now := valueobject.NewTime(utils.TimeNow())
+	user := &User{
+		ID:        utils.NewUUID(),
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     valueobject.NewEmail(email),
+		Phone:     valueobject.NewPhone(phone),
+		Password:  valueobject.NewPassword(passwordHash),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+
+	var opts []domain_error.Option
+	if err := user.Email.Validate(); err != nil {
+		opts = append(opts, domain_error.WithFieldViolation("email", err.Error()))
+	}
+	if err := user.Password.ValidateHash(); err != nil {
+		opts = append(opts, domain_error.WithFieldViolation("password_hash", err.Error()))
+	}
+	if err := user.Phone.Validate(); err != nil {
+		opts = append(opts, domain_error.WithFieldViolation("phone", err.Error()))
+	}
+	if len(opts) > 0 {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	return user, nil
+}
+
+func UserFromDatabase(id, firstName, lastName, email, phone, password string, createdAt, updatedAt, version int64) *User {
 	passwordVO := valueobject.NewPassword(password)
 	emailVO := valueobject.NewEmail(email)
 	phoneVO := valueobject.NewPhone(phone)
@@ -56,22 +104,29 @@ func UserFromDatabase(id, firstName, lastName, email, phone, password string, cr
 		Password:  passwordVO,
 		CreatedAt: createdAtVO,
 		UpdatedAt: updatedAtVO,
+		Version:   version,
 	}
 
 	return user
 }
 
+// Validate reports every invalid field at once as a VALIDATION_FAILED error.
 func (u *User) Validate() error {
+	var opts []domain_error.Option
 	if err := u.Email.Validate(); err != nil {
-		return err
+		opts = append(opts, domain_error.WithFieldViolation("email", err.Error()))
 	}
 
 	if err := u.Password.Validate(); err != nil {
-		return err
+		opts = append(opts, domain_error.WithFieldViolation("password", err.Error()))
 	}
 
 	if err := u.Phone.Validate(); err != nil {
-		return err
+		opts = append(opts, domain_error.WithFieldViolation("phone", err.Error()))
+	}
+
+	if len(opts) > 0 {
+		return domain_error.New(domain_error.ReasonValidationFailed, opts...)
 	}
 
 	return nil
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// TagSource says what set a UserTag.
+type TagSource string
+
+const (
+	TagSourceAdmin TagSource = "admin"
+	// TagSourceRule tags are set and removed by the tag rules, which never
+	// touch admin tags.
+	TagSourceRule TagSource = "rule"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// UserTag puts a user in a customer segment, e.g. "vip" or "churn-risk".
+type UserTag struct {
+	UserID    string            `json:"user_id"`
+	Tag       string            `json:"tag"`
+	Source    TagSource         `json:"source"`
+	CreatedAt sharedvo.DateTime `json:"created_at"`
+	UpdatedAt sharedvo.DateTime `json:"updated_at"`
+}
+
+func NewUserTag(userID, tag string, source TagSource) (*UserTag, error) {
+	if err := ValidateTag(tag); err != nil {
+		return nil, err
+	}
+
+	now := sharedvo.NewTime(utils.TimeNow())
+	return &UserTag{
+		UserID:    userID,
+		Tag:       tag,
+		Source:    source,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func UserTagFromDatabase(userID, tag, source string, createdAt, updatedAt int64) *UserTag {
+	return &UserTag{
+		UserID:    userID,
+		Tag:       tag,
+		Source:    TagSource(source),
+		CreatedAt: sharedvo.NewTime(createdAt),
+		UpdatedAt: sharedvo.NewTime(updatedAt),
+	}
+}
+
+// ValidateTag fails with VALIDATION_FAILED on tags unless tag is lower-case
+// letters, digits and dashes, at most 50 characters.
+func ValidateTag(tag string) error {
+	if !tagPattern.MatchString(tag) {
+		return domain_error.New(
+			domain_error.ReasonValidationFailed,
+			domain_error.WithFieldViolation("tags", fmt.Sprintf("%q must be lower-case letters, digits and dashes, at most 50 characters", tag)),
+		)
+	}
+
+	return nil
+}
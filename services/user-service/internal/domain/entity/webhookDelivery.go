@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// enums
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead marks a delivery that exhausted its retries (dead letter).
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             string               `json:"id"`
+	SubscriptionID string               `json:"subscription_id"`
+	EventID        string               `json:"event_id"`
+	EventType      string               `json:"event_type"`
+	Payload        []byte               `json:"-"`
+	Status         DeliveryStatus       `json:"status"`
+	Attempts       int32                `json:"attempts"`
+	LastStatusCode int32                `json:"last_status_code"`
+	LastError      string               `json:"last_error"`
+	NextAttemptAt  valueobject.DateTime `json:"next_attempt_at"`
+	CreatedAt      valueobject.DateTime `json:"created_at"`
+	UpdatedAt      valueobject.DateTime `json:"updated_at"`
+}
+
+func NewWebhookDelivery(subscriptionID string, event *Event, payload []byte) *WebhookDelivery {
+	nowVO := valueobject.NewTime(utils.TimeNow())
+
+	return &WebhookDelivery{
+		ID:             utils.NewUUID(),
+		SubscriptionID: subscriptionID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  nowVO,
+		CreatedAt:      nowVO,
+		UpdatedAt:      nowVO,
+	}
+}
+
+func WebhookDeliveryFromDatabase(id, subscriptionID, eventID, eventType string, payload []byte, status string, attempts, lastStatusCode int32, lastError string, nextAttemptAt, createdAt, updatedAt int64) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             id,
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         DeliveryStatus(status),
+		Attempts:       attempts,
+		LastStatusCode: lastStatusCode,
+		LastError:      lastError,
+		NextAttemptAt:  valueobject.NewTime(nextAttemptAt),
+		CreatedAt:      valueobject.NewTime(createdAt),
+		UpdatedAt:      valueobject.NewTime(updatedAt),
+	}
+}
+
+func (d *WebhookDelivery) MarkSucceeded(statusCode int32) {
+	d.Attempts++
+	d.Status = DeliverySucceeded
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.UpdatedAt = valueobject.NewTime(utils.TimeNow())
+}
+
+// MarkFailed records a failed attempt and either schedules the next one or,
+// once maxAttempts is reached, moves the delivery to the dead letter state.
+func (d *WebhookDelivery) MarkFailed(statusCode int32, errMsg string, maxAttempts int32, nextAttemptAt int64) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = errMsg
+	d.UpdatedAt = valueobject.NewTime(utils.TimeNow())
+
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+
+	d.NextAttemptAt = valueobject.NewTime(nextAttemptAt)
+}
+
+// Requeue moves a dead delivery back to pending with a fresh retry budget.
+func (d *WebhookDelivery) Requeue() {
+	nowVO := valueobject.NewTime(utils.TimeNow())
+
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = nowVO
+	d.UpdatedAt = nowVO
+}
@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// WildcardEventType subscribes to every event type.
+const WildcardEventType = "*"
+
+var eventTypePattern = regexp.MustCompile(`^[a-z]+(\.[a-z_]+)+$`)
+
+// sharedAddressSpace is carrier-grade NAT (RFC 6598), which is not global
+// though netip does not call it private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WebhookOwner manages a subscription: a user, or an internal service
+// identified by its SPIFFE ID. Exactly one of the fields is set.
+type WebhookOwner struct {
+	UserID  string `json:"user_id,omitempty"`
+	Service string `json:"service,omitempty"`
+}
+
+func UserWebhookOwner(userID string) WebhookOwner {
+	return WebhookOwner{UserID: userID}
+}
+
+func ServiceWebhookOwner(spiffeID string) WebhookOwner {
+	return WebhookOwner{Service: spiffeID}
+}
+
+type WebhookSubscription struct {
+	ID         string               `json:"id"`
+	Owner      WebhookOwner         `json:"owner"`
+	URL        string               `json:"url"`
+	Secret     string               `json:"-"`
+	EventTypes []string             `json:"event_types"`
+	Active     bool                 `json:"active"`
+	CreatedAt  valueobject.DateTime `json:"created_at"`
+	UpdatedAt  valueobject.DateTime `json:"updated_at"`
+}
+
+func NewWebhookSubscription(owner WebhookOwner, rawURL, secret string, eventTypes []string) (*WebhookSubscription, error) {
+	nowVO := valueobject.NewTime(utils.TimeNow())
+
+	sub := &WebhookSubscription{
+		ID:         utils.NewUUID(),
+		Owner:      owner,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedAt:  nowVO,
+		UpdatedAt:  nowVO,
+	}
+
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+func WebhookSubscriptionFromDatabase(id string, owner WebhookOwner, rawURL, secret string, eventTypes []string, active bool, createdAt, updatedAt int64) *WebhookSubscription {
+	return &WebhookSubscription{
+		ID:         id,
+		Owner:      owner,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     active,
+		CreatedAt:  valueobject.NewTime(createdAt),
+		UpdatedAt:  valueobject.NewTime(updatedAt),
+	}
+}
+
+// Validate reports every invalid field at once as a VALIDATION_FAILED error.
+func (s *WebhookSubscription) Validate() error {
+	var opts []domain_error.Option
+	u, err := url.Parse(s.URL)
+	switch {
+	case err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http"):
+		opts = append(opts, domain_error.WithFieldViolation("url", "webhook url must be an absolute http(s) url"))
+	case s.RestrictsAddresses() && u.Scheme != "https":
+		opts = append(opts, domain_error.WithFieldViolation("url", "webhook url must use https"))
+	case s.RestrictsAddresses() && !publicHost(u.Hostname()):
+		opts = append(opts, domain_error.WithFieldViolation("url", "webhook url must point to a public host"))
+	}
+
+	if len(s.Secret) < 16 {
+		opts = append(opts, domain_error.WithFieldViolation("secret", "webhook secret must be at least 16 characters long"))
+	}
+
+	if len(s.EventTypes) == 0 {
+		opts = append(opts, domain_error.WithFieldViolation("event_types", "webhook must subscribe to at least one event type"))
+	}
+
+	for _, eventType := range s.EventTypes {
+		if eventType != WildcardEventType && !eventTypePattern.MatchString(eventType) {
+			opts = append(opts, domain_error.WithFieldViolation("event_types", "invalid event type: "+eventType))
+		}
+	}
+
+	if len(opts) > 0 {
+		return domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	return nil
+}
+
+// RestrictsAddresses reports whether deliveries may only reach global
+// addresses. Users' subscriptions may not reach the internal network, while
+// internal services' subscriptions usually point into it.
+func (s *WebhookSubscription) RestrictsAddresses() bool {
+	return s.Owner.Service == ""
+}
+
+// IsPublicAddress reports whether addr is a global unicast address, which a
+// user's webhook may be delivered to: not loopback, private, link-local (such
+// as the 169.254.169.254 metadata service), shared or unspecified.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// publicHost rejects hosts that are known to be internal without resolving
+// them; names are checked again on every delivery, once resolved.
+func publicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddress(addr)
+	}
+
+	return true
+}
+
+// Matches reports whether the subscription wants the event. A user's
+// subscriptions only receive events about that user, while an internal
+// service's receive those of every user.
+func (s *WebhookSubscription) Matches(event *Event) bool {
+	if s.Owner.Service == "" && s.Owner.UserID != event.Subject {
+		return false
+	}
+
+	return s.Active && (slices.Contains(s.EventTypes, WildcardEventType) || slices.Contains(s.EventTypes, event.Type))
+}
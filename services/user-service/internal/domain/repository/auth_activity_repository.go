@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// AuthActivityRepository counts authentication attempts in fixed windows
+// shared by every replica, and keeps the anomalies raised from them until
+// they expire.
+type AuthActivityRepository interface {
+	// CountAttempt counts an attempt of kind in the window starting at
+	// window; the count is kept for ttl.
+	CountAttempt(ctx context.Context, kind entity.AuthAttemptKind, window time.Time, ttl time.Duration) error
+	// GetAttemptCounts returns the attempts of kind in the windows starting
+	// at each of windows, zero for windows without any.
+	GetAttemptCounts(ctx context.Context, kind entity.AuthAttemptKind, windows []time.Time) ([]int64, error)
+	// AddFailedLogin records that source failed to log in as email in the
+	// window starting at window, and returns how many distinct emails source
+	// failed to log in as in it; the count is approximate, off by about 1%.
+	AddFailedLogin(ctx context.Context, source, email string, window time.Time, ttl time.Duration) (int64, error)
+	// RaiseAnomaly keeps anomaly until it expires. It reports false, and
+	// keeps the raised one, when an anomaly of the same scope is raised.
+	RaiseAnomaly(ctx context.Context, anomaly *entity.AuthAnomaly) (bool, error)
+	// HasAnomaly reports whether an anomaly of any of scopes is raised.
+	HasAnomaly(ctx context.Context, scopes ...string) (bool, error)
+}
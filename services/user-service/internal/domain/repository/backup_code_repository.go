@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+)
+
+// BackupCodeRepository keeps the hashes of each user's backup codes.
+type BackupCodeRepository interface {
+	// ReplaceBackupCodes drops the user's codes, used or not, for a new set.
+	ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error
+	// ConsumeBackupCode marks the user's unused code with codeHash as used,
+	// and reports whether there was one.
+	ConsumeBackupCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CountUnusedBackupCodes(ctx context.Context, userID string) (int, error)
+}
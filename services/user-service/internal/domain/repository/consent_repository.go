@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ConsentRepository keeps every consent decision, so we can show what a user
+// agreed to and when; the latest decision per purpose is in force.
+type ConsentRepository interface {
+	// ListConsents returns the decision in force for each purpose the user
+	// decided on.
+	ListConsents(ctx context.Context, userID string) ([]*entity.Consent, error)
+	// ListConsentRecords returns every decision of the user, oldest first.
+	ListConsentRecords(ctx context.Context, userID string) ([]*entity.Consent, error)
+	// SaveConsent records a decision, keeping the earlier ones.
+	SaveConsent(ctx context.Context, consent *entity.Consent) error
+}
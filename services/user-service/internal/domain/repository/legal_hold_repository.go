@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// LegalHoldRepository keeps the legal holds of users.
+type LegalHoldRepository interface {
+	// GetLegalHold fails with NOT_FOUND when the user is not under a hold.
+	GetLegalHold(ctx context.Context, userID string) (*entity.LegalHold, error)
+	// SaveLegalHold places the hold, or replaces the reason of the user's
+	// hold; CreatedAt is set to when the hold was first placed.
+	SaveLegalHold(ctx context.Context, hold *entity.LegalHold) error
+	// DeleteLegalHold releases the user's hold. It affects no rows when the
+	// user is not under one.
+	DeleteLegalHold(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// LoginLocationRepository remembers the countries each user signed in from.
+type LoginLocationRepository interface {
+	// ListLoginLocations returns the user's locations, most recently seen
+	// first.
+	ListLoginLocations(ctx context.Context, userID string) ([]*entity.LoginLocation, error)
+	// SaveLoginLocation records a login from the location's country, keeping
+	// when the country was first seen.
+	SaveLoginLocation(ctx context.Context, location *entity.LoginLocation) error
+	// CountExpiredLoginLocations counts the locations of every user last
+	// seen before before, except those of users under a legal hold.
+	CountExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error)
+	// DeleteExpiredLoginLocations deletes the locations
+	// CountExpiredLoginLocations counts.
+	DeleteExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LoginChallengeRepository keeps pending login challenges until they expire.
+type LoginChallengeRepository interface {
+	CreateChallenge(ctx context.Context, challenge *entity.LoginChallenge, ttl time.Duration) error
+	// ReserveAttempt counts an attempt at the challenge before its code is
+	// checked, and returns the challenge with the attempt counted, so
+	// concurrent guesses never share a count. It fails with NOT_FOUND once
+	// the challenge expired or was deleted.
+	ReserveAttempt(ctx context.Context, id string) (*entity.LoginChallenge, error)
+	// DeleteChallenge reports whether the challenge still existed, so only
+	// one caller can use its code.
+	DeleteChallenge(ctx context.Context, id string) (bool, error)
+}
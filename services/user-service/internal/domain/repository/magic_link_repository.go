@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// MagicLinkRepository keeps issued magic links until they are used or
+// expire.
+type MagicLinkRepository interface {
+	CreateMagicLink(ctx context.Context, link *entity.MagicLink, ttl time.Duration) error
+	// TakeMagicLink deletes the link and returns it, so only one caller gets
+	// it. It fails with NOT_FOUND once the link expired or was taken.
+	TakeMagicLink(ctx context.Context, id string) (*entity.MagicLink, error)
+}
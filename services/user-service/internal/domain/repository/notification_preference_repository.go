@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type NotificationPreferenceRepository interface {
+	GetPreferencesByUserID(ctx context.Context, userID string) ([]*entity.NotificationPreference, error)
+	GetPreference(ctx context.Context, userID, channel, category string) (*entity.NotificationPreference, error)
+	UpsertPreference(ctx context.Context, pref *entity.NotificationPreference) error
+}
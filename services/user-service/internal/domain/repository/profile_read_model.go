@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ProfileReadModel is a denormalized copy of user profiles that profile
+// reads are served from. It is kept up to date from the user events rather
+// than by the writes, so it may lag the users table and may not hold every
+// user.
+type ProfileReadModel interface {
+	// SaveProfile stores the profile unless a later version of it, or the
+	// user's deletion, is stored already. The password is not stored.
+	SaveProfile(ctx context.Context, user *entity.User) error
+	// DeleteProfile records that the user was deleted, so a late event
+	// cannot bring the profile back.
+	DeleteProfile(ctx context.Context, id string) error
+	// GetProfiles returns the stored profiles among ids, and the ids it holds
+	// nothing about, which must be read from the users table. Deleted users
+	// are in neither.
+	GetProfiles(ctx context.Context, ids []string) (profiles []*entity.User, missing []string, err error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// SCIMRepository keeps the users and groups organizations' IdPs provisioned.
+type SCIMRepository interface {
+	// CreateUser fails with ALREADY_EXISTS if the user was provisioned
+	// before.
+	CreateUser(ctx context.Context, user *entity.SCIMUser) error
+	// GetUser fails with NOT_FOUND for users no IdP provisioned.
+	GetUser(ctx context.Context, userID string) (*entity.SCIMUser, error)
+	GetUserByExternalID(ctx context.Context, connectionID, externalID string) (*entity.SCIMUser, error)
+	// ListUsers returns a page of the connection's users, oldest first, with
+	// the number of them.
+	ListUsers(ctx context.Context, connectionID string, offset, limit int32) ([]*entity.SCIMUser, int64, error)
+	// ListUsersByIDs returns those of ids the connection provisioned.
+	ListUsersByIDs(ctx context.Context, connectionID string, ids []string) ([]*entity.SCIMUser, error)
+	UpdateUser(ctx context.Context, user *entity.SCIMUser) error
+	// DeleteUser forgets the user with its group memberships.
+	DeleteUser(ctx context.Context, userID string) error
+
+	// CreateGroup fails with ALREADY_EXISTS if the connection has a group of
+	// the same display name.
+	CreateGroup(ctx context.Context, group *entity.SCIMGroup) error
+	GetGroup(ctx context.Context, connectionID, id string) (*entity.SCIMGroup, error)
+	GetGroupByDisplayName(ctx context.Context, connectionID, displayName string) (*entity.SCIMGroup, error)
+	GetGroupByExternalID(ctx context.Context, connectionID, externalID string) (*entity.SCIMGroup, error)
+	// ListGroups returns a page of the connection's groups, oldest first,
+	// with the number of them.
+	ListGroups(ctx context.Context, connectionID string, offset, limit int32) ([]*entity.SCIMGroup, int64, error)
+	// UpdateGroup writes the display name and external ID of the group.
+	UpdateGroup(ctx context.Context, group *entity.SCIMGroup) error
+	AddGroupMembers(ctx context.Context, groupID string, userIDs []string) error
+	RemoveGroupMembers(ctx context.Context, groupID string, userIDs []string) error
+	ReplaceGroupMembers(ctx context.Context, groupID string, userIDs []string) error
+	// DeleteGroup reports whether the group existed.
+	DeleteGroup(ctx context.Context, connectionID, id string) (bool, error)
+}
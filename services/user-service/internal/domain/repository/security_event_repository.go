@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// SecurityEventRepository keeps the security events of each user.
+type SecurityEventRepository interface {
+	// ListSecurityEvents returns up to limit events of the user older than
+	// beforeID, newest first; a zero beforeID starts from the newest.
+	ListSecurityEvents(ctx context.Context, userID string, beforeID int64, limit int32) ([]*entity.SecurityEvent, error)
+	// HasUserAgent reports whether the user has an event of kind from
+	// userAgent.
+	HasUserAgent(ctx context.Context, userID string, kind entity.SecurityEventKind, userAgent string) (bool, error)
+	// SaveSecurityEvent stores the event and sets its ID.
+	SaveSecurityEvent(ctx context.Context, event *entity.SecurityEvent) error
+	// CountExpiredSecurityEvents counts the events of every user created
+	// before before, except those of users under a legal hold.
+	CountExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error)
+	// DeleteExpiredSecurityEvents deletes the events
+	// CountExpiredSecurityEvents counts.
+	DeleteExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error)
+}
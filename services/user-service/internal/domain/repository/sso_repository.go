@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// SSOConnectionRepository keeps the identity providers of organizations.
+type SSOConnectionRepository interface {
+	// CreateConnection fails with ALREADY_EXISTS if another connection has
+	// one of the domains.
+	CreateConnection(ctx context.Context, conn *entity.SSOConnection) error
+	GetConnection(ctx context.Context, id string) (*entity.SSOConnection, error)
+	// GetConnectionByDomain fails with NOT_FOUND when no connection owns the
+	// email domain.
+	GetConnectionByDomain(ctx context.Context, domain string) (*entity.SSOConnection, error)
+	ListConnections(ctx context.Context) ([]*entity.SSOConnection, error)
+	// SetSCIMToken sets the hash of the connection's SCIM token, and reports
+	// whether the connection exists.
+	SetSCIMToken(ctx context.Context, id, tokenHash string) (bool, error)
+	// GetConnectionBySCIMToken fails with NOT_FOUND for unknown hashes.
+	GetConnectionBySCIMToken(ctx context.Context, tokenHash string) (*entity.SSOConnection, error)
+	// DeleteConnection removes the connection with its domains, identities
+	// and SCIM users and groups, and reports whether it existed.
+	DeleteConnection(ctx context.Context, id string) (bool, error)
+
+	// GetIdentity fails with NOT_FOUND for accounts never signed in with.
+	GetIdentity(ctx context.Context, connectionID, subject string) (*entity.SSOIdentity, error)
+	// SaveIdentity links the account to its user, or records another
+	// sign-in of a linked one.
+	SaveIdentity(ctx context.Context, identity *entity.SSOIdentity) error
+}
+
+// SSOStateRepository keeps sign-ins in progress at an IdP until they expire.
+type SSOStateRepository interface {
+	CreateState(ctx context.Context, state *entity.SSOState, ttl time.Duration) error
+	// TakeState deletes the state and returns it, so each works once. It
+	// fails with NOT_FOUND once the state expired or was taken.
+	TakeState(ctx context.Context, id string) (*entity.SSOState, error)
+}
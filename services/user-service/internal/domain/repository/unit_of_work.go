@@ -0,0 +1,16 @@
+package repository
+
+import "context"
+
+// UnitOfWork runs fn atomically: repository calls made with the ctx passed to
+// fn share one transaction, which is rolled back if fn returns an error.
+// Nested calls roll back only their own work.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// UserUnitOfWork is UnitOfWork for the rows of one user, such as their
+// notification preferences, which live on the user's shard.
+type UserUnitOfWork interface {
+	Do(ctx context.Context, userID string, fn func(ctx context.Context) error) error
+}
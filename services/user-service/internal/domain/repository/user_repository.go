@@ -3,14 +3,44 @@ package repository
 import (
 	"context"
 
+	"github.com/phongloihong/go-shop/pkg/filter"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	// UpdateUser writes user's profile fields if the stored user is still at
+	// user.Version, and on success bumps user.Version to the stored one. It
+	// affects no rows when the user is missing or was changed since.
 	UpdateUser(ctx context.Context, user *entity.User) (int64, error)
 	ChangePassword(ctx context.Context, id string, newPassword string) (int64, error)
+	// DeleteUser deletes the user with its notification preferences, tags and
+	// consents. It affects no rows when the user is missing.
+	DeleteUser(ctx context.Context, id string) (int64, error)
 	GetUserByID(ctx context.Context, id string) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	// EmailKeyExists reports whether a user's email has the given
+	// valueobject.Email.DedupKey.
+	EmailKeyExists(ctx context.Context, key string) (bool, error)
 	GetPublicProfileByIds(ctx context.Context, ids []string) ([]*entity.UserPublicProfile, error)
+	// GetUsersByIDs returns the users among ids that exist, in no particular
+	// order. ids must be UUIDs.
+	GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error)
+	// ListUsersAfter returns up to limit users with an ID greater than
+	// afterID that match where, in ID order; an empty afterID starts from the
+	// first user and a nil where matches every user. where is parsed against
+	// UserFilterSchema.
+	ListUsersAfter(ctx context.Context, afterID string, limit int32, where filter.Expr) ([]*entity.User, error)
+}
+
+// UserFilterSchema lists the fields users can be filtered on, named as in
+// user.v2.User.
+var UserFilterSchema = filter.Schema{
+	"id":               filter.String,
+	"email":            filter.String,
+	"phone":            filter.String,
+	"name.given_name":  filter.String,
+	"name.family_name": filter.String,
+	"create_time":      filter.Timestamp,
+	"update_time":      filter.Timestamp,
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type UserTagRepository interface {
+	// ListTags returns the tags of the users among userIDs, by user and then
+	// tag. userIDs must be UUIDs.
+	ListTags(ctx context.Context, userIDs []string) ([]*entity.UserTag, error)
+	// SaveTag adds tag, or refreshes its UpdatedAt if the user has it. An
+	// admin tag stays an admin tag when a rule saves it.
+	SaveTag(ctx context.Context, tag *entity.UserTag) error
+	// DeleteTag removes tag from the user whatever its source. It affects no
+	// rows when the user does not have it.
+	DeleteTag(ctx context.Context, userID, tag string) (int64, error)
+	// DeleteStaleRuleTags removes the rule tags named tag that were last
+	// saved before before, from every user.
+	DeleteStaleRuleTags(ctx context.Context, tag string, before time.Time) (int64, error)
+}
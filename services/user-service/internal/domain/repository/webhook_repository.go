@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	ListSubscriptionsByOwner(ctx context.Context, owner entity.WebhookOwner) ([]*entity.WebhookSubscription, error)
+	// ListActiveSubscriptionsForEvent returns the active subscriptions that
+	// want events of eventType about subject, as WebhookSubscription.Matches
+	// decides.
+	ListActiveSubscriptionsForEvent(ctx context.Context, eventType, subject string) ([]*entity.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string, owner entity.WebhookOwner) (int64, error)
+
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries that are due and
+	// pushes their next attempt past lease so concurrent workers skip them.
+	ClaimDueDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]*entity.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	ListDeliveriesBySubscription(ctx context.Context, subscriptionID string, limit int32) ([]*entity.WebhookDelivery, error)
+	// DeleteFinishedDeliveries deletes succeeded and dead deliveries last
+	// updated before the given time and returns how many it deleted.
+	DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
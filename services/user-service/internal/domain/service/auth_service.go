@@ -8,6 +8,8 @@ type (
 	TokenClaims struct {
 		UserID  string
 		TokenID string
+		// GuestID is set instead of UserID in guest tokens.
+		GuestID string `json:",omitempty"`
 	}
 
 	TokenPairs struct {
@@ -21,6 +23,12 @@ type AuthService interface {
 	// Token life cycle management
 	GenerateToken(user *entity.User) (*TokenPairs, error)
 	ValidateToken(token string, secret []byte) (*TokenClaims, error)
+	// GenerateGuestToken issues an access token for a visitor without an
+	// account; it has no refresh token.
+	GenerateGuestToken(guestID string) (*TokenPairs, error)
+	// ValidateGuestToken returns the guest ID of a token issued by
+	// GenerateGuestToken.
+	ValidateGuestToken(token string) (string, error)
 	// RefreshToken(token string) (*TokenPairs, error)
 
 	// Token Management
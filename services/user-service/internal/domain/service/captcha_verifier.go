@@ -0,0 +1,10 @@
+package service
+
+import "context"
+
+type CaptchaVerifier interface {
+	// Verify reports whether token is a CAPTCHA solved from ip. An empty
+	// token is not, unless CAPTCHAs are not set up, in which case every
+	// token is.
+	Verify(ctx context.Context, token, ip string) (bool, error)
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type EventPublisher interface {
+	// Publish hands the event to its subscribers, e.g. by queueing webhook
+	// deliveries.
+	Publish(ctx context.Context, event *entity.Event) error
+}
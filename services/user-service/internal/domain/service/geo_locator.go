@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type GeoLocator interface {
+	// Locate returns where ip is. Addresses it cannot place, such as private
+	// ones, have an unknown location rather than an error.
+	Locate(ctx context.Context, ip string) (entity.Location, error)
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// IdentityProvider runs the OpenID Connect authorization code flow against
+// the IdP of a connection.
+type IdentityProvider interface {
+	// AuthorizationURL returns where to send the user to sign in at the IdP,
+	// which then redirects to redirectURL with state.ID and a code.
+	AuthorizationURL(ctx context.Context, conn *entity.SSOConnection, redirectURL string, state *entity.SSOState) (string, error)
+	// Exchange trades the code for an ID token and returns its claims once
+	// the token's signature, issuer, audience and expiry are verified.
+	// Checking the nonce is up to the caller.
+	Exchange(ctx context.Context, conn *entity.SSOConnection, redirectURL, code, codeVerifier string) (*entity.SSOClaims, error)
+}
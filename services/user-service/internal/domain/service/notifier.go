@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// Notifier hands secrets meant for one user, such as login codes and
+// sign-in links, straight to the notification service. Unlike events, what it sends is never
+// stored, so the secrets cannot be read back later.
+type Notifier interface {
+	SendLoginCode(ctx context.Context, code *entity.LoginCode) error
+	SendMagicLink(ctx context.Context, link *entity.MagicLinkEmail) error
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+type WebhookSender interface {
+	// Send posts a signed delivery to the subscription URL and returns the
+	// HTTP status code. A non-nil error means the attempt failed.
+	Send(ctx context.Context, sub *entity.WebhookSubscription, delivery *entity.WebhookDelivery) (int32, error)
+}
@@ -0,0 +1,44 @@
+package valueobject
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ConsentPurpose is something the user must agree to before we do it.
+type ConsentPurpose string
+
+const (
+	ConsentMarketingEmail   ConsentPurpose = "marketing_email"
+	ConsentMarketingSMS     ConsentPurpose = "marketing_sms"
+	ConsentMarketingPush    ConsentPurpose = "marketing_push"
+	ConsentAnalyticsCookies ConsentPurpose = "analytics_cookies"
+)
+
+func ConsentPurposes() []ConsentPurpose {
+	return []ConsentPurpose{ConsentMarketingEmail, ConsentMarketingSMS, ConsentMarketingPush, ConsentAnalyticsCookies}
+}
+
+func (p ConsentPurpose) String() string {
+	return string(p)
+}
+
+func (p ConsentPurpose) Validate() error {
+	if !slices.Contains(ConsentPurposes(), p) {
+		return fmt.Errorf("invalid consent purpose: %s", p)
+	}
+
+	return nil
+}
+
+// MarketingConsent is the purpose marketing over channel needs consent for.
+func MarketingConsent(channel NotificationChannel) ConsentPurpose {
+	switch channel {
+	case ChannelEmail:
+		return ConsentMarketingEmail
+	case ChannelSMS:
+		return ConsentMarketingSMS
+	default:
+		return ConsentMarketingPush
+	}
+}
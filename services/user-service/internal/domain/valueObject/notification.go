@@ -0,0 +1,61 @@
+package valueobject
+
+import (
+	"fmt"
+	"slices"
+)
+
+// enums
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	ChannelSMS   NotificationChannel = "sms"
+	ChannelPush  NotificationChannel = "push"
+)
+
+func NotificationChannels() []NotificationChannel {
+	return []NotificationChannel{ChannelEmail, ChannelSMS, ChannelPush}
+}
+
+func (c NotificationChannel) String() string {
+	return string(c)
+}
+
+func (c NotificationChannel) Validate() error {
+	if !slices.Contains(NotificationChannels(), c) {
+		return fmt.Errorf("invalid notification channel: %s", c)
+	}
+
+	return nil
+}
+
+type NotificationCategory string
+
+const (
+	CategoryTransactional NotificationCategory = "transactional"
+	CategoryMarketing     NotificationCategory = "marketing"
+)
+
+func NotificationCategories() []NotificationCategory {
+	return []NotificationCategory{CategoryTransactional, CategoryMarketing}
+}
+
+func (c NotificationCategory) String() string {
+	return string(c)
+}
+
+func (c NotificationCategory) Validate() error {
+	if !slices.Contains(NotificationCategories(), c) {
+		return fmt.Errorf("invalid notification category: %s", c)
+	}
+
+	return nil
+}
+
+// DefaultEnabled reports whether a category is delivered when the user has
+// not stored a preference. Transactional messages are opt-out, marketing
+// messages are opt-in.
+func (c NotificationCategory) DefaultEnabled() bool {
+	return c == CategoryTransactional
+}
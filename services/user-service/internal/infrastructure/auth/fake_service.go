@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+// FakeService is an in-memory AuthService for usecase tests and local
+// development. It issues opaque, readable tokens instead of JWTs and only
+// accepts tokens it issued itself; the secret passed to ValidateToken is
+// ignored.
+type FakeService struct {
+	mu        sync.RWMutex
+	expiresIn int64
+	tokens    map[string]*service.TokenClaims
+	issued    int
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{
+		expiresIn: 30 * 60,
+		tokens:    make(map[string]*service.TokenClaims),
+	}
+}
+
+var _ service.AuthService = (*FakeService)(nil)
+
+func (f *FakeService) GenerateToken(user *entity.User) (*service.TokenPairs, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.issued++
+	tokenID := utils.NewUUID()
+	accessToken := fmt.Sprintf("fake-access-%d-%s", f.issued, user.ID)
+	refreshToken := fmt.Sprintf("fake-refresh-%d-%s", f.issued, user.ID)
+	for _, token := range []string{accessToken, refreshToken} {
+		f.tokens[token] = &service.TokenClaims{UserID: user.ID, TokenID: tokenID}
+	}
+
+	return &service.TokenPairs{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    f.expiresIn,
+	}, nil
+}
+
+func (f *FakeService) GenerateGuestToken(guestID string) (*service.TokenPairs, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.issued++
+	accessToken := fmt.Sprintf("fake-guest-%d-%s", f.issued, guestID)
+	f.tokens[accessToken] = &service.TokenClaims{GuestID: guestID, TokenID: utils.NewUUID()}
+
+	return &service.TokenPairs{
+		AccessToken: accessToken,
+		ExpiresIn:   f.expiresIn,
+	}, nil
+}
+
+func (f *FakeService) ValidateGuestToken(token string) (string, error) {
+	claims, err := f.ValidateToken(token, nil)
+	if err != nil {
+		return "", err
+	}
+	if claims.GuestID == "" {
+		return "", domain_error.NewInvalidData("not a guest token")
+	}
+
+	return claims.GuestID, nil
+}
+
+func (f *FakeService) ValidateToken(token string, _ []byte) (*service.TokenClaims, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	claims, ok := f.tokens[token]
+	if !ok {
+		return nil, domain_error.NewInvalidData("invalid token claims or token is not valid")
+	}
+
+	copied := *claims
+	return &copied, nil
+}
+
+// Revoke makes token fail validation from now on, e.g. to test expiry.
+func (f *FakeService) Revoke(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.tokens, token)
+}
@@ -5,7 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	domain_error "github.com/phongloihong/go-shop/services/user-service/internal/domain/domain_errors"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
 	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
@@ -16,14 +16,16 @@ type JWTService struct {
 	refreshSecret    []byte
 	accessExpiresIn  time.Duration
 	refreshExpiresIn time.Duration
+	guestExpiresIn   time.Duration
 }
 
-func NewJWTService(accessSecret []byte, refreshSecret []byte, accessExpiresIn, refreshExpiresIn time.Duration) service.AuthService {
+func NewJWTService(accessSecret []byte, refreshSecret []byte, accessExpiresIn, refreshExpiresIn, guestExpiresIn time.Duration) service.AuthService {
 	return &JWTService{
 		accessSecret:     accessSecret,
 		refreshSecret:    refreshSecret,
 		accessExpiresIn:  accessExpiresIn,
 		refreshExpiresIn: refreshExpiresIn,
+		guestExpiresIn:   guestExpiresIn,
 	}
 }
 
@@ -35,12 +37,14 @@ type customClaims struct {
 func (j *JWTService) GenerateToken(user *entity.User) (*service.TokenPairs, error) {
 	createTime := time.Now()
 
-	accessToken, err := j.signToken(user, createTime, j.accessSecret, j.accessExpiresIn)
+	claims := service.TokenClaims{UserID: user.ID}
+
+	accessToken, err := j.signToken(claims, createTime, j.accessSecret, j.accessExpiresIn)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := j.signToken(user, createTime, j.refreshSecret, j.refreshExpiresIn)
+	refreshToken, err := j.signToken(claims, createTime, j.refreshSecret, j.refreshExpiresIn)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +56,33 @@ func (j *JWTService) GenerateToken(user *entity.User) (*service.TokenPairs, erro
 	}, err
 }
 
+// GenerateGuestToken signs guest tokens with the access secret, so services
+// that accept access tokens can read the guest ID too; handlers that need a
+// user reject them, since their UserID is empty.
+func (j *JWTService) GenerateGuestToken(guestID string) (*service.TokenPairs, error) {
+	accessToken, err := j.signToken(service.TokenClaims{GuestID: guestID}, time.Now(), j.accessSecret, j.guestExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.TokenPairs{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(j.guestExpiresIn.Seconds()),
+	}, nil
+}
+
+func (j *JWTService) ValidateGuestToken(token string) (string, error) {
+	claims, err := j.ValidateToken(token, j.accessSecret)
+	if err != nil {
+		return "", err
+	}
+	if claims.GuestID == "" {
+		return "", domain_error.NewInvalidData("not a guest token")
+	}
+
+	return claims.GuestID, nil
+}
+
 func (j *JWTService) ValidateToken(tokenString string, secret []byte) (*service.TokenClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &customClaims{}, func(token *jwt.Token) (any, error) {
 		// check signing method
@@ -72,11 +103,9 @@ func (j *JWTService) ValidateToken(tokenString string, secret []byte) (*service.
 	return nil, domain_error.NewInvalidData("invalid token claims or token is not valid")
 }
 
-func (j *JWTService) signToken(user *entity.User, createTime time.Time, secret []byte, expiresIn time.Duration) (string, error) {
+func (j *JWTService) signToken(tokenClaims service.TokenClaims, createTime time.Time, secret []byte, expiresIn time.Duration) (string, error) {
 	claims := &customClaims{
-		TokenClaims: service.TokenClaims{
-			UserID: user.ID,
-		},
+		TokenClaims: tokenClaims,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "UserService",
 			Subject:   "",
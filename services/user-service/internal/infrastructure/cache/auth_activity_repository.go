@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// AuthActivityRepository counts attempts in a string per kind and window,
+// the emails of failed logins in a HyperLogLog per source and window, and
+// keeps each anomaly as a string that expires with it. It exports the
+// attempts and raised anomalies as metrics.
+type AuthActivityRepository struct {
+	client *redis.Client
+	prefix string
+
+	attempts  *prometheus.CounterVec
+	anomalies *prometheus.CounterVec
+}
+
+// NewAuthActivityRepository registers the metrics with registerer, if not
+// nil.
+func NewAuthActivityRepository(client *redis.Client, prefix string, registerer prometheus.Registerer) *AuthActivityRepository {
+	r := &AuthActivityRepository{
+		client: client,
+		prefix: prefix,
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_attempts_total",
+			Help: "Failed logins and registrations, by kind.",
+		}, []string{"kind"}),
+		anomalies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_anomalies_total",
+			Help: "Anomalies raised over logins and registrations, by kind.",
+		}, []string{"kind"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(r.attempts, r.anomalies)
+	}
+
+	return r
+}
+
+func (r *AuthActivityRepository) CountAttempt(ctx context.Context, kind entity.AuthAttemptKind, window time.Time, ttl time.Duration) error {
+	r.attempts.WithLabelValues(string(kind)).Inc()
+
+	key := r.attemptsKey(kind, window)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return domain_error.NewInternalError("failed to count auth attempt: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *AuthActivityRepository) GetAttemptCounts(ctx context.Context, kind entity.AuthAttemptKind, windows []time.Time) ([]int64, error) {
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(windows))
+	for i, window := range windows {
+		keys[i] = r.attemptsKey(kind, window)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, domain_error.NewInternalError("failed to read auth attempts: " + err.Error())
+	}
+
+	counts := make([]int64, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *AuthActivityRepository) AddFailedLogin(ctx context.Context, source, email string, window time.Time, ttl time.Duration) (int64, error) {
+	key := r.prefix + "emails:" + source + ":" + strconv.FormatInt(window.Unix(), 10)
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.PFAdd(ctx, key, email)
+		pipe.Expire(ctx, key, ttl)
+		count = pipe.PFCount(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, domain_error.NewInternalError("failed to record failed login: " + err.Error())
+	}
+
+	return count.Val(), nil
+}
+
+func (r *AuthActivityRepository) RaiseAnomaly(ctx context.Context, anomaly *entity.AuthAnomaly) (bool, error) {
+	ttl := time.Until(anomaly.ExpiresAt.Time())
+	if ttl <= 0 {
+		return false, nil
+	}
+
+	value, err := json.Marshal(anomaly)
+	if err != nil {
+		return false, domain_error.NewInternalError("failed to encode auth anomaly: " + err.Error())
+	}
+	raised, err := r.client.SetNX(ctx, r.anomalyKey(anomaly.Scope()), value, ttl).Result()
+	if err != nil {
+		return false, domain_error.NewInternalError("failed to raise auth anomaly: " + err.Error())
+	}
+	if raised {
+		r.anomalies.WithLabelValues(string(anomaly.Kind)).Inc()
+	}
+
+	return raised, nil
+}
+
+func (r *AuthActivityRepository) HasAnomaly(ctx context.Context, scopes ...string) (bool, error) {
+	if len(scopes) == 0 {
+		return false, nil
+	}
+
+	keys := make([]string, len(scopes))
+	for i, scope := range scopes {
+		keys[i] = r.anomalyKey(scope)
+	}
+	n, err := r.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, domain_error.NewInternalError("failed to read auth anomalies: " + err.Error())
+	}
+
+	return n > 0, nil
+}
+
+func (r *AuthActivityRepository) attemptsKey(kind entity.AuthAttemptKind, window time.Time) string {
+	return r.prefix + "attempts:" + string(kind) + ":" + strconv.FormatInt(window.Unix(), 10)
+}
+
+func (r *AuthActivityRepository) anomalyKey(scope string) string {
+	return r.prefix + "anomaly:" + scope
+}
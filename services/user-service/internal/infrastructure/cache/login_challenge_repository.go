@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveAttemptScript counts an attempt and reads the challenge in one
+// step, without recreating a challenge that expired in the meantime.
+var reserveAttemptScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return {}
+end
+redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+return redis.call('HGETALL', KEYS[1])
+`)
+
+// LoginChallengeRepository keeps each challenge as a hash that expires with
+// the challenge.
+type LoginChallengeRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewLoginChallengeRepository(client *redis.Client, prefix string) *LoginChallengeRepository {
+	return &LoginChallengeRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *LoginChallengeRepository) CreateChallenge(ctx context.Context, challenge *entity.LoginChallenge, ttl time.Duration) error {
+	key := r.prefix + challenge.ID
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", challenge.UserID,
+			"code_hash", challenge.CodeHash,
+			"ip", challenge.IP,
+			"country", challenge.Location.Country,
+			"city", challenge.Location.City,
+			"attempts", challenge.Attempts,
+			"expires_at", challenge.ExpiresAt.Unix(),
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return domain_error.NewInternalError("failed to save login challenge: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *LoginChallengeRepository) ReserveAttempt(ctx context.Context, id string) (*entity.LoginChallenge, error) {
+	values, err := reserveAttemptScript.Run(ctx, r.client, []string{r.prefix + id}).StringSlice()
+	if err != nil {
+		return nil, domain_error.NewInternalError("failed to count login challenge attempt: " + err.Error())
+	}
+	if len(values) == 0 {
+		return nil, domain_error.NewNotFoundError("login challenge not found")
+	}
+
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		fields[values[i]] = values[i+1]
+	}
+	attempts, _ := strconv.Atoi(fields["attempts"])
+	expiresAt, _ := strconv.ParseInt(fields["expires_at"], 10, 64)
+
+	return &entity.LoginChallenge{
+		ID:       id,
+		UserID:   fields["user_id"],
+		CodeHash: fields["code_hash"],
+		IP:       fields["ip"],
+		Location: entity.Location{
+			Country: fields["country"],
+			City:    fields["city"],
+		},
+		Attempts:  attempts,
+		ExpiresAt: sharedvo.NewTime(expiresAt),
+	}, nil
+}
+
+func (r *LoginChallengeRepository) DeleteChallenge(ctx context.Context, id string) (bool, error) {
+	deleted, err := r.client.Del(ctx, r.prefix+id).Result()
+	if err != nil {
+		return false, domain_error.NewInternalError("failed to delete login challenge: " + err.Error())
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// MagicLinkRepository keeps each link as a hash that expires with the link.
+type MagicLinkRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewMagicLinkRepository(client *redis.Client, prefix string) *MagicLinkRepository {
+	return &MagicLinkRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *MagicLinkRepository) CreateMagicLink(ctx context.Context, link *entity.MagicLink, ttl time.Duration) error {
+	key := r.prefix + link.ID
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", link.UserID,
+			"expires_at", link.ExpiresAt.Unix(),
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return domain_error.NewInternalError("failed to save magic link: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *MagicLinkRepository) TakeMagicLink(ctx context.Context, id string) (*entity.MagicLink, error) {
+	key := r.prefix + id
+	// read and delete in one MULTI, so of two concurrent takes only one
+	// finds the link
+	var read *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		read = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, domain_error.NewInternalError("failed to take magic link: " + err.Error())
+	}
+
+	fields := read.Val()
+	if len(fields) == 0 {
+		return nil, domain_error.NewNotFoundError("magic link not found")
+	}
+	expiresAt, _ := strconv.ParseInt(fields["expires_at"], 10, 64)
+
+	return &entity.MagicLink{
+		ID:        id,
+		UserID:    fields["user_id"],
+		ExpiresAt: sharedvo.NewTime(expiresAt),
+	}, nil
+}
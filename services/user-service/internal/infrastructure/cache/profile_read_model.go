@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/redis/go-redis/v9"
+)
+
+// saveProfileScript writes a profile unless the stored one is later or the
+// user was deleted, as events of one user may be projected out of order.
+var saveProfileScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'deleted') == '1' then
+  return 0
+end
+local current = redis.call('HGET', KEYS[1], 'version')
+if current and tonumber(current) > tonumber(ARGV[1]) then
+  return 0
+end
+redis.call('HSET', KEYS[1], 'version', ARGV[1], 'data', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// profilePhoneField names the cached phone number to the FieldCipher, so
+// its ciphertext cannot be copied into the users table.
+const profilePhoneField = "profiles.phone"
+
+// ProfileReadModel keeps each profile as a hash of its version and JSON
+// encoded cachedProfile. Profiles expire ttl after their last write, which
+// bounds both the memory used and how long a failed projection leaves a
+// profile stale; a deleted user's hash is kept as a tombstone until then.
+type ProfileReadModel struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+	fields *encryption.FieldCipher
+}
+
+// cachedProfile is a user as stored in Redis: the phone number stays
+// encrypted by fields, as in the users table, and the password is left out.
+type cachedProfile struct {
+	ID              string `json:"id"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Email           string `json:"email"`
+	PhoneCiphertext string `json:"phone_ciphertext,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at"`
+	Version         int64  `json:"version"`
+}
+
+// NewProfileReadModel encrypts the cached phone numbers with fields.
+func NewProfileReadModel(client *redis.Client, prefix string, ttl time.Duration, fields *encryption.FieldCipher) *ProfileReadModel {
+	return &ProfileReadModel{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+		fields: fields,
+	}
+}
+
+func (r *ProfileReadModel) SaveProfile(ctx context.Context, user *entity.User) error {
+	profile := cachedProfile{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email.String(),
+		CreatedAt: user.CreatedAt.Unix(),
+		UpdatedAt: user.UpdatedAt.Unix(),
+		Version:   user.Version,
+	}
+	if user.Phone != "" {
+		ciphertext, _, err := r.fields.Encrypt(ctx, profilePhoneField, user.Phone.String())
+		if err != nil {
+			return domain_error.NewInternalError("failed to encrypt profile phone number: " + err.Error())
+		}
+		profile.PhoneCiphertext = ciphertext
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return domain_error.NewInternalError("failed to encode profile: " + err.Error())
+	}
+
+	err = saveProfileScript.Run(ctx, r.client, []string{r.prefix + user.ID}, user.Version, data, r.ttl.Milliseconds()).Err()
+	if err != nil {
+		return domain_error.NewInternalError("failed to save profile: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *ProfileReadModel) DeleteProfile(ctx context.Context, id string) error {
+	key := r.prefix + id
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, key, "data")
+		pipe.HSet(ctx, key, "deleted", "1")
+		pipe.Expire(ctx, key, r.ttl)
+		return nil
+	})
+	if err != nil {
+		return domain_error.NewInternalError("failed to delete profile: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *ProfileReadModel) GetProfiles(ctx context.Context, ids []string) ([]*entity.User, []string, error) {
+	reads := make([]*redis.SliceCmd, 0, len(ids))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			reads = append(reads, pipe.HMGet(ctx, r.prefix+id, "deleted", "data"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, domain_error.NewInternalError("failed to get profiles: " + err.Error())
+	}
+
+	var (
+		profiles []*entity.User
+		missing  []string
+	)
+	for i, read := range reads {
+		fields := read.Val()
+		if deleted, _ := fields[0].(string); deleted == "1" {
+			continue
+		}
+		data, ok := fields[1].(string)
+		if !ok {
+			missing = append(missing, ids[i])
+			continue
+		}
+
+		var profile cachedProfile
+		if err := json.Unmarshal([]byte(data), &profile); err != nil {
+			return nil, nil, domain_error.NewInternalError("failed to decode profile: " + err.Error())
+		}
+		var phone string
+		if profile.PhoneCiphertext != "" {
+			phone, err = r.fields.Decrypt(ctx, profilePhoneField, profile.PhoneCiphertext)
+			if err != nil {
+				return nil, nil, domain_error.NewInternalError("failed to decrypt profile phone number: " + err.Error())
+			}
+		}
+		profiles = append(profiles, entity.UserFromDatabase(profile.ID, profile.FirstName, profile.LastName, profile.Email, phone, "", profile.CreatedAt, profile.UpdatedAt, profile.Version))
+	}
+
+	return profiles, missing, nil
+}
@@ -1 +1,17 @@
 package cache
+
+import (
+	"fmt"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient connects lazily; callers wait for redis with Ping.
+func NewRedisClient(cfg *config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+}
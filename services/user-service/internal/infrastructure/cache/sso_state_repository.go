@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// SSOStateRepository keeps each sign-in in progress as a hash that expires
+// with it.
+type SSOStateRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewSSOStateRepository(client *redis.Client, prefix string) *SSOStateRepository {
+	return &SSOStateRepository{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (r *SSOStateRepository) CreateState(ctx context.Context, state *entity.SSOState, ttl time.Duration) error {
+	key := r.prefix + state.ID
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"connection_id", state.ConnectionID,
+			"nonce", state.Nonce,
+			"code_verifier", state.CodeVerifier,
+			"expires_at", state.ExpiresAt.Unix(),
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return domain_error.NewInternalError("failed to save SSO state: " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *SSOStateRepository) TakeState(ctx context.Context, id string) (*entity.SSOState, error) {
+	key := r.prefix + id
+	// read and delete in one MULTI, so of two concurrent takes only one
+	// finds the state
+	var read *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		read = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, domain_error.NewInternalError("failed to take SSO state: " + err.Error())
+	}
+
+	fields := read.Val()
+	if len(fields) == 0 {
+		return nil, domain_error.NewNotFoundError("SSO state not found")
+	}
+	expiresAt, _ := strconv.ParseInt(fields["expires_at"], 10, 64)
+
+	return &entity.SSOState{
+		ID:           id,
+		ConnectionID: fields["connection_id"],
+		Nonce:        fields["nonce"],
+		CodeVerifier: fields["code_verifier"],
+		ExpiresAt:    sharedvo.NewTime(expiresAt),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+// HTTPVerifier checks tokens at a siteverify endpoint, as reCAPTCHA,
+// hCaptcha and Turnstile serve: a form post of secret, response and
+// remoteip answered with {"success": true} for solved CAPTCHAs.
+type HTTPVerifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+// NewHTTPVerifier returns a verifier posting to verifyURL, or one that
+// accepts every token when verifyURL or secret is empty.
+func NewHTTPVerifier(verifyURL, secret string, timeout time.Duration) service.CaptchaVerifier {
+	if verifyURL == "" || secret == "" {
+		return noopVerifier{}
+	}
+
+	return &HTTPVerifier{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (v *HTTPVerifier) Verify(ctx context.Context, token, ip string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+	}
+	if ip != "" {
+		form.Set("remoteip", ip)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err != nil {
+		return false, fmt.Errorf("failed to decode CAPTCHA verification: %w", err)
+	}
+
+	return body.Success, nil
+}
+
+type noopVerifier struct{}
+
+func (noopVerifier) Verify(context.Context, string, string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,405 @@
+// Package backup takes logical backups of the service databases with
+// pg_dump, checks that they restore, and restores them with pg_restore. The
+// PostgreSQL client tools must be on the PATH.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+)
+
+const (
+	manifestName = "manifest.json"
+	// timeLayout names each backup after when it was taken, in UTC, so
+	// names sort by age.
+	timeLayout = "20060102T150405Z"
+	// partialSuffix marks a backup still being written; it is renamed once
+	// every dump succeeded, so an interrupted run is never restored from.
+	partialSuffix = ".partial"
+)
+
+// Database is a database to back up.
+type Database struct {
+	// Name identifies the database within a backup: "primary", or
+	// "shard-N" for the Nth user shard.
+	Name   string
+	Config config.DatabaseConfig
+}
+
+// Databases returns the primary database of cfg followed by its user
+// shards.
+func Databases(cfg *config.DatabaseConfig) []Database {
+	primary := *cfg
+	primary.UserShards = nil
+
+	ret := []Database{{Name: "primary", Config: primary}}
+	for i, shard := range cfg.UserShards {
+		ret = append(ret, Database{Name: fmt.Sprintf("shard-%d", i+1), Config: shard})
+	}
+
+	return ret
+}
+
+// Manifest lists the dumps of a backup.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+}
+
+// File is the pg_dump archive of one database.
+type File struct {
+	Database string `json:"database"`
+	// Name is the file name within the backup directory.
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Backup is a complete backup under the backup directory.
+type Backup struct {
+	Path      string
+	CreatedAt time.Time
+}
+
+// Create dumps every database into a new backup under dir and returns its
+// path. Dumps are pg_dump custom-format archives, which pg_restore can
+// restore table by table.
+func Create(ctx context.Context, dir string, databases []Database, now time.Time) (string, error) {
+	path := filepath.Join(dir, now.UTC().Format(timeLayout))
+	partial := path + partialSuffix
+	if err := os.MkdirAll(partial, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	manifest := Manifest{CreatedAt: now.UTC()}
+	for _, db := range databases {
+		name := db.Name + ".dump"
+		err := run(ctx, db.Config, "pg_dump",
+			"--format=custom",
+			"--no-owner",
+			"--no-privileges",
+			"--file", filepath.Join(partial, name),
+			"--dbname", db.Config.DBName,
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to dump %s: %w", db.Name, err)
+		}
+
+		size, sum, err := checksum(filepath.Join(partial, name))
+		if err != nil {
+			return "", err
+		}
+		manifest.Files = append(manifest.Files, File{
+			Database: db.Name,
+			Name:     name,
+			Size:     size,
+			SHA256:   sum,
+		})
+	}
+
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(partial, manifestName), raw, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return "", fmt.Errorf("failed to complete backup: %w", err)
+	}
+
+	return path, nil
+}
+
+// List returns the complete backups under dir, oldest first.
+func List(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret []Backup
+	for _, entry := range entries {
+		createdAt, err := time.Parse(timeLayout, entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+		ret = append(ret, Backup{Path: filepath.Join(dir, entry.Name()), CreatedAt: createdAt})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].CreatedAt.Before(ret[j].CreatedAt) })
+
+	return ret, nil
+}
+
+// ReadManifest reads the manifest of the backup at path and checks the size
+// and checksum of its dumps.
+func ReadManifest(path string) (*Manifest, error) {
+	raw, err := os.ReadFile(filepath.Join(path, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	for _, file := range manifest.Files {
+		size, sum, err := checksum(filepath.Join(path, file.Name))
+		if err != nil {
+			return nil, err
+		}
+		if size != file.Size || sum != file.SHA256 {
+			return nil, fmt.Errorf("dump of %s is corrupt: checksum does not match the manifest", file.Database)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// Restore creates the database into on the server of target and restores
+// the dump of database from the backup at path into it. It never restores
+// over an existing database: point the service at into once it is done.
+func Restore(ctx context.Context, path string, manifest *Manifest, database string, target config.DatabaseConfig, into string) error {
+	file, ok := manifest.file(database)
+	if !ok {
+		return fmt.Errorf("backup has no dump of %s", database)
+	}
+
+	if err := createDatabase(ctx, target, into); err != nil {
+		return err
+	}
+
+	err := run(ctx, target, "pg_restore",
+		"--no-owner",
+		"--no-privileges",
+		"--exit-on-error",
+		"--single-transaction",
+		"--dbname", into,
+		filepath.Join(path, file.Name),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s into %s: %w", database, into, err)
+	}
+
+	return nil
+}
+
+// Verification is what a restore of one dump into a scratch database found.
+type Verification struct {
+	Database string
+	// MigrationVersion is the schema version recorded by golang-migrate.
+	MigrationVersion int64
+	Users            int64
+}
+
+// Verify restores every dump of the backup at path into a scratch database
+// on the server of its database, checks that the schema is at a clean
+// migration version, counts the users and drops the scratch database.
+func Verify(ctx context.Context, path string, manifest *Manifest, databases []Database) ([]Verification, error) {
+	byName := make(map[string]Database, len(databases))
+	for _, db := range databases {
+		byName[db.Name] = db
+	}
+
+	ret := make([]Verification, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		db, ok := byName[file.Database]
+		if !ok {
+			return ret, fmt.Errorf("backup has a dump of %s, which is not configured", file.Database)
+		}
+
+		scratch := fmt.Sprintf("%s_verify_%d", db.Config.DBName, time.Now().UnixNano())
+		verification, err := verifyDump(ctx, path, manifest, db, scratch)
+		if dropErr := dropDatabase(context.WithoutCancel(ctx), db.Config, scratch); dropErr != nil {
+			err = errors.Join(err, dropErr)
+		}
+		if err != nil {
+			return ret, fmt.Errorf("verification of %s failed: %w", file.Database, err)
+		}
+		ret = append(ret, verification)
+	}
+
+	return ret, nil
+}
+
+func verifyDump(ctx context.Context, path string, manifest *Manifest, db Database, scratch string) (Verification, error) {
+	ret := Verification{Database: db.Name}
+	if err := Restore(ctx, path, manifest, db.Name, db.Config, scratch); err != nil {
+		return ret, err
+	}
+
+	cfg := db.Config
+	cfg.DBName = scratch
+	conn, err := connect(ctx, cfg)
+	if err != nil {
+		return ret, err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	var dirty bool
+	err = conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&ret.MigrationVersion, &dirty)
+	if err != nil {
+		return ret, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if dirty {
+		return ret, fmt.Errorf("migration %d was interrupted when the backup was taken", ret.MigrationVersion)
+	}
+
+	if err := conn.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&ret.Users); err != nil {
+		return ret, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return ret, nil
+}
+
+// Prune deletes the backups under dir taken more than maxAge before now,
+// except the newest keep, and returns the paths it deleted. Unfinished
+// backups older than maxAge are deleted too.
+func Prune(dir string, keep int, maxAge time.Duration, now time.Time) ([]string, error) {
+	backups, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.Add(-maxAge)
+	var deleted []string
+	for i, backup := range backups {
+		if i >= len(backups)-keep || !backup.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(backup.Path); err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %w", backup.Path, err)
+		}
+		deleted = append(deleted, backup.Path)
+	}
+
+	partials, err := filepath.Glob(filepath.Join(dir, "*"+partialSuffix))
+	if err != nil {
+		return deleted, err
+	}
+	for _, partial := range partials {
+		createdAt, err := time.Parse(timeLayout, strings.TrimSuffix(filepath.Base(partial), partialSuffix))
+		if err != nil || !createdAt.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(partial); err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %w", partial, err)
+		}
+		deleted = append(deleted, partial)
+	}
+
+	return deleted, nil
+}
+
+func (m *Manifest) file(database string) (File, bool) {
+	for _, file := range m.Files {
+		if file.Database == database {
+			return file, true
+		}
+	}
+
+	return File{}, false
+}
+
+// run runs a PostgreSQL client tool against the server of cfg. The password
+// goes in the environment rather than on the command line, where other
+// users of the host could see it.
+func run(ctx context.Context, cfg config.DatabaseConfig, tool string, args ...string) error {
+	args = append([]string{
+		"--host", cfg.Host,
+		"--port", strconv.Itoa(cfg.Port),
+		"--username", cfg.User,
+		"--no-password",
+	}, args...)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+cfg.Password)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w: %s", tool, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func connect(ctx context.Context, cfg config.DatabaseConfig) (*pgx.Conn, error) {
+	connConfig, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s@%s:%d/%s", cfg.User, cfg.Host, cfg.Port, cfg.DBName))
+	if err != nil {
+		return nil, err
+	}
+	connConfig.Password = cfg.Password
+
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.DBName, err)
+	}
+
+	return conn, nil
+}
+
+// createDatabase creates name on the server of cfg, connecting through the
+// database cfg names, and fails if it exists.
+func createDatabase(ctx context.Context, cfg config.DatabaseConfig, name string) error {
+	conn, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+pgx.Identifier{name}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to create database %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func dropDatabase(ctx context.Context, cfg config.DatabaseConfig, name string) error {
+	conn, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err := conn.Exec(ctx, "DROP DATABASE IF EXISTS "+pgx.Identifier{name}.Sanitize()+" WITH (FORCE)"); err != nil {
+		return fmt.Errorf("failed to drop database %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func checksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
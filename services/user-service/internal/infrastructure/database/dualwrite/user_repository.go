@@ -0,0 +1,359 @@
+// Package dualwrite mirrors repository writes to a second datastore while
+// data is migrated to it, e.g. to a redesigned users table or a new shard
+// layout, so the switch needs no downtime.
+package dualwrite
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type mirrorMetrics struct {
+	errors     *prometheus.CounterVec
+	mismatches *prometheus.CounterVec
+}
+
+func newMirrorMetrics(registerer prometheus.Registerer) *mirrorMetrics {
+	m := &mirrorMetrics{
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dual_write_mirror_errors_total",
+			Help: "Calls that failed on the mirror datastore, by operation.",
+		}, []string{"operation"}),
+		mismatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dual_write_mismatches_total",
+			Help: "Results that differ between the primary and mirror datastores, by operation.",
+		}, []string{"operation"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.errors, m.mismatches)
+	}
+
+	return m
+}
+
+const (
+	// maxComparisons bounds the read comparisons running at once; reads
+	// past it are not compared rather than queued.
+	maxComparisons = 64
+	// compareTimeout bounds a comparison's read of the mirror.
+	compareTimeout = 5 * time.Second
+	// clockSkew is how far the timestamps of a user may differ between the
+	// datastores, as each stamps a created user with its own clock.
+	clockSkew = 2 * time.Second
+)
+
+// UserRepository serves callers from the primary repository and repeats
+// every successful write on the mirror. The primary stays authoritative:
+// mirror failures and differing results are logged and counted, never
+// returned. Like ShardedUserRepository, calls must not run inside a unit of
+// work, as a mirrored write cannot be rolled back with the primary's.
+type UserRepository struct {
+	primary      repository.UserRepository
+	mirror       repository.UserRepository
+	compareReads bool
+	logger       *slog.Logger
+	metrics      *mirrorMetrics
+	comparisons  chan struct{}
+}
+
+// NewUserRepository mirrors the writes of primary to mirror. With
+// compareReads, reads of users are repeated on the mirror in the background
+// and compared too. Metrics are registered with registerer when it is not
+// nil.
+func NewUserRepository(primary, mirror repository.UserRepository, compareReads bool, logger *slog.Logger, registerer prometheus.Registerer) *UserRepository {
+	return &UserRepository{
+		primary:      primary,
+		mirror:       mirror,
+		compareReads: compareReads,
+		logger:       logger,
+		metrics:      newMirrorMetrics(registerer),
+		comparisons:  make(chan struct{}, maxComparisons),
+	}
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	ret, err := r.primary.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrored, err := r.mirror.CreateUser(mirrorContext(ctx), copyUser(ret))
+	if err != nil {
+		r.mirrorFailed(ctx, "CreateUser", ret.ID, err)
+	} else if diff := diffUsers(ret, mirrored); len(diff) > 0 {
+		r.mismatch(ctx, "CreateUser", ret.ID, diff)
+	}
+
+	return ret, nil
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, user *entity.User) (int64, error) {
+	// the primary bumps user.Version, and the mirror must expect the old one
+	mirrored := copyUser(user)
+	rows, err := r.primary.UpdateUser(ctx, user)
+	if err != nil || rows == 0 {
+		return rows, err
+	}
+
+	mirrorRows, err := r.mirror.UpdateUser(mirrorContext(ctx), mirrored)
+	r.checkWrite(ctx, "UpdateUser", user.ID, rows, mirrorRows, err)
+
+	return rows, nil
+}
+
+func (r *UserRepository) ChangePassword(ctx context.Context, id string, newPassword string) (int64, error) {
+	rows, err := r.primary.ChangePassword(ctx, id, newPassword)
+	if err != nil || rows == 0 {
+		return rows, err
+	}
+
+	mirrorRows, err := r.mirror.ChangePassword(mirrorContext(ctx), id, newPassword)
+	r.checkWrite(ctx, "ChangePassword", id, rows, mirrorRows, err)
+
+	return rows, nil
+}
+
+// DeleteUser deletes from the mirror even if the primary had no such user,
+// so a user left over on the mirror goes too.
+func (r *UserRepository) DeleteUser(ctx context.Context, id string) (int64, error) {
+	rows, err := r.primary.DeleteUser(ctx, id)
+	if err != nil {
+		return rows, err
+	}
+
+	mirrorRows, err := r.mirror.DeleteUser(mirrorContext(ctx), id)
+	r.checkWrite(ctx, "DeleteUser", id, rows, mirrorRows, err)
+
+	return rows, nil
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
+	user, err := r.primary.GetUserByID(ctx, id)
+
+	snapshot := copyUser(user)
+	r.compare(ctx, "GetUserByID", id, func(ctx context.Context) ([]string, error) {
+		mirrored, mirrorErr := r.mirror.GetUserByID(ctx, id)
+		return diffLookups(snapshot, err, mirrored, mirrorErr)
+	})
+
+	return user, err
+}
+
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user, err := r.primary.GetUserByEmail(ctx, email)
+
+	snapshot := copyUser(user)
+	r.compare(ctx, "GetUserByEmail", email, func(ctx context.Context) ([]string, error) {
+		mirrored, mirrorErr := r.mirror.GetUserByEmail(ctx, email)
+		return diffLookups(snapshot, err, mirrored, mirrorErr)
+	})
+
+	return user, err
+}
+
+func (r *UserRepository) EmailKeyExists(ctx context.Context, key string) (bool, error) {
+	return r.primary.EmailKeyExists(ctx, key)
+}
+
+func (r *UserRepository) GetPublicProfileByIds(ctx context.Context, ids []string) ([]*entity.UserPublicProfile, error) {
+	return r.primary.GetPublicProfileByIds(ctx, ids)
+}
+
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error) {
+	users, err := r.primary.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := copyUsers(users)
+	r.compare(ctx, "GetUsersByIDs", "", func(ctx context.Context) ([]string, error) {
+		mirrored, err := r.mirror.GetUsersByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return diffUserSets(snapshot, mirrored), nil
+	})
+
+	return users, nil
+}
+
+func (r *UserRepository) ListUsersAfter(ctx context.Context, afterID string, limit int32, where filter.Expr) ([]*entity.User, error) {
+	users, err := r.primary.ListUsersAfter(ctx, afterID, limit, where)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := copyUsers(users)
+	r.compare(ctx, "ListUsersAfter", afterID, func(ctx context.Context) ([]string, error) {
+		mirrored, err := r.mirror.ListUsersAfter(ctx, afterID, limit, where)
+		if err != nil {
+			return nil, err
+		}
+		return diffUserSets(snapshot, mirrored), nil
+	})
+
+	return users, nil
+}
+
+// checkWrite records a mirrored write that failed or affected another
+// number of rows than on the primary, i.e. the user is missing or at
+// another version on the mirror.
+func (r *UserRepository) checkWrite(ctx context.Context, operation, id string, rows, mirrorRows int64, err error) {
+	if err != nil {
+		r.mirrorFailed(ctx, operation, id, err)
+		return
+	}
+	if rows != mirrorRows {
+		r.mismatch(ctx, operation, id, []string{fmt.Sprintf("%d rows affected on primary, %d on mirror", rows, mirrorRows)})
+	}
+}
+
+// compare runs check against the mirror in the background, unless reads are
+// not compared or too many comparisons are running already. key identifies
+// what was read in the log.
+func (r *UserRepository) compare(ctx context.Context, operation, key string, check func(ctx context.Context) ([]string, error)) {
+	if !r.compareReads {
+		return
+	}
+	select {
+	case r.comparisons <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-r.comparisons }()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compareTimeout)
+		defer cancel()
+
+		diff, err := check(ctx)
+		if err != nil {
+			r.mirrorFailed(ctx, operation, key, err)
+			return
+		}
+		if len(diff) > 0 {
+			r.mismatch(ctx, operation, key, diff)
+		}
+	}()
+}
+
+func (r *UserRepository) mirrorFailed(ctx context.Context, operation, key string, err error) {
+	r.metrics.errors.WithLabelValues(operation).Inc()
+	r.logger.ErrorContext(ctx, "dual write mirror failed", "operation", operation, "key", key, "error", err)
+}
+
+func (r *UserRepository) mismatch(ctx context.Context, operation, key string, diff []string) {
+	r.metrics.mismatches.WithLabelValues(operation).Inc()
+	r.logger.WarnContext(ctx, "dual write mismatch", "operation", operation, "key", key, "diff", diff)
+}
+
+// mirrorContext keeps a mirrored write going when the caller goes away
+// after the primary write, which would otherwise leave the datastores apart.
+func mirrorContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// diffLookups compares the results of looking a user up on both datastores.
+// A failed primary lookup is not compared, and a failed mirror lookup is
+// returned as the error.
+func diffLookups(primary *entity.User, primaryErr error, mirror *entity.User, mirrorErr error) ([]string, error) {
+	if primaryErr != nil && !domain_error.IsNotFound(primaryErr) {
+		return nil, nil
+	}
+	if mirrorErr != nil && !domain_error.IsNotFound(mirrorErr) {
+		return nil, mirrorErr
+	}
+
+	if found, mirrorFound := primaryErr == nil, mirrorErr == nil; found != mirrorFound {
+		return []string{fmt.Sprintf("found on primary: %t, on mirror: %t", found, mirrorFound)}, nil
+	}
+	if primary == nil {
+		return nil, nil
+	}
+
+	return diffUsers(primary, mirror), nil
+}
+
+// diffUserSets compares two lists of users by ID, whatever their order.
+func diffUserSets(primary, mirror []*entity.User) []string {
+	mirrorByID := make(map[string]*entity.User, len(mirror))
+	for _, user := range mirror {
+		mirrorByID[user.ID] = user
+	}
+
+	var diff []string
+	for _, user := range primary {
+		mirrored, ok := mirrorByID[user.ID]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("user %s missing on mirror", user.ID))
+			continue
+		}
+		delete(mirrorByID, user.ID)
+
+		for _, field := range diffUsers(user, mirrored) {
+			diff = append(diff, fmt.Sprintf("user %s: %s", user.ID, field))
+		}
+	}
+	for id := range mirrorByID {
+		diff = append(diff, fmt.Sprintf("user %s only on mirror", id))
+	}
+
+	return diff
+}
+
+// diffUsers names the fields that differ between the two copies of a user.
+func diffUsers(primary, mirror *entity.User) []string {
+	var diff []string
+	check := func(field string, equal bool) {
+		if !equal {
+			diff = append(diff, field)
+		}
+	}
+
+	check("id", primary.ID == mirror.ID)
+	check("first_name", primary.FirstName == mirror.FirstName)
+	check("last_name", primary.LastName == mirror.LastName)
+	check("email", primary.Email == mirror.Email)
+	check("phone", primary.Phone == mirror.Phone)
+	check("password", primary.Password == mirror.Password)
+	check("created_at", closeInTime(primary.CreatedAt.Time(), mirror.CreatedAt.Time()))
+	check("updated_at", closeInTime(primary.UpdatedAt.Time(), mirror.UpdatedAt.Time()))
+	check("version", primary.Version == mirror.Version)
+
+	return diff
+}
+
+func closeInTime(a, b time.Time) bool {
+	return a.Sub(b).Abs() <= clockSkew
+}
+
+// copyUser copies a user before it is compared in the background, as the
+// caller may change the one it was given.
+func copyUser(user *entity.User) *entity.User {
+	if user == nil {
+		return nil
+	}
+	ret := *user
+
+	return &ret
+}
+
+func copyUsers(users []*entity.User) []*entity.User {
+	ret := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		ret = append(ret, copyUser(user))
+	}
+
+	return ret
+}
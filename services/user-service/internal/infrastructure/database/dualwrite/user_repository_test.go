@@ -0,0 +1,363 @@
+package dualwrite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// memUserRepo keeps users in memory and bumps versions on update like the
+// Postgres repository. Its methods fail with err when it is set.
+type memUserRepo struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[string]entity.User
+	err   error
+}
+
+func newMemUserRepo(users ...*entity.User) *memUserRepo {
+	r := &memUserRepo{users: map[string]entity.User{}}
+	for _, user := range users {
+		r.users[user.ID] = *user
+	}
+	return r
+}
+
+func (r *memUserRepo) CreateUser(_ context.Context, user *entity.User) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.users[user.ID] = *user
+
+	return copyUser(user), nil
+}
+
+func (r *memUserRepo) UpdateUser(_ context.Context, user *entity.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	stored, ok := r.users[user.ID]
+	if !ok || stored.Version != user.Version {
+		return 0, nil
+	}
+	user.Version++
+	r.users[user.ID] = *user
+
+	return 1, nil
+}
+
+func (r *memUserRepo) DeleteUser(_ context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	if _, ok := r.users[id]; !ok {
+		return 0, nil
+	}
+	delete(r.users, id)
+
+	return 1, nil
+}
+
+func (r *memUserRepo) GetUserByID(_ context.Context, id string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain_error.NewNotFoundError("user not found")
+	}
+
+	return &user, nil
+}
+
+func (r *memUserRepo) get(id string) (entity.User, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	return user, ok
+}
+
+func testUser(id string) *entity.User {
+	now := valueobject.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Unix())
+	return &entity.User{
+		ID:        id,
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     valueobject.NewEmail(id + "@example.com"),
+		Password:  valueobject.NewPassword("hash"),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+}
+
+func newTestRepository(primary, mirror *memUserRepo, compareReads bool) *UserRepository {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewUserRepository(primary, mirror, compareReads, logger, prometheus.NewRegistry())
+}
+
+func (r *UserRepository) counts(operation string) (errs, mismatches float64) {
+	return testutil.ToFloat64(r.metrics.errors.WithLabelValues(operation)),
+		testutil.ToFloat64(r.metrics.mismatches.WithLabelValues(operation))
+}
+
+// waitForComparisons waits until the background comparisons are done.
+func (r *UserRepository) waitForComparisons(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(r.comparisons) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("comparisons still running after 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUserRepositoryMirrorsWrites(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates the user on both", func(t *testing.T) {
+		primary, mirror := newMemUserRepo(), newMemUserRepo()
+		repo := newTestRepository(primary, mirror, false)
+
+		if _, err := repo.CreateUser(ctx, testUser("u1")); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		if _, ok := mirror.get("u1"); !ok {
+			t.Error("user was not created on the mirror")
+		}
+		if errs, mismatches := repo.counts("CreateUser"); errs != 0 || mismatches != 0 {
+			t.Errorf("errors, mismatches = %v, %v, want none", errs, mismatches)
+		}
+	})
+
+	t.Run("a mirror failure is counted, not returned", func(t *testing.T) {
+		primary, mirror := newMemUserRepo(), newMemUserRepo()
+		mirror.err = errors.New("connection refused")
+		repo := newTestRepository(primary, mirror, false)
+
+		if _, err := repo.CreateUser(ctx, testUser("u1")); err != nil {
+			t.Fatalf("CreateUser = %v, want the primary's success", err)
+		}
+		if _, ok := primary.get("u1"); !ok {
+			t.Error("user was not created on the primary")
+		}
+		if errs, _ := repo.counts("CreateUser"); errs != 1 {
+			t.Errorf("mirror errors = %v, want 1", errs)
+		}
+	})
+
+	t.Run("a primary failure is returned and not mirrored", func(t *testing.T) {
+		primary, mirror := newMemUserRepo(), newMemUserRepo()
+		primary.err = errors.New("connection refused")
+		repo := newTestRepository(primary, mirror, false)
+
+		if _, err := repo.CreateUser(ctx, testUser("u1")); !errors.Is(err, primary.err) {
+			t.Fatalf("CreateUser = %v, want %v", err, primary.err)
+		}
+		if _, ok := mirror.get("u1"); ok {
+			t.Error("user was created on the mirror")
+		}
+	})
+
+	t.Run("updates the mirror at the version the primary had", func(t *testing.T) {
+		primary, mirror := newMemUserRepo(testUser("u1")), newMemUserRepo(testUser("u1"))
+		repo := newTestRepository(primary, mirror, false)
+
+		user := testUser("u1")
+		user.FirstName = "Johnny"
+		rows, err := repo.UpdateUser(ctx, user)
+		if err != nil || rows != 1 {
+			t.Fatalf("UpdateUser = %d, %v, want 1 row", rows, err)
+		}
+		if user.Version != 2 {
+			t.Errorf("caller's user is at version %d, want 2", user.Version)
+		}
+		if mirrored, _ := mirror.get("u1"); mirrored.FirstName != "Johnny" || mirrored.Version != 2 {
+			t.Errorf("mirror has %q at version %d, want %q at version 2", mirrored.FirstName, mirrored.Version, "Johnny")
+		}
+		if _, mismatches := repo.counts("UpdateUser"); mismatches != 0 {
+			t.Errorf("mismatches = %v, want 0", mismatches)
+		}
+	})
+
+	t.Run("an update of a user missing on the mirror is a mismatch", func(t *testing.T) {
+		// created before dual writes were enabled and not backfilled yet
+		primary, mirror := newMemUserRepo(testUser("u1")), newMemUserRepo()
+		repo := newTestRepository(primary, mirror, false)
+
+		rows, err := repo.UpdateUser(ctx, testUser("u1"))
+		if err != nil || rows != 1 {
+			t.Fatalf("UpdateUser = %d, %v, want the primary's 1 row", rows, err)
+		}
+		if _, mismatches := repo.counts("UpdateUser"); mismatches != 1 {
+			t.Errorf("mismatches = %v, want 1", mismatches)
+		}
+	})
+
+	t.Run("a stale update is not mirrored", func(t *testing.T) {
+		current := testUser("u1")
+		current.Version = 3
+		primary, mirror := newMemUserRepo(current), newMemUserRepo(current)
+		repo := newTestRepository(primary, mirror, false)
+
+		rows, err := repo.UpdateUser(ctx, testUser("u1"))
+		if err != nil || rows != 0 {
+			t.Fatalf("UpdateUser = %d, %v, want 0 rows", rows, err)
+		}
+		if mirrored, _ := mirror.get("u1"); mirrored.Version != 3 {
+			t.Errorf("mirror is at version %d, want 3", mirrored.Version)
+		}
+	})
+
+	t.Run("deletes a user left over on the mirror", func(t *testing.T) {
+		primary, mirror := newMemUserRepo(), newMemUserRepo(testUser("u1"))
+		repo := newTestRepository(primary, mirror, false)
+
+		rows, err := repo.DeleteUser(ctx, "u1")
+		if err != nil || rows != 0 {
+			t.Fatalf("DeleteUser = %d, %v, want the primary's 0 rows", rows, err)
+		}
+		if _, ok := mirror.get("u1"); ok {
+			t.Error("user is still on the mirror")
+		}
+		if _, mismatches := repo.counts("DeleteUser"); mismatches != 1 {
+			t.Errorf("mismatches = %v, want 1", mismatches)
+		}
+	})
+}
+
+func TestUserRepositoryComparesReads(t *testing.T) {
+	ctx := context.Background()
+	changed := testUser("u1")
+	changed.LastName = "Smith"
+
+	tests := []struct {
+		name           string
+		mirror         *memUserRepo
+		compareReads   bool
+		wantMismatches float64
+		wantErrors     float64
+	}{
+		{name: "same user", mirror: newMemUserRepo(testUser("u1")), compareReads: true},
+		{name: "differing user", mirror: newMemUserRepo(changed), compareReads: true, wantMismatches: 1},
+		{name: "missing on the mirror", mirror: newMemUserRepo(), compareReads: true, wantMismatches: 1},
+		{name: "mirror failure", mirror: &memUserRepo{err: errors.New("connection refused")}, compareReads: true, wantErrors: 1},
+		{name: "reads not compared", mirror: newMemUserRepo(changed)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(newMemUserRepo(testUser("u1")), tt.mirror, tt.compareReads)
+
+			user, err := repo.GetUserByID(ctx, "u1")
+			if err != nil || user.LastName != "Doe" {
+				t.Fatalf("GetUserByID = %+v, %v, want the primary's user", user, err)
+			}
+			repo.waitForComparisons(t)
+
+			if errs, mismatches := repo.counts("GetUserByID"); errs != tt.wantErrors || mismatches != tt.wantMismatches {
+				t.Errorf("errors, mismatches = %v, %v, want %v, %v", errs, mismatches, tt.wantErrors, tt.wantMismatches)
+			}
+		})
+	}
+}
+
+func TestDiffUsers(t *testing.T) {
+	base := testUser("u1")
+	withSkew := func(d time.Duration) *entity.User {
+		user := testUser("u1")
+		user.CreatedAt = valueobject.NewTime(base.CreatedAt.Time().Add(d).Unix())
+		return user
+	}
+	atVersion := func(version int64) *entity.User {
+		user := testUser("u1")
+		user.Version = version
+		return user
+	}
+
+	tests := []struct {
+		name   string
+		mirror *entity.User
+		want   []string
+	}{
+		{name: "equal", mirror: testUser("u1")},
+		{name: "timestamps within the clock skew", mirror: withSkew(clockSkew)},
+		{name: "timestamps past the clock skew", mirror: withSkew(clockSkew + time.Second), want: []string{"created_at"}},
+		{name: "other version", mirror: atVersion(2), want: []string{"version"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffUsers(base, tt.mirror)
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("diffUsers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffUserSets(t *testing.T) {
+	got := diffUserSets(
+		[]*entity.User{testUser("u1"), testUser("u2")},
+		[]*entity.User{testUser("u3"), testUser("u1")},
+	)
+
+	want := map[string]bool{"user u2 missing on mirror": true, "user u3 only on mirror": true}
+	if len(got) != len(want) {
+		t.Fatalf("diffUserSets = %v, want %d differences", got, len(want))
+	}
+	for _, diff := range got {
+		if !want[diff] {
+			t.Errorf("unexpected difference %q", diff)
+		}
+	}
+}
+
+func TestDiffLookups(t *testing.T) {
+	notFound := domain_error.NewNotFoundError("user not found")
+	failed := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		primary    *entity.User
+		primaryErr error
+		mirror     *entity.User
+		mirrorErr  error
+		wantDiff   bool
+		wantErr    error
+	}{
+		{name: "found on both", primary: testUser("u1"), mirror: testUser("u1")},
+		{name: "missing on both", primaryErr: notFound, mirrorErr: notFound},
+		{name: "missing on the mirror", primary: testUser("u1"), mirrorErr: notFound, wantDiff: true},
+		{name: "only on the mirror", primaryErr: notFound, mirror: testUser("u1"), wantDiff: true},
+		{name: "primary failure is not compared", primaryErr: failed, mirror: testUser("u1")},
+		{name: "mirror failure", primary: testUser("u1"), mirrorErr: failed, wantErr: failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := diffLookups(tt.primary, tt.primaryErr, tt.mirror, tt.mirrorErr)
+			if !errors.Is(err, tt.wantErr) || (len(diff) > 0) != tt.wantDiff {
+				t.Errorf("diffLookups = %v, %v, want a difference: %t, error %v", diff, err, tt.wantDiff, tt.wantErr)
+			}
+		})
+	}
+}
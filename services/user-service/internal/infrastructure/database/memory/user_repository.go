@@ -0,0 +1,256 @@
+// Package memory holds in-memory implementations of the domain repositories
+// for fast usecase tests and for running the service without Postgres. They
+// mirror the Postgres repositories' behaviour, including their domain errors,
+// and must be kept in step with them.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+)
+
+type UserRepository struct {
+	mu      sync.RWMutex
+	byID    map[string]*entity.User
+	byEmail map[string]string
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		byID:    make(map[string]*entity.User),
+		byEmail: make(map[string]string),
+	}
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// CreateUser stores a copy of user.
+func (r *UserRepository) CreateUser(_ context.Context, user *entity.User) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byEmail[user.Email.String()]; ok {
+		return nil, domain_error.New(
+			domain_error.ReasonEmailAlreadyExists,
+			domain_error.WithMessage(fmt.Sprintf("user with email %s already exists", user.Email.String())),
+			domain_error.WithFieldViolation("email", "already registered"),
+		)
+	}
+
+	stored := *user
+	if stored.ID == "" {
+		stored.ID = utils.NewUUID()
+	}
+	r.byID[stored.ID] = &stored
+	r.byEmail[stored.Email.String()] = stored.ID
+
+	created := stored
+	return &created, nil
+}
+
+func (r *UserRepository) UpdateUser(_ context.Context, user *entity.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.byID[user.ID]
+	if !ok || current.Version != user.Version {
+		return 0, nil
+	}
+
+	if ownerID, taken := r.byEmail[user.Email.String()]; taken && ownerID != user.ID {
+		return 0, domain_error.NewInternalError(fmt.Sprintf("failed to update user: email %s already exists", user.Email.String()))
+	}
+
+	updated := *current
+	updated.FirstName = user.FirstName
+	updated.LastName = user.LastName
+	updated.Email = user.Email
+	updated.Phone = user.Phone
+	updated.UpdatedAt = user.UpdatedAt
+	updated.Version++
+	user.Version = updated.Version
+
+	delete(r.byEmail, current.Email.String())
+	r.byEmail[updated.Email.String()] = updated.ID
+	r.byID[updated.ID] = &updated
+
+	return 1, nil
+}
+
+// ChangePassword stores newPassword as given; callers pass it hashed.
+func (r *UserRepository) ChangePassword(_ context.Context, id string, newPassword string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.byID[id]
+	if !ok {
+		return 0, nil
+	}
+
+	updated := *current
+	updated.Password = valueobject.NewPassword(newPassword)
+	updated.UpdatedAt = valueobject.NewTime(utils.TimeNow())
+	r.byID[id] = &updated
+
+	return 1, nil
+}
+
+func (r *UserRepository) DeleteUser(_ context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.byID[id]
+	if !ok {
+		return 0, nil
+	}
+
+	delete(r.byEmail, current.Email.String())
+	delete(r.byID, id)
+
+	return 1, nil
+}
+
+func (r *UserRepository) GetUserByID(_ context.Context, id string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.byID[id]
+	if !ok {
+		return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", id)))
+	}
+
+	found := *user
+	return &found, nil
+}
+
+func (r *UserRepository) GetUserByEmail(_ context.Context, email string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byEmail[email]
+	if !ok {
+		return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user with email %s not found", email)))
+	}
+
+	found := *r.byID[id]
+	return &found, nil
+}
+
+func (r *UserRepository) EmailKeyExists(_ context.Context, key string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for email := range r.byEmail {
+		if valueobject.NewEmail(email).DedupKey() == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetPublicProfileByIds skips unknown ids and, like Postgres without an ORDER
+// BY, makes no ordering promise; profiles come back sorted by id.
+func (r *UserRepository) GetPublicProfileByIds(_ context.Context, ids []string) ([]*entity.UserPublicProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ret := make([]*entity.UserPublicProfile, 0)
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		user, ok := r.byID[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ret = append(ret, entity.NewUserPublicProfile(user.ID, user.FirstName, user.LastName))
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+
+	return ret, nil
+}
+
+// GetUsersByIDs skips unknown ids and returns users sorted by id.
+func (r *UserRepository) GetUsersByIDs(_ context.Context, ids []string) ([]*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ret := make([]*entity.User, 0)
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		user, ok := r.byID[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		found := *user
+		ret = append(ret, &found)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+
+	return ret, nil
+}
+
+// ListUsersAfter orders by the ID string, which matches Postgres' UUID order
+// for the lower-case IDs the service generates.
+func (r *UserRepository) ListUsersAfter(_ context.Context, afterID string, limit int32, where filter.Expr) ([]*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ret := make([]*entity.User, 0)
+	for id, user := range r.byID {
+		if id > afterID && filter.Match(where, userFilterValue(user)) {
+			found := *user
+			ret = append(ret, &found)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	if len(ret) > int(limit) {
+		ret = ret[:limit]
+	}
+
+	return ret, nil
+}
+
+// userFilterValue returns the values of repository.UserFilterSchema's fields
+// as the Postgres repository compares them.
+func userFilterValue(user *entity.User) func(field string) any {
+	return func(field string) any {
+		switch field {
+		case "id":
+			return user.ID
+		case "email":
+			return user.Email.String()
+		case "phone":
+			return user.Phone.String()
+		case "name.given_name":
+			return user.FirstName
+		case "name.family_name":
+			return user.LastName
+		case "create_time":
+			return user.CreatedAt.Time().UTC()
+		case "update_time":
+			return user.UpdatedAt.Time().UTC()
+		default:
+			return nil
+		}
+	}
+}
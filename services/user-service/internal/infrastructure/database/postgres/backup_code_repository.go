@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type BackupCodeRepository struct {
+	base *sqlc.Queries
+}
+
+func NewBackupCodeRepository(db sqlc.DBTX) *BackupCodeRepository {
+	return &BackupCodeRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *BackupCodeRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *BackupCodeRepository) ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	err := r.queries(ctx).ReplaceUserBackupCodes(ctx, sqlc.ReplaceUserBackupCodesParams{
+		UserID:     uuid,
+		CodeHashes: codeHashes,
+		CreatedAt:  pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		return queryError(err, "failed to replace backup codes")
+	}
+
+	return nil
+}
+
+func (r *BackupCodeRepository) ConsumeBackupCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return false, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	result, err := r.queries(ctx).ConsumeUserBackupCode(ctx, sqlc.ConsumeUserBackupCodeParams{
+		UserID:   uuid,
+		CodeHash: codeHash,
+		UsedAt:   pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		return false, queryError(err, "failed to use backup code")
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *BackupCodeRepository) CountUnusedBackupCodes(ctx context.Context, userID string) (int, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	count, err := r.queries(ctx).CountUnusedUserBackupCodes(ctx, uuid)
+	if err != nil {
+		return 0, queryError(err, "failed to count backup codes")
+	}
+
+	return int(count), nil
+}
@@ -3,12 +3,20 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/pkg/uow"
 	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
 )
 
-func NewConnection(ctx context.Context, cfg *config.DatabaseConfig) (*pgx.Conn, error) {
+// NewConnection creates the connection pool. tracer, if not nil, observes
+// every query.
+func NewConnection(ctx context.Context, cfg *config.DatabaseConfig, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
 	connectionString := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		cfg.User,
@@ -17,10 +25,107 @@ func NewConnection(ctx context.Context, cfg *config.DatabaseConfig) (*pgx.Conn,
 		cfg.Port,
 		cfg.DBName,
 	)
-	conn, err := pgx.Connect(ctx, connectionString)
+	poolConfig, err := pgxpool.ParseConfig(connectionString)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if tracer != nil {
+		poolConfig.ConnConfig.Tracer = tracer
+	}
+
+	// connections are opened lazily; callers wait for the database with Ping
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
+
+// NewShardConnections opens a pool for each of cfg.UserShards. Shards without
+// a statement timeout of their own use cfg's.
+func NewShardConnections(ctx context.Context, cfg *config.DatabaseConfig, tracer pgx.QueryTracer) ([]*pgxpool.Pool, error) {
+	pools := make([]*pgxpool.Pool, 0, len(cfg.UserShards))
+	for i, shardCfg := range cfg.UserShards {
+		if shardCfg.StatementTimeout == 0 {
+			shardCfg.StatementTimeout = cfg.StatementTimeout
+		}
+
+		pool, err := NewConnection(ctx, &shardCfg, tracer)
+		if err != nil {
+			for _, opened := range pools {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("user shard %d: %w", i+1, err)
+		}
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// UserRepositories are the repositories of the user and the tables kept on
+// the user's shard.
+type UserRepositories struct {
+	Users                   repository.UserRepository
+	NotificationPreferences repository.NotificationPreferenceRepository
+	Tags                    repository.UserTagRepository
+	Consents                repository.ConsentRepository
+	LoginLocations          repository.LoginLocationRepository
+	SecurityEvents          repository.SecurityEventRepository
+	BackupCodes             repository.BackupCodeRepository
+	LegalHolds              repository.LegalHoldRepository
+	// UnitOfWork runs transactions on the shard of a user, which the
+	// repositories above join.
+	UnitOfWork repository.UserUnitOfWork
+}
+
+// NewUserRepositories returns the user repositories on primary, spread over
+// userShards as well when there are any. Users' phone numbers are encrypted
+// by fields.
+func NewUserRepositories(primary sqlc.DBTX, userShards []sqlc.DBTX, fields *encryption.FieldCipher) UserRepositories {
+	if len(userShards) == 0 {
+		return UserRepositories{
+			Users:                   NewUserRepository(primary, fields),
+			NotificationPreferences: NewNotificationPreferenceRepository(primary),
+			Tags:                    NewUserTagRepository(primary),
+			Consents:                NewConsentRepository(primary),
+			LoginLocations:          NewLoginLocationRepository(primary),
+			SecurityEvents:          NewSecurityEventRepository(primary),
+			BackupCodes:             NewBackupCodeRepository(primary),
+			LegalHolds:              NewLegalHoldRepository(primary),
+			UnitOfWork:              NewUserUnitOfWork(NewShardRouter([]sqlc.DBTX{primary})),
+		}
+	}
+
+	router := NewShardRouter(append([]sqlc.DBTX{primary}, userShards...))
+	return UserRepositories{
+		Users:                   NewShardedUserRepository(router, fields),
+		NotificationPreferences: NewShardedNotificationPreferenceRepository(router),
+		Tags:                    NewShardedUserTagRepository(router),
+		Consents:                NewShardedConsentRepository(router),
+		LoginLocations:          NewShardedLoginLocationRepository(router),
+		SecurityEvents:          NewShardedSecurityEventRepository(router),
+		BackupCodes:             NewShardedBackupCodeRepository(router),
+		LegalHolds:              NewShardedLegalHoldRepository(router),
+		UnitOfWork:              NewUserUnitOfWork(router),
+	}
+}
+
+// queriesFor returns base bound to the unit of work transaction in ctx, or
+// base itself outside a unit of work.
+func queriesFor(ctx context.Context, base *sqlc.Queries) *sqlc.Queries {
+	if tx, ok := uow.Tx(ctx); ok {
+		return base.WithTx(tx)
+	}
+
+	return base
+}
+
+// dbFor is queriesFor for queries built at runtime, which sqlc cannot
+// generate.
+func dbFor(ctx context.Context, db sqlc.DBTX) sqlc.DBTX {
+	if tx, ok := uow.Tx(ctx); ok {
+		return tx
+	}
 
-	return conn, nil
+	return db
 }
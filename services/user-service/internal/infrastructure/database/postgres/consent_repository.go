@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type ConsentRepository struct {
+	base *sqlc.Queries
+}
+
+func NewConsentRepository(db sqlc.DBTX) *ConsentRepository {
+	return &ConsentRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *ConsentRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *ConsentRepository) ListConsents(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	consents, err := r.queries(ctx).ListCurrentUserConsents(ctx, uuid)
+	if err != nil {
+		return nil, queryError(err, "failed to list consents")
+	}
+
+	return sqlcConsentsToEntity(consents), nil
+}
+
+func (r *ConsentRepository) ListConsentRecords(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	consents, err := r.queries(ctx).ListUserConsentRecords(ctx, uuid)
+	if err != nil {
+		return nil, queryError(err, "failed to list consent records")
+	}
+
+	return sqlcConsentsToEntity(consents), nil
+}
+
+func (r *ConsentRepository) SaveConsent(ctx context.Context, consent *entity.Consent) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(consent.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", consent.UserID))
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(consent.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).InsertUserConsent(ctx, sqlc.InsertUserConsentParams{
+		UserID:        uuid,
+		Purpose:       consent.Purpose.String(),
+		Granted:       consent.Granted,
+		PolicyVersion: consent.PolicyVersion,
+		CreatedAt:     createdAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save consent")
+	}
+
+	return nil
+}
+
+func sqlcConsentsToEntity(consents []sqlc.UserConsent) []*entity.Consent {
+	ret := make([]*entity.Consent, 0, len(consents))
+	for _, consent := range consents {
+		ret = append(ret, entity.ConsentFromDatabase(
+			consent.UserID.String(),
+			consent.Purpose,
+			consent.Granted,
+			consent.PolicyVersion,
+			consent.CreatedAt.Time.Unix(),
+		))
+	}
+
+	return ret
+}
@@ -1,9 +1,11 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
 )
 
 func isDuplicateKeyError(err error) bool {
@@ -14,3 +16,25 @@ func isDuplicateKeyError(err error) bool {
 
 	return false
 }
+
+// isStatementTimeout reports whether the server canceled the query, e.g. for
+// running past statement_timeout.
+func isStatementTimeout(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "57014" // query_canceled
+}
+
+// queryError converts a failed query into a domain error prefixed with msg.
+// Canceled contexts, expired deadlines and statement timeouts keep their
+// meaning, so clients can tell a slow query from a broken one.
+func queryError(err error, msg string) error {
+	err = fmt.Errorf("%s: %w", msg, err)
+	if ctxErr, ok := domain_error.FromContext(err); ok {
+		return ctxErr
+	}
+	if isStatementTimeout(err) {
+		return domain_error.New(domain_error.ReasonDeadlineExceeded, domain_error.WithMessage(err.Error()))
+	}
+
+	return domain_error.NewInternalError(err.Error())
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type LegalHoldRepository struct {
+	base *sqlc.Queries
+}
+
+func NewLegalHoldRepository(db sqlc.DBTX) *LegalHoldRepository {
+	return &LegalHoldRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *LegalHoldRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *LegalHoldRepository) GetLegalHold(ctx context.Context, userID string) (*entity.LegalHold, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	hold, err := r.queries(ctx).GetUserLegalHold(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("user %s is not under a legal hold", userID))
+		}
+
+		return nil, queryError(err, "failed to get legal hold")
+	}
+
+	return entity.LegalHoldFromDatabase(hold.UserID.String(), hold.Reason, hold.CreatedAt.Time.Unix()), nil
+}
+
+func (r *LegalHoldRepository) SaveLegalHold(ctx context.Context, hold *entity.LegalHold) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(hold.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", hold.UserID))
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(hold.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	saved, err := r.queries(ctx).UpsertUserLegalHold(ctx, sqlc.UpsertUserLegalHoldParams{
+		UserID:    uuid,
+		Reason:    hold.Reason,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save legal hold")
+	}
+	hold.CreatedAt = sharedvo.NewTime(saved.CreatedAt.Time.Unix())
+
+	return nil
+}
+
+func (r *LegalHoldRepository) DeleteLegalHold(ctx context.Context, userID string) (int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	ret, err := r.queries(ctx).DeleteUserLegalHold(ctx, uuid)
+	if err != nil {
+		return 0, queryError(err, "failed to delete legal hold")
+	}
+
+	return ret.RowsAffected(), nil
+}
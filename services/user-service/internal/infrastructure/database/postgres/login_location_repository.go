@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type LoginLocationRepository struct {
+	base *sqlc.Queries
+}
+
+func NewLoginLocationRepository(db sqlc.DBTX) *LoginLocationRepository {
+	return &LoginLocationRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *LoginLocationRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *LoginLocationRepository) ListLoginLocations(ctx context.Context, userID string) ([]*entity.LoginLocation, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	locations, err := r.queries(ctx).ListUserLoginLocations(ctx, uuid)
+	if err != nil {
+		return nil, queryError(err, "failed to list login locations")
+	}
+
+	ret := make([]*entity.LoginLocation, 0, len(locations))
+	for _, location := range locations {
+		ret = append(ret, entity.LoginLocationFromDatabase(
+			location.UserID.String(),
+			location.Country,
+			location.City,
+			location.LastIp,
+			location.FirstSeenAt.Time.Unix(),
+			location.LastSeenAt.Time.Unix(),
+		))
+	}
+
+	return ret, nil
+}
+
+func (r *LoginLocationRepository) SaveLoginLocation(ctx context.Context, location *entity.LoginLocation) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(location.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", location.UserID))
+	}
+
+	firstSeenAt := pgtype.Timestamp{}
+	if err := firstSeenAt.Scan(location.FirstSeenAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan first seen timestamp: %s", err.Error()))
+	}
+
+	lastSeenAt := pgtype.Timestamp{}
+	if err := lastSeenAt.Scan(location.LastSeenAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan last seen timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).UpsertUserLoginLocation(ctx, sqlc.UpsertUserLoginLocationParams{
+		UserID:      uuid,
+		Country:     location.Country,
+		City:        location.City,
+		LastIp:      location.LastIP,
+		FirstSeenAt: firstSeenAt,
+		LastSeenAt:  lastSeenAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save login location")
+	}
+
+	return nil
+}
+
+func (r *LoginLocationRepository) CountExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.queries(ctx).CountExpiredUserLoginLocations(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, queryError(err, "failed to count expired login locations")
+	}
+
+	return n, nil
+}
+
+func (r *LoginLocationRepository) DeleteExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error) {
+	ret, err := r.queries(ctx).DeleteExpiredUserLoginLocations(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, queryError(err, "failed to delete expired login locations")
+	}
+
+	return ret.RowsAffected(), nil
+}
@@ -0,0 +1,3 @@
+-- sqlfluff:disable
+
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,10 @@
+-- sqlfluff:disable
+
+CREATE TABLE notification_preferences (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  channel VARCHAR(20) NOT NULL,
+  category VARCHAR(20) NOT NULL,
+  enabled BOOLEAN NOT NULL,
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (user_id, channel, category)
+);
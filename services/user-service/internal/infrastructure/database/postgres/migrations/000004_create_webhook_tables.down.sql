@@ -0,0 +1,4 @@
+-- sqlfluff:disable
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,18 @@
+-- sqlfluff:disable
+
+CREATE TABLE jobs (
+  id BIGSERIAL PRIMARY KEY,
+  kind TEXT NOT NULL,
+  args JSONB NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempt INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL,
+  run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  locked_until TIMESTAMP,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_jobs_status ON jobs(status, id);
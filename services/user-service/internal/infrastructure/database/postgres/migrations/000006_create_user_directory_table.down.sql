@@ -0,0 +1,7 @@
+-- sqlfluff:disable
+
+ALTER TABLE webhook_subscriptions
+  ADD CONSTRAINT webhook_subscriptions_owner_id_fkey
+  FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS user_directory;
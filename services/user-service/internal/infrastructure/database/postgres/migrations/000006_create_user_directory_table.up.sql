@@ -0,0 +1,15 @@
+-- sqlfluff:disable
+
+-- maps emails to user IDs when users are sharded; only the primary database's
+-- copy is used
+CREATE TABLE user_directory (
+  email VARCHAR(100) PRIMARY KEY,
+  user_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_directory_user_id ON user_directory(user_id);
+
+-- webhook subscriptions stay on the primary database while their owner may
+-- live on another shard
+ALTER TABLE webhook_subscriptions DROP CONSTRAINT webhook_subscriptions_owner_id_fkey;
@@ -0,0 +1,3 @@
+-- sqlfluff:disable
+
+ALTER TABLE users DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- sqlfluff:disable
+
+-- bumped by every profile update; clients send it back as a precondition so
+-- concurrent edits don't overwrite each other
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- sqlfluff:disable
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS result;
@@ -0,0 +1,4 @@
+-- sqlfluff:disable
+
+-- lets jobs serve as long-running operations whose callers read the outcome
+ALTER TABLE jobs ADD COLUMN result JSONB;
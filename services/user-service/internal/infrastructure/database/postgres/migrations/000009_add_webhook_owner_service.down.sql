@@ -0,0 +1,7 @@
+-- sqlfluff:disable
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_owner_service;
+DELETE FROM webhook_subscriptions WHERE owner_service IS NOT NULL;
+ALTER TABLE webhook_subscriptions DROP CONSTRAINT IF EXISTS webhook_subscriptions_owner_check;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS owner_service;
+ALTER TABLE webhook_subscriptions ALTER COLUMN owner_id SET NOT NULL;
@@ -0,0 +1,10 @@
+-- sqlfluff:disable
+
+-- internal services, identified by their SPIFFE ID, own subscriptions too;
+-- theirs receive the events of every user, a user's only those about the user
+ALTER TABLE webhook_subscriptions ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE webhook_subscriptions ADD COLUMN owner_service TEXT;
+ALTER TABLE webhook_subscriptions ADD CONSTRAINT webhook_subscriptions_owner_check
+  CHECK ((owner_id IS NULL) <> (owner_service IS NULL));
+
+CREATE INDEX idx_webhook_subscriptions_owner_service ON webhook_subscriptions(owner_service);
@@ -0,0 +1,15 @@
+-- sqlfluff:disable
+
+-- customer segments; kept on the user's shard like notification preferences.
+-- Tag rules refresh updated_at on every run, then delete the rule tags they
+-- did not refresh.
+CREATE TABLE user_tags (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  tag VARCHAR(50) NOT NULL,
+  source VARCHAR(20) NOT NULL CHECK (source IN ('admin', 'rule')),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, tag)
+);
+
+CREATE INDEX idx_user_tags_tag ON user_tags(tag, source, updated_at);
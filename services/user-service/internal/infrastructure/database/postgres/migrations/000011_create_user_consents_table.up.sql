@@ -0,0 +1,22 @@
+-- sqlfluff:disable
+
+-- every consent decision, kept as proof of what the user agreed to and
+-- under which policy version; the latest row per purpose is in force. Kept
+-- on the user's shard like notification preferences.
+CREATE TABLE user_consents (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  purpose VARCHAR(30) NOT NULL,
+  granted BOOLEAN NOT NULL,
+  policy_version VARCHAR(50) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_consents_user_purpose ON user_consents(user_id, purpose, id DESC);
+
+-- marketing now needs consent as well as the preference; users who enabled
+-- marketing before consents existed keep receiving it
+INSERT INTO user_consents (user_id, purpose, granted, policy_version, created_at)
+SELECT user_id, 'marketing_' || channel, TRUE, 'legacy', COALESCE(updated_at, NOW())
+FROM notification_preferences
+WHERE category = 'marketing' AND enabled;
@@ -0,0 +1,6 @@
+-- sqlfluff:disable
+
+-- emails lower-cased by `cmd/reshard -normalize-emails` stay lower-cased:
+-- their original case is not kept, so there is nothing to restore
+DROP INDEX IF EXISTS idx_user_directory_email_key;
+DROP INDEX IF EXISTS idx_users_email_key;
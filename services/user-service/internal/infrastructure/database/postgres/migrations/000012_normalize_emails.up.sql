@@ -0,0 +1,11 @@
+-- sqlfluff:disable
+
+-- emails are stored lower-cased from now on. Existing ones are lower-cased
+-- by `go run ./cmd/reshard -normalize-emails` rather than here: this
+-- migration only sees its own database, while two users whose emails differ
+-- only in case may live on different shards.
+
+-- finds the addresses that differ only in a "+tag"; the expression must match
+-- valueobject.Email.DedupKey
+CREATE INDEX idx_users_email_key ON users ((regexp_replace(email, '\+[^@]*@', '@')));
+CREATE INDEX idx_user_directory_email_key ON user_directory ((regexp_replace(email, '\+[^@]*@', '@')));
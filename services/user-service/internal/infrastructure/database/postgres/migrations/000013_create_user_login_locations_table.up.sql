@@ -0,0 +1,13 @@
+-- sqlfluff:disable
+
+-- the countries a user has signed in from, to spot logins from elsewhere.
+-- Kept on the user's shard like notification preferences.
+CREATE TABLE user_login_locations (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  country VARCHAR(2) NOT NULL,
+  city VARCHAR(100) NOT NULL DEFAULT '',
+  last_ip VARCHAR(45) NOT NULL,
+  first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, country)
+);
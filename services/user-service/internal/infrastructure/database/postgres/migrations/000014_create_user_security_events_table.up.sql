@@ -0,0 +1,17 @@
+-- sqlfluff:disable
+
+-- security-relevant account activity, e.g. password changes and logins from
+-- new devices, for the user and support to review. Kept on the user's shard
+-- like notification preferences.
+CREATE TABLE user_security_events (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind VARCHAR(30) NOT NULL,
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  user_agent VARCHAR(512) NOT NULL DEFAULT '',
+  country VARCHAR(2) NOT NULL DEFAULT '',
+  city VARCHAR(100) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_security_events_user ON user_security_events(user_id, id DESC);
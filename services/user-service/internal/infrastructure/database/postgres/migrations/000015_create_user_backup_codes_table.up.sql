@@ -0,0 +1,14 @@
+-- sqlfluff:disable
+
+-- one-time codes finishing a challenged login without the emailed code;
+-- only a hash of each code is kept. Kept on the user's shard like
+-- notification preferences.
+CREATE TABLE user_backup_codes (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash VARCHAR(64) NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_backup_codes_user ON user_backup_codes(user_id, code_hash);
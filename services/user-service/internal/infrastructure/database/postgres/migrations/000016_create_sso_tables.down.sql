@@ -0,0 +1,5 @@
+-- sqlfluff:disable
+
+DROP TABLE IF EXISTS sso_identities;
+DROP TABLE IF EXISTS sso_connection_domains;
+DROP TABLE IF EXISTS sso_connections;
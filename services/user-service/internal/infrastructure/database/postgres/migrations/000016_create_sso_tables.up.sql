@@ -0,0 +1,30 @@
+-- sqlfluff:disable
+
+-- OpenID Connect identity providers of organizations, each owning the email
+-- domains whose users must sign in through it. Only the primary database's
+-- copy is used, like the user directory.
+CREATE TABLE sso_connections (
+  id UUID PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  issuer VARCHAR(2048) NOT NULL,
+  client_id VARCHAR(255) NOT NULL,
+  client_secret VARCHAR(1024) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE sso_connection_domains (
+  domain VARCHAR(255) PRIMARY KEY,
+  connection_id UUID NOT NULL REFERENCES sso_connections(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sso_connection_domains_connection_id ON sso_connection_domains(connection_id);
+
+-- the local user of each IdP account; users may live on another shard
+CREATE TABLE sso_identities (
+  connection_id UUID NOT NULL REFERENCES sso_connections(id) ON DELETE CASCADE,
+  subject VARCHAR(255) NOT NULL,
+  user_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_login_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (connection_id, subject)
+);
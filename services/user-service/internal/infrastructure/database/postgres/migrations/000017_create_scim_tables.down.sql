@@ -0,0 +1,6 @@
+-- sqlfluff:disable
+
+DROP TABLE IF EXISTS scim_group_members;
+DROP TABLE IF EXISTS scim_groups;
+DROP TABLE IF EXISTS scim_users;
+ALTER TABLE sso_connections DROP COLUMN IF EXISTS scim_token_hash;
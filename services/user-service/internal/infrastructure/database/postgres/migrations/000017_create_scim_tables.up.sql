@@ -0,0 +1,36 @@
+-- sqlfluff:disable
+
+-- the SHA-256 of the bearer token an organization's IdP provisions users
+-- with; NULL until CreateScimToken is called
+ALTER TABLE sso_connections ADD COLUMN scim_token_hash VARCHAR(64) UNIQUE;
+
+-- the users an organization's IdP provisioned over SCIM; users may live on
+-- another shard
+CREATE TABLE scim_users (
+  user_id UUID PRIMARY KEY,
+  connection_id UUID NOT NULL REFERENCES sso_connections(id) ON DELETE CASCADE,
+  external_id VARCHAR(255) NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scim_users_connection_id ON scim_users(connection_id, created_at, user_id);
+
+CREATE TABLE scim_groups (
+  id UUID PRIMARY KEY,
+  connection_id UUID NOT NULL REFERENCES sso_connections(id) ON DELETE CASCADE,
+  display_name VARCHAR(255) NOT NULL,
+  external_id VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (connection_id, display_name)
+);
+
+CREATE TABLE scim_group_members (
+  group_id UUID NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES scim_users(user_id) ON DELETE CASCADE,
+  PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_scim_group_members_user_id ON scim_group_members(user_id);
@@ -0,0 +1,5 @@
+-- sqlfluff:disable
+
+DROP INDEX IF EXISTS idx_user_login_locations_last_seen_at;
+DROP INDEX IF EXISTS idx_user_security_events_created_at;
+DROP TABLE IF EXISTS user_legal_holds;
@@ -0,0 +1,14 @@
+-- sqlfluff:disable
+
+-- users whose data must be preserved, e.g. for litigation; the retention
+-- purge skips them and they cannot be deleted. Kept on the user's shard like
+-- the data it protects.
+CREATE TABLE user_legal_holds (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  reason VARCHAR(500) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- the retention purge filters by age
+CREATE INDEX idx_user_security_events_created_at ON user_security_events(created_at);
+CREATE INDEX idx_user_login_locations_last_seen_at ON user_login_locations(last_seen_at);
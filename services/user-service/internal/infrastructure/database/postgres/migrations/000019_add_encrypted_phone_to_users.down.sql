@@ -0,0 +1,8 @@
+-- sqlfluff:disable
+
+-- phone numbers written since the up migration are only in phone_ciphertext;
+-- run cmd/encrypt-pii -decrypt before migrating down to keep them
+DROP INDEX IF EXISTS idx_users_phone_hash;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS phone_hash,
+  DROP COLUMN IF EXISTS phone_ciphertext;
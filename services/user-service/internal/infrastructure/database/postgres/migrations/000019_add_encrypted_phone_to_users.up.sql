@@ -0,0 +1,10 @@
+-- sqlfluff:disable
+
+-- phone numbers are encrypted by the service; phone_hash is a keyed hash of
+-- the number so users can still be found by it. phone keeps the numbers
+-- written before, until cmd/encrypt-pii encrypts them.
+ALTER TABLE users
+  ADD COLUMN phone_ciphertext TEXT DEFAULT NULL,
+  ADD COLUMN phone_hash VARCHAR(64) DEFAULT NULL;
+
+CREATE INDEX idx_users_phone_hash ON users(phone_hash);
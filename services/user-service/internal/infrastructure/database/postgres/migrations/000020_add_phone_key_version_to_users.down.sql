@@ -0,0 +1,5 @@
+-- sqlfluff:disable
+
+-- ciphertexts keep their key version, so nothing is lost but the index
+DROP INDEX IF EXISTS idx_users_phone_key_version;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_key_version;
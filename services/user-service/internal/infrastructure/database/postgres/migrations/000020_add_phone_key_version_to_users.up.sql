@@ -0,0 +1,9 @@
+-- sqlfluff:disable
+
+-- version of the master key phone_ciphertext is under, so reencrypt_fields
+-- can find the values left on an older key; the ciphertexts written so far
+-- are all under key 1
+ALTER TABLE users ADD COLUMN phone_key_version INTEGER DEFAULT NULL;
+UPDATE users SET phone_key_version = 1 WHERE phone_ciphertext IS NOT NULL;
+
+CREATE INDEX idx_users_phone_key_version ON users(phone_key_version) WHERE phone_ciphertext IS NOT NULL;
@@ -0,0 +1,7 @@
+-- sqlfluff:disable
+
+ALTER TABLE jobs
+  ALTER COLUMN run_at TYPE TIMESTAMP USING run_at AT TIME ZONE 'UTC',
+  ALTER COLUMN locked_until TYPE TIMESTAMP USING locked_until AT TIME ZONE 'UTC',
+  ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+  ALTER COLUMN finished_at TYPE TIMESTAMP USING finished_at AT TIME ZONE 'UTC';
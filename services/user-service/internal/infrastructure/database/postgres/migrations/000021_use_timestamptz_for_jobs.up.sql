@@ -0,0 +1,10 @@
+-- sqlfluff:disable
+
+-- run_at and locked_until are compared with both NOW() and times sent by the
+-- service, which only agree when the columns carry a zone. The times stored
+-- so far were written by a server running in UTC.
+ALTER TABLE jobs
+  ALTER COLUMN run_at TYPE TIMESTAMPTZ USING run_at AT TIME ZONE 'UTC',
+  ALTER COLUMN locked_until TYPE TIMESTAMPTZ USING locked_until AT TIME ZONE 'UTC',
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+  ALTER COLUMN finished_at TYPE TIMESTAMPTZ USING finished_at AT TIME ZONE 'UTC';
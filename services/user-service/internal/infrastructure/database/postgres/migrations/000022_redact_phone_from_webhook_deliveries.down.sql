@@ -0,0 +1,4 @@
+-- sqlfluff:disable
+
+-- the redacted phone numbers cannot be restored; nothing to do
+SELECT 1;
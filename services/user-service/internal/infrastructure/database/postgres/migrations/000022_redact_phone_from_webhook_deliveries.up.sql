@@ -0,0 +1,8 @@
+-- sqlfluff:disable
+
+-- user events no longer carry the phone number; drop it from the deliveries
+-- stored before, which would otherwise keep it in plaintext
+UPDATE webhook_deliveries
+SET payload = payload #- '{data,phone}'
+WHERE event_type IN ('user.created', 'user.updated', 'user.deleted')
+  AND payload->'data' ? 'phone';
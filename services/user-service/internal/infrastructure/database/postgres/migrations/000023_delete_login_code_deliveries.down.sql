@@ -0,0 +1,4 @@
+-- sqlfluff:disable
+
+-- the deleted deliveries cannot be restored; nothing to do
+SELECT 1;
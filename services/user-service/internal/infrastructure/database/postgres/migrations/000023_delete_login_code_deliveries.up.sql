@@ -0,0 +1,5 @@
+-- sqlfluff:disable
+
+-- login codes are sent to the notification service directly now; drop the
+-- deliveries that stored them in plaintext
+DELETE FROM webhook_deliveries WHERE event_type = 'user.login_code_issued';
@@ -0,0 +1,6 @@
+-- sqlfluff:disable
+
+-- sign-in links are sent to the notification service directly now; drop the
+-- deliveries that stored them, as each one signs its user in until it
+-- expires
+DELETE FROM webhook_deliveries WHERE event_type = 'user.magic_link_issued';
@@ -0,0 +1,47 @@
+// Package migrations embeds the golang-migrate SQL files so they can be
+// applied without the migrate CLI, e.g. by the integration test harness.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Up applies every up migration in version order. It keeps no version table,
+// so it is only meant for fresh databases; use the migrate CLI elsewhere.
+func Up(ctx context.Context, db execer) error {
+	files, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		raw, err := FS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+		if strings.TrimSpace(string(raw)) == "" {
+			continue
+		}
+
+		if _, err := db.Exec(ctx, string(raw)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
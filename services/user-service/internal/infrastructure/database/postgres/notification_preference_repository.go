@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type NotificationPreferenceRepository struct {
+	base *sqlc.Queries
+}
+
+func NewNotificationPreferenceRepository(db sqlc.DBTX) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *NotificationPreferenceRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *NotificationPreferenceRepository) GetPreferencesByUserID(ctx context.Context, userID string) ([]*entity.NotificationPreference, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	prefs, err := r.queries(ctx).GetNotificationPreferencesByUserID(ctx, uuid)
+	if err != nil {
+		return nil, queryError(err, "failed to get notification preferences")
+	}
+
+	ret := make([]*entity.NotificationPreference, 0, len(prefs))
+	for _, pref := range prefs {
+		ret = append(ret, r.sqlcPreferenceToEntity(pref))
+	}
+
+	return ret, nil
+}
+
+func (r *NotificationPreferenceRepository) GetPreference(ctx context.Context, userID, channel, category string) (*entity.NotificationPreference, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	pref, err := r.queries(ctx).GetNotificationPreference(ctx, sqlc.GetNotificationPreferenceParams{
+		UserID:   uuid,
+		Channel:  channel,
+		Category: category,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("notification preference %s/%s not set", channel, category))
+		}
+
+		return nil, queryError(err, "failed to get notification preference")
+	}
+
+	return r.sqlcPreferenceToEntity(pref), nil
+}
+
+func (r *NotificationPreferenceRepository) UpsertPreference(ctx context.Context, pref *entity.NotificationPreference) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(pref.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", pref.UserID))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(pref.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).UpsertNotificationPreference(ctx, sqlc.UpsertNotificationPreferenceParams{
+		UserID:    uuid,
+		Channel:   pref.Channel.String(),
+		Category:  pref.Category.String(),
+		Enabled:   pref.Enabled,
+		UpdatedAt: updatedAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save notification preference")
+	}
+
+	return nil
+}
+
+func (*NotificationPreferenceRepository) sqlcPreferenceToEntity(pref sqlc.NotificationPreference) *entity.NotificationPreference {
+	return entity.NotificationPreferenceFromDatabase(
+		pref.UserID.String(),
+		pref.Channel,
+		pref.Category,
+		pref.Enabled,
+		pref.UpdatedAt.Time.Unix(),
+	)
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+)
+
+// PhoneRewriteStats counts the users examined, the users whose phone number
+// was rewritten, and those skipped because a write changed the number
+// meanwhile; a skipped number was written in its new form by that write.
+type PhoneRewriteStats struct {
+	Scanned   int
+	Rewritten int
+	Skipped   int
+}
+
+// EncryptPlaintextPhones encrypts the phone numbers still stored in the
+// clear, from before UserRepository encrypted them, on every shard of
+// router. It can run while the service serves traffic, and be repeated.
+func EncryptPlaintextPhones(ctx context.Context, router *ShardRouter, fields *encryption.FieldCipher, batchSize int32) (PhoneRewriteStats, error) {
+	return rewritePhones(ctx, router, allUsers(batchSize), func(user sqlc.User) (*sqlc.RewriteUserPhoneParams, error) {
+		if !user.Phone.Valid || user.Phone.String == "" {
+			return nil, nil
+		}
+
+		return encryptedPhoneParams(ctx, fields, user.Phone.String)
+	})
+}
+
+// ReencryptPhones re-encrypts the phone numbers under a master key other
+// than the current one of fields, on every shard of router. It can run
+// while the service serves traffic, and be repeated.
+func ReencryptPhones(ctx context.Context, router *ShardRouter, fields *encryption.FieldCipher, batchSize int32) (PhoneRewriteStats, error) {
+	list := func(ctx context.Context, queries *sqlc.Queries, after pgtype.UUID) ([]sqlc.User, error) {
+		return queries.ListUsersWithPhoneKeyVersionOtherThan(ctx, sqlc.ListUsersWithPhoneKeyVersionOtherThanParams{
+			KeyVersion: pgtype.Int4{Int32: fields.KeyVersion(), Valid: true},
+			AfterID:    after,
+			BatchSize:  batchSize,
+		})
+	}
+
+	return rewritePhones(ctx, router, list, func(user sqlc.User) (*sqlc.RewriteUserPhoneParams, error) {
+		phone, err := fields.Decrypt(ctx, phoneField, user.PhoneCiphertext.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return encryptedPhoneParams(ctx, fields, phone)
+	})
+}
+
+// CountPhonesByKeyVersion returns how many encrypted phone numbers are under
+// each master key version, over every shard of router. Numbers from before
+// key versions were recorded count as version 0.
+func CountPhonesByKeyVersion(ctx context.Context, router *ShardRouter) (map[int32]int64, error) {
+	counts := make(map[int32]int64)
+	for index, db := range router.Shards() {
+		rows, err := sqlc.New(db).CountUsersByPhoneKeyVersion(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count phone numbers of shard %d: %w", index, err)
+		}
+		for _, row := range rows {
+			counts[row.PhoneKeyVersion.Int32] += row.Users
+		}
+	}
+
+	return counts, nil
+}
+
+func encryptedPhoneParams(ctx context.Context, fields *encryption.FieldCipher, phone string) (*sqlc.RewriteUserPhoneParams, error) {
+	encrypted, err := encryptPhone(ctx, fields, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlc.RewriteUserPhoneParams{
+		PhoneCiphertext: encrypted.ciphertext,
+		PhoneHash:       encrypted.hash,
+		PhoneKeyVersion: encrypted.keyVersion,
+	}, nil
+}
+
+// DecryptPhones stores every encrypted phone number in the clear again, the
+// way it was before migration 000019, e.g. before migrating down. The
+// service must be stopped meanwhile, or it encrypts new numbers again.
+func DecryptPhones(ctx context.Context, router *ShardRouter, fields *encryption.FieldCipher, batchSize int32) (PhoneRewriteStats, error) {
+	return rewritePhones(ctx, router, allUsers(batchSize), func(user sqlc.User) (*sqlc.RewriteUserPhoneParams, error) {
+		if !user.PhoneCiphertext.Valid {
+			return nil, nil
+		}
+
+		phone, err := fields.Decrypt(ctx, phoneField, user.PhoneCiphertext.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return &sqlc.RewriteUserPhoneParams{
+			Phone: pgtype.Text{String: phone, Valid: true},
+		}, nil
+	})
+}
+
+// userLister returns the next batch of users of a shard after the one with
+// ID after, in ID order.
+type userLister func(ctx context.Context, queries *sqlc.Queries, after pgtype.UUID) ([]sqlc.User, error)
+
+func allUsers(batchSize int32) userLister {
+	return func(ctx context.Context, queries *sqlc.Queries, after pgtype.UUID) ([]sqlc.User, error) {
+		return queries.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{AfterID: after, BatchSize: batchSize})
+	}
+}
+
+// rewritePhones stores the phone number of every user list returns as
+// rewrite returns it; users for which rewrite returns nil are left alone.
+func rewritePhones(ctx context.Context, router *ShardRouter, list userLister, rewrite func(sqlc.User) (*sqlc.RewriteUserPhoneParams, error)) (PhoneRewriteStats, error) {
+	var stats PhoneRewriteStats
+	for index, db := range router.Shards() {
+		queries := sqlc.New(db)
+		after := pgtype.UUID{Valid: true}
+		for {
+			users, err := list(ctx, queries, after)
+			if err != nil {
+				return stats, fmt.Errorf("failed to list users of shard %d: %w", index, err)
+			}
+			if len(users) == 0 {
+				break
+			}
+
+			for _, user := range users {
+				stats.Scanned++
+				params, err := rewrite(user)
+				if err != nil {
+					return stats, fmt.Errorf("failed to rewrite phone number of user %s: %w", user.ID.String(), err)
+				}
+				if params == nil {
+					continue
+				}
+
+				params.ID = user.ID
+				params.OldPhone = user.Phone
+				params.OldPhoneCiphertext = user.PhoneCiphertext
+				ret, err := queries.RewriteUserPhone(ctx, *params)
+				if err != nil {
+					return stats, fmt.Errorf("failed to store phone number of user %s: %w", user.ID.String(), err)
+				}
+				if ret.RowsAffected() == 0 {
+					stats.Skipped++
+					continue
+				}
+				stats.Rewritten++
+			}
+			after = users[len(users)-1].ID
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PhoneKeyRotation moves phone numbers onto the current master key after
+// a rotation, and exports the progress.
+type PhoneKeyRotation struct {
+	router    *ShardRouter
+	fields    *encryption.FieldCipher
+	batchSize int32
+
+	keyVersion  prometheus.Gauge
+	values      *prometheus.GaugeVec
+	reencrypted prometheus.Counter
+}
+
+// NewPhoneKeyRotation registers the rotation metrics with registerer, if
+// not nil.
+func NewPhoneKeyRotation(router *ShardRouter, fields *encryption.FieldCipher, batchSize int32, registerer prometheus.Registerer) *PhoneKeyRotation {
+	r := &PhoneKeyRotation{
+		router:    router,
+		fields:    fields,
+		batchSize: batchSize,
+		keyVersion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "encryption_key_version",
+			Help:        "Version of the master key new values are encrypted under.",
+			ConstLabels: prometheus.Labels{"field": phoneField},
+		}),
+		values: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "encrypted_field_values",
+			Help:        "Encrypted values by master key version, as of the last re-encryption run.",
+			ConstLabels: prometheus.Labels{"field": phoneField},
+		}, []string{"key_version"}),
+		reencrypted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "encrypted_field_reencryptions_total",
+			Help:        "Values re-encrypted under the current master key.",
+			ConstLabels: prometheus.Labels{"field": phoneField},
+		}),
+	}
+	r.keyVersion.Set(float64(fields.KeyVersion()))
+	if registerer != nil {
+		registerer.MustRegister(r.keyVersion, r.values, r.reencrypted)
+	}
+
+	return r
+}
+
+// Reencrypt re-encrypts the phone numbers under older master keys, then
+// counts the numbers under each key and returns how many are still under
+// older ones: those written meanwhile by replicas still on an older key.
+func (r *PhoneKeyRotation) Reencrypt(ctx context.Context) (reencrypted, remaining int64, err error) {
+	stats, err := ReencryptPhones(ctx, r.router, r.fields, r.batchSize)
+	r.reencrypted.Add(float64(stats.Rewritten))
+	if err != nil {
+		return int64(stats.Rewritten), 0, err
+	}
+
+	counts, err := CountPhonesByKeyVersion(ctx, r.router)
+	if err != nil {
+		return int64(stats.Rewritten), 0, err
+	}
+
+	current := r.fields.KeyVersion()
+	r.values.Reset()
+	for version, n := range counts {
+		r.values.WithLabelValues(strconv.FormatInt(int64(version), 10)).Set(float64(n))
+		if version != current {
+			remaining += n
+		}
+	}
+
+	return int64(stats.Rewritten), remaining, nil
+}
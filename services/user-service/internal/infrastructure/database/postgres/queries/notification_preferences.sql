@@ -0,0 +1,21 @@
+-- name: GetNotificationPreferencesByUserID :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1 AND channel = $2 AND category = $3;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (
+  user_id,
+  channel,
+  category,
+  enabled,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT (user_id, channel, category) DO UPDATE
+SET
+  enabled = EXCLUDED.enabled,
+  updated_at = EXCLUDED.updated_at;
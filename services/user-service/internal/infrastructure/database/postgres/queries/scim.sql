@@ -0,0 +1,117 @@
+-- name: InsertScimUser :exec
+INSERT INTO scim_users (
+  user_id,
+  connection_id,
+  external_id,
+  active,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $5
+);
+
+-- name: GetScimUser :one
+SELECT * FROM scim_users
+WHERE user_id = $1;
+
+-- name: GetScimUserByExternalID :one
+SELECT * FROM scim_users
+WHERE connection_id = $1 AND external_id = $2;
+
+-- name: ListScimUsers :many
+SELECT * FROM scim_users
+WHERE connection_id = $1
+ORDER BY created_at, user_id
+LIMIT $2 OFFSET $3;
+
+-- name: CountScimUsers :one
+SELECT COUNT(*) FROM scim_users
+WHERE connection_id = $1;
+
+-- name: ListScimUsersByIDs :many
+SELECT * FROM scim_users
+WHERE connection_id = sqlc.arg(connection_id) AND user_id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: UpdateScimUser :execresult
+UPDATE scim_users
+SET external_id = $2,
+    active = $3,
+    updated_at = $4
+WHERE user_id = $1;
+
+-- name: DeleteScimUser :exec
+DELETE FROM scim_users
+WHERE user_id = $1;
+
+-- name: InsertScimGroup :exec
+-- one statement, so a taken display name leaves no members behind
+WITH scim_group AS (
+  INSERT INTO scim_groups (
+    id,
+    connection_id,
+    display_name,
+    external_id,
+    created_at,
+    updated_at
+  ) VALUES (
+    sqlc.arg(id), sqlc.arg(connection_id), sqlc.arg(display_name), sqlc.arg(external_id), sqlc.arg(created_at), sqlc.arg(created_at)
+  )
+)
+INSERT INTO scim_group_members (group_id, user_id)
+SELECT sqlc.arg(id), unnest(sqlc.arg(member_ids)::uuid[]);
+
+-- name: GetScimGroup :one
+SELECT * FROM scim_groups
+WHERE connection_id = $1 AND id = $2;
+
+-- name: GetScimGroupByDisplayName :one
+SELECT * FROM scim_groups
+WHERE connection_id = $1 AND display_name = $2;
+
+-- name: GetScimGroupByExternalID :one
+SELECT * FROM scim_groups
+WHERE connection_id = $1 AND external_id = $2;
+
+-- name: ListScimGroups :many
+SELECT * FROM scim_groups
+WHERE connection_id = $1
+ORDER BY created_at, id
+LIMIT $2 OFFSET $3;
+
+-- name: CountScimGroups :one
+SELECT COUNT(*) FROM scim_groups
+WHERE connection_id = $1;
+
+-- name: UpdateScimGroup :execresult
+UPDATE scim_groups
+SET display_name = $2,
+    external_id = $3,
+    updated_at = $4
+WHERE id = $1;
+
+-- name: DeleteScimGroup :execresult
+DELETE FROM scim_groups
+WHERE connection_id = $1 AND id = $2;
+
+-- name: ListScimGroupMembers :many
+SELECT * FROM scim_group_members
+WHERE group_id = ANY(sqlc.arg(group_ids)::uuid[])
+ORDER BY group_id, user_id;
+
+-- name: InsertScimGroupMembers :exec
+INSERT INTO scim_group_members (group_id, user_id)
+SELECT sqlc.arg(group_id)::uuid, unnest(sqlc.arg(user_ids)::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteScimGroupMembers :exec
+DELETE FROM scim_group_members
+WHERE group_id = sqlc.arg(group_id) AND user_id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: ReplaceScimGroupMembers :exec
+WITH removed AS (
+  DELETE FROM scim_group_members
+  WHERE group_id = sqlc.arg(group_id) AND NOT user_id = ANY(sqlc.arg(user_ids)::uuid[])
+)
+INSERT INTO scim_group_members (group_id, user_id)
+SELECT sqlc.arg(group_id)::uuid, unnest(sqlc.arg(user_ids)::uuid[])
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,65 @@
+-- name: InsertSsoConnection :exec
+-- one statement, so a domain taken by another connection leaves no
+-- connection behind
+WITH connection AS (
+  INSERT INTO sso_connections (
+    id,
+    name,
+    issuer,
+    client_id,
+    client_secret,
+    created_at
+  ) VALUES (
+    sqlc.arg(id), sqlc.arg(name), sqlc.arg(issuer), sqlc.arg(client_id), sqlc.arg(client_secret), sqlc.arg(created_at)
+  )
+)
+INSERT INTO sso_connection_domains (domain, connection_id)
+SELECT unnest(sqlc.arg(domains)::text[]), sqlc.arg(id);
+
+-- name: GetSsoConnection :one
+SELECT * FROM sso_connections
+WHERE id = $1;
+
+-- name: GetSsoConnectionByDomain :one
+SELECT c.* FROM sso_connections c
+JOIN sso_connection_domains d ON d.connection_id = c.id
+WHERE d.domain = $1;
+
+-- name: ListSsoConnections :many
+SELECT * FROM sso_connections
+ORDER BY name, id;
+
+-- name: ListSsoConnectionDomains :many
+SELECT * FROM sso_connection_domains
+WHERE connection_id = ANY(sqlc.arg(connection_ids)::uuid[])
+ORDER BY domain;
+
+-- name: DeleteSsoConnection :execresult
+DELETE FROM sso_connections
+WHERE id = $1;
+
+-- name: GetSsoIdentity :one
+SELECT * FROM sso_identities
+WHERE connection_id = $1 AND subject = $2;
+
+-- name: UpsertSsoIdentity :exec
+INSERT INTO sso_identities (
+  connection_id,
+  subject,
+  user_id,
+  created_at,
+  last_login_at
+) VALUES (
+  $1, $2, $3, $4, $4
+) ON CONFLICT (connection_id, subject) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    last_login_at = EXCLUDED.last_login_at;
+
+-- name: SetSsoConnectionScimToken :execresult
+UPDATE sso_connections
+SET scim_token_hash = $2
+WHERE id = $1;
+
+-- name: GetSsoConnectionByScimToken :one
+SELECT * FROM sso_connections
+WHERE scim_token_hash = $1;
@@ -0,0 +1,32 @@
+-- name: ReplaceUserBackupCodes :exec
+-- one statement, so the old set is gone exactly when the new one exists
+WITH deleted AS (
+  DELETE FROM user_backup_codes
+  WHERE user_id = sqlc.arg(user_id)
+)
+INSERT INTO user_backup_codes (user_id, code_hash, created_at)
+SELECT sqlc.arg(user_id), unnest(sqlc.arg(code_hashes)::text[]), sqlc.arg(created_at);
+
+-- name: ConsumeUserBackupCode :execresult
+UPDATE user_backup_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedUserBackupCodes :one
+SELECT COUNT(*) FROM user_backup_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: ListUserBackupCodes :many
+SELECT * FROM user_backup_codes
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: CopyUserBackupCode :exec
+INSERT INTO user_backup_codes (
+  user_id,
+  code_hash,
+  used_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4
+);
@@ -0,0 +1,20 @@
+-- name: ListCurrentUserConsents :many
+SELECT DISTINCT ON (purpose) * FROM user_consents
+WHERE user_id = $1
+ORDER BY purpose, id DESC;
+
+-- name: ListUserConsentRecords :many
+SELECT * FROM user_consents
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: InsertUserConsent :exec
+INSERT INTO user_consents (
+  user_id,
+  purpose,
+  granted,
+  policy_version,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+);
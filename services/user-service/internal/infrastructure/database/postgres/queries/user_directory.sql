@@ -0,0 +1,41 @@
+-- name: InsertUserDirectoryEntry :exec
+INSERT INTO user_directory (
+  email,
+  user_id
+) VALUES (
+  $1, $2
+);
+
+-- name: BackfillUserDirectoryEntry :exec
+INSERT INTO user_directory (
+  email,
+  user_id
+) VALUES (
+  $1, $2
+) ON CONFLICT (email) DO NOTHING;
+
+-- name: GetUserDirectoryEntry :one
+SELECT * FROM user_directory
+WHERE email = $1;
+
+-- name: DeleteUserDirectoryEntry :exec
+DELETE FROM user_directory
+WHERE email = $1 AND user_id = $2;
+
+-- name: DirectoryEmailKeyExists :one
+-- uses idx_user_directory_email_key
+SELECT EXISTS (
+  SELECT 1 FROM user_directory
+  WHERE regexp_replace(email, '\+[^@]*@', '@') = sqlc.arg(email_key)
+);
+
+-- name: NormalizeUserDirectoryEmail :exec
+-- lower-cases the entry's email unless the lower-cased one has an entry
+UPDATE user_directory
+SET email = sqlc.arg(normalized_email)
+WHERE email = sqlc.arg(email)
+  AND user_id = sqlc.arg(user_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM user_directory AS taken
+    WHERE taken.email = sqlc.arg(normalized_email)
+  );
@@ -0,0 +1,19 @@
+-- name: GetUserLegalHold :one
+SELECT * FROM user_legal_holds
+WHERE user_id = $1;
+
+-- name: UpsertUserLegalHold :one
+-- placing a hold again replaces its reason but keeps when it was placed
+INSERT INTO user_legal_holds (
+  user_id,
+  reason,
+  created_at
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (user_id) DO UPDATE
+SET reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: DeleteUserLegalHold :execresult
+DELETE FROM user_legal_holds
+WHERE user_id = $1;
@@ -0,0 +1,31 @@
+-- name: ListUserLoginLocations :many
+SELECT * FROM user_login_locations
+WHERE user_id = $1
+ORDER BY last_seen_at DESC;
+
+-- name: UpsertUserLoginLocation :exec
+INSERT INTO user_login_locations (
+  user_id,
+  country,
+  city,
+  last_ip,
+  first_seen_at,
+  last_seen_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) ON CONFLICT (user_id, country) DO UPDATE
+SET
+  city = EXCLUDED.city,
+  last_ip = EXCLUDED.last_ip,
+  first_seen_at = LEAST(user_login_locations.first_seen_at, EXCLUDED.first_seen_at),
+  last_seen_at = GREATEST(user_login_locations.last_seen_at, EXCLUDED.last_seen_at);
+
+-- name: CountExpiredUserLoginLocations :one
+SELECT COUNT(*) FROM user_login_locations l
+WHERE l.last_seen_at < sqlc.arg(last_seen_before)
+  AND NOT EXISTS (SELECT 1 FROM user_legal_holds h WHERE h.user_id = l.user_id);
+
+-- name: DeleteExpiredUserLoginLocations :execresult
+DELETE FROM user_login_locations l
+WHERE l.last_seen_at < sqlc.arg(last_seen_before)
+  AND NOT EXISTS (SELECT 1 FROM user_legal_holds h WHERE h.user_id = l.user_id);
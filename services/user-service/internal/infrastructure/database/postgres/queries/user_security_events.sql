@@ -0,0 +1,39 @@
+-- name: ListUserSecurityEvents :many
+SELECT * FROM user_security_events
+WHERE user_id = $1 AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_items);
+
+-- name: ListAllUserSecurityEvents :many
+SELECT * FROM user_security_events
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: UserAgentSecurityEventExists :one
+SELECT EXISTS (
+  SELECT 1 FROM user_security_events
+  WHERE user_id = $1 AND kind = $2 AND user_agent = $3
+);
+
+-- name: InsertUserSecurityEvent :one
+INSERT INTO user_security_events (
+  user_id,
+  kind,
+  ip,
+  user_agent,
+  country,
+  city,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id;
+
+-- name: CountExpiredUserSecurityEvents :one
+SELECT COUNT(*) FROM user_security_events e
+WHERE e.created_at < sqlc.arg(created_before)
+  AND NOT EXISTS (SELECT 1 FROM user_legal_holds h WHERE h.user_id = e.user_id);
+
+-- name: DeleteExpiredUserSecurityEvents :execresult
+DELETE FROM user_security_events e
+WHERE e.created_at < sqlc.arg(created_before)
+  AND NOT EXISTS (SELECT 1 FROM user_legal_holds h WHERE h.user_id = e.user_id);
@@ -0,0 +1,27 @@
+-- name: ListUserTagsByUserIDs :many
+SELECT * FROM user_tags
+WHERE user_id = ANY(sqlc.arg(user_ids)::uuid[])
+ORDER BY user_id, tag;
+
+-- name: UpsertUserTag :exec
+-- an admin tag stays one when a rule sets it too
+INSERT INTO user_tags (
+  user_id,
+  tag,
+  source,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $4
+) ON CONFLICT (user_id, tag) DO UPDATE
+SET
+  source = CASE WHEN user_tags.source = 'admin' THEN 'admin' ELSE EXCLUDED.source END,
+  updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteUserTag :execresult
+DELETE FROM user_tags
+WHERE user_id = $1 AND tag = $2;
+
+-- name: DeleteStaleRuleUserTags :execresult
+DELETE FROM user_tags
+WHERE tag = $1 AND source = 'rule' AND updated_at < sqlc.arg(before);
@@ -1,16 +1,79 @@
 -- name: InsertUser :one
 INSERT INTO users (
+  id,
   first_name,
   last_name,
   email,
   phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
   password,
   created_at,
   updated_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
+-- name: CopyUser :exec
+INSERT INTO users (
+  id,
+  first_name,
+  last_name,
+  email,
+  phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
+  password,
+  created_at,
+  updated_at,
+  version
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: SyncUser :execresult
+-- copies a user, replacing an older version of it
+INSERT INTO users (
+  id,
+  first_name,
+  last_name,
+  email,
+  phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
+  password,
+  created_at,
+  updated_at,
+  version
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) ON CONFLICT (id) DO UPDATE SET
+  first_name = EXCLUDED.first_name,
+  last_name = EXCLUDED.last_name,
+  email = EXCLUDED.email,
+  phone = EXCLUDED.phone,
+  phone_ciphertext = EXCLUDED.phone_ciphertext,
+  phone_hash = EXCLUDED.phone_hash,
+  phone_key_version = EXCLUDED.phone_key_version,
+  password = EXCLUDED.password,
+  created_at = EXCLUDED.created_at,
+  updated_at = EXCLUDED.updated_at,
+  version = EXCLUDED.version
+WHERE users.version < EXCLUDED.version;
+
+-- name: CountUsersByPhoneKeyVersion :many
+-- uses idx_users_phone_key_version
+SELECT phone_key_version, COUNT(*) AS users FROM users
+WHERE phone_ciphertext IS NOT NULL
+GROUP BY phone_key_version;
+
+-- name: DeleteUser :execresult
+DELETE FROM users
+WHERE id = $1;
+
 -- name: UpdateUser :execresult
 UPDATE users
 SET
@@ -18,8 +81,42 @@ SET
   last_name = $3,
   email = $4,
   phone = $5,
-  updated_at = $6
-WHERE id = $1;
+  phone_ciphertext = $6,
+  phone_hash = $7,
+  phone_key_version = $8,
+  updated_at = $9,
+  version = version + 1
+WHERE id = $1 AND version = sqlc.arg(expected_version);
+
+-- name: ListUsersWithPhoneKeyVersionOtherThan :many
+-- uses idx_users_phone_key_version
+SELECT * FROM users
+WHERE phone_ciphertext IS NOT NULL
+  AND phone_key_version IS DISTINCT FROM sqlc.arg(key_version)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: NormalizeUserEmail :execresult
+-- lower-cases the email, unless a write changed it since it was read
+UPDATE users
+SET
+  email = sqlc.arg(normalized_email),
+  version = version + 1
+WHERE id = $1 AND email = sqlc.arg(email);
+
+-- name: RewriteUserPhone :execresult
+-- stores the phone number in another form without changing it, unless a
+-- write changed it since it was read
+UPDATE users
+SET
+  phone = $2,
+  phone_ciphertext = $3,
+  phone_hash = $4,
+  phone_key_version = $5
+WHERE id = $1
+  AND phone IS NOT DISTINCT FROM sqlc.arg(old_phone)
+  AND phone_ciphertext IS NOT DISTINCT FROM sqlc.arg(old_phone_ciphertext);
 
 -- name: UpdateUserPassword :execresult
 UPDATE users
@@ -36,6 +133,23 @@ WHERE id = $1;
 SELECT * FROM users
 WHERE email = $1;
 
+-- name: EmailKeyExists :one
+-- uses idx_users_email_key
+SELECT EXISTS (
+  SELECT 1 FROM users
+  WHERE regexp_replace(email, '\+[^@]*@', '@') = sqlc.arg(email_key)
+);
+
 -- name: GetPublicProfileByIds :many
 SELECT id, first_name, last_name FROM users
-WHERE id = ANY(sqlc.arg(user_ids)::string[]);
+WHERE id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: GetUsersByIds :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: ListUsersAfter :many
+SELECT * FROM users
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
@@ -0,0 +1,87 @@
+-- name: InsertWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  id,
+  owner_id,
+  owner_service,
+  url,
+  secret,
+  event_types,
+  active,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: ListWebhookSubscriptionsByOwner :many
+SELECT * FROM webhook_subscriptions
+WHERE owner_id = sqlc.narg(owner_id) OR owner_service = sqlc.narg(owner_service)
+ORDER BY created_at DESC;
+
+-- name: ListActiveWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE active = TRUE
+  AND (sqlc.arg(event_type)::text = ANY(event_types) OR '*' = ANY(event_types))
+  AND (owner_service IS NOT NULL OR owner_id = sqlc.narg(subject)::uuid);
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: DeleteWebhookSubscription :execresult
+DELETE FROM webhook_subscriptions
+WHERE id = $1 AND (owner_id = sqlc.narg(owner_id) OR owner_service = sqlc.narg(owner_service));
+
+-- name: InsertWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+  id,
+  subscription_id,
+  event_id,
+  event_type,
+  payload,
+  status,
+  attempts,
+  next_attempt_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+);
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+  SELECT d.id FROM webhook_deliveries d
+  WHERE d.status = 'pending' AND d.next_attempt_at <= NOW()
+  ORDER BY d.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET
+  status = $2,
+  attempts = $3,
+  last_status_code = $4,
+  last_error = $5,
+  next_attempt_at = $6,
+  updated_at = $7
+WHERE id = $1;
+
+-- name: DeleteFinishedWebhookDeliveries :execresult
+DELETE FROM webhook_deliveries
+WHERE status IN ('succeeded', 'dead')
+  AND updated_at < sqlc.arg(finished_before);
+
+-- name: ListWebhookDeliveriesBySubscription :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
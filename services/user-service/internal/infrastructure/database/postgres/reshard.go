@@ -0,0 +1,435 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+// ReshardStats counts the users examined and the users moved, or that would
+// move in a dry run.
+type ReshardStats struct {
+	Scanned int
+	Moved   int
+}
+
+// Reshard moves users from the first from shards of router to the shard the
+// full shard list assigns them, together with their notification
+// preferences, tags, consent records, login locations, security events,
+// backup codes and legal hold. Jump hashing only ever moves users onto the added shards.
+// Each user is copied before it is deleted from its old shard, so an
+// interrupted run can be repeated; writes should be paused meanwhile.
+func Reshard(ctx context.Context, router *ShardRouter, from int, batchSize int32, dryRun bool) (ReshardStats, error) {
+	var stats ReshardStats
+
+	shards := router.Shards()
+	if from < 1 || from > len(shards) {
+		return stats, fmt.Errorf("from must be between 1 and %d, got %d", len(shards), from)
+	}
+
+	for source := 0; source < from; source++ {
+		src := sqlc.New(shards[source])
+		after := pgtype.UUID{Valid: true}
+		for {
+			users, err := src.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{AfterID: after, BatchSize: batchSize})
+			if err != nil {
+				return stats, fmt.Errorf("failed to list users of shard %d: %w", source, err)
+			}
+			if len(users) == 0 {
+				break
+			}
+
+			for _, user := range users {
+				stats.Scanned++
+				target := shardOf(user.ID, len(shards))
+				if target == source {
+					continue
+				}
+
+				stats.Moved++
+				if dryRun {
+					continue
+				}
+				if err := moveUser(ctx, src, sqlc.New(shards[target]), user); err != nil {
+					return stats, fmt.Errorf("failed to move user %s from shard %d to %d: %w", user.ID.String(), source, target, err)
+				}
+			}
+			after = users[len(users)-1].ID
+		}
+	}
+
+	return stats, nil
+}
+
+func moveUser(ctx context.Context, src, dst *sqlc.Queries, user sqlc.User) error {
+	err := dst.CopyUser(ctx, sqlc.CopyUserParams{
+		ID:              user.ID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Email:           user.Email,
+		Phone:           user.Phone,
+		PhoneCiphertext: user.PhoneCiphertext,
+		PhoneHash:       user.PhoneHash,
+		PhoneKeyVersion: user.PhoneKeyVersion,
+		Password:        user.Password,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		Version:         user.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy user: %w", err)
+	}
+
+	prefs, err := src.GetNotificationPreferencesByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read notification preferences: %w", err)
+	}
+	for _, pref := range prefs {
+		err := dst.UpsertNotificationPreference(ctx, sqlc.UpsertNotificationPreferenceParams{
+			UserID:    pref.UserID,
+			Channel:   pref.Channel,
+			Category:  pref.Category,
+			Enabled:   pref.Enabled,
+			UpdatedAt: pref.UpdatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy notification preferences: %w", err)
+		}
+	}
+
+	tags, err := src.ListUserTagsByUserIDs(ctx, []string{user.ID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to read user tags: %w", err)
+	}
+	for _, tag := range tags {
+		err := dst.UpsertUserTag(ctx, sqlc.UpsertUserTagParams{
+			UserID:    tag.UserID,
+			Tag:       tag.Tag,
+			Source:    tag.Source,
+			CreatedAt: tag.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy user tags: %w", err)
+		}
+	}
+
+	// consent records are history rather than upserts, so they are copied
+	// only if an interrupted run did not copy them already
+	copied, err := dst.ListUserConsentRecords(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read copied consent records: %w", err)
+	}
+	if len(copied) == 0 {
+		consents, err := src.ListUserConsentRecords(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read consent records: %w", err)
+		}
+		for _, consent := range consents {
+			err := dst.InsertUserConsent(ctx, sqlc.InsertUserConsentParams{
+				UserID:        consent.UserID,
+				Purpose:       consent.Purpose,
+				Granted:       consent.Granted,
+				PolicyVersion: consent.PolicyVersion,
+				CreatedAt:     consent.CreatedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to copy consent records: %w", err)
+			}
+		}
+	}
+
+	locations, err := src.ListUserLoginLocations(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read login locations: %w", err)
+	}
+	for _, location := range locations {
+		err := dst.UpsertUserLoginLocation(ctx, sqlc.UpsertUserLoginLocationParams{
+			UserID:      location.UserID,
+			Country:     location.Country,
+			City:        location.City,
+			LastIp:      location.LastIp,
+			FirstSeenAt: location.FirstSeenAt,
+			LastSeenAt:  location.LastSeenAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy login locations: %w", err)
+		}
+	}
+
+	// like consent records, security events are copied only once; the
+	// destination gives them new IDs in the same order
+	copiedEvents, err := dst.ListAllUserSecurityEvents(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read copied security events: %w", err)
+	}
+	if len(copiedEvents) == 0 {
+		events, err := src.ListAllUserSecurityEvents(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read security events: %w", err)
+		}
+		for _, event := range events {
+			_, err := dst.InsertUserSecurityEvent(ctx, sqlc.InsertUserSecurityEventParams{
+				UserID:    event.UserID,
+				Kind:      event.Kind,
+				Ip:        event.Ip,
+				UserAgent: event.UserAgent,
+				Country:   event.Country,
+				City:      event.City,
+				CreatedAt: event.CreatedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to copy security events: %w", err)
+			}
+		}
+	}
+
+	// backup codes have no key to upsert on either
+	copiedCodes, err := dst.ListUserBackupCodes(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read copied backup codes: %w", err)
+	}
+	if len(copiedCodes) == 0 {
+		codes, err := src.ListUserBackupCodes(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read backup codes: %w", err)
+		}
+		for _, code := range codes {
+			err := dst.CopyUserBackupCode(ctx, sqlc.CopyUserBackupCodeParams{
+				UserID:    code.UserID,
+				CodeHash:  code.CodeHash,
+				UsedAt:    code.UsedAt,
+				CreatedAt: code.CreatedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to copy backup codes: %w", err)
+			}
+		}
+	}
+
+	hold, err := src.GetUserLegalHold(ctx, user.ID)
+	switch {
+	case err == nil:
+		_, err := dst.UpsertUserLegalHold(ctx, sqlc.UpsertUserLegalHoldParams{
+			UserID:    hold.UserID,
+			Reason:    hold.Reason,
+			CreatedAt: hold.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy legal hold: %w", err)
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("failed to read legal hold: %w", err)
+	}
+
+	// preferences, tags, consents, login locations, security events, backup
+	// codes and legal holds follow through ON DELETE CASCADE
+	if _, err := src.DeleteUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// BackfillUserDirectory adds the directory entry of every user on every
+// shard, e.g. when sharding is first enabled on an existing database.
+// Existing entries are kept. It returns the number of users examined.
+func BackfillUserDirectory(ctx context.Context, router *ShardRouter, batchSize int32) (int, error) {
+	directory := sqlc.New(router.Shards()[0])
+
+	scanned := 0
+	for index, db := range router.Shards() {
+		queries := sqlc.New(db)
+		after := pgtype.UUID{Valid: true}
+		for {
+			users, err := queries.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{AfterID: after, BatchSize: batchSize})
+			if err != nil {
+				return scanned, fmt.Errorf("failed to list users of shard %d: %w", index, err)
+			}
+			if len(users) == 0 {
+				break
+			}
+
+			for _, user := range users {
+				scanned++
+				err := directory.BackfillUserDirectoryEntry(ctx, sqlc.BackfillUserDirectoryEntryParams{
+					Email:  user.Email,
+					UserID: user.ID,
+				})
+				if err != nil {
+					return scanned, fmt.Errorf("failed to add directory entry of user %s: %w", user.ID.String(), err)
+				}
+			}
+			after = users[len(users)-1].ID
+		}
+	}
+
+	return scanned, nil
+}
+
+// BackfillStats counts the users examined and those copied to the target,
+// or whose copy there was replaced by a newer version.
+type BackfillStats struct {
+	Scanned int
+	Copied  int
+}
+
+// BackfillDualWriteTarget copies every user of source to the shard of target
+// the user belongs on, with its directory entry, for a dual-write migration:
+// only writes are mirrored, so users created before dual writes were enabled
+// are missing on the target until copied. A user already on the target is
+// replaced only if its version there is older, so the run can be repeated
+// while writes are mirrored, e.g. to fix the users reported as dual write
+// mismatches. Only the users table is copied.
+func BackfillDualWriteTarget(ctx context.Context, source, target *ShardRouter, batchSize int32) (BackfillStats, error) {
+	var stats BackfillStats
+
+	shards := target.Shards()
+	directory := sqlc.New(shards[0])
+	err := forEachUser(ctx, source, batchSize, func(_ int, user sqlc.User) error {
+		stats.Scanned++
+
+		tag, err := sqlc.New(shards[shardOf(user.ID, len(shards))]).SyncUser(ctx, sqlc.SyncUserParams{
+			ID:              user.ID,
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Email:           user.Email,
+			Phone:           user.Phone,
+			PhoneCiphertext: user.PhoneCiphertext,
+			PhoneHash:       user.PhoneHash,
+			PhoneKeyVersion: user.PhoneKeyVersion,
+			Password:        user.Password,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+			Version:         user.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy user %s: %w", user.ID.String(), err)
+		}
+		stats.Copied += int(tag.RowsAffected())
+
+		err = directory.BackfillUserDirectoryEntry(ctx, sqlc.BackfillUserDirectoryEntryParams{
+			Email:  user.Email,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add directory entry of user %s: %w", user.ID.String(), err)
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+// NormalizeEmailsStats counts the users examined, the users whose email was
+// lower-cased, or would be in a dry run, and those left as they are because
+// another user's email differs from theirs only in case.
+type NormalizeEmailsStats struct {
+	Scanned    int
+	Normalized int
+	Clashing   int
+}
+
+// NormalizeEmails lower-cases the emails of the users on every shard of
+// router, and their directory entries, as valueobject.NewEmail does for new
+// users. It replaces a migration because a migration only sees its own
+// database, while two users whose emails differ only in case may live on
+// different shards; such users are left as they are, to be merged by hand.
+// Run it before the first reshard and after each one; it can be repeated.
+func NormalizeEmails(ctx context.Context, router *ShardRouter, batchSize int32, dryRun bool) (NormalizeEmailsStats, error) {
+	var stats NormalizeEmailsStats
+
+	type candidate struct {
+		shard int
+		user  sqlc.User
+	}
+	var candidates []candidate
+	owners := make(map[string]int)
+
+	// the first pass finds the mixed-case emails, the second counts every
+	// user who has one of them lower-cased
+	err := forEachUser(ctx, router, batchSize, func(shard int, user sqlc.User) error {
+		stats.Scanned++
+		if normalized := strings.ToLower(user.Email); normalized != user.Email {
+			candidates = append(candidates, candidate{shard: shard, user: user})
+			owners[normalized] = 0
+		}
+		return nil
+	})
+	if err != nil || len(candidates) == 0 {
+		return stats, err
+	}
+	err = forEachUser(ctx, router, batchSize, func(_ int, user sqlc.User) error {
+		if count, ok := owners[strings.ToLower(user.Email)]; ok {
+			owners[strings.ToLower(user.Email)] = count + 1
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	directory := sqlc.New(router.Shards()[0])
+	for _, c := range candidates {
+		normalized := strings.ToLower(c.user.Email)
+		if owners[normalized] > 1 {
+			stats.Clashing++
+			continue
+		}
+
+		stats.Normalized++
+		if dryRun {
+			continue
+		}
+		_, err := sqlc.New(router.Shards()[c.shard]).NormalizeUserEmail(ctx, sqlc.NormalizeUserEmailParams{
+			ID:              c.user.ID,
+			NormalizedEmail: normalized,
+			Email:           c.user.Email,
+		})
+		if err != nil {
+			return stats, fmt.Errorf("failed to normalize email of user %s on shard %d: %w", c.user.ID.String(), c.shard, err)
+		}
+		err = directory.NormalizeUserDirectoryEmail(ctx, sqlc.NormalizeUserDirectoryEmailParams{
+			NormalizedEmail: normalized,
+			Email:           c.user.Email,
+			UserID:          c.user.ID,
+		})
+		if err != nil {
+			return stats, fmt.Errorf("failed to normalize directory entry of user %s: %w", c.user.ID.String(), err)
+		}
+	}
+
+	return stats, nil
+}
+
+// forEachUser calls fn with every user of every shard of router and the
+// index of the user's shard.
+func forEachUser(ctx context.Context, router *ShardRouter, batchSize int32, fn func(shard int, user sqlc.User) error) error {
+	for index, db := range router.Shards() {
+		queries := sqlc.New(db)
+		after := pgtype.UUID{Valid: true}
+		for {
+			users, err := queries.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{AfterID: after, BatchSize: batchSize})
+			if err != nil {
+				return fmt.Errorf("failed to list users of shard %d: %w", index, err)
+			}
+			if len(users) == 0 {
+				break
+			}
+
+			for _, user := range users {
+				if err := fn(index, user); err != nil {
+					return err
+				}
+			}
+			after = users[len(users)-1].ID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/migrations"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/testutil"
+)
+
+// startSecondShard creates and migrates another database next to pool's.
+func startSecondShard(t *testing.T, pool *pgxpool.Pool) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := pool.Exec(ctx, "CREATE DATABASE user_shard_1"); err != nil {
+		t.Fatalf("failed to create shard database: %v", err)
+	}
+	cfg := pool.Config().Copy()
+	cfg.ConnConfig.Database = "user_shard_1"
+	shard, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to shard database: %v", err)
+	}
+	t.Cleanup(shard.Close)
+
+	if err := migrations.Up(ctx, shard); err != nil {
+		t.Fatalf("failed to migrate shard database: %v", err)
+	}
+
+	return shard
+}
+
+func TestNormalizeEmails(t *testing.T) {
+	primary := testutil.StartPostgres(t)
+	shard := startSecondShard(t, primary)
+	router := postgres.NewShardRouter([]sqlc.DBTX{primary, shard})
+	ctx := context.Background()
+
+	insert := func(t *testing.T, db sqlc.DBTX, email string) {
+		t.Helper()
+
+		var id string
+		err := db.QueryRow(ctx, `INSERT INTO users (first_name, last_name, email, password) VALUES ('John', 'Doe', $1, 'x') RETURNING id::text`, email).Scan(&id)
+		if err != nil {
+			t.Fatalf("failed to insert user %s: %v", email, err)
+		}
+		if _, err := primary.Exec(ctx, `INSERT INTO user_directory (email, user_id) VALUES ($1, $2)`, email, id); err != nil {
+			t.Fatalf("failed to insert directory entry of %s: %v", email, err)
+		}
+	}
+	emails := func(t *testing.T, db sqlc.DBTX, table string) map[string]bool {
+		t.Helper()
+
+		rows, err := db.Query(ctx, "SELECT email FROM "+table)
+		if err != nil {
+			t.Fatalf("failed to list emails of %s: %v", table, err)
+		}
+		defer rows.Close()
+
+		got := map[string]bool{}
+		for rows.Next() {
+			var email string
+			if err := rows.Scan(&email); err != nil {
+				t.Fatalf("failed to scan email: %v", err)
+			}
+			got[email] = true
+		}
+		return got
+	}
+
+	insert(t, primary, "Lan@Example.com")
+	insert(t, primary, "minh@example.com")
+	// the same address in another case on another shard
+	insert(t, primary, "Hoa@Example.com")
+	insert(t, shard, "hoa@example.com")
+
+	stats, err := postgres.NormalizeEmails(ctx, router, 1, true)
+	if err != nil {
+		t.Fatalf("NormalizeEmails dry run: %v", err)
+	}
+	if stats != (postgres.NormalizeEmailsStats{Scanned: 4, Normalized: 1, Clashing: 1}) {
+		t.Errorf("NormalizeEmails dry run = %+v, want 4 scanned, 1 normalized and 1 clashing", stats)
+	}
+	if !emails(t, primary, "users")["Lan@Example.com"] {
+		t.Fatal("dry run lower-cased an email")
+	}
+
+	if _, err := postgres.NormalizeEmails(ctx, router, 1, false); err != nil {
+		t.Fatalf("NormalizeEmails: %v", err)
+	}
+
+	users := emails(t, primary, "users")
+	if !users["lan@example.com"] || users["Lan@Example.com"] {
+		t.Errorf("users of the primary = %v, want Lan@Example.com lower-cased", users)
+	}
+	if !users["Hoa@Example.com"] {
+		t.Errorf("users of the primary = %v, want Hoa@Example.com kept for clashing across shards", users)
+	}
+	directory := emails(t, primary, "user_directory")
+	if !directory["lan@example.com"] || !directory["Hoa@Example.com"] || !directory["hoa@example.com"] {
+		t.Errorf("directory = %v, want lan@example.com lower-cased and both hoa entries kept", directory)
+	}
+
+	// a repeated run finds nothing left to do
+	stats, err = postgres.NormalizeEmails(ctx, router, 1, false)
+	if err != nil {
+		t.Fatalf("repeated NormalizeEmails: %v", err)
+	}
+	if stats.Normalized != 0 {
+		t.Errorf("repeated NormalizeEmails normalized %d emails, want 0", stats.Normalized)
+	}
+}
+
+func TestBackfillDualWriteTarget(t *testing.T) {
+	source := testutil.StartPostgres(t)
+	target := startSecondShard(t, source)
+	ctx := context.Background()
+
+	insert := func(t *testing.T, db sqlc.DBTX, id, firstName string, version int) {
+		t.Helper()
+
+		_, err := db.Exec(ctx, `INSERT INTO users (id, first_name, last_name, email, password, version) VALUES ($1, $2, 'Doe', $3, 'x', $4)`, id, firstName, id+"@example.com", version)
+		if err != nil {
+			t.Fatalf("failed to insert user %s: %v", id, err)
+		}
+	}
+	firstName := func(t *testing.T, id string) string {
+		t.Helper()
+
+		var name string
+		if err := target.QueryRow(ctx, `SELECT first_name FROM users WHERE id = $1`, id).Scan(&name); err != nil {
+			t.Fatalf("failed to read user %s on the target: %v", id, err)
+		}
+		return name
+	}
+
+	const (
+		missing  = "00000000-0000-0000-0000-000000000001"
+		outdated = "00000000-0000-0000-0000-000000000002"
+		newer    = "00000000-0000-0000-0000-000000000003"
+	)
+	insert(t, source, missing, "Lan", 1)
+	insert(t, source, outdated, "Minh", 3)
+	insert(t, target, outdated, "Old", 2)
+	// written on the target after the source row was read
+	insert(t, source, newer, "Hoa", 1)
+	insert(t, target, newer, "Hoa Mai", 2)
+
+	run := func(t *testing.T) postgres.BackfillStats {
+		t.Helper()
+
+		stats, err := postgres.BackfillDualWriteTarget(ctx,
+			postgres.NewShardRouter([]sqlc.DBTX{source}),
+			postgres.NewShardRouter([]sqlc.DBTX{target}),
+			1,
+		)
+		if err != nil {
+			t.Fatalf("BackfillDualWriteTarget: %v", err)
+		}
+		return stats
+	}
+
+	if stats := run(t); stats != (postgres.BackfillStats{Scanned: 3, Copied: 2}) {
+		t.Errorf("BackfillDualWriteTarget = %+v, want 3 scanned and 2 copied", stats)
+	}
+	for id, want := range map[string]string{missing: "Lan", outdated: "Minh", newer: "Hoa Mai"} {
+		if got := firstName(t, id); got != want {
+			t.Errorf("user %s on the target is %q, want %q", id, got, want)
+		}
+	}
+	var entries int
+	if err := target.QueryRow(ctx, `SELECT COUNT(*) FROM user_directory`).Scan(&entries); err != nil || entries != 3 {
+		t.Errorf("target directory has %d entries (%v), want 3", entries, err)
+	}
+
+	// a repeated run finds nothing left to copy
+	if stats := run(t); stats.Copied != 0 {
+		t.Errorf("repeated BackfillDualWriteTarget copied %d users, want 0", stats.Copied)
+	}
+}
@@ -0,0 +1,492 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+// SCIMRepository keeps provisioned users and groups on the primary database
+// with their SSO connections; the users themselves may live on a shard.
+type SCIMRepository struct {
+	base *sqlc.Queries
+}
+
+func NewSCIMRepository(db sqlc.DBTX) *SCIMRepository {
+	return &SCIMRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *SCIMRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *SCIMRepository) CreateUser(ctx context.Context, user *entity.SCIMUser) error {
+	userID, connectionID, err := scanUserAndConnection(user.UserID, user.ConnectionID)
+	if err != nil {
+		return err
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(user.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	err = r.queries(ctx).InsertScimUser(ctx, sqlc.InsertScimUserParams{
+		UserID:       userID,
+		ConnectionID: connectionID,
+		ExternalID:   user.ExternalID,
+		Active:       user.Active,
+		CreatedAt:    createdAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return domain_error.New(domain_error.ReasonAlreadyExists, domain_error.WithMessage(fmt.Sprintf("user %s is already provisioned", user.UserID)))
+		}
+		return queryError(err, "failed to create SCIM user")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) GetUser(ctx context.Context, userID string) (*entity.SCIMUser, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	user, err := r.queries(ctx).GetScimUser(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s is not provisioned", userID)))
+		}
+		return nil, queryError(err, "failed to get SCIM user")
+	}
+
+	return scimUserFromDatabase(user), nil
+}
+
+func (r *SCIMRepository) GetUserByExternalID(ctx context.Context, connectionID, externalID string) (*entity.SCIMUser, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	user, err := r.queries(ctx).GetScimUserByExternalID(ctx, sqlc.GetScimUserByExternalIDParams{
+		ConnectionID: uuid,
+		ExternalID:   externalID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("no user with external ID %s", externalID)))
+		}
+		return nil, queryError(err, "failed to get SCIM user by external ID")
+	}
+
+	return scimUserFromDatabase(user), nil
+}
+
+func (r *SCIMRepository) ListUsers(ctx context.Context, connectionID string, offset, limit int32) ([]*entity.SCIMUser, int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, 0, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	total, err := r.queries(ctx).CountScimUsers(ctx, uuid)
+	if err != nil {
+		return nil, 0, queryError(err, "failed to count SCIM users")
+	}
+
+	users, err := r.queries(ctx).ListScimUsers(ctx, sqlc.ListScimUsersParams{
+		ConnectionID: uuid,
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		return nil, 0, queryError(err, "failed to list SCIM users")
+	}
+
+	ret := make([]*entity.SCIMUser, 0, len(users))
+	for _, user := range users {
+		ret = append(ret, scimUserFromDatabase(user))
+	}
+
+	return ret, total, nil
+}
+
+func (r *SCIMRepository) ListUsersByIDs(ctx context.Context, connectionID string, ids []string) ([]*entity.SCIMUser, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	users, err := r.queries(ctx).ListScimUsersByIDs(ctx, sqlc.ListScimUsersByIDsParams{
+		ConnectionID: uuid,
+		UserIds:      ids,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to list SCIM users by IDs")
+	}
+
+	ret := make([]*entity.SCIMUser, 0, len(users))
+	for _, user := range users {
+		ret = append(ret, scimUserFromDatabase(user))
+	}
+
+	return ret, nil
+}
+
+func (r *SCIMRepository) UpdateUser(ctx context.Context, user *entity.SCIMUser) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(user.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", user.UserID))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(user.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	result, err := r.queries(ctx).UpdateScimUser(ctx, sqlc.UpdateScimUserParams{
+		UserID:     uuid,
+		ExternalID: user.ExternalID,
+		Active:     user.Active,
+		UpdatedAt:  updatedAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to update SCIM user")
+	}
+	if result.RowsAffected() == 0 {
+		return domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s is not provisioned", user.UserID)))
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) DeleteUser(ctx context.Context, userID string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	if err := r.queries(ctx).DeleteScimUser(ctx, uuid); err != nil {
+		return queryError(err, "failed to delete SCIM user")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) CreateGroup(ctx context.Context, group *entity.SCIMGroup) error {
+	id, connectionID, err := scanGroupAndConnection(group.ID, group.ConnectionID)
+	if err != nil {
+		return err
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(group.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	err = r.queries(ctx).InsertScimGroup(ctx, sqlc.InsertScimGroupParams{
+		ID:           id,
+		ConnectionID: connectionID,
+		DisplayName:  group.DisplayName,
+		ExternalID:   group.ExternalID,
+		CreatedAt:    createdAt,
+		MemberIds:    group.Members,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return groupNameTaken(group.DisplayName)
+		}
+		return queryError(err, "failed to create SCIM group")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) GetGroup(ctx context.Context, connectionID, id string) (*entity.SCIMGroup, error) {
+	groupID, connID, err := scanGroupAndConnection(id, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := r.queries(ctx).GetScimGroup(ctx, sqlc.GetScimGroupParams{
+		ConnectionID: connID,
+		ID:           groupID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("group %s not found", id))
+		}
+		return nil, queryError(err, "failed to get SCIM group")
+	}
+
+	ret, err := r.withMembers(ctx, []sqlc.ScimGroup{group})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SCIMRepository) GetGroupByDisplayName(ctx context.Context, connectionID, displayName string) (*entity.SCIMGroup, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	group, err := r.queries(ctx).GetScimGroupByDisplayName(ctx, sqlc.GetScimGroupByDisplayNameParams{
+		ConnectionID: uuid,
+		DisplayName:  displayName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("no group named %s", displayName))
+		}
+		return nil, queryError(err, "failed to get SCIM group by display name")
+	}
+
+	ret, err := r.withMembers(ctx, []sqlc.ScimGroup{group})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SCIMRepository) GetGroupByExternalID(ctx context.Context, connectionID, externalID string) (*entity.SCIMGroup, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	group, err := r.queries(ctx).GetScimGroupByExternalID(ctx, sqlc.GetScimGroupByExternalIDParams{
+		ConnectionID: uuid,
+		ExternalID:   externalID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("no group with external ID %s", externalID))
+		}
+		return nil, queryError(err, "failed to get SCIM group by external ID")
+	}
+
+	ret, err := r.withMembers(ctx, []sqlc.ScimGroup{group})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SCIMRepository) ListGroups(ctx context.Context, connectionID string, offset, limit int32) ([]*entity.SCIMGroup, int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, 0, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	total, err := r.queries(ctx).CountScimGroups(ctx, uuid)
+	if err != nil {
+		return nil, 0, queryError(err, "failed to count SCIM groups")
+	}
+
+	groups, err := r.queries(ctx).ListScimGroups(ctx, sqlc.ListScimGroupsParams{
+		ConnectionID: uuid,
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		return nil, 0, queryError(err, "failed to list SCIM groups")
+	}
+
+	ret, err := r.withMembers(ctx, groups)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (r *SCIMRepository) UpdateGroup(ctx context.Context, group *entity.SCIMGroup) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(group.ID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid group ID: %s", group.ID))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(group.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	result, err := r.queries(ctx).UpdateScimGroup(ctx, sqlc.UpdateScimGroupParams{
+		ID:          uuid,
+		DisplayName: group.DisplayName,
+		ExternalID:  group.ExternalID,
+		UpdatedAt:   updatedAt,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return groupNameTaken(group.DisplayName)
+		}
+		return queryError(err, "failed to update SCIM group")
+	}
+	if result.RowsAffected() == 0 {
+		return domain_error.NewNotFoundError(fmt.Sprintf("group %s not found", group.ID))
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) AddGroupMembers(ctx context.Context, groupID string, userIDs []string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(groupID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid group ID: %s", groupID))
+	}
+
+	err := r.queries(ctx).InsertScimGroupMembers(ctx, sqlc.InsertScimGroupMembersParams{
+		GroupID: uuid,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return queryError(err, "failed to add SCIM group members")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) RemoveGroupMembers(ctx context.Context, groupID string, userIDs []string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(groupID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid group ID: %s", groupID))
+	}
+
+	err := r.queries(ctx).DeleteScimGroupMembers(ctx, sqlc.DeleteScimGroupMembersParams{
+		GroupID: uuid,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return queryError(err, "failed to remove SCIM group members")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) ReplaceGroupMembers(ctx context.Context, groupID string, userIDs []string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(groupID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid group ID: %s", groupID))
+	}
+
+	err := r.queries(ctx).ReplaceScimGroupMembers(ctx, sqlc.ReplaceScimGroupMembersParams{
+		GroupID: uuid,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return queryError(err, "failed to replace SCIM group members")
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) DeleteGroup(ctx context.Context, connectionID, id string) (bool, error) {
+	groupID, connID, err := scanGroupAndConnection(id, connectionID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.queries(ctx).DeleteScimGroup(ctx, sqlc.DeleteScimGroupParams{
+		ConnectionID: connID,
+		ID:           groupID,
+	})
+	if err != nil {
+		return false, queryError(err, "failed to delete SCIM group")
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// withMembers loads the members of groups in one query.
+func (r *SCIMRepository) withMembers(ctx context.Context, groups []sqlc.ScimGroup) ([]*entity.SCIMGroup, error) {
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID.String())
+	}
+
+	members, err := r.queries(ctx).ListScimGroupMembers(ctx, ids)
+	if err != nil {
+		return nil, queryError(err, "failed to list SCIM group members")
+	}
+	byGroup := make(map[string][]string, len(groups))
+	for _, member := range members {
+		key := member.GroupID.String()
+		byGroup[key] = append(byGroup[key], member.UserID.String())
+	}
+
+	ret := make([]*entity.SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		ret = append(ret, &entity.SCIMGroup{
+			ID:           group.ID.String(),
+			ConnectionID: group.ConnectionID.String(),
+			DisplayName:  group.DisplayName,
+			ExternalID:   group.ExternalID,
+			Members:      byGroup[group.ID.String()],
+			CreatedAt:    valueobject.NewTime(group.CreatedAt.Time.Unix()),
+			UpdatedAt:    valueobject.NewTime(group.UpdatedAt.Time.Unix()),
+		})
+	}
+
+	return ret, nil
+}
+
+func scimUserFromDatabase(user sqlc.ScimUser) *entity.SCIMUser {
+	return &entity.SCIMUser{
+		UserID:       user.UserID.String(),
+		ConnectionID: user.ConnectionID.String(),
+		ExternalID:   user.ExternalID,
+		Active:       user.Active,
+		CreatedAt:    valueobject.NewTime(user.CreatedAt.Time.Unix()),
+		UpdatedAt:    valueobject.NewTime(user.UpdatedAt.Time.Unix()),
+	}
+}
+
+func scanUserAndConnection(userID, connectionID string) (pgtype.UUID, pgtype.UUID, error) {
+	user, conn := pgtype.UUID{}, pgtype.UUID{}
+	if err := user.Scan(userID); err != nil {
+		return user, conn, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+	if err := conn.Scan(connectionID); err != nil {
+		return user, conn, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	return user, conn, nil
+}
+
+func scanGroupAndConnection(groupID, connectionID string) (pgtype.UUID, pgtype.UUID, error) {
+	group, conn := pgtype.UUID{}, pgtype.UUID{}
+	if err := group.Scan(groupID); err != nil {
+		return group, conn, domain_error.NewInvalidData(fmt.Sprintf("invalid group ID: %s", groupID))
+	}
+	if err := conn.Scan(connectionID); err != nil {
+		return group, conn, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	return group, conn, nil
+}
+
+func groupNameTaken(displayName string) error {
+	return domain_error.New(
+		domain_error.ReasonAlreadyExists,
+		domain_error.WithMessage(fmt.Sprintf("a group named %s already exists", displayName)),
+		domain_error.WithFieldViolation("displayName", "another group has this display name"),
+	)
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type SecurityEventRepository struct {
+	base *sqlc.Queries
+}
+
+func NewSecurityEventRepository(db sqlc.DBTX) *SecurityEventRepository {
+	return &SecurityEventRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *SecurityEventRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *SecurityEventRepository) ListSecurityEvents(ctx context.Context, userID string, beforeID int64, limit int32) ([]*entity.SecurityEvent, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	events, err := r.queries(ctx).ListUserSecurityEvents(ctx, sqlc.ListUserSecurityEventsParams{
+		UserID:   uuid,
+		BeforeID: beforeID,
+		MaxItems: limit,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to list security events")
+	}
+
+	ret := make([]*entity.SecurityEvent, 0, len(events))
+	for _, event := range events {
+		ret = append(ret, entity.SecurityEventFromDatabase(
+			event.ID,
+			event.UserID.String(),
+			event.Kind,
+			event.Ip,
+			event.UserAgent,
+			event.Country,
+			event.City,
+			event.CreatedAt.Time.Unix(),
+		))
+	}
+
+	return ret, nil
+}
+
+func (r *SecurityEventRepository) HasUserAgent(ctx context.Context, userID string, kind entity.SecurityEventKind, userAgent string) (bool, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return false, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	exists, err := r.queries(ctx).UserAgentSecurityEventExists(ctx, sqlc.UserAgentSecurityEventExistsParams{
+		UserID:    uuid,
+		Kind:      string(kind),
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return false, queryError(err, "failed to check security events")
+	}
+
+	return exists, nil
+}
+
+func (r *SecurityEventRepository) SaveSecurityEvent(ctx context.Context, event *entity.SecurityEvent) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(event.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", event.UserID))
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(event.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	var location entity.Location
+	if event.Location != nil {
+		location = *event.Location
+	}
+
+	id, err := r.queries(ctx).InsertUserSecurityEvent(ctx, sqlc.InsertUserSecurityEventParams{
+		UserID:    uuid,
+		Kind:      string(event.Kind),
+		Ip:        event.IP,
+		UserAgent: event.UserAgent,
+		Country:   location.Country,
+		City:      location.City,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save security event")
+	}
+	event.ID = id
+
+	return nil
+}
+
+func (r *SecurityEventRepository) CountExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.queries(ctx).CountExpiredUserSecurityEvents(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, queryError(err, "failed to count expired security events")
+	}
+
+	return n, nil
+}
+
+func (r *SecurityEventRepository) DeleteExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error) {
+	ret, err := r.queries(ctx).DeleteExpiredUserSecurityEvents(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, queryError(err, "failed to delete expired security events")
+	}
+
+	return ret.RowsAffected(), nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/shard"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+// ShardRouter places each user, with the rows keyed by their ID, on one of
+// several databases by a hash of the user ID. Shard 0 is the primary
+// database, which also holds the email directory and every unsharded table.
+//
+// The shard list is append-only: removing or reordering shards relocates
+// users, and adding one requires moving users with Reshard first.
+type ShardRouter struct {
+	shards []sqlc.DBTX
+}
+
+func NewShardRouter(shards []sqlc.DBTX) *ShardRouter {
+	return &ShardRouter{shards: shards}
+}
+
+// Shards returns the databases in shard order.
+func (r *ShardRouter) Shards() []sqlc.DBTX {
+	return r.shards
+}
+
+// ForUser returns the shard index of the user with the given ID.
+func (r *ShardRouter) ForUser(id string) (int, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", id))
+	}
+
+	return shardOf(uuid, len(r.shards)), nil
+}
+
+// shardOf hashes the canonical form of id, so IDs differing only in case
+// land on the same shard.
+func shardOf(id pgtype.UUID, n int) int {
+	return shard.Index(id.String(), n)
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+)
+
+// ShardedBackupCodeRepository keeps a user's backup codes on the user's
+// shard.
+type ShardedBackupCodeRepository struct {
+	router *ShardRouter
+	shards []*BackupCodeRepository
+}
+
+func NewShardedBackupCodeRepository(router *ShardRouter) *ShardedBackupCodeRepository {
+	shards := make([]*BackupCodeRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewBackupCodeRepository(db))
+	}
+
+	return &ShardedBackupCodeRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedBackupCodeRepository) shardFor(userID string) (*BackupCodeRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedBackupCodeRepository) ReplaceBackupCodes(ctx context.Context, userID string, codeHashes []string) error {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return err
+	}
+
+	return shard.ReplaceBackupCodes(ctx, userID, codeHashes)
+}
+
+func (r *ShardedBackupCodeRepository) ConsumeBackupCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return false, err
+	}
+
+	return shard.ConsumeBackupCode(ctx, userID, codeHash)
+}
+
+func (r *ShardedBackupCodeRepository) CountUnusedBackupCodes(ctx context.Context, userID string) (int, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.CountUnusedBackupCodes(ctx, userID)
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedConsentRepository keeps a user's consents on the user's shard,
+// next to the row they reference.
+type ShardedConsentRepository struct {
+	router *ShardRouter
+	shards []*ConsentRepository
+}
+
+func NewShardedConsentRepository(router *ShardRouter) *ShardedConsentRepository {
+	shards := make([]*ConsentRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewConsentRepository(db))
+	}
+
+	return &ShardedConsentRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedConsentRepository) shardFor(userID string) (*ConsentRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedConsentRepository) ListConsents(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.ListConsents(ctx, userID)
+}
+
+func (r *ShardedConsentRepository) ListConsentRecords(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.ListConsentRecords(ctx, userID)
+}
+
+func (r *ShardedConsentRepository) SaveConsent(ctx context.Context, consent *entity.Consent) error {
+	shard, err := r.shardFor(consent.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.SaveConsent(ctx, consent)
+}
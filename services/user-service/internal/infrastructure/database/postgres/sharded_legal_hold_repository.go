@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedLegalHoldRepository keeps a user's legal hold on the user's shard,
+// next to the data it protects.
+type ShardedLegalHoldRepository struct {
+	router *ShardRouter
+	shards []*LegalHoldRepository
+}
+
+func NewShardedLegalHoldRepository(router *ShardRouter) *ShardedLegalHoldRepository {
+	shards := make([]*LegalHoldRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewLegalHoldRepository(db))
+	}
+
+	return &ShardedLegalHoldRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedLegalHoldRepository) shardFor(userID string) (*LegalHoldRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedLegalHoldRepository) GetLegalHold(ctx context.Context, userID string) (*entity.LegalHold, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.GetLegalHold(ctx, userID)
+}
+
+func (r *ShardedLegalHoldRepository) SaveLegalHold(ctx context.Context, hold *entity.LegalHold) error {
+	shard, err := r.shardFor(hold.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.SaveLegalHold(ctx, hold)
+}
+
+func (r *ShardedLegalHoldRepository) DeleteLegalHold(ctx context.Context, userID string) (int64, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.DeleteLegalHold(ctx, userID)
+}
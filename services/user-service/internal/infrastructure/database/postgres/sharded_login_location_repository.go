@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedLoginLocationRepository keeps a user's login locations on the
+// user's shard.
+type ShardedLoginLocationRepository struct {
+	router *ShardRouter
+	shards []*LoginLocationRepository
+}
+
+func NewShardedLoginLocationRepository(router *ShardRouter) *ShardedLoginLocationRepository {
+	shards := make([]*LoginLocationRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewLoginLocationRepository(db))
+	}
+
+	return &ShardedLoginLocationRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedLoginLocationRepository) shardFor(userID string) (*LoginLocationRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedLoginLocationRepository) ListLoginLocations(ctx context.Context, userID string) ([]*entity.LoginLocation, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.ListLoginLocations(ctx, userID)
+}
+
+func (r *ShardedLoginLocationRepository) SaveLoginLocation(ctx context.Context, location *entity.LoginLocation) error {
+	shard, err := r.shardFor(location.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.SaveLoginLocation(ctx, location)
+}
+
+func (r *ShardedLoginLocationRepository) CountExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error) {
+	var counted int64
+	for _, shard := range r.shards {
+		n, err := shard.CountExpiredLoginLocations(ctx, before)
+		if err != nil {
+			return counted, err
+		}
+		counted += n
+	}
+
+	return counted, nil
+}
+
+func (r *ShardedLoginLocationRepository) DeleteExpiredLoginLocations(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for _, shard := range r.shards {
+		n, err := shard.DeleteExpiredLoginLocations(ctx, before)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	return deleted, nil
+}
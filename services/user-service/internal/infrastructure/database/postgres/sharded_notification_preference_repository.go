@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedNotificationPreferenceRepository keeps a user's notification
+// preferences on the user's shard, next to the row they reference.
+type ShardedNotificationPreferenceRepository struct {
+	router *ShardRouter
+	shards []*NotificationPreferenceRepository
+}
+
+func NewShardedNotificationPreferenceRepository(router *ShardRouter) *ShardedNotificationPreferenceRepository {
+	shards := make([]*NotificationPreferenceRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewNotificationPreferenceRepository(db))
+	}
+
+	return &ShardedNotificationPreferenceRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedNotificationPreferenceRepository) shardFor(userID string) (*NotificationPreferenceRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedNotificationPreferenceRepository) GetPreferencesByUserID(ctx context.Context, userID string) ([]*entity.NotificationPreference, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.GetPreferencesByUserID(ctx, userID)
+}
+
+func (r *ShardedNotificationPreferenceRepository) GetPreference(ctx context.Context, userID, channel, category string) (*entity.NotificationPreference, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.GetPreference(ctx, userID, channel, category)
+}
+
+func (r *ShardedNotificationPreferenceRepository) UpsertPreference(ctx context.Context, pref *entity.NotificationPreference) error {
+	shard, err := r.shardFor(pref.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.UpsertPreference(ctx, pref)
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedSecurityEventRepository keeps a user's security events on the
+// user's shard.
+type ShardedSecurityEventRepository struct {
+	router *ShardRouter
+	shards []*SecurityEventRepository
+}
+
+func NewShardedSecurityEventRepository(router *ShardRouter) *ShardedSecurityEventRepository {
+	shards := make([]*SecurityEventRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewSecurityEventRepository(db))
+	}
+
+	return &ShardedSecurityEventRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedSecurityEventRepository) shardFor(userID string) (*SecurityEventRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedSecurityEventRepository) ListSecurityEvents(ctx context.Context, userID string, beforeID int64, limit int32) ([]*entity.SecurityEvent, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.ListSecurityEvents(ctx, userID, beforeID, limit)
+}
+
+func (r *ShardedSecurityEventRepository) HasUserAgent(ctx context.Context, userID string, kind entity.SecurityEventKind, userAgent string) (bool, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return false, err
+	}
+
+	return shard.HasUserAgent(ctx, userID, kind, userAgent)
+}
+
+func (r *ShardedSecurityEventRepository) SaveSecurityEvent(ctx context.Context, event *entity.SecurityEvent) error {
+	shard, err := r.shardFor(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.SaveSecurityEvent(ctx, event)
+}
+
+func (r *ShardedSecurityEventRepository) CountExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error) {
+	var counted int64
+	for _, shard := range r.shards {
+		n, err := shard.CountExpiredSecurityEvents(ctx, before)
+		if err != nil {
+			return counted, err
+		}
+		counted += n
+	}
+
+	return counted, nil
+}
+
+func (r *ShardedSecurityEventRepository) DeleteExpiredSecurityEvents(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for _, shard := range r.shards {
+		n, err := shard.DeleteExpiredSecurityEvents(ctx, before)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,343 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+)
+
+// staleClaimAge is how long a directory entry is trusted without a matching
+// user, well above the time between claiming an email and writing the user.
+const staleClaimAge = time.Minute
+
+// ShardedUserRepository spreads users over the shards of a ShardRouter.
+// Lookups by ID go to a single shard; lookups by email resolve the ID through
+// the user_directory table on the primary database, which also keeps emails
+// unique across shards. Calls must not run inside a unit of work, as a
+// transaction cannot span databases.
+type ShardedUserRepository struct {
+	router    *ShardRouter
+	directory *sqlc.Queries
+	shards    []*UserRepository
+}
+
+func NewShardedUserRepository(router *ShardRouter, fields *encryption.FieldCipher) *ShardedUserRepository {
+	shards := make([]*UserRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewUserRepository(db, fields))
+	}
+
+	return &ShardedUserRepository{
+		router:    router,
+		directory: sqlc.New(router.Shards()[0]),
+		shards:    shards,
+	}
+}
+
+func (r *ShardedUserRepository) shardFor(id string) (*UserRepository, error) {
+	index, err := r.router.ForUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedUserRepository) CreateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	shard, err := r.shardFor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.claimEmail(ctx, user.Email.String(), user.ID); err != nil {
+		return nil, err
+	}
+
+	ret, err := shard.CreateUser(ctx, user)
+	if err != nil {
+		r.releaseEmail(ctx, user.Email.String(), user.ID)
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *ShardedUserRepository) UpdateUser(ctx context.Context, user *entity.User) (int64, error) {
+	shard, err := r.shardFor(user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := shard.GetUserByID(ctx, user.ID)
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	oldEmail, newEmail := current.Email.String(), user.Email.String()
+	if oldEmail == newEmail {
+		return shard.UpdateUser(ctx, user)
+	}
+
+	if err := r.claimEmail(ctx, newEmail, user.ID); err != nil {
+		return 0, err
+	}
+	affected, err := shard.UpdateUser(ctx, user)
+	if err != nil || affected == 0 {
+		r.releaseEmail(ctx, newEmail, user.ID)
+		return 0, err
+	}
+	r.releaseEmail(ctx, oldEmail, user.ID)
+
+	return affected, nil
+}
+
+func (r *ShardedUserRepository) ChangePassword(ctx context.Context, id string, newPassword string) (int64, error) {
+	shard, err := r.shardFor(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.ChangePassword(ctx, id, newPassword)
+}
+
+func (r *ShardedUserRepository) DeleteUser(ctx context.Context, id string) (int64, error) {
+	shard, err := r.shardFor(id)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := shard.GetUserByID(ctx, id)
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	affected, err := shard.DeleteUser(ctx, id)
+	if err != nil || affected == 0 {
+		return 0, err
+	}
+	r.releaseEmail(ctx, current.Email.String(), id)
+
+	return affected, nil
+}
+
+func (r *ShardedUserRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
+	shard, err := r.shardFor(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return shard.GetUserByID(ctx, id)
+}
+
+func (r *ShardedUserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	notFound := domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user with email %s not found", email)))
+
+	entry, err := r.directory.GetUserDirectoryEntry(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, notFound
+		}
+		return nil, queryError(err, "failed to look up user by email")
+	}
+
+	user, err := r.shards[shardOf(entry.UserID, len(r.shards))].GetUserByID(ctx, entry.UserID.String())
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			// a directory entry left behind by a failed create or update
+			return nil, notFound
+		}
+		return nil, err
+	}
+	if user.Email.String() != email {
+		return nil, notFound
+	}
+
+	return user, nil
+}
+
+// EmailKeyExists searches the directory, which holds the emails of every
+// shard. A claim left behind by a failed create counts until it is taken
+// over.
+func (r *ShardedUserRepository) EmailKeyExists(ctx context.Context, key string) (bool, error) {
+	exists, err := r.directory.DirectoryEmailKeyExists(ctx, key)
+	if err != nil {
+		return false, queryError(err, "failed to look up email key")
+	}
+
+	return exists, nil
+}
+
+func (r *ShardedUserRepository) GetPublicProfileByIds(ctx context.Context, ids []string) ([]*entity.UserPublicProfile, error) {
+	byShard := make(map[int][]string)
+	for _, id := range ids {
+		index, err := r.router.ForUser(id)
+		if err != nil {
+			// matches no user, as in a single database
+			continue
+		}
+		byShard[index] = append(byShard[index], id)
+	}
+
+	ret := make([]*entity.UserPublicProfile, 0, len(ids))
+	for index, shardIDs := range byShard {
+		profiles, err := r.shards[index].GetPublicProfileByIds(ctx, shardIDs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, profiles...)
+	}
+
+	return ret, nil
+}
+
+func (r *ShardedUserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error) {
+	byShard := make(map[int][]string)
+	for _, id := range ids {
+		index, err := r.router.ForUser(id)
+		if err != nil {
+			// matches no user, as in a single database
+			continue
+		}
+		byShard[index] = append(byShard[index], id)
+	}
+
+	ret := make([]*entity.User, 0, len(ids))
+	for index, shardIDs := range byShard {
+		users, err := r.shards[index].GetUsersByIDs(ctx, shardIDs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, users...)
+	}
+
+	return ret, nil
+}
+
+// ListUsersAfter merges the next page of every shard, so the result is in
+// the same global ID order as with a single database.
+func (r *ShardedUserRepository) ListUsersAfter(ctx context.Context, afterID string, limit int32, where filter.Expr) ([]*entity.User, error) {
+	ret := make([]*entity.User, 0)
+	for _, shard := range r.shards {
+		users, err := shard.ListUsersAfter(ctx, afterID, limit, where)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, users...)
+	}
+
+	// canonical UUID strings sort like the UUIDs themselves
+	slices.SortFunc(ret, func(a, b *entity.User) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(ret) > int(limit) {
+		ret = ret[:limit]
+	}
+
+	return ret, nil
+}
+
+// claimEmail reserves email for the user in the directory, failing with
+// EMAIL_ALREADY_EXISTS if another user holds it on any shard. An entry whose
+// user no longer has the email is taken over once it is older than
+// staleClaimAge, so a crash between the directory and shard writes cannot
+// lock an email forever.
+func (r *ShardedUserRepository) claimEmail(ctx context.Context, email, userID string) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := r.directory.InsertUserDirectoryEntry(ctx, sqlc.InsertUserDirectoryEntryParams{
+			Email:  email,
+			UserID: uuid,
+		})
+		if err == nil {
+			return nil
+		}
+		if !isDuplicateKeyError(err) {
+			return queryError(err, "failed to claim email")
+		}
+
+		alreadyExists := domain_error.New(
+			domain_error.ReasonEmailAlreadyExists,
+			domain_error.WithMessage(fmt.Sprintf("user with email %s already exists", email)),
+			domain_error.WithFieldViolation("email", "already registered"),
+		)
+		if attempt > 0 {
+			return alreadyExists
+		}
+
+		stale, err := r.isStaleClaim(ctx, email)
+		if err != nil {
+			return err
+		}
+		if !stale {
+			return alreadyExists
+		}
+	}
+}
+
+// isStaleClaim reports whether the directory entry of email is old and its
+// user no longer has the email, and if so deletes it.
+func (r *ShardedUserRepository) isStaleClaim(ctx context.Context, email string) (bool, error) {
+	entry, err := r.directory.GetUserDirectoryEntry(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// released meanwhile
+			return true, nil
+		}
+		return false, queryError(err, "failed to look up user by email")
+	}
+	// a recent entry may belong to a create still in flight
+	if time.Since(entry.CreatedAt.Time) < staleClaimAge {
+		return false, nil
+	}
+
+	holder, err := r.shards[shardOf(entry.UserID, len(r.shards))].GetUserByID(ctx, entry.UserID.String())
+	if err != nil && !domain_error.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && holder.Email.String() == email {
+		return false, nil
+	}
+
+	r.releaseEmail(ctx, email, entry.UserID.String())
+	return true, nil
+}
+
+// releaseEmail frees a directory entry of the user. A failure only leaves a
+// stale entry, which GetUserByEmail ignores, so it is logged and not
+// returned.
+func (r *ShardedUserRepository) releaseEmail(ctx context.Context, email, userID string) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return
+	}
+
+	err := r.directory.DeleteUserDirectoryEntry(context.WithoutCancel(ctx), sqlc.DeleteUserDirectoryEntryParams{
+		Email:  email,
+		UserID: uuid,
+	})
+	if err != nil {
+		log.Printf("failed to release email %s of user %s: %v", email, userID, err)
+	}
+}
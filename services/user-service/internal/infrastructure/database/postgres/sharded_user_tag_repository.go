@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+)
+
+// ShardedUserTagRepository keeps a user's tags on the user's shard, next to
+// the row they reference.
+type ShardedUserTagRepository struct {
+	router *ShardRouter
+	shards []*UserTagRepository
+}
+
+func NewShardedUserTagRepository(router *ShardRouter) *ShardedUserTagRepository {
+	shards := make([]*UserTagRepository, 0, len(router.Shards()))
+	for _, db := range router.Shards() {
+		shards = append(shards, NewUserTagRepository(db))
+	}
+
+	return &ShardedUserTagRepository{
+		router: router,
+		shards: shards,
+	}
+}
+
+func (r *ShardedUserTagRepository) shardFor(userID string) (*UserTagRepository, error) {
+	index, err := r.router.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shards[index], nil
+}
+
+func (r *ShardedUserTagRepository) ListTags(ctx context.Context, userIDs []string) ([]*entity.UserTag, error) {
+	byShard := make(map[int][]string)
+	for _, id := range userIDs {
+		index, err := r.router.ForUser(id)
+		if err != nil {
+			// matches no user, as in a single database
+			continue
+		}
+		byShard[index] = append(byShard[index], id)
+	}
+
+	ret := make([]*entity.UserTag, 0)
+	for index, shardIDs := range byShard {
+		tags, err := r.shards[index].ListTags(ctx, shardIDs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tags...)
+	}
+
+	slices.SortFunc(ret, func(a, b *entity.UserTag) int {
+		return cmp.Or(cmp.Compare(a.UserID, b.UserID), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return ret, nil
+}
+
+func (r *ShardedUserTagRepository) SaveTag(ctx context.Context, tag *entity.UserTag) error {
+	shard, err := r.shardFor(tag.UserID)
+	if err != nil {
+		return err
+	}
+
+	return shard.SaveTag(ctx, tag)
+}
+
+func (r *ShardedUserTagRepository) DeleteTag(ctx context.Context, userID, tag string) (int64, error) {
+	shard, err := r.shardFor(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.DeleteTag(ctx, userID, tag)
+}
+
+func (r *ShardedUserTagRepository) DeleteStaleRuleTags(ctx context.Context, tag string, before time.Time) (int64, error) {
+	var deleted int64
+	for _, shard := range r.shards {
+		n, err := shard.DeleteStaleRuleTags(ctx, tag, before)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	return deleted, nil
+}
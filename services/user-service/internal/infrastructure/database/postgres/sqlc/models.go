@@ -8,13 +8,155 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type NotificationPreference struct {
+	UserID    pgtype.UUID
+	Channel   string
+	Category  string
+	Enabled   bool
+	UpdatedAt pgtype.Timestamp
+}
+
+type ScimGroup struct {
+	ID           pgtype.UUID
+	ConnectionID pgtype.UUID
+	DisplayName  string
+	ExternalID   string
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+}
+
+type ScimGroupMember struct {
+	GroupID pgtype.UUID
+	UserID  pgtype.UUID
+}
+
+type ScimUser struct {
+	UserID       pgtype.UUID
+	ConnectionID pgtype.UUID
+	ExternalID   string
+	Active       bool
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+}
+
+type SsoConnection struct {
+	ID            pgtype.UUID
+	Name          string
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	CreatedAt     pgtype.Timestamp
+	ScimTokenHash pgtype.Text
+}
+
+type SsoConnectionDomain struct {
+	Domain       string
+	ConnectionID pgtype.UUID
+}
+
+type SsoIdentity struct {
+	ConnectionID pgtype.UUID
+	Subject      string
+	UserID       pgtype.UUID
+	CreatedAt    pgtype.Timestamp
+	LastLoginAt  pgtype.Timestamp
+}
+
 type User struct {
-	ID        pgtype.UUID
-	FirstName string
-	LastName  string
+	ID              pgtype.UUID
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           pgtype.Text
+	Password        string
+	CreatedAt       pgtype.Timestamp
+	UpdatedAt       pgtype.Timestamp
+	Version         int64
+	PhoneCiphertext pgtype.Text
+	PhoneHash       pgtype.Text
+	PhoneKeyVersion pgtype.Int4
+}
+
+type UserBackupCode struct {
+	ID        int64
+	UserID    pgtype.UUID
+	CodeHash  string
+	UsedAt    pgtype.Timestamp
+	CreatedAt pgtype.Timestamp
+}
+
+type UserConsent struct {
+	ID            int64
+	UserID        pgtype.UUID
+	Purpose       string
+	Granted       bool
+	PolicyVersion string
+	CreatedAt     pgtype.Timestamp
+}
+
+type UserDirectory struct {
 	Email     string
-	Phone     pgtype.Text
-	Password  string
+	UserID    pgtype.UUID
+	CreatedAt pgtype.Timestamp
+}
+
+type UserLegalHold struct {
+	UserID    pgtype.UUID
+	Reason    string
+	CreatedAt pgtype.Timestamp
+}
+
+type UserLoginLocation struct {
+	UserID      pgtype.UUID
+	Country     string
+	City        string
+	LastIp      string
+	FirstSeenAt pgtype.Timestamp
+	LastSeenAt  pgtype.Timestamp
+}
+
+type UserSecurityEvent struct {
+	ID        int64
+	UserID    pgtype.UUID
+	Kind      string
+	Ip        string
+	UserAgent string
+	Country   string
+	City      string
+	CreatedAt pgtype.Timestamp
+}
+
+type UserTag struct {
+	UserID    pgtype.UUID
+	Tag       string
+	Source    string
 	CreatedAt pgtype.Timestamp
 	UpdatedAt pgtype.Timestamp
 }
+
+type WebhookDelivery struct {
+	ID             pgtype.UUID
+	SubscriptionID pgtype.UUID
+	EventID        pgtype.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	LastStatusCode int32
+	LastError      string
+	NextAttemptAt  pgtype.Timestamp
+	CreatedAt      pgtype.Timestamp
+	UpdatedAt      pgtype.Timestamp
+}
+
+type WebhookSubscription struct {
+	ID           pgtype.UUID
+	OwnerID      pgtype.UUID
+	Url          string
+	Secret       string
+	EventTypes   []string
+	Active       bool
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+	OwnerService pgtype.Text
+}
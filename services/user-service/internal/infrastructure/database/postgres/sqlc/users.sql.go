@@ -12,9 +12,117 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const copyUser = `-- name: CopyUser :exec
+INSERT INTO users (
+  id,
+  first_name,
+  last_name,
+  email,
+  phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
+  password,
+  created_at,
+  updated_at,
+  version
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) ON CONFLICT (id) DO NOTHING
+`
+
+type CopyUserParams struct {
+	ID              pgtype.UUID
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           pgtype.Text
+	PhoneCiphertext pgtype.Text
+	PhoneHash       pgtype.Text
+	PhoneKeyVersion pgtype.Int4
+	Password        string
+	CreatedAt       pgtype.Timestamp
+	UpdatedAt       pgtype.Timestamp
+	Version         int64
+}
+
+func (q *Queries) CopyUser(ctx context.Context, arg CopyUserParams) error {
+	_, err := q.db.Exec(ctx, copyUser,
+		arg.ID,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.Phone,
+		arg.PhoneCiphertext,
+		arg.PhoneHash,
+		arg.PhoneKeyVersion,
+		arg.Password,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Version,
+	)
+	return err
+}
+
+const countUsersByPhoneKeyVersion = `-- name: CountUsersByPhoneKeyVersion :many
+SELECT phone_key_version, COUNT(*) AS users FROM users
+WHERE phone_ciphertext IS NOT NULL
+GROUP BY phone_key_version
+`
+
+type CountUsersByPhoneKeyVersionRow struct {
+	PhoneKeyVersion pgtype.Int4
+	Users           int64
+}
+
+// uses idx_users_phone_key_version
+func (q *Queries) CountUsersByPhoneKeyVersion(ctx context.Context) ([]CountUsersByPhoneKeyVersionRow, error) {
+	rows, err := q.db.Query(ctx, countUsersByPhoneKeyVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUsersByPhoneKeyVersionRow
+	for rows.Next() {
+		var i CountUsersByPhoneKeyVersionRow
+		if err := rows.Scan(&i.PhoneKeyVersion, &i.Users); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteUser = `-- name: DeleteUser :execresult
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id pgtype.UUID) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteUser, id)
+}
+
+const emailKeyExists = `-- name: EmailKeyExists :one
+SELECT EXISTS (
+  SELECT 1 FROM users
+  WHERE regexp_replace(email, '\+[^@]*@', '@') = $1
+)
+`
+
+// uses idx_users_email_key
+func (q *Queries) EmailKeyExists(ctx context.Context, emailKey string) (bool, error) {
+	row := q.db.QueryRow(ctx, emailKeyExists, emailKey)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const getPublicProfileByIds = `-- name: GetPublicProfileByIds :many
 SELECT id, first_name, last_name FROM users
-WHERE id = ANY($1::string[])
+WHERE id = ANY($1::uuid[])
 `
 
 type GetPublicProfileByIdsRow struct {
@@ -44,7 +152,7 @@ func (q *Queries) GetPublicProfileByIds(ctx context.Context, userIds []string) (
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, first_name, last_name, email, phone, password, created_at, updated_at FROM users
+SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
 WHERE email = $1
 `
 
@@ -60,12 +168,16 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneCiphertext,
+		&i.PhoneHash,
+		&i.PhoneKeyVersion,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, first_name, last_name, email, phone, password, created_at, updated_at FROM users
+SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
 WHERE id = $1
 `
 
@@ -81,40 +193,94 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneCiphertext,
+		&i.PhoneHash,
+		&i.PhoneKeyVersion,
 	)
 	return i, err
 }
 
+const getUsersByIds = `-- name: GetUsersByIds :many
+SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetUsersByIds(ctx context.Context, userIds []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIds, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.Phone,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.PhoneCiphertext,
+			&i.PhoneHash,
+			&i.PhoneKeyVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertUser = `-- name: InsertUser :one
 INSERT INTO users (
+  id,
   first_name,
   last_name,
   email,
   phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
   password,
   created_at,
   updated_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, first_name, last_name, email, phone, password, created_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version
 `
 
 type InsertUserParams struct {
-	FirstName string
-	LastName  string
-	Email     string
-	Phone     pgtype.Text
-	Password  string
-	CreatedAt pgtype.Timestamp
-	UpdatedAt pgtype.Timestamp
+	ID              pgtype.UUID
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           pgtype.Text
+	PhoneCiphertext pgtype.Text
+	PhoneHash       pgtype.Text
+	PhoneKeyVersion pgtype.Int4
+	Password        string
+	CreatedAt       pgtype.Timestamp
+	UpdatedAt       pgtype.Timestamp
 }
 
 func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
 	row := q.db.QueryRow(ctx, insertUser,
+		arg.ID,
 		arg.FirstName,
 		arg.LastName,
 		arg.Email,
 		arg.Phone,
+		arg.PhoneCiphertext,
+		arg.PhoneHash,
+		arg.PhoneKeyVersion,
 		arg.Password,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -129,10 +295,227 @@ func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.PhoneCiphertext,
+		&i.PhoneHash,
+		&i.PhoneKeyVersion,
 	)
 	return i, err
 }
 
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUsersAfterParams struct {
+	AfterID   pgtype.UUID
+	BatchSize int32
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersAfter, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.Phone,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.PhoneCiphertext,
+			&i.PhoneHash,
+			&i.PhoneKeyVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithPhoneKeyVersionOtherThan = `-- name: ListUsersWithPhoneKeyVersionOtherThan :many
+SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
+WHERE phone_ciphertext IS NOT NULL
+  AND phone_key_version IS DISTINCT FROM $1
+  AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListUsersWithPhoneKeyVersionOtherThanParams struct {
+	KeyVersion pgtype.Int4
+	AfterID    pgtype.UUID
+	BatchSize  int32
+}
+
+// uses idx_users_phone_key_version
+func (q *Queries) ListUsersWithPhoneKeyVersionOtherThan(ctx context.Context, arg ListUsersWithPhoneKeyVersionOtherThanParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersWithPhoneKeyVersionOtherThan, arg.KeyVersion, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.Phone,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.PhoneCiphertext,
+			&i.PhoneHash,
+			&i.PhoneKeyVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const normalizeUserEmail = `-- name: NormalizeUserEmail :execresult
+UPDATE users
+SET
+  email = $2,
+  version = version + 1
+WHERE id = $1 AND email = $3
+`
+
+type NormalizeUserEmailParams struct {
+	ID              pgtype.UUID
+	NormalizedEmail string
+	Email           string
+}
+
+// lower-cases the email, unless a write changed it since it was read
+func (q *Queries) NormalizeUserEmail(ctx context.Context, arg NormalizeUserEmailParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, normalizeUserEmail, arg.ID, arg.NormalizedEmail, arg.Email)
+}
+
+const rewriteUserPhone = `-- name: RewriteUserPhone :execresult
+UPDATE users
+SET
+  phone = $2,
+  phone_ciphertext = $3,
+  phone_hash = $4,
+  phone_key_version = $5
+WHERE id = $1
+  AND phone IS NOT DISTINCT FROM $6
+  AND phone_ciphertext IS NOT DISTINCT FROM $7
+`
+
+type RewriteUserPhoneParams struct {
+	ID                 pgtype.UUID
+	Phone              pgtype.Text
+	PhoneCiphertext    pgtype.Text
+	PhoneHash          pgtype.Text
+	PhoneKeyVersion    pgtype.Int4
+	OldPhone           pgtype.Text
+	OldPhoneCiphertext pgtype.Text
+}
+
+// stores the phone number in another form without changing it, unless a
+// write changed it since it was read
+func (q *Queries) RewriteUserPhone(ctx context.Context, arg RewriteUserPhoneParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, rewriteUserPhone,
+		arg.ID,
+		arg.Phone,
+		arg.PhoneCiphertext,
+		arg.PhoneHash,
+		arg.PhoneKeyVersion,
+		arg.OldPhone,
+		arg.OldPhoneCiphertext,
+	)
+}
+
+const syncUser = `-- name: SyncUser :execresult
+INSERT INTO users (
+  id,
+  first_name,
+  last_name,
+  email,
+  phone,
+  phone_ciphertext,
+  phone_hash,
+  phone_key_version,
+  password,
+  created_at,
+  updated_at,
+  version
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) ON CONFLICT (id) DO UPDATE SET
+  first_name = EXCLUDED.first_name,
+  last_name = EXCLUDED.last_name,
+  email = EXCLUDED.email,
+  phone = EXCLUDED.phone,
+  phone_ciphertext = EXCLUDED.phone_ciphertext,
+  phone_hash = EXCLUDED.phone_hash,
+  phone_key_version = EXCLUDED.phone_key_version,
+  password = EXCLUDED.password,
+  created_at = EXCLUDED.created_at,
+  updated_at = EXCLUDED.updated_at,
+  version = EXCLUDED.version
+WHERE users.version < EXCLUDED.version
+`
+
+type SyncUserParams struct {
+	ID              pgtype.UUID
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           pgtype.Text
+	PhoneCiphertext pgtype.Text
+	PhoneHash       pgtype.Text
+	PhoneKeyVersion pgtype.Int4
+	Password        string
+	CreatedAt       pgtype.Timestamp
+	UpdatedAt       pgtype.Timestamp
+	Version         int64
+}
+
+// copies a user, replacing an older version of it
+func (q *Queries) SyncUser(ctx context.Context, arg SyncUserParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, syncUser,
+		arg.ID,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.Phone,
+		arg.PhoneCiphertext,
+		arg.PhoneHash,
+		arg.PhoneKeyVersion,
+		arg.Password,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Version,
+	)
+}
+
 const updateUser = `-- name: UpdateUser :execresult
 UPDATE users
 SET
@@ -140,17 +523,25 @@ SET
   last_name = $3,
   email = $4,
   phone = $5,
-  updated_at = $6
-WHERE id = $1
+  phone_ciphertext = $6,
+  phone_hash = $7,
+  phone_key_version = $8,
+  updated_at = $9,
+  version = version + 1
+WHERE id = $1 AND version = $10
 `
 
 type UpdateUserParams struct {
-	ID        pgtype.UUID
-	FirstName string
-	LastName  string
-	Email     string
-	Phone     pgtype.Text
-	UpdatedAt pgtype.Timestamp
+	ID              pgtype.UUID
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           pgtype.Text
+	PhoneCiphertext pgtype.Text
+	PhoneHash       pgtype.Text
+	PhoneKeyVersion pgtype.Int4
+	UpdatedAt       pgtype.Timestamp
+	ExpectedVersion int64
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (pgconn.CommandTag, error) {
@@ -160,7 +551,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (pgconn.
 		arg.LastName,
 		arg.Email,
 		arg.Phone,
+		arg.PhoneCiphertext,
+		arg.PhoneHash,
+		arg.PhoneKeyVersion,
 		arg.UpdatedAt,
+		arg.ExpectedVersion,
 	)
 }
 
@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+// SSOConnectionRepository keeps connections and identities on the primary
+// database, since they are looked up before the user, and so the shard, is
+// known.
+type SSOConnectionRepository struct {
+	base *sqlc.Queries
+}
+
+func NewSSOConnectionRepository(db sqlc.DBTX) *SSOConnectionRepository {
+	return &SSOConnectionRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *SSOConnectionRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *SSOConnectionRepository) CreateConnection(ctx context.Context, conn *entity.SSOConnection) error {
+	id := pgtype.UUID{}
+	if err := id.Scan(conn.ID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", conn.ID))
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(conn.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).InsertSsoConnection(ctx, sqlc.InsertSsoConnectionParams{
+		ID:           id,
+		Name:         conn.Name,
+		Issuer:       conn.Issuer,
+		ClientID:     conn.ClientID,
+		ClientSecret: conn.ClientSecret,
+		CreatedAt:    createdAt,
+		Domains:      conn.Domains,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return domain_error.New(
+				domain_error.ReasonAlreadyExists,
+				domain_error.WithMessage(fmt.Sprintf("a domain of SSO connection %s belongs to another connection", conn.Name)),
+				domain_error.WithFieldViolation("domains", "a domain already belongs to another connection"),
+			)
+		}
+		return queryError(err, "failed to create SSO connection")
+	}
+
+	return nil
+}
+
+func (r *SSOConnectionRepository) GetConnection(ctx context.Context, id string) (*entity.SSOConnection, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", id))
+	}
+
+	conn, err := r.queries(ctx).GetSsoConnection(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonSSOConnectionNotFound, domain_error.WithMessage(fmt.Sprintf("SSO connection %s not found", id)))
+		}
+		return nil, queryError(err, "failed to get SSO connection")
+	}
+
+	ret, err := r.withDomains(ctx, []sqlc.SsoConnection{conn})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SSOConnectionRepository) GetConnectionByDomain(ctx context.Context, domain string) (*entity.SSOConnection, error) {
+	conn, err := r.queries(ctx).GetSsoConnectionByDomain(ctx, domain)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonSSOConnectionNotFound, domain_error.WithMessage(fmt.Sprintf("no SSO connection for domain %s", domain)))
+		}
+		return nil, queryError(err, "failed to get SSO connection by domain")
+	}
+
+	ret, err := r.withDomains(ctx, []sqlc.SsoConnection{conn})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SSOConnectionRepository) ListConnections(ctx context.Context) ([]*entity.SSOConnection, error) {
+	conns, err := r.queries(ctx).ListSsoConnections(ctx)
+	if err != nil {
+		return nil, queryError(err, "failed to list SSO connections")
+	}
+
+	return r.withDomains(ctx, conns)
+}
+
+func (r *SSOConnectionRepository) SetSCIMToken(ctx context.Context, id, tokenHash string) (bool, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return false, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", id))
+	}
+
+	result, err := r.queries(ctx).SetSsoConnectionScimToken(ctx, sqlc.SetSsoConnectionScimTokenParams{
+		ID:            uuid,
+		ScimTokenHash: pgtype.Text{String: tokenHash, Valid: true},
+	})
+	if err != nil {
+		return false, queryError(err, "failed to set SCIM token")
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *SSOConnectionRepository) GetConnectionBySCIMToken(ctx context.Context, tokenHash string) (*entity.SSOConnection, error) {
+	conn, err := r.queries(ctx).GetSsoConnectionByScimToken(ctx, pgtype.Text{String: tokenHash, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonSSOConnectionNotFound, domain_error.WithMessage("no SSO connection for the SCIM token"))
+		}
+		return nil, queryError(err, "failed to get SSO connection by SCIM token")
+	}
+
+	ret, err := r.withDomains(ctx, []sqlc.SsoConnection{conn})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret[0], nil
+}
+
+func (r *SSOConnectionRepository) DeleteConnection(ctx context.Context, id string) (bool, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return false, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", id))
+	}
+
+	result, err := r.queries(ctx).DeleteSsoConnection(ctx, uuid)
+	if err != nil {
+		return false, queryError(err, "failed to delete SSO connection")
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *SSOConnectionRepository) GetIdentity(ctx context.Context, connectionID, subject string) (*entity.SSOIdentity, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(connectionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", connectionID))
+	}
+
+	identity, err := r.queries(ctx).GetSsoIdentity(ctx, sqlc.GetSsoIdentityParams{
+		ConnectionID: uuid,
+		Subject:      subject,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError("SSO identity not found")
+		}
+		return nil, queryError(err, "failed to get SSO identity")
+	}
+
+	return &entity.SSOIdentity{
+		ConnectionID: identity.ConnectionID.String(),
+		Subject:      identity.Subject,
+		UserID:       identity.UserID.String(),
+	}, nil
+}
+
+func (r *SSOConnectionRepository) SaveIdentity(ctx context.Context, identity *entity.SSOIdentity) error {
+	connectionID := pgtype.UUID{}
+	if err := connectionID.Scan(identity.ConnectionID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid SSO connection ID: %s", identity.ConnectionID))
+	}
+	userID := pgtype.UUID{}
+	if err := userID.Scan(identity.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", identity.UserID))
+	}
+
+	err := r.queries(ctx).UpsertSsoIdentity(ctx, sqlc.UpsertSsoIdentityParams{
+		ConnectionID: connectionID,
+		Subject:      identity.Subject,
+		UserID:       userID,
+		CreatedAt:    pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		return queryError(err, "failed to save SSO identity")
+	}
+
+	return nil
+}
+
+// withDomains loads the domains of conns in one query.
+func (r *SSOConnectionRepository) withDomains(ctx context.Context, conns []sqlc.SsoConnection) ([]*entity.SSOConnection, error) {
+	ids := make([]string, 0, len(conns))
+	for _, conn := range conns {
+		ids = append(ids, conn.ID.String())
+	}
+
+	domains, err := r.queries(ctx).ListSsoConnectionDomains(ctx, ids)
+	if err != nil {
+		return nil, queryError(err, "failed to list SSO connection domains")
+	}
+	byConnection := make(map[string][]string, len(conns))
+	for _, domain := range domains {
+		key := domain.ConnectionID.String()
+		byConnection[key] = append(byConnection[key], domain.Domain)
+	}
+
+	ret := make([]*entity.SSOConnection, 0, len(conns))
+	for _, conn := range conns {
+		ret = append(ret, entity.SSOConnectionFromDatabase(
+			conn.ID.String(),
+			conn.Name,
+			conn.Issuer,
+			conn.ClientID,
+			conn.ClientSecret,
+			byConnection[conn.ID.String()],
+			conn.CreatedAt.Time.Unix(),
+			conn.ScimTokenHash.String,
+		))
+	}
+
+	return ret, nil
+}
@@ -2,30 +2,67 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	domain_error "github.com/phongloihong/go-shop/services/user-service/internal/domain/domain_errors"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
 	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// userFilterColumns are the columns of repository.UserFilterSchema's fields.
+var userFilterColumns = map[string]string{
+	"id":               "id::text",
+	"email":            "email",
+	"phone":            "COALESCE(phone_hash, '')",
+	"name.given_name":  "first_name",
+	"name.family_name": "last_name",
+	"create_time":      "created_at",
+	"update_time":      "updated_at",
+}
+
+// phoneField names the phone column to the FieldCipher.
+const phoneField = "users.phone"
+
+// UserRepository stores phone numbers encrypted by fields, with a hash to
+// find users by. Rows written before phones were encrypted keep them in the
+// phone column until EncryptPlaintextPhones moves them.
 type UserRepository struct {
-	queries *sqlc.Queries
+	db     sqlc.DBTX
+	base   *sqlc.Queries
+	fields *encryption.FieldCipher
 }
 
-func NewUserRepository(db sqlc.DBTX) *UserRepository {
+func NewUserRepository(db sqlc.DBTX, fields *encryption.FieldCipher) *UserRepository {
 	return &UserRepository{
-		queries: sqlc.New(db),
+		db:     db,
+		base:   sqlc.New(db),
+		fields: fields,
 	}
 }
 
+// queries joins the transaction of a unit of work running ctx, if any.
+func (ur *UserRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, ur.base)
+}
+
 func (ur *UserRepository) CreateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
-	phone := pgtype.Text{}
-	if err := phone.Scan(user.Phone.String()); err != nil {
-		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid phone number: %s", user.Phone.String()))
+	// stored under the entity's ID so a sharded repository can route a user
+	// before it exists
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(user.ID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", user.ID))
+	}
+
+	phone, err := ur.encryptPhone(ctx, user.Phone.String())
+	if err != nil {
+		return nil, err
 	}
 
 	timeNow := pgtype.Timestamp{}
@@ -33,40 +70,31 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *entity.User) (*e
 		return nil, domain_error.NewInvalidData(fmt.Sprintf("failed to scan current time: %s", err.Error()))
 	}
 
-	hashPassword, err := user.Password.Hash()
-	if err != nil {
-		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to hash password: %s", err.Error()))
-	}
-
-	newUser, err := ur.queries.InsertUser(ctx, sqlc.InsertUserParams{
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email.String(),
-		Phone:     phone,
-		Password:  hashPassword,
-		CreatedAt: timeNow,
-		UpdatedAt: timeNow,
+	newUser, err := ur.queries(ctx).InsertUser(ctx, sqlc.InsertUserParams{
+		ID:              uuid,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Email:           user.Email.String(),
+		PhoneCiphertext: phone.ciphertext,
+		PhoneHash:       phone.hash,
+		PhoneKeyVersion: phone.keyVersion,
+		Password:        user.Password.String(),
+		CreatedAt:       timeNow,
+		UpdatedAt:       timeNow,
 	})
 	if err != nil {
 		if isDuplicateKeyError(err) {
-			return nil, domain_error.NewAlreadyExistsError(fmt.Sprintf("user with email %s already exists", user.Email.String()))
+			return nil, domain_error.New(
+				domain_error.ReasonEmailAlreadyExists,
+				domain_error.WithMessage(fmt.Sprintf("user with email %s already exists", user.Email.String())),
+				domain_error.WithFieldViolation("email", "already registered"),
+			)
 		}
 
-		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to create user: %s", err.Error()))
+		return nil, queryError(err, "failed to create user")
 	}
 
-	ret := entity.UserFromDatabase(
-		newUser.ID.String(),
-		newUser.FirstName,
-		newUser.LastName,
-		newUser.Email,
-		newUser.Phone.String,
-		newUser.Password,
-		newUser.CreatedAt.Time.Unix(),
-		newUser.UpdatedAt.Time.Unix(),
-	)
-
-	return ret, nil
+	return ur.sqlcUserToEntity(ctx, newUser)
 }
 
 func (ur *UserRepository) UpdateUser(ctx context.Context, user *entity.User) (int64, error) {
@@ -75,9 +103,9 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *entity.User) (in
 		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", user.ID))
 	}
 
-	phone := pgtype.Text{}
-	if err := phone.Scan(user.Phone); err != nil {
-		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid phone number: %s", user.Phone))
+	phone, err := ur.encryptPhone(ctx, user.Phone.String())
+	if err != nil {
+		return 0, err
 	}
 
 	updatedAt := pgtype.Timestamp{}
@@ -86,16 +114,22 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *entity.User) (in
 	}
 
 	updateParams := sqlc.UpdateUserParams{
-		ID:        uuid,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email.String(),
-		Phone:     phone,
-		UpdatedAt: updatedAt,
+		ID:              uuid,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Email:           user.Email.String(),
+		PhoneCiphertext: phone.ciphertext,
+		PhoneHash:       phone.hash,
+		PhoneKeyVersion: phone.keyVersion,
+		UpdatedAt:       updatedAt,
+		ExpectedVersion: user.Version,
 	}
-	ret, err := ur.queries.UpdateUser(ctx, updateParams)
+	ret, err := ur.queries(ctx).UpdateUser(ctx, updateParams)
 	if err != nil {
-		return 0, domain_error.NewInternalError(fmt.Sprintf("failed to update user: %s", err.Error()))
+		return 0, queryError(err, "failed to update user")
+	}
+	if ret.RowsAffected() > 0 {
+		user.Version++
 	}
 
 	return ret.RowsAffected(), nil
@@ -117,9 +151,24 @@ func (ur *UserRepository) ChangePassword(ctx context.Context, id string, newPass
 		Password:  newPassword,
 		UpdatedAt: updatedAt,
 	}
-	ret, err := ur.queries.UpdateUserPassword(ctx, updateParams)
+	ret, err := ur.queries(ctx).UpdateUserPassword(ctx, updateParams)
 	if err != nil {
-		return 0, domain_error.NewInternalError(fmt.Sprintf("failed to change password: %s", err.Error()))
+		return 0, queryError(err, "failed to change password")
+	}
+
+	return ret.RowsAffected(), nil
+}
+
+func (ur *UserRepository) DeleteUser(ctx context.Context, id string) (int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", id))
+	}
+
+	// preferences follow through ON DELETE CASCADE
+	ret, err := ur.queries(ctx).DeleteUser(ctx, uuid)
+	if err != nil {
+		return 0, queryError(err, "failed to delete user")
 	}
 
 	return ret.RowsAffected(), nil
@@ -131,28 +180,43 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, id string) (*entity.U
 		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", id))
 	}
 
-	user, err := ur.queries.GetUserByID(ctx, uuid)
+	user, err := ur.queries(ctx).GetUserByID(ctx, uuid)
 	if err != nil {
-		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to get user by ID: %s", err.Error()))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", id)))
+		}
+		return nil, queryError(err, "failed to get user by ID")
 	}
 
-	return ur.sqlcUserToEntity(user), nil
+	return ur.sqlcUserToEntity(ctx, user)
 }
 
 func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
-	user, err := ur.queries.GetUserByEmail(ctx, email)
+	user, err := ur.queries(ctx).GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to get user by email: %s", err.Error()))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user with email %s not found", email)))
+		}
+		return nil, queryError(err, "failed to get user by email")
 	}
 
-	return ur.sqlcUserToEntity(user), nil
+	return ur.sqlcUserToEntity(ctx, user)
+}
+
+func (ur *UserRepository) EmailKeyExists(ctx context.Context, key string) (bool, error) {
+	exists, err := ur.queries(ctx).EmailKeyExists(ctx, key)
+	if err != nil {
+		return false, queryError(err, "failed to look up email key")
+	}
+
+	return exists, nil
 }
 
 func (ur *UserRepository) GetPublicProfileByIds(ctx context.Context, ids []string) ([]*entity.UserPublicProfile, error) {
 	ret := make([]*entity.UserPublicProfile, 0)
-	users, err := ur.queries.GetPublicProfileByIds(ctx, ids)
+	users, err := ur.queries(ctx).GetPublicProfileByIds(ctx, ids)
 	if err != nil {
-		return nil, domain_error.NewInternalError(fmt.Sprintf("failed to get public profiles by IDs: %s", err.Error()))
+		return nil, queryError(err, "failed to get public profiles by IDs")
 	}
 
 	for _, user := range users {
@@ -166,15 +230,133 @@ func (ur *UserRepository) GetPublicProfileByIds(ctx context.Context, ids []strin
 	return ret, nil
 }
 
-func (*UserRepository) sqlcUserToEntity(sqlcUser sqlc.User) *entity.User {
+func (ur *UserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*entity.User, error) {
+	users, err := ur.queries(ctx).GetUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, queryError(err, "failed to get users by IDs")
+	}
+
+	ret := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		u, err := ur.sqlcUserToEntity(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, u)
+	}
+
+	return ret, nil
+}
+
+func (ur *UserRepository) ListUsersAfter(ctx context.Context, afterID string, limit int32, where filter.Expr) ([]*entity.User, error) {
+	// the nil UUID sorts before every generated ID
+	uuid := pgtype.UUID{Valid: true}
+	if afterID != "" {
+		if err := uuid.Scan(afterID); err != nil {
+			return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", afterID))
+		}
+	}
+
+	var users []sqlc.User
+	var err error
+	if where == nil {
+		users, err = ur.queries(ctx).ListUsersAfter(ctx, sqlc.ListUsersAfterParams{
+			AfterID:   uuid,
+			BatchSize: limit,
+		})
+	} else {
+		users, err = ur.listUsersWhere(ctx, uuid, limit, where)
+	}
+	if err != nil {
+		return nil, queryError(err, "failed to list users")
+	}
+
+	ret := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		u, err := ur.sqlcUserToEntity(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, u)
+	}
+
+	return ret, nil
+}
+
+// listUsersWhere is the ListUsersAfter query with the filter's condition
+// added; values are bound as arguments, never spliced into the SQL.
+func (ur *UserRepository) listUsersWhere(ctx context.Context, afterID pgtype.UUID, limit int32, where filter.Expr) ([]sqlc.User, error) {
+	condition, args, err := filter.SQL(where, userFilterColumns, 3, filter.HashedField("phone", ur.fields.BlindIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbFor(ctx, ur.db).Query(ctx, `SELECT id, first_name, last_name, email, phone, password, created_at, updated_at, version, phone_ciphertext, phone_hash, phone_key_version FROM users
+WHERE id > $1 AND `+condition+`
+ORDER BY id
+LIMIT $2`, append([]any{afterID, limit}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[sqlc.User])
+}
+
+// encryptedPhone is how a phone number is stored: its ciphertext, the
+// version of the master key of the ciphertext, and its hash. All are NULL
+// without a phone.
+type encryptedPhone struct {
+	ciphertext pgtype.Text
+	keyVersion pgtype.Int4
+	hash       pgtype.Text
+}
+
+func encryptPhone(ctx context.Context, fields *encryption.FieldCipher, phone string) (encryptedPhone, error) {
+	if phone == "" {
+		return encryptedPhone{}, nil
+	}
+
+	ciphertext, keyVersion, err := fields.Encrypt(ctx, phoneField, phone)
+	if err != nil {
+		return encryptedPhone{}, err
+	}
+
+	return encryptedPhone{
+		ciphertext: pgtype.Text{String: ciphertext, Valid: true},
+		keyVersion: pgtype.Int4{Int32: keyVersion, Valid: true},
+		hash:       pgtype.Text{String: fields.BlindIndex(phone), Valid: true},
+	}, nil
+}
+
+func (ur *UserRepository) encryptPhone(ctx context.Context, phone string) (encryptedPhone, error) {
+	encrypted, err := encryptPhone(ctx, ur.fields, phone)
+	if err != nil {
+		return encryptedPhone{}, queryError(err, "failed to encrypt phone number")
+	}
+
+	return encrypted, nil
+}
+
+func (ur *UserRepository) sqlcUserToEntity(ctx context.Context, sqlcUser sqlc.User) (*entity.User, error) {
+	// rows not yet moved by EncryptPlaintextPhones still have the plaintext
+	phone := sqlcUser.Phone.String
+	if sqlcUser.PhoneCiphertext.Valid {
+		var err error
+		phone, err = ur.fields.Decrypt(ctx, phoneField, sqlcUser.PhoneCiphertext.String)
+		if err != nil {
+			return nil, queryError(err, "failed to decrypt phone number")
+		}
+	}
+
 	return entity.UserFromDatabase(
 		sqlcUser.ID.String(),
 		sqlcUser.FirstName,
 		sqlcUser.LastName,
 		sqlcUser.Email,
-		sqlcUser.Phone.String,
+		phone,
 		sqlcUser.Password,
 		sqlcUser.CreatedAt.Time.Unix(),
 		sqlcUser.UpdatedAt.Time.Unix(),
-	)
+		sqlcUser.Version,
+	), nil
 }
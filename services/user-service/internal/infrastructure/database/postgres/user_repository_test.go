@@ -0,0 +1,94 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/phongloihong/go-shop/services/user-service/internal/testutil"
+)
+
+func TestUserRepositoryCreateUser(t *testing.T) {
+	pool := testutil.StartPostgres(t)
+	fields, err := encryption.NewLocalFieldCipher(testutil.TestConfig().Encryption)
+	if err != nil {
+		t.Fatalf("failed to create field cipher: %v", err)
+	}
+	repo := postgres.NewUserRepositories(pool, nil, fields).Users
+	ctx := context.Background()
+
+	t.Run("stores the user and finds it by email", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		user, err := entity.NewUser("John", "Doe", "John@Example.com", "0901234567", "Secret123!")
+		if err != nil {
+			t.Fatalf("NewUser: %v", err)
+		}
+		created, err := repo.CreateUser(ctx, user)
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		got, err := repo.GetUserByEmail(ctx, "john@example.com")
+		if err != nil {
+			t.Fatalf("GetUserByEmail: %v", err)
+		}
+		if got.ID != created.ID {
+			t.Errorf("ID = %s, want %s", got.ID, created.ID)
+		}
+		if got.Phone.String() != "0901234567" {
+			t.Errorf("Phone = %q, want the number it was created with", got.Phone)
+		}
+		if got.Version != 1 {
+			t.Errorf("Version = %d, want 1", got.Version)
+		}
+	})
+
+	t.Run("encrypts the phone number at rest", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		user, err := entity.NewUser("Jane", "Doe", "jane@example.com", "0907654321", "Secret123!")
+		if err != nil {
+			t.Fatalf("NewUser: %v", err)
+		}
+		if _, err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		var phone, ciphertext *string
+		err = pool.QueryRow(ctx, "SELECT phone, phone_ciphertext FROM users WHERE id = $1", user.ID).Scan(&phone, &ciphertext)
+		if err != nil {
+			t.Fatalf("failed to read the stored user: %v", err)
+		}
+		if phone != nil && *phone != "" {
+			t.Errorf("phone column = %q, want it empty", *phone)
+		}
+		if ciphertext == nil || *ciphertext == "" || *ciphertext == "0907654321" {
+			t.Errorf("phone_ciphertext = %v, want the encrypted number", ciphertext)
+		}
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		testutil.ResetDatabase(t, pool)
+
+		first, err := entity.NewUser("John", "Doe", "john@example.com", "", "Secret123!")
+		if err != nil {
+			t.Fatalf("NewUser: %v", err)
+		}
+		if _, err := repo.CreateUser(ctx, first); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		second, err := entity.NewUser("Johnny", "Doe", "john@example.com", "", "Secret123!")
+		if err != nil {
+			t.Fatalf("NewUser: %v", err)
+		}
+		_, err = repo.CreateUser(ctx, second)
+		if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonEmailAlreadyExists {
+			t.Errorf("CreateUser of a taken email = %v, want %s", err, domain_error.ReasonEmailAlreadyExists)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type UserTagRepository struct {
+	base *sqlc.Queries
+}
+
+func NewUserTagRepository(db sqlc.DBTX) *UserTagRepository {
+	return &UserTagRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *UserTagRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *UserTagRepository) ListTags(ctx context.Context, userIDs []string) ([]*entity.UserTag, error) {
+	tags, err := r.queries(ctx).ListUserTagsByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, queryError(err, "failed to list user tags")
+	}
+
+	ret := make([]*entity.UserTag, 0, len(tags))
+	for _, tag := range tags {
+		ret = append(ret, entity.UserTagFromDatabase(
+			tag.UserID.String(),
+			tag.Tag,
+			tag.Source,
+			tag.CreatedAt.Time.Unix(),
+			tag.UpdatedAt.Time.Unix(),
+		))
+	}
+
+	return ret, nil
+}
+
+func (r *UserTagRepository) SaveTag(ctx context.Context, tag *entity.UserTag) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(tag.UserID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", tag.UserID))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(tag.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).UpsertUserTag(ctx, sqlc.UpsertUserTagParams{
+		UserID:    uuid,
+		Tag:       tag.Tag,
+		Source:    string(tag.Source),
+		CreatedAt: updatedAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to save user tag")
+	}
+
+	return nil
+}
+
+func (r *UserTagRepository) DeleteTag(ctx context.Context, userID, tag string) (int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(userID); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid user ID: %s", userID))
+	}
+
+	ret, err := r.queries(ctx).DeleteUserTag(ctx, sqlc.DeleteUserTagParams{
+		UserID: uuid,
+		Tag:    tag,
+	})
+	if err != nil {
+		return 0, queryError(err, "failed to delete user tag")
+	}
+
+	return ret.RowsAffected(), nil
+}
+
+func (r *UserTagRepository) DeleteStaleRuleTags(ctx context.Context, tag string, before time.Time) (int64, error) {
+	ret, err := r.queries(ctx).DeleteStaleRuleUserTags(ctx, sqlc.DeleteStaleRuleUserTagsParams{
+		Tag:    tag,
+		Before: pgtype.Timestamp{Time: before, Valid: true},
+	})
+	if err != nil {
+		return 0, queryError(err, "failed to delete stale rule tags")
+	}
+
+	return ret.RowsAffected(), nil
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/uow"
+)
+
+// UserUnitOfWork runs a uow.Do on the shard of a user, so the user's
+// repositories join a transaction on the database that holds their rows.
+// Without shards, every user is on the primary. The databases must be able
+// to begin transactions, as pools do.
+type UserUnitOfWork struct {
+	router *ShardRouter
+}
+
+func NewUserUnitOfWork(router *ShardRouter) *UserUnitOfWork {
+	return &UserUnitOfWork{router: router}
+}
+
+func (u *UserUnitOfWork) Do(ctx context.Context, userID string, fn func(ctx context.Context) error) error {
+	index, err := u.router.ForUser(userID)
+	if err != nil {
+		return err
+	}
+
+	db, ok := u.router.Shards()[index].(uow.Beginner)
+	if !ok {
+		return domain_error.NewInternalError(fmt.Sprintf("shard %d cannot begin transactions", index))
+	}
+
+	return uow.New(db).Do(ctx, fn)
+}
@@ -0,0 +1,356 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres/sqlc"
+)
+
+type WebhookRepository struct {
+	base *sqlc.Queries
+}
+
+func NewWebhookRepository(db sqlc.DBTX) *WebhookRepository {
+	return &WebhookRepository{
+		base: sqlc.New(db),
+	}
+}
+
+// queries joins the transaction of a unit of work running ctx, if any.
+func (r *WebhookRepository) queries(ctx context.Context) *sqlc.Queries {
+	return queriesFor(ctx, r.base)
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	id := pgtype.UUID{}
+	if err := id.Scan(sub.ID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid subscription ID: %s", sub.ID))
+	}
+
+	ownerID, ownerService, err := ownerParams(sub.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(sub.CreatedAt.Time()); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(sub.UpdatedAt.Time()); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	newSub, err := r.queries(ctx).InsertWebhookSubscription(ctx, sqlc.InsertWebhookSubscriptionParams{
+		ID:           id,
+		OwnerID:      ownerID,
+		OwnerService: ownerService,
+		Url:          sub.URL,
+		Secret:       sub.Secret,
+		EventTypes:   sub.EventTypes,
+		Active:       sub.Active,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to create webhook subscription")
+	}
+
+	return r.sqlcSubscriptionToEntity(newSub), nil
+}
+
+func (r *WebhookRepository) ListSubscriptionsByOwner(ctx context.Context, owner entity.WebhookOwner) ([]*entity.WebhookSubscription, error) {
+	ownerID, ownerService, err := ownerParams(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := r.queries(ctx).ListWebhookSubscriptionsByOwner(ctx, sqlc.ListWebhookSubscriptionsByOwnerParams{
+		OwnerID:      ownerID,
+		OwnerService: ownerService,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to list webhook subscriptions")
+	}
+
+	ret := make([]*entity.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		ret = append(ret, r.sqlcSubscriptionToEntity(sub))
+	}
+
+	return ret, nil
+}
+
+func (r *WebhookRepository) ListActiveSubscriptionsForEvent(ctx context.Context, eventType, subject string) ([]*entity.WebhookSubscription, error) {
+	// an event about no user, or not about a user ID, reaches only
+	// internal services
+	subjectID := pgtype.UUID{}
+	_ = subjectID.Scan(subject)
+
+	subs, err := r.queries(ctx).ListActiveWebhookSubscriptionsForEvent(ctx, sqlc.ListActiveWebhookSubscriptionsForEventParams{
+		EventType: eventType,
+		Subject:   subjectID,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to list webhook subscriptions")
+	}
+
+	ret := make([]*entity.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		ret = append(ret, r.sqlcSubscriptionToEntity(sub))
+	}
+
+	return ret, nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid subscription ID: %s", id))
+	}
+
+	sub, err := r.queries(ctx).GetWebhookSubscription(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("webhook subscription %s not found", id))
+		}
+
+		return nil, queryError(err, "failed to get webhook subscription")
+	}
+
+	return r.sqlcSubscriptionToEntity(sub), nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string, owner entity.WebhookOwner) (int64, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("invalid subscription ID: %s", id))
+	}
+
+	ownerID, ownerService, err := ownerParams(owner)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, err := r.queries(ctx).DeleteWebhookSubscription(ctx, sqlc.DeleteWebhookSubscriptionParams{
+		ID:           uuid,
+		OwnerID:      ownerID,
+		OwnerService: ownerService,
+	})
+	if err != nil {
+		return 0, queryError(err, "failed to delete webhook subscription")
+	}
+
+	return ret.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	id := pgtype.UUID{}
+	if err := id.Scan(delivery.ID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid delivery ID: %s", delivery.ID))
+	}
+
+	subscriptionID := pgtype.UUID{}
+	if err := subscriptionID.Scan(delivery.SubscriptionID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid subscription ID: %s", delivery.SubscriptionID))
+	}
+
+	eventID := pgtype.UUID{}
+	if err := eventID.Scan(delivery.EventID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid event ID: %s", delivery.EventID))
+	}
+
+	nextAttemptAt := pgtype.Timestamp{}
+	if err := nextAttemptAt.Scan(delivery.NextAttemptAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan next attempt timestamp: %s", err.Error()))
+	}
+
+	createdAt := pgtype.Timestamp{}
+	if err := createdAt.Scan(delivery.CreatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan created timestamp: %s", err.Error()))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(delivery.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).InsertWebhookDelivery(ctx, sqlc.InsertWebhookDeliveryParams{
+		ID:             id,
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	leaseUntil := pgtype.Timestamp{}
+	if err := leaseUntil.Scan(time.Now().Add(lease)); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("failed to scan lease timestamp: %s", err.Error()))
+	}
+
+	deliveries, err := r.queries(ctx).ClaimDueWebhookDeliveries(ctx, sqlc.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: leaseUntil,
+		BatchSize:  limit,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to claim webhook deliveries")
+	}
+
+	ret := make([]*entity.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ret = append(ret, r.sqlcDeliveryToEntity(delivery))
+	}
+
+	return ret, nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid delivery ID: %s", id))
+	}
+
+	delivery, err := r.queries(ctx).GetWebhookDelivery(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain_error.NewNotFoundError(fmt.Sprintf("webhook delivery %s not found", id))
+		}
+
+		return nil, queryError(err, "failed to get webhook delivery")
+	}
+
+	return r.sqlcDeliveryToEntity(delivery), nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(delivery.ID); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("invalid delivery ID: %s", delivery.ID))
+	}
+
+	nextAttemptAt := pgtype.Timestamp{}
+	if err := nextAttemptAt.Scan(delivery.NextAttemptAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan next attempt timestamp: %s", err.Error()))
+	}
+
+	updatedAt := pgtype.Timestamp{}
+	if err := updatedAt.Scan(delivery.UpdatedAt.Time()); err != nil {
+		return domain_error.NewInvalidData(fmt.Sprintf("failed to scan updated timestamp: %s", err.Error()))
+	}
+
+	err := r.queries(ctx).UpdateWebhookDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
+		ID:             uuid,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  nextAttemptAt,
+		UpdatedAt:      updatedAt,
+	})
+	if err != nil {
+		return queryError(err, "failed to update webhook delivery")
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	finishedBefore := pgtype.Timestamp{}
+	if err := finishedBefore.Scan(before); err != nil {
+		return 0, domain_error.NewInvalidData(fmt.Sprintf("failed to scan timestamp: %s", err.Error()))
+	}
+
+	ret, err := r.queries(ctx).DeleteFinishedWebhookDeliveries(ctx, finishedBefore)
+	if err != nil {
+		return 0, queryError(err, "failed to delete webhook deliveries")
+	}
+
+	return ret.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) ListDeliveriesBySubscription(ctx context.Context, subscriptionID string, limit int32) ([]*entity.WebhookDelivery, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(subscriptionID); err != nil {
+		return nil, domain_error.NewInvalidData(fmt.Sprintf("invalid subscription ID: %s", subscriptionID))
+	}
+
+	deliveries, err := r.queries(ctx).ListWebhookDeliveriesBySubscription(ctx, sqlc.ListWebhookDeliveriesBySubscriptionParams{
+		SubscriptionID: uuid,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, queryError(err, "failed to list webhook deliveries")
+	}
+
+	ret := make([]*entity.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ret = append(ret, r.sqlcDeliveryToEntity(delivery))
+	}
+
+	return ret, nil
+}
+
+// ownerParams returns the owner_id and owner_service columns of owner, of
+// which only one is not NULL.
+func ownerParams(owner entity.WebhookOwner) (pgtype.UUID, pgtype.Text, error) {
+	if owner.Service != "" {
+		return pgtype.UUID{}, pgtype.Text{String: owner.Service, Valid: true}, nil
+	}
+
+	ownerID := pgtype.UUID{}
+	if err := ownerID.Scan(owner.UserID); err != nil {
+		return pgtype.UUID{}, pgtype.Text{}, domain_error.NewInvalidData(fmt.Sprintf("invalid owner ID: %s", owner.UserID))
+	}
+
+	return ownerID, pgtype.Text{}, nil
+}
+
+func (*WebhookRepository) sqlcSubscriptionToEntity(sub sqlc.WebhookSubscription) *entity.WebhookSubscription {
+	return entity.WebhookSubscriptionFromDatabase(
+		sub.ID.String(),
+		entity.WebhookOwner{UserID: sub.OwnerID.String(), Service: sub.OwnerService.String},
+		sub.Url,
+		sub.Secret,
+		sub.EventTypes,
+		sub.Active,
+		sub.CreatedAt.Time.Unix(),
+		sub.UpdatedAt.Time.Unix(),
+	)
+}
+
+func (*WebhookRepository) sqlcDeliveryToEntity(delivery sqlc.WebhookDelivery) *entity.WebhookDelivery {
+	return entity.WebhookDeliveryFromDatabase(
+		delivery.ID.String(),
+		delivery.SubscriptionID.String(),
+		delivery.EventID.String(),
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.NextAttemptAt.Time.Unix(),
+		delivery.CreatedAt.Time.Unix(),
+		delivery.UpdatedAt.Time.Unix(),
+	)
+}
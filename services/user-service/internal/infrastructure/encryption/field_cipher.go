@@ -0,0 +1,243 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+)
+
+const (
+	// v1Prefix started the ciphertexts written before master keys had
+	// versions, all under master key 1.
+	v1Prefix = "v1."
+	// v2Prefix starts ciphertexts as "v2.<master key version>.<base64>".
+	v2Prefix = "v2."
+	// maxCachedKeys bounds the unwrapped data keys kept to decrypt values.
+	maxCachedKeys = 1024
+)
+
+// FieldCipher encrypts column values with a data key of its KeyManager,
+// which it replaces every dataKeyTTL, and stores the wrapped data key and
+// the version of the master key that wrapped it with each value. Lookups of
+// encrypted columns go through BlindIndex instead. It is safe for
+// concurrent use.
+type FieldCipher struct {
+	keys       KeyManager
+	hashKey    []byte
+	dataKeyTTL time.Duration
+
+	mu      sync.Mutex
+	current *dataKey
+	// unwrapped caches the data keys of decrypted values, so the KeyManager
+	// is asked once per data key.
+	unwrapped map[wrappedKey]cipher.AEAD
+}
+
+type dataKey struct {
+	aead      cipher.AEAD
+	wrapped   wrappedKey
+	expiresAt time.Time
+}
+
+type wrappedKey struct {
+	version int32
+	key     string
+}
+
+// NewFieldCipher encrypts with data keys from keys, and keys BlindIndex
+// with hashKey.
+func NewFieldCipher(keys KeyManager, hashKey string, dataKeyTTL time.Duration) (*FieldCipher, error) {
+	if len(hashKey) < 32 {
+		return nil, errors.New("hash key must be at least 32 characters")
+	}
+
+	return &FieldCipher{
+		keys:       keys,
+		hashKey:    []byte(hashKey),
+		dataKeyTTL: dataKeyTTL,
+		unwrapped:  make(map[wrappedKey]cipher.AEAD),
+	}, nil
+}
+
+// KeyVersion is the version of the master key Encrypt uses. Values under
+// other versions are due to be re-encrypted.
+func (c *FieldCipher) KeyVersion() int32 {
+	return c.keys.KeyVersion()
+}
+
+// Encrypt encrypts value for the column named field, e.g. "users.phone",
+// and returns the version of the master key used. The ciphertext only
+// decrypts for the same field, so it cannot be copied into another column.
+func (c *FieldCipher) Encrypt(ctx context.Context, field, value string) (string, int32, error) {
+	key, err := c.dataKey(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+
+	sealed, err := seal(key.aead, []byte(value), []byte(field))
+	if err != nil {
+		return "", 0, err
+	}
+
+	raw := binary.BigEndian.AppendUint16(nil, uint16(len(key.wrapped.key)))
+	raw = append(raw, key.wrapped.key...)
+	raw = append(raw, sealed...)
+
+	ciphertext := v2Prefix + strconv.FormatInt(int64(key.wrapped.version), 10) + "." + base64.RawStdEncoding.EncodeToString(raw)
+	return ciphertext, key.wrapped.version, nil
+}
+
+// Decrypt decrypts a value Encrypt returned for field.
+func (c *FieldCipher) Decrypt(ctx context.Context, field, ciphertext string) (string, error) {
+	version, encoded, err := parseCiphertext(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("%w of %s", err, field)
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < 2 {
+		return "", fmt.Errorf("malformed ciphertext of %s", field)
+	}
+	wrappedLen := int(binary.BigEndian.Uint16(raw))
+	if len(raw) < 2+wrappedLen {
+		return "", fmt.Errorf("malformed ciphertext of %s", field)
+	}
+	wrapped, sealed := raw[2:2+wrappedLen], raw[2+wrappedLen:]
+
+	aead, err := c.unwrap(ctx, wrappedKey{version: version, key: string(wrapped)})
+	if err != nil {
+		return "", err
+	}
+	value, err := open(aead, sealed, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+
+	return string(value), nil
+}
+
+// parseCiphertext returns the master key version of ciphertext and its
+// base64 part.
+func parseCiphertext(ciphertext string) (int32, string, error) {
+	if encoded, ok := strings.CutPrefix(ciphertext, v1Prefix); ok {
+		return 1, encoded, nil
+	}
+
+	rest, ok := strings.CutPrefix(ciphertext, v2Prefix)
+	if !ok {
+		return 0, "", errors.New("unknown ciphertext format")
+	}
+	version, encoded, ok := strings.Cut(rest, ".")
+	if !ok {
+		return 0, "", errors.New("malformed ciphertext")
+	}
+	v, err := strconv.ParseInt(version, 10, 32)
+	if err != nil {
+		return 0, "", errors.New("malformed ciphertext")
+	}
+
+	return int32(v), encoded, nil
+}
+
+// BlindIndex returns a keyed hash of value, stored next to the ciphertext
+// so the column can still be looked up by equality. The same value always
+// has the same index, so it reveals which rows share a value, but not the
+// value. An empty value has an empty index, so "no value" stays findable.
+func (c *FieldCipher) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// dataKey returns the data key to encrypt with, generating one when there
+// is none yet, it expired, or the master key version changed.
+func (c *FieldCipher) dataKey(ctx context.Context) (*dataKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil && time.Now().Before(c.current.expiresAt) && c.current.wrapped.version == c.keys.KeyVersion() {
+		return c.current, nil
+	}
+
+	plaintext, wrapped, version, err := c.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	c.current = &dataKey{
+		aead:      aead,
+		wrapped:   wrappedKey{version: version, key: string(wrapped)},
+		expiresAt: time.Now().Add(c.dataKeyTTL),
+	}
+	c.cache(c.current.wrapped, aead)
+
+	return c.current, nil
+}
+
+func (c *FieldCipher) unwrap(ctx context.Context, wrapped wrappedKey) (cipher.AEAD, error) {
+	c.mu.Lock()
+	aead, ok := c.unwrapped[wrapped]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	plaintext, err := c.keys.DecryptDataKey(ctx, wrapped.version, []byte(wrapped.key))
+	if err != nil {
+		return nil, err
+	}
+	aead, err = newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache(wrapped, aead)
+	c.mu.Unlock()
+
+	return aead, nil
+}
+
+// cache must be called with mu held.
+func (c *FieldCipher) cache(wrapped wrappedKey, aead cipher.AEAD) {
+	if len(c.unwrapped) >= maxCachedKeys {
+		clear(c.unwrapped)
+	}
+	c.unwrapped[wrapped] = aead
+}
+
+// NewLocalFieldCipher returns the FieldCipher of cfg, with the master keys
+// kept in memory by a LocalKeyManager.
+func NewLocalFieldCipher(cfg *config.EncryptionConfig) (*FieldCipher, error) {
+	masterKeys := make(map[int32]string, len(cfg.MasterKeys))
+	for _, key := range cfg.MasterKeys {
+		masterKeys[key.Version] = key.Key
+	}
+
+	keys, err := NewLocalKeyManager(cfg.KeyVersion, masterKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFieldCipher(keys, cfg.HashKey, cfg.DataKeyTTL)
+}
@@ -0,0 +1,126 @@
+// Package encryption encrypts sensitive columns, such as phone numbers, in
+// the application before they are stored. It uses envelope encryption: each
+// value is encrypted with a data key, which is stored with the value
+// wrapped by a master key that never leaves the KeyManager.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const dataKeySize = 32
+
+// KeyManager holds the versioned master keys that wrap data keys, e.g. a
+// cloud KMS. Rotating the master key adds a version; older versions stay
+// until no stored value uses them.
+type KeyManager interface {
+	// KeyVersion is the version of the master key GenerateDataKey wraps
+	// with.
+	KeyVersion() int32
+	// GenerateDataKey returns a new data key, and the key wrapped by the
+	// master key of KeyVersion for storage.
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, keyVersion int32, err error)
+	// DecryptDataKey unwraps a data key returned by GenerateDataKey.
+	DecryptDataKey(ctx context.Context, keyVersion int32, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyManager keeps the master keys in memory, for deployments without
+// a KMS and for development.
+type LocalKeyManager struct {
+	version int32
+	masters map[int32]cipher.AEAD
+}
+
+// NewLocalKeyManager takes the base64 of each 32-byte master key by
+// version, and wraps new data keys with the key of version.
+func NewLocalKeyManager(version int32, masterKeys map[int32]string) (*LocalKeyManager, error) {
+	masters := make(map[int32]cipher.AEAD, len(masterKeys))
+	for v, masterKey := range masterKeys {
+		key, err := base64.StdEncoding.DecodeString(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("master key %d is not base64: %w", v, err)
+		}
+		if len(key) != dataKeySize {
+			return nil, fmt.Errorf("master key %d must be %d bytes, got %d", v, dataKeySize, len(key))
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		masters[v] = aead
+	}
+	if _, ok := masters[version]; !ok {
+		return nil, fmt.Errorf("no master key of version %d", version)
+	}
+
+	return &LocalKeyManager{version: version, masters: masters}, nil
+}
+
+var _ KeyManager = (*LocalKeyManager)(nil)
+
+func (m *LocalKeyManager) KeyVersion() int32 {
+	return m.version
+}
+
+func (m *LocalKeyManager) GenerateDataKey(ctx context.Context) ([]byte, []byte, int32, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, 0, err
+	}
+
+	wrapped, err := seal(m.masters[m.version], key, nil)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return key, wrapped, m.version, nil
+}
+
+func (m *LocalKeyManager) DecryptDataKey(ctx context.Context, keyVersion int32, wrapped []byte) ([]byte, error) {
+	master, ok := m.masters[keyVersion]
+	if !ok {
+		return nil, fmt.Errorf("no master key of version %d", keyVersion)
+	}
+
+	key, err := open(master, wrapped, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext, authenticating additionalData with it, under a
+// random nonce, which it puts first.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
@@ -0,0 +1,93 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+// HTTPLocator looks addresses up in an HTTP geolocation API answering with
+// ipinfo-style JSON, e.g. {"country": "VN", "city": "Hanoi", "org": "AS45899
+// VNPT Corp"}.
+type HTTPLocator struct {
+	urlTemplate string
+	client      *http.Client
+}
+
+// NewHTTPLocator returns a locator calling urlTemplate with "{ip}" replaced
+// by the address, or one that locates nothing when urlTemplate is empty.
+func NewHTTPLocator(urlTemplate string, timeout time.Duration) service.GeoLocator {
+	if urlTemplate == "" {
+		return noopLocator{}
+	}
+
+	return &HTTPLocator{
+		urlTemplate: urlTemplate,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+func (l *HTTPLocator) Locate(ctx context.Context, ip string) (entity.Location, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return entity.Location{}, nil
+	}
+
+	lookupURL := strings.ReplaceAll(l.urlTemplate, "{ip}", url.PathEscape(addr.String()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return entity.Location{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return entity.Location{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return entity.Location{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Country string `json:"country"`
+		City    string `json:"city"`
+		Org     string `json:"org"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err != nil {
+		return entity.Location{}, fmt.Errorf("failed to decode geolocation: %w", err)
+	}
+
+	return entity.Location{
+		Country: strings.ToUpper(body.Country),
+		City:    body.City,
+		ASN:     asn(body.Org),
+	}, nil
+}
+
+// asn returns the AS number org starts with, or "" when it has none.
+func asn(org string) string {
+	number, _, _ := strings.Cut(org, " ")
+	if !strings.HasPrefix(number, "AS") {
+		return ""
+	}
+
+	return number
+}
+
+type noopLocator struct{}
+
+func (noopLocator) Locate(context.Context, string) (entity.Location, error) {
+	return entity.Location{}, nil
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/webhook"
+)
+
+// Notification types, sent in the TypeHeader.
+const (
+	TypeLoginCode = "login_code"
+	TypeMagicLink = "magic_link"
+)
+
+const (
+	SignatureHeader = "X-Notification-Signature"
+	TypeHeader      = "X-Notification-Type"
+)
+
+// ErrDisabled is returned by the notifier of a service without a
+// notification URL.
+var ErrDisabled = errors.New("notifications are not configured")
+
+// HTTPNotifier posts each notification once to the notification service,
+// signed like webhook deliveries, and keeps no copy of it.
+type HTTPNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewHTTPNotifier returns a notifier posting to url, or one that fails every
+// notification with ErrDisabled when url or secret is empty.
+func NewHTTPNotifier(url, secret string, timeout time.Duration) service.Notifier {
+	if url == "" || secret == "" {
+		return disabledNotifier{}
+	}
+
+	return &HTTPNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *HTTPNotifier) SendLoginCode(ctx context.Context, code *entity.LoginCode) error {
+	return n.send(ctx, TypeLoginCode, code)
+}
+
+func (n *HTTPNotifier) SendMagicLink(ctx context.Context, link *entity.MagicLinkEmail) error {
+	return n.send(ctx, TypeMagicLink, link)
+}
+
+func (n *HTTPNotifier) send(ctx context.Context, notificationType string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s notification: %w", notificationType, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TypeHeader, notificationType)
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%s,v1=%s", timestamp, webhook.Sign(n.secret, timestamp, body)))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", notificationType, err)
+	}
+	defer resp.Body.Close()
+
+	// drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send %s notification: unexpected status code %d", notificationType, resp.StatusCode)
+	}
+
+	return nil
+}
+
+type disabledNotifier struct{}
+
+func (disabledNotifier) SendLoginCode(context.Context, *entity.LoginCode) error {
+	return ErrDisabled
+}
+
+func (disabledNotifier) SendMagicLink(context.Context, *entity.MagicLinkEmail) error {
+	return ErrDisabled
+}
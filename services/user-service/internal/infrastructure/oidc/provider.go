@@ -0,0 +1,324 @@
+// Package oidc signs users in at OpenID Connect identity providers with the
+// authorization code flow and PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+const (
+	// cacheTTL is how long discovery documents and signing keys are used
+	// before they are fetched again.
+	cacheTTL = time.Hour
+	// minKeyRefresh spaces out the key fetches forced by tokens signed with
+	// an unknown key, so bogus tokens cannot flood the IdP.
+	minKeyRefresh = time.Minute
+	maxBodyBytes  = 1 << 20
+)
+
+// Provider talks to any IdP that publishes its endpoints at
+// <issuer>/.well-known/openid-configuration. It caches the discovery
+// documents and signing keys of every issuer it has seen.
+type Provider struct {
+	client *http.Client
+
+	mu        sync.Mutex
+	documents map[string]*document
+	keys      map[string]*keySet
+}
+
+type document struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+
+	fetchedAt time.Time
+}
+
+type keySet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+var _ service.IdentityProvider = (*Provider)(nil)
+
+func NewProvider(timeout time.Duration) *Provider {
+	return &Provider{
+		client:    &http.Client{Timeout: timeout},
+		documents: make(map[string]*document),
+		keys:      make(map[string]*keySet),
+	}
+}
+
+func (p *Provider) AuthorizationURL(ctx context.Context, conn *entity.SSOConnection, redirectURL string, state *entity.SSOState) (string, error) {
+	doc, err := p.discover(ctx, conn.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint of %s: %w", conn.Issuer, err)
+	}
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", conn.ClientID)
+	query.Set("redirect_uri", redirectURL)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state.ID)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, conn *entity.SSOConnection, redirectURL, code, codeVerifier string) (*entity.SSOClaims, error) {
+	doc, err := p.discover(ctx, conn.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// client_secret_basic is the default; use client_secret_post only for
+	// IdPs that do not take the former
+	basic := len(doc.TokenAuthMethods) == 0 || slices.Contains(doc.TokenAuthMethods, "client_secret_basic")
+	if !basic {
+		form.Set("client_id", conn.ClientID)
+		form.Set("client_secret", conn.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basic {
+		req.SetBasicAuth(url.QueryEscape(conn.ClientID), url.QueryEscape(conn.ClientSecret))
+	}
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code at %s: %w", conn.Issuer, err)
+	}
+	if status != http.StatusOK || body.IDToken == "" {
+		return nil, fmt.Errorf("token endpoint of %s answered %d: %s %s", conn.Issuer, status, body.Error, body.ErrorDescription)
+	}
+
+	return p.verify(ctx, doc, conn.ClientID, body.IDToken)
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email string `json:"email"`
+	// EmailVerified is a bool, or a string at some IdPs.
+	EmailVerified any    `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+}
+
+func (p *Provider) verify(ctx context.Context, doc *document, clientID, idToken string) (*entity.SSOClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, doc.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token from %s: %w", doc.Issuer, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("ID token from %s has no subject", doc.Issuer)
+	}
+
+	verified := true
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v != "false"
+	}
+
+	return &entity.SSOClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+// discover returns the discovery document of issuer.
+func (p *Provider) discover(ctx context.Context, issuer string) (*document, error) {
+	p.mu.Lock()
+	doc, ok := p.documents[issuer]
+	p.mu.Unlock()
+	if ok && time.Since(doc.fetchedAt) < cacheTTL {
+		return doc, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	doc = &document{}
+	status, err := p.doJSON(req, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover %s: %w", issuer, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery of %s answered %d", issuer, status)
+	}
+	// a document naming another issuer could pass off that issuer's tokens
+	if doc.Issuer != issuer {
+		return nil, fmt.Errorf("discovery of %s names issuer %s", issuer, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery of %s lacks endpoints", issuer)
+	}
+	doc.fetchedAt = time.Now()
+
+	p.mu.Lock()
+	p.documents[issuer] = doc
+	p.mu.Unlock()
+
+	return doc, nil
+}
+
+// key returns the signing key kid of the JWKS at uri, fetching the set again
+// when the IdP has rotated to a key not seen yet.
+func (p *Provider) key(ctx context.Context, uri, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	set, ok := p.keys[uri]
+	p.mu.Unlock()
+	if ok {
+		if key, found := set.keys[kid]; found && time.Since(set.fetchedAt) < cacheTTL {
+			return key, nil
+		}
+		if time.Since(set.fetchedAt) < minKeyRefresh {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	set, err := p.fetchKeys(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.keys[uri] = set
+	p.mu.Unlock()
+
+	key, found := set.keys[kid]
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, uri string) (*keySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	status, err := p.doJSON(req, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys from %s: %w", uri, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("signing keys at %s answered %d", uri, status)
+	}
+
+	set := &keySet{
+		keys:      make(map[string]*rsa.PublicKey, len(body.Keys)),
+		fetchedAt: time.Now(),
+	}
+	for _, k := range body.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		set.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("no RSA signing keys at " + uri)
+	}
+
+	return set, nil
+}
+
+// doJSON sends req and decodes the JSON body of the response into v,
+// whatever its status, which it returns.
+func (p *Provider) doJSON(req *http.Request, v any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBodyBytes)).Decode(v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// HTTPSender posts deliveries without following redirects. Users'
+// subscriptions are sent through a client whose dialer refuses non-global
+// addresses, checked on the resolved address so that DNS rebinding cannot
+// reach the internal network either.
+type HTTPSender struct {
+	client       *http.Client
+	publicClient *http.Client
+}
+
+func NewHTTPSender(timeout time.Duration) service.WebhookSender {
+	return &HTTPSender{
+		client:       newClient(timeout, nil),
+		publicClient: newClient(timeout, publicAddressesOnly),
+	}
+}
+
+// newClient dials through control, when set, and then never through a proxy,
+// which would be the address control sees.
+func newClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if control != nil {
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: control}).DialContext
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// a redirect is a non-2xx answer like any other
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !entity.IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+	}
+
+	return nil
+}
+
+func (s *HTTPSender) Send(ctx context.Context, sub *entity.WebhookSubscription, delivery *entity.WebhookDelivery) (int32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%s,v1=%s", timestamp, Sign(sub.Secret, timestamp, delivery.Payload)))
+
+	client := s.client
+	if sub.RestrictsAddresses() {
+		client = s.publicClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return int32(resp.StatusCode), fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return int32(resp.StatusCode), nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>". Receivers
+// recompute it with their copy of the secret to verify a delivery, and reject
+// stale timestamps to prevent replays.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,202 @@
+package testutil
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/phongloihong/go-shop/api/client"
+	"github.com/phongloihong/go-shop/api/gen/user/v1/userv1connect"
+	"github.com/phongloihong/go-shop/api/gen/user/v2/userv2connect"
+	"github.com/phongloihong/go-shop/pkg/cors"
+	"github.com/phongloihong/go-shop/pkg/health"
+	"github.com/phongloihong/go-shop/pkg/interceptor"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/pkg/uow"
+	"github.com/phongloihong/go-shop/services/user-service/internal/config"
+	"github.com/phongloihong/go-shop/services/user-service/internal/delivery/connect"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/cache"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/captcha"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/postgres"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/encryption"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/geoip"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/webhook"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/redis/go-redis/v9"
+)
+
+// Server is the user service's Connect API served over httptest, wired to
+// the test's containers exactly as cmd/main.go wires production.
+type Server struct {
+	URL      string
+	Config   *config.Config
+	Webhooks *usecase.WebhookUseCase
+	Jobs     *jobs.Queue
+
+	t *testing.T
+}
+
+// TestConfig returns the config NewServer starts from: fixed JWT and magic
+// link secrets, no geolocation of logins, rate limiting, anomaly detection,
+// CAPTCHAs, chaos and request size limits off, and fast webhook retries. Tests may change RateLimit, Chaos and RequestSize on the returned
+// Server's Config while it runs.
+func TestConfig() *config.Config {
+	return &config.Config{
+		Server: &config.ServerConfig{},
+		Auth: &config.AuthConfig{
+			PasswordSecret: "test-password-secret",
+			AccessSecret:   "test-access-secret",
+			RefreshSecret:  "test-refresh-secret",
+		},
+		Encryption: &config.EncryptionConfig{
+			MasterKeys: []config.MasterKeyConfig{
+				{Version: 1, Key: "dGVzdC1tYXN0ZXIta2V5LTMyLWJ5dGVzLWxvbmchISE="},
+			},
+			KeyVersion: 1,
+			HashKey:    "test-hash-key-of-at-least-32-characters",
+			DataKeyTTL: time.Hour,
+		},
+		Email:     &config.EmailConfig{},
+		LoginRisk: &config.LoginRiskConfig{CodeTTL: 10 * time.Minute},
+		// zero thresholds disable every check
+		AuthAnomaly:  &config.AuthAnomalyConfig{},
+		Captcha:      &config.CaptchaConfig{},
+		Notification: &config.NotificationConfig{Timeout: time.Second},
+		MagicLink: &config.MagicLinkConfig{
+			Secret: "test-magic-link-secret",
+			URL:    "http://localhost/login/magic",
+			TTL:    15 * time.Minute,
+		},
+		SSO: &config.SSOConfig{
+			RedirectURL: "http://localhost/login/sso",
+			StateTTL:    10 * time.Minute,
+			HTTPTimeout: time.Second,
+		},
+		ReadModel: &config.ReadModelConfig{ProfileTTL: time.Hour},
+		Webhook: &config.WebhookConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     100 * time.Millisecond,
+			PollInterval:   10 * time.Millisecond,
+			RequestTimeout: time.Second,
+			BatchSize:      10,
+		},
+		RateLimit:   &config.RateLimitConfig{},
+		Chaos:       &interceptor.ChaosConfig{},
+		RequestSize: &interceptor.SizeLimitConfig{},
+		CORS:        &cors.Config{},
+		Jobs: &jobs.WorkerConfig{
+			PollInterval:   10 * time.Millisecond,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     100 * time.Millisecond,
+		},
+	}
+}
+
+// NewServer serves the API against pool and redisClient until the test ends.
+// configure, if given, adjusts TestConfig first, e.g. to enable rate limits.
+func NewServer(t *testing.T, pool *pgxpool.Pool, redisClient *redis.Client, configure ...func(*config.Config)) *Server {
+	t.Helper()
+
+	cfg := TestConfig()
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	webhookUseCase := usecase.NewWebhookUseCase(
+		uow.New(pool),
+		postgres.NewWebhookRepository(pool),
+		webhook.NewHTTPSender(cfg.Webhook.RequestTimeout),
+		usecase.WebhookRetryPolicy{
+			MaxAttempts:    cfg.Webhook.MaxAttempts,
+			InitialBackoff: cfg.Webhook.InitialBackoff,
+			MaxBackoff:     cfg.Webhook.MaxBackoff,
+		},
+		cfg.Region,
+	)
+	jobQueue := jobs.New(pool, "jobs")
+
+	// the API screens logins and registrations with it, and the scheduler
+	// looks for spikes
+	authAnomalies := usecase.NewAuthAnomalyDetector(
+		cache.NewAuthActivityRepository(redisClient, "user-service:auth-activity:", nil),
+		geoip.NewHTTPLocator(cfg.LoginRisk.GeoIPURL, cfg.LoginRisk.GeoIPTimeout),
+		captcha.NewHTTPVerifier(cfg.Captcha.VerifyURL, cfg.Captcha.Secret, cfg.Captcha.Timeout),
+		webhookUseCase,
+		usecase.AuthAnomalyPolicy{
+			StuffingWindow:   cfg.AuthAnomaly.StuffingWindow,
+			MaxEmailsPerIP:   cfg.AuthAnomaly.MaxEmailsPerIP,
+			MaxEmailsPerASN:  cfg.AuthAnomaly.MaxEmailsPerASN,
+			SpikeWindow:      cfg.AuthAnomaly.SpikeWindow,
+			BaselineWindows:  cfg.AuthAnomaly.BaselineWindows,
+			SpikeFactor:      cfg.AuthAnomaly.SpikeFactor,
+			MinFailedLogins:  cfg.AuthAnomaly.MinFailedLogins,
+			MinRegistrations: cfg.AuthAnomaly.MinRegistrations,
+			Cooldown:         cfg.AuthAnomaly.Cooldown,
+		},
+	)
+
+	readiness := &health.Readiness{}
+	readiness.SetReady(true)
+	rateLimitConfig := func() *config.RateLimitConfig {
+		return cfg.RateLimit
+	}
+	chaosConfig := func() *interceptor.ChaosConfig {
+		return cfg.Chaos
+	}
+	sizeLimitConfig := func() *interceptor.SizeLimitConfig {
+		return cfg.RequestSize
+	}
+
+	fields, err := encryption.NewLocalFieldCipher(cfg.Encryption)
+	if err != nil {
+		t.Fatalf("failed to create field cipher: %v", err)
+	}
+
+	server, err := connect.StartConnect(cfg, slog.Default(), readiness, rateLimitConfig, chaosConfig, sizeLimitConfig, pool, postgres.NewUserRepositories(pool, nil, fields), fields, redisClient, webhookUseCase, authAnomalies, jobQueue)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	httpServer := httptest.NewServer(server.Handler)
+	t.Cleanup(httpServer.Close)
+
+	return &Server{
+		URL:      httpServer.URL,
+		Config:   cfg,
+		Webhooks: webhookUseCase,
+		Jobs:     jobQueue,
+		t:        t,
+	}
+}
+
+// UserClient returns a user.v1 client sending token as bearer token; an
+// empty token calls anonymously. Retries are off so tests see every error.
+func (s *Server) UserClient(token string) userv1connect.UserServiceClient {
+	return s.factory(token).UserService(s.URL)
+}
+
+// UserClientV2 is UserClient for user.v2.
+func (s *Server) UserClientV2(token string) userv2connect.UserServiceClient {
+	return s.factory(token).UserServiceV2(s.URL)
+}
+
+// WebhookClient is UserClient for the webhook service.
+func (s *Server) WebhookClient(token string) userv1connect.WebhookServiceClient {
+	return s.factory(token).WebhookService(s.URL)
+}
+
+func (s *Server) factory(token string) *client.Factory {
+	s.t.Helper()
+
+	factory, err := client.NewFactory(
+		client.WithStaticToken(token),
+		client.WithRetry(client.RetryPolicy{}),
+	)
+	if err != nil {
+		s.t.Fatalf("failed to create client factory: %v", err)
+	}
+
+	return factory
+}
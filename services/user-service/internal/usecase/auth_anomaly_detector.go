@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+// AuthAnomalyPolicy sets when logins and registrations look like an attack.
+type AuthAnomalyPolicy struct {
+	// StuffingWindow is the window the distinct emails of failed logins are
+	// counted in, per address and per network.
+	StuffingWindow time.Duration
+	// MaxEmailsPerIP and MaxEmailsPerASN are how many distinct emails one
+	// address, or one network, may fail to log in as per StuffingWindow
+	// before it is flagged for credential stuffing. Zero disables the check.
+	MaxEmailsPerIP  int64
+	MaxEmailsPerASN int64
+	// SpikeWindow is the window failed logins and registrations are counted
+	// in. The last complete window is a spike from SpikeFactor times the
+	// average of the BaselineWindows before it, and from at least
+	// MinFailedLogins or MinRegistrations, so quiet hours never trip it.
+	// Zero minimums disable the checks.
+	SpikeWindow      time.Duration
+	BaselineWindows  int
+	SpikeFactor      float64
+	MinFailedLogins  int64
+	MinRegistrations int64
+	// Cooldown is how long an anomaly lasts once raised.
+	Cooldown time.Duration
+}
+
+// AuthSource is where a login or registration comes from.
+type AuthSource struct {
+	IP string
+	// ASN is the network of IP, when geolocation knows it.
+	ASN string
+}
+
+func (s AuthSource) scopes() []string {
+	var scopes []string
+	if s.IP != "" {
+		scopes = append(scopes, "ip:"+s.IP)
+	}
+	if s.ASN != "" {
+		scopes = append(scopes, "asn:"+s.ASN)
+	}
+
+	return scopes
+}
+
+// AuthAnomalyDetector watches failed logins and registrations for attacks:
+// an address or network failing to log in as many different emails, and
+// spikes over the whole service. It alerts with the
+// user.auth_anomaly_detected event, and while an anomaly lasts the logins
+// and registrations it covers have to solve a CAPTCHA. The counts live in
+// the repository, so every replica sees the same attack.
+type AuthAnomalyDetector struct {
+	activity repository.AuthActivityRepository
+	locator  service.GeoLocator
+	captcha  service.CaptchaVerifier
+	events   service.EventPublisher
+	policy   AuthAnomalyPolicy
+
+	// knownSpikes holds, per kind of spike, until when the last successful
+	// check found it ongoing, so a spike outlives the repository failing
+	// midway.
+	mu          sync.Mutex
+	knownSpikes map[entity.AuthAnomalyKind]time.Time
+}
+
+func NewAuthAnomalyDetector(
+	activity repository.AuthActivityRepository,
+	locator service.GeoLocator,
+	captcha service.CaptchaVerifier,
+	events service.EventPublisher,
+	policy AuthAnomalyPolicy,
+) *AuthAnomalyDetector {
+	return &AuthAnomalyDetector{
+		activity: activity,
+		locator:  locator,
+		captcha:  captcha,
+		events:   events,
+		policy:   policy,
+
+		knownSpikes: make(map[entity.AuthAnomalyKind]time.Time),
+	}
+}
+
+// ScreenLogin is called before a login from ip is checked, and returns its
+// source for RecordFailedLogin. It fails with CAPTCHA_REQUIRED while ip or
+// its network is flagged, or failed logins spike, and captchaToken is not a
+// solved CAPTCHA.
+func (d *AuthAnomalyDetector) ScreenLogin(ctx context.Context, ip, captchaToken string) (AuthSource, error) {
+	return d.screen(ctx, entity.AuthAnomalyLoginFailureSpike, ip, captchaToken)
+}
+
+// ScreenRegistration is ScreenLogin for registrations, which need a CAPTCHA
+// while registrations spike instead.
+func (d *AuthAnomalyDetector) ScreenRegistration(ctx context.Context, ip, captchaToken string) (AuthSource, error) {
+	return d.screen(ctx, entity.AuthAnomalyRegistrationSpike, ip, captchaToken)
+}
+
+// screen fails closed during a known spike: when the anomalies or the
+// CAPTCHA cannot be checked then, the attempt needs a CAPTCHA that can be
+// verified. Otherwise an unavailable dependency lets it through, so an outage
+// never locks users out; the tightened rate limits still hold.
+func (d *AuthAnomalyDetector) screen(ctx context.Context, spike entity.AuthAnomalyKind, ip, captchaToken string) (AuthSource, error) {
+	source := AuthSource{IP: ip}
+	if ip != "" {
+		location, err := d.locator.Locate(ctx, ip)
+		if err != nil {
+			log.Printf("failed to locate auth attempt from %s: %v", ip, err)
+		}
+		source.ASN = location.ASN
+	}
+
+	attacked := d.spiking(ctx, spike)
+	if scopes := source.scopes(); !attacked && len(scopes) > 0 {
+		flagged, err := d.activity.HasAnomaly(ctx, scopes...)
+		if err != nil {
+			log.Printf("failed to check auth anomalies of %s: %v", ip, err)
+		}
+		attacked = flagged
+	}
+	if !attacked {
+		return source, nil
+	}
+
+	solved, err := d.captcha.Verify(ctx, captchaToken, ip)
+	if err != nil {
+		log.Printf("failed to verify CAPTCHA from %s: %v", ip, err)
+	}
+	if !solved {
+		return source, domain_error.New(domain_error.ReasonCaptchaRequired)
+	}
+
+	return source, nil
+}
+
+// spiking reports whether a spike of kind is ongoing or, when the repository
+// cannot tell, whether one was the last time it could.
+func (d *AuthAnomalyDetector) spiking(ctx context.Context, kind entity.AuthAnomalyKind) bool {
+	spiking, err := d.activity.HasAnomaly(ctx, string(kind))
+	if err != nil {
+		log.Printf("failed to check %s: %v", kind, err)
+		return d.knownSpike(kind)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if spiking {
+		// it lasts Cooldown at most from now
+		d.knownSpikes[kind] = time.Now().Add(d.policy.Cooldown)
+	} else {
+		delete(d.knownSpikes, kind)
+	}
+
+	return spiking
+}
+
+func (d *AuthAnomalyDetector) knownSpike(kind entity.AuthAnomalyKind) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return time.Now().Before(d.knownSpikes[kind])
+}
+
+// UnderAttack reports whether ip is flagged or logins or registrations
+// spike, for the rate limits to tighten. Unlike the screens it does not
+// locate ip, so flagged networks are only caught by the CAPTCHA. When the
+// repository cannot tell, it reports the spikes known from the screens.
+func (d *AuthAnomalyDetector) UnderAttack(ctx context.Context, ip string) bool {
+	attacked, err := d.activity.HasAnomaly(ctx,
+		"ip:"+ip,
+		string(entity.AuthAnomalyLoginFailureSpike),
+		string(entity.AuthAnomalyRegistrationSpike),
+	)
+	if err != nil {
+		log.Printf("failed to check auth anomalies of %s: %v", ip, err)
+		return d.knownSpike(entity.AuthAnomalyLoginFailureSpike) || d.knownSpike(entity.AuthAnomalyRegistrationSpike)
+	}
+
+	return attacked
+}
+
+// RecordFailedLogin counts a wrong email or password from source, and
+// flags source once it failed to log in as too many distinct emails.
+func (d *AuthAnomalyDetector) RecordFailedLogin(ctx context.Context, source AuthSource, email string) {
+	now := time.Now()
+	d.countAttempt(ctx, entity.AuthAttemptLoginFailed, now)
+
+	if d.policy.StuffingWindow <= 0 {
+		return
+	}
+	if source.IP != "" && d.policy.MaxEmailsPerIP > 0 {
+		d.checkStuffing(ctx, now, "ip:"+source.IP, email, d.policy.MaxEmailsPerIP)
+	}
+	if source.ASN != "" && d.policy.MaxEmailsPerASN > 0 {
+		d.checkStuffing(ctx, now, "asn:"+source.ASN, email, d.policy.MaxEmailsPerASN)
+	}
+}
+
+// RecordRegistration counts a new account.
+func (d *AuthAnomalyDetector) RecordRegistration(ctx context.Context) {
+	d.countAttempt(ctx, entity.AuthAttemptRegistered, time.Now())
+}
+
+func (d *AuthAnomalyDetector) countAttempt(ctx context.Context, kind entity.AuthAttemptKind, now time.Time) {
+	if d.policy.SpikeWindow <= 0 {
+		return
+	}
+
+	// the counts outlive the baseline of the last complete window
+	ttl := time.Duration(d.policy.BaselineWindows+2) * d.policy.SpikeWindow
+	if err := d.activity.CountAttempt(ctx, kind, now.Truncate(d.policy.SpikeWindow), ttl); err != nil {
+		log.Printf("failed to count %s attempt: %v", kind, err)
+	}
+}
+
+func (d *AuthAnomalyDetector) checkStuffing(ctx context.Context, now time.Time, source, email string, threshold int64) {
+	window := now.Truncate(d.policy.StuffingWindow)
+	emails, err := d.activity.AddFailedLogin(ctx, source, email, window, d.policy.StuffingWindow)
+	if err != nil {
+		log.Printf("failed to record failed login from %s: %v", source, err)
+		return
+	}
+	if emails < threshold {
+		return
+	}
+
+	_, err = d.raise(ctx, &entity.AuthAnomaly{
+		Kind:      entity.AuthAnomalyCredentialStuffing,
+		Source:    source,
+		Observed:  emails,
+		Threshold: threshold,
+		ExpiresAt: sharedvo.NewTime(now.Add(d.policy.Cooldown).Unix()),
+	})
+	if err != nil {
+		log.Printf("failed to flag %s: %v", source, err)
+	}
+}
+
+// DetectSpikes compares the last complete window of failed logins and of
+// registrations with the windows before it, and returns the spikes it
+// raised. A spike already raised is not raised again until it expires.
+func (d *AuthAnomalyDetector) DetectSpikes(ctx context.Context) ([]*entity.AuthAnomaly, error) {
+	now := time.Now()
+	checks := []struct {
+		attempt entity.AuthAttemptKind
+		spike   entity.AuthAnomalyKind
+		minimum int64
+	}{
+		{entity.AuthAttemptLoginFailed, entity.AuthAnomalyLoginFailureSpike, d.policy.MinFailedLogins},
+		{entity.AuthAttemptRegistered, entity.AuthAnomalyRegistrationSpike, d.policy.MinRegistrations},
+	}
+
+	var raised []*entity.AuthAnomaly
+	for _, check := range checks {
+		if check.minimum <= 0 || d.policy.BaselineWindows <= 0 || d.policy.SpikeWindow <= 0 {
+			continue
+		}
+
+		anomaly, err := d.detectSpike(ctx, now, check.attempt, check.spike, check.minimum)
+		if err != nil {
+			return raised, err
+		}
+		if anomaly != nil {
+			raised = append(raised, anomaly)
+		}
+	}
+
+	return raised, nil
+}
+
+func (d *AuthAnomalyDetector) detectSpike(ctx context.Context, now time.Time, attempt entity.AuthAttemptKind, spike entity.AuthAnomalyKind, minimum int64) (*entity.AuthAnomaly, error) {
+	// the baseline windows, oldest first, then the last complete window
+	window := d.policy.SpikeWindow
+	last := now.Truncate(window).Add(-window)
+	windows := make([]time.Time, d.policy.BaselineWindows+1)
+	for i := range windows {
+		windows[i] = last.Add(-time.Duration(len(windows)-1-i) * window)
+	}
+
+	counts, err := d.activity.GetAttemptCounts(ctx, attempt, windows)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline int64
+	for _, count := range counts[:len(counts)-1] {
+		baseline += count
+	}
+	average := float64(baseline) / float64(d.policy.BaselineWindows)
+	threshold := max(int64(math.Ceil(average*d.policy.SpikeFactor)), minimum)
+	observed := counts[len(counts)-1]
+	if observed < threshold {
+		return nil, nil
+	}
+
+	anomaly := &entity.AuthAnomaly{
+		Kind:      spike,
+		Observed:  observed,
+		Threshold: threshold,
+		ExpiresAt: sharedvo.NewTime(now.Add(d.policy.Cooldown).Unix()),
+	}
+	raised, err := d.raise(ctx, anomaly)
+	if err != nil || !raised {
+		return nil, err
+	}
+	d.mu.Lock()
+	d.knownSpikes[spike] = anomaly.ExpiresAt.Time()
+	d.mu.Unlock()
+
+	return anomaly, nil
+}
+
+// raise keeps anomaly and alerts of it, unless one of its scope is raised
+// already.
+func (d *AuthAnomalyDetector) raise(ctx context.Context, anomaly *entity.AuthAnomaly) (bool, error) {
+	raised, err := d.activity.RaiseAnomaly(ctx, anomaly)
+	if err != nil || !raised {
+		return false, err
+	}
+
+	var from string
+	if anomaly.Source != "" {
+		from = " from " + anomaly.Source
+	}
+	log.Printf("auth anomaly %s%s: %d against a threshold of %d, until %s",
+		anomaly.Kind, from, anomaly.Observed, anomaly.Threshold, anomaly.ExpiresAt.Time().Format(time.RFC3339))
+	publishEvent(ctx, d.events, entity.NewEvent(entity.EventAuthAnomalyDetected, "", anomaly))
+
+	return true, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const (
+	// backupCodeCount is how many codes a generated set has.
+	backupCodeCount = 10
+	// backupCodeAlphabet leaves out 0, 1, i, l and o, which read alike.
+	backupCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+// BackupCodeUseCase manages the one-time codes users keep for finishing a
+// challenged login when they cannot read the emailed code.
+type BackupCodeUseCase struct {
+	userRepo       repository.UserRepository
+	backupCodeRepo repository.BackupCodeRepository
+	security       *SecurityEventUseCase
+}
+
+func NewBackupCodeUseCase(userRepo repository.UserRepository, backupCodeRepo repository.BackupCodeRepository, security *SecurityEventUseCase) *BackupCodeUseCase {
+	return &BackupCodeUseCase{
+		userRepo:       userRepo,
+		backupCodeRepo: backupCodeRepo,
+		security:       security,
+	}
+}
+
+// GenerateBackupCodes replaces the user's backup codes with a new set and
+// returns it. Only hashes are stored, so this is the one time the codes can
+// be shown. The password is asked again, as with ChangePassword.
+func (u *BackupCodeUseCase) GenerateBackupCodes(ctx context.Context, params dto.GenerateBackupCodesRequest) ([]string, error) {
+	user, err := u.userRepo.GetUserByID(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.Password.CompareHash(params.Password); err != nil {
+		return nil, domain_error.New(domain_error.ReasonInvalidCredentials)
+	}
+
+	codes := make([]string, 0, backupCodeCount)
+	hashes := make([]string, 0, backupCodeCount)
+	for range backupCodeCount {
+		code, err := newBackupCode()
+		if err != nil {
+			return nil, domain_error.NewInternalError(fmt.Sprintf("failed to generate backup code: %s", err.Error()))
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(user.ID, code))
+	}
+
+	if err := u.backupCodeRepo.ReplaceBackupCodes(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+	u.security.Record(ctx, entity.NewSecurityEvent(user.ID, entity.SecurityEventBackupCodesGenerated, params.IP, params.UserAgent))
+
+	return codes, nil
+}
+
+// CountBackupCodes returns how many of the user's backup codes are unused,
+// failing with USER_NOT_FOUND for unknown users.
+func (u *BackupCodeUseCase) CountBackupCodes(ctx context.Context, userID string) (int, error) {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	return u.backupCodeRepo.CountUnusedBackupCodes(ctx, userID)
+}
+
+// Consume uses up the user's backup code, and reports whether it was one
+// of their unused codes. Case, spaces and dashes in code do not matter.
+func (u *BackupCodeUseCase) Consume(ctx context.Context, userID, code string) (bool, error) {
+	if normalizeBackupCode(code) == "" {
+		return false, nil
+	}
+
+	return u.backupCodeRepo.ConsumeBackupCode(ctx, userID, hashBackupCode(userID, code))
+}
+
+// newBackupCode returns a random code of 10 characters, shown as two groups
+// of five.
+func newBackupCode() (string, error) {
+	b := make([]byte, 10)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(backupCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = backupCodeAlphabet[n.Int64()]
+	}
+
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+func normalizeBackupCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// hashBackupCode binds the code to its user, so equal codes of two users
+// hash apart.
+func hashBackupCode(userID, code string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + normalizeBackupCode(code)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+type ConsentUseCase struct {
+	userRepo    repository.UserRepository
+	consentRepo repository.ConsentRepository
+	events      service.EventPublisher
+}
+
+// NewConsentUseCase builds the use case; events receives a
+// user.consents_updated event whenever a user decides on consents.
+func NewConsentUseCase(userRepo repository.UserRepository, consentRepo repository.ConsentRepository, events service.EventPublisher) *ConsentUseCase {
+	return &ConsentUseCase{
+		userRepo:    userRepo,
+		consentRepo: consentRepo,
+		events:      events,
+	}
+}
+
+// GetConsents returns the user's decision on every purpose, with the ones
+// never decided not granted.
+func (u *ConsentUseCase) GetConsents(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	stored, err := u.consentRepo.ListConsents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := entity.DefaultConsents(userID)
+	for _, consent := range ret {
+		for _, s := range stored {
+			if s.Purpose == consent.Purpose {
+				*consent = *s
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// UpdateConsents records the user's decisions and returns the decision on
+// every purpose. Each decision is recorded, even one that repeats the
+// current one, since it may have been made under a newer policy version.
+func (u *ConsentUseCase) UpdateConsents(ctx context.Context, params dto.UpdateConsentsRequest) ([]*entity.Consent, error) {
+	// validate everything before writing so a bad entry doesn't leave a partial update
+	consents := make([]*entity.Consent, 0, len(params.Consents))
+	for _, c := range params.Consents {
+		consent, err := entity.NewConsent(params.UserID, c.Purpose, c.Granted, c.PolicyVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		consents = append(consents, consent)
+	}
+
+	for _, consent := range consents {
+		if err := u.consentRepo.SaveConsent(ctx, consent); err != nil {
+			return nil, err
+		}
+	}
+
+	ret, err := u.GetConsents(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	publishEvent(ctx, u.events, entity.NewEvent(entity.EventConsentsUpdated, params.UserID, entity.ConsentChange{
+		UserID:   params.UserID,
+		Consents: entity.NewConsentState(ret),
+	}))
+
+	return ret, nil
+}
+
+// ListConsentRecords returns every decision of the user, oldest first,
+// failing with USER_NOT_FOUND for unknown users.
+func (u *ConsentUseCase) ListConsentRecords(ctx context.Context, userID string) ([]*entity.Consent, error) {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.consentRepo.ListConsentRecords(ctx, userID)
+}
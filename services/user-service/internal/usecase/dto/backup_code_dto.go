@@ -0,0 +1,10 @@
+package dto
+
+type (
+	GenerateBackupCodesRequest struct {
+		UserID    string `json:"user_id"`
+		Password  string `json:"password"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+	}
+)
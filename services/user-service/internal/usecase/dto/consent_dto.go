@@ -0,0 +1,14 @@
+package dto
+
+type (
+	ConsentDecision struct {
+		Purpose       string `json:"purpose"`
+		Granted       bool   `json:"granted"`
+		PolicyVersion string `json:"policy_version"`
+	}
+
+	UpdateConsentsRequest struct {
+		UserID   string            `json:"user_id"`
+		Consents []ConsentDecision `json:"consents"`
+	}
+)
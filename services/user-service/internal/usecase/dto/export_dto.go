@@ -0,0 +1,6 @@
+package dto
+
+type ExportUsersRequest struct {
+	AfterID   string `json:"after_id"`
+	BatchSize int32  `json:"batch_size"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type (
+	// ImportUser is an account moved over from another system, with the
+	// bcrypt hash of its password there.
+	ImportUser struct {
+		FirstName    string `json:"first_name"`
+		LastName     string `json:"last_name"`
+		Email        string `json:"email"`
+		Phone        string `json:"phone"`
+		PasswordHash string `json:"password_hash"`
+	}
+
+	// ImportUsersArgs are the job arguments of an import.
+	ImportUsersArgs struct {
+		Users []ImportUser `json:"users"`
+	}
+)
@@ -0,0 +1,13 @@
+package dto
+
+type (
+	RequestMagicLinkRequest struct {
+		Email string `json:"email"`
+	}
+
+	ConsumeMagicLinkRequest struct {
+		Token     string `json:"token"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+	}
+)
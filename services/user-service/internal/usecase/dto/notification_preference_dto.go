@@ -0,0 +1,20 @@
+package dto
+
+type (
+	NotificationPreference struct {
+		Channel  string `json:"channel"`
+		Category string `json:"category"`
+		Enabled  bool   `json:"enabled"`
+	}
+
+	UpdateNotificationPreferencesRequest struct {
+		UserID      string                   `json:"user_id"`
+		Preferences []NotificationPreference `json:"preferences"`
+	}
+
+	CheckNotificationAllowedRequest struct {
+		UserID   string `json:"user_id"`
+		Channel  string `json:"channel"`
+		Category string `json:"category"`
+	}
+)
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// PurgeResult reports the purge of one kind of record.
+type PurgeResult struct {
+	Kind string `json:"kind"`
+	// Before is the cutoff; older records were purged.
+	Before time.Time `json:"before"`
+	// Count is how many records were deleted, or would have been in a dry
+	// run.
+	Count  int64 `json:"count"`
+	DryRun bool  `json:"dry_run"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+type (
+	// SCIMFilter is a SCIM filter of the form `attribute eq "value"`, the
+	// only form IdPs use to look resources up.
+	SCIMFilter struct {
+		// Attribute is lower-cased, e.g. "username".
+		Attribute string `json:"attribute"`
+		Value     string `json:"value"`
+	}
+
+	ListSCIMResourcesRequest struct {
+		// Filter is nil to list every resource.
+		Filter *SCIMFilter `json:"filter,omitempty"`
+		// StartIndex is 1-based, as in SCIM.
+		StartIndex int32 `json:"start_index"`
+		Count      int32 `json:"count"`
+	}
+
+	CreateSCIMUserRequest struct {
+		UserName   string `json:"user_name"`
+		GivenName  string `json:"given_name"`
+		FamilyName string `json:"family_name"`
+		ExternalID string `json:"external_id"`
+		Active     bool   `json:"active"`
+	}
+
+	// PatchSCIMUserRequest changes the set fields of a user; a PUT sets all
+	// of them.
+	PatchSCIMUserRequest struct {
+		UserName   *string `json:"user_name,omitempty"`
+		GivenName  *string `json:"given_name,omitempty"`
+		FamilyName *string `json:"family_name,omitempty"`
+		ExternalID *string `json:"external_id,omitempty"`
+		Active     *bool   `json:"active,omitempty"`
+	}
+
+	CreateSCIMGroupRequest struct {
+		DisplayName string   `json:"display_name"`
+		ExternalID  string   `json:"external_id"`
+		Members     []string `json:"members"`
+	}
+
+	PatchSCIMGroupRequest struct {
+		DisplayName *string `json:"display_name,omitempty"`
+		ExternalID  *string `json:"external_id,omitempty"`
+		// MemberOps apply in order.
+		MemberOps []SCIMMemberOp `json:"member_ops,omitempty"`
+	}
+
+	SCIMMemberOp struct {
+		// Op is "add", "remove" or "replace".
+		Op      string   `json:"op"`
+		UserIDs []string `json:"user_ids"`
+	}
+)
@@ -0,0 +1,9 @@
+package dto
+
+type (
+	ListSecurityEventsRequest struct {
+		UserID    string `json:"user_id"`
+		PageSize  int32  `json:"page_size"`
+		PageToken string `json:"page_token"`
+	}
+)
@@ -0,0 +1,22 @@
+package dto
+
+type (
+	CreateSSOConnectionRequest struct {
+		Name         string   `json:"name"`
+		Issuer       string   `json:"issuer"`
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret"`
+		Domains      []string `json:"domains"`
+	}
+
+	StartSSOLoginRequest struct {
+		Email string `json:"email"`
+	}
+
+	FinishSSOLoginRequest struct {
+		State     string `json:"state"`
+		Code      string `json:"code"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+	}
+)
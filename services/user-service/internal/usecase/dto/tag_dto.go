@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type TagRule struct {
+	Tag string `json:"tag"`
+	// Filter selects the users to tag, in the ListUsers filter syntax; empty
+	// matches every user.
+	Filter string `json:"filter"`
+	// InactiveFor, when set, further limits the rule to users whose profile
+	// has not changed for that long.
+	InactiveFor time.Duration `json:"inactive_for"`
+}
+
+type ApplyTagRuleResult struct {
+	Tagged  int   `json:"tagged"`
+	Removed int64 `json:"removed"`
+}
@@ -7,10 +7,58 @@ type (
 		Email     string `json:"email"`
 		Phone     string `json:"phone"`
 		Password  string `json:"password"`
+		// GuestToken, when set, moves the guest's activity to the new user.
+		GuestToken string `json:"guest_token"`
+		// IP is the caller's address, watched for attacks along with
+		// CaptchaToken, which an attack requires.
+		IP           string `json:"ip"`
+		CaptchaToken string `json:"captcha_token"`
 	}
 
 	LoginRequest struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		// IP is the caller's address, checked against the user's usual
+		// login locations.
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+		// CaptchaToken is a solved CAPTCHA, required from callers that look
+		// like an attack.
+		CaptchaToken string `json:"captcha_token"`
+	}
+
+	// VerifyLoginRequest finishes a login challenged with
+	// LOGIN_VERIFICATION_REQUIRED, with either the emailed Code or one of
+	// the user's backup codes.
+	VerifyLoginRequest struct {
+		ChallengeID string `json:"challenge_id"`
+		Code        string `json:"code"`
+		BackupCode  string `json:"backup_code"`
+		UserAgent   string `json:"user_agent"`
+	}
+
+	ChangePasswordRequest struct {
+		UserID      string `json:"user_id"`
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+		IP          string `json:"ip"`
+		UserAgent   string `json:"user_agent"`
+	}
+
+	ListUsersRequest struct {
+		PageSize  int32  `json:"page_size"`
+		PageToken string `json:"page_token"`
+		Filter    string `json:"filter"`
+	}
+
+	// UpdateProfileRequest changes only the fields that are not nil. A
+	// non-zero ExpectedVersion makes the update fail unless the user is still
+	// at that version.
+	UpdateProfileRequest struct {
+		UserID          string  `json:"user_id"`
+		FirstName       *string `json:"first_name,omitempty"`
+		LastName        *string `json:"last_name,omitempty"`
+		Phone           *string `json:"phone,omitempty"`
+		ExpectedVersion int64   `json:"expected_version,omitempty"`
 	}
 )
@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+
+type (
+	CreateWebhookSubscriptionRequest struct {
+		Owner      entity.WebhookOwner `json:"owner"`
+		URL        string              `json:"url"`
+		Secret     string              `json:"secret"`
+		EventTypes []string            `json:"event_types"`
+	}
+
+	ListWebhookDeliveriesRequest struct {
+		Owner          entity.WebhookOwner `json:"owner"`
+		SubscriptionID string              `json:"subscription_id"`
+		Limit          int32               `json:"limit"`
+	}
+)
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const (
+	defaultExportBatchSize = 500
+	maxExportBatchSize     = 1000
+)
+
+type ExportUseCase struct {
+	userRepo repository.UserRepository
+}
+
+func NewExportUseCase(userRepo repository.UserRepository) *ExportUseCase {
+	return &ExportUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// ExportUsers reads users in ID order one batch at a time and passes each
+// batch to send, so memory stays bounded however many users there are. It
+// stops at the first error from the repository or send.
+func (u *ExportUseCase) ExportUsers(ctx context.Context, params dto.ExportUsersRequest, send func([]*entity.User) error) error {
+	batchSize := params.BatchSize
+	if batchSize <= 0 || batchSize > maxExportBatchSize {
+		batchSize = defaultExportBatchSize
+	}
+
+	afterID := params.AfterID
+	for {
+		users, err := u.userRepo.ListUsersAfter(ctx, afterID, batchSize, nil)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		if err := send(users); err != nil {
+			return err
+		}
+
+		if len(users) < int(batchSize) {
+			return nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/jobs"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const (
+	// ImportUsersJobKind is the job kind of ImportUsers operations.
+	ImportUsersJobKind = "import_users"
+
+	maxImportUsers = 1000
+	// a failed import is retried a few times; users created by an earlier
+	// attempt are then reported as EMAIL_ALREADY_EXISTS
+	importUsersMaxAttempts = 3
+)
+
+// ImportUsersResult is the result of an import job.
+type ImportUsersResult struct {
+	Imported int32               `json:"imported"`
+	Failures []ImportUserFailure `json:"failures"`
+}
+
+// ImportUserFailure is why the user at Index of the import was skipped.
+type ImportUserFailure struct {
+	Index   int32               `json:"index"`
+	Reason  domain_error.Reason `json:"reason"`
+	Message string              `json:"message"`
+}
+
+type ImportUseCase struct {
+	userRepo repository.UserRepository
+	queue    *jobs.Queue
+	events   service.EventPublisher
+}
+
+// NewImportUseCase builds the use case; events receives a user.created event
+// for every imported user.
+func NewImportUseCase(userRepo repository.UserRepository, queue *jobs.Queue, events service.EventPublisher) *ImportUseCase {
+	return &ImportUseCase{
+		userRepo: userRepo,
+		queue:    queue,
+		events:   events,
+	}
+}
+
+// StartImport queues the import of users and returns its job, which
+// ImportUsers then runs on a worker.
+func (u *ImportUseCase) StartImport(ctx context.Context, users []dto.ImportUser) (*jobs.Job, error) {
+	if len(users) == 0 || len(users) > maxImportUsers {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("users", fmt.Sprintf("must hold 1 to %d users", maxImportUsers)))
+	}
+
+	id, err := u.queue.Enqueue(ctx, ImportUsersJobKind, dto.ImportUsersArgs{Users: users}, jobs.WithMaxAttempts(importUsersMaxAttempts))
+	if err != nil {
+		return nil, queueError(err)
+	}
+
+	job, err := u.queue.Get(ctx, id)
+	if err != nil {
+		return nil, queueError(err)
+	}
+
+	return job, nil
+}
+
+// ImportUsers is the job handler of ImportUsersJobKind. Users that are
+// invalid or whose email is taken are skipped and reported in the job's
+// ImportUsersResult; other errors fail the attempt so it is retried. It
+// stops between users once ctx is done, e.g. when the import is cancelled.
+func (u *ImportUseCase) ImportUsers(ctx context.Context, job *jobs.Job) error {
+	var args dto.ImportUsersArgs
+	if err := job.UnmarshalArgs(&args); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	result := ImportUsersResult{Failures: []ImportUserFailure{}}
+	for i, item := range args.Users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		user, err := entity.NewImportedUser(item.FirstName, item.LastName, item.Email, item.Phone, item.PasswordHash)
+		if err == nil {
+			user, err = u.userRepo.CreateUser(ctx, user)
+		}
+
+		reason, _ := domain_error.ReasonOf(err)
+		switch {
+		case err == nil:
+			result.Imported++
+			publishUserEvent(ctx, u.events, entity.EventUserCreated, user, entity.NewConsentState(nil))
+		case reason == domain_error.ReasonValidationFailed || reason == domain_error.ReasonEmailAlreadyExists:
+			result.Failures = append(result.Failures, ImportUserFailure{
+				Index:   int32(i),
+				Reason:  reason,
+				Message: failureMessage(err),
+			})
+		default:
+			return fmt.Errorf("failed to import user %d: %w", i, err)
+		}
+	}
+
+	return job.SetResult(result)
+}
+
+// failureMessage is err's message followed by the fields it found invalid.
+func failureMessage(err error) string {
+	violations := domain_error.FieldViolationsOf(err)
+	if len(violations) == 0 {
+		return err.Error()
+	}
+
+	details := make([]string, 0, len(violations))
+	for _, v := range violations {
+		details = append(details, v.Field+": "+v.Description)
+	}
+
+	return err.Error() + " (" + strings.Join(details, "; ") + ")"
+}
+
+func queueError(err error) error {
+	if ctxErr, ok := domain_error.FromContext(err); ok {
+		return ctxErr
+	}
+
+	return domain_error.NewInternalError(err.Error())
+}
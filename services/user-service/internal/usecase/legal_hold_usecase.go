@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+)
+
+// LegalHoldUseCase puts users under legal holds, which exempt their data
+// from the retention purge and stop them from being deleted.
+type LegalHoldUseCase struct {
+	userRepo   repository.UserRepository
+	legalHolds repository.LegalHoldRepository
+}
+
+func NewLegalHoldUseCase(userRepo repository.UserRepository, legalHolds repository.LegalHoldRepository) *LegalHoldUseCase {
+	return &LegalHoldUseCase{
+		userRepo:   userRepo,
+		legalHolds: legalHolds,
+	}
+}
+
+// GetLegalHold returns the user's legal hold, or nil when the user is not
+// under one. It fails with USER_NOT_FOUND for unknown users.
+func (u *LegalHoldUseCase) GetLegalHold(ctx context.Context, userID string) (*entity.LegalHold, error) {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	hold, err := u.legalHolds.GetLegalHold(ctx, userID)
+	if domain_error.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return hold, err
+}
+
+// PlaceLegalHold puts the user under a legal hold, or replaces the reason of
+// the user's hold.
+func (u *LegalHoldUseCase) PlaceLegalHold(ctx context.Context, userID, reason string) (*entity.LegalHold, error) {
+	hold, err := entity.NewLegalHold(userID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := u.legalHolds.SaveLegalHold(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ReleaseLegalHold releases the user's legal hold, so the user's expired
+// data is purged on the next run. Releasing a user who is not under a hold
+// is not an error.
+func (u *LegalHoldUseCase) ReleaseLegalHold(ctx context.Context, userID string) error {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	_, err := u.legalHolds.DeleteLegalHold(ctx, userID)
+	return err
+}
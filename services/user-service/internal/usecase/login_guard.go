@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// maxLoginCodeAttempts is how many wrong codes a challenge takes before it
+// is dropped and the user has to log in again.
+const maxLoginCodeAttempts = 5
+
+// LoginRiskPolicy sets how challenged logins are verified.
+type LoginRiskPolicy struct {
+	// CodeTTL is how long an emailed code can be used.
+	CodeTTL time.Duration
+}
+
+// LoginGuard challenges logins from a country the user never signed in from
+// with a code sent by email, and records them as security events. A backup
+// code answers a challenge as well as the emailed code.
+type LoginGuard struct {
+	locator        service.GeoLocator
+	locationRepo   repository.LoginLocationRepository
+	challengeRepo  repository.LoginChallengeRepository
+	notifier       service.Notifier
+	securityEvents *SecurityEventUseCase
+	backupCodes    *BackupCodeUseCase
+	policy         LoginRiskPolicy
+}
+
+func NewLoginGuard(
+	locator service.GeoLocator,
+	locationRepo repository.LoginLocationRepository,
+	challengeRepo repository.LoginChallengeRepository,
+	notifier service.Notifier,
+	securityEvents *SecurityEventUseCase,
+	backupCodes *BackupCodeUseCase,
+	policy LoginRiskPolicy,
+) *LoginGuard {
+	return &LoginGuard{
+		locator:        locator,
+		locationRepo:   locationRepo,
+		challengeRepo:  challengeRepo,
+		notifier:       notifier,
+		securityEvents: securityEvents,
+		backupCodes:    backupCodes,
+		policy:         policy,
+	}
+}
+
+// Check is called once user entered the right password from ip. It records
+// the login and lets it through, or fails with LOGIN_VERIFICATION_REQUIRED
+// carrying the challenge_id to answer with Verify. The first located login
+// of a user only records its country, and logins that cannot be located
+// are let through, so an unavailable lookup never locks users out.
+func (g *LoginGuard) Check(ctx context.Context, user *entity.User, ip, userAgent string) error {
+	location, err := g.locator.Locate(ctx, ip)
+	if err != nil {
+		log.Printf("failed to locate login of user %s from %s: %v", user.ID, ip, err)
+		return nil
+	}
+	if !location.Known() {
+		return nil
+	}
+
+	known, err := g.locationRepo.ListLoginLocations(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if len(known) == 0 || hasCountry(known, location.Country) {
+		return g.locationRepo.SaveLoginLocation(ctx, entity.NewLoginLocation(user.ID, ip, location))
+	}
+
+	return g.challenge(ctx, user, ip, userAgent, location)
+}
+
+func (g *LoginGuard) challenge(ctx context.Context, user *entity.User, ip, userAgent string, location entity.Location) error {
+	code, err := newLoginCode()
+	if err != nil {
+		return domain_error.NewInternalError(fmt.Sprintf("failed to generate login code: %s", err.Error()))
+	}
+
+	challenge := &entity.LoginChallenge{
+		ID:        utils.NewUUID(),
+		UserID:    user.ID,
+		IP:        ip,
+		Location:  location,
+		ExpiresAt: sharedvo.NewTime(utils.TimeNow() + int64(g.policy.CodeTTL.Seconds())),
+	}
+	challenge.CodeHash = hashLoginCode(challenge.ID, code)
+	if err := g.challengeRepo.CreateChallenge(ctx, challenge, g.policy.CodeTTL); err != nil {
+		return err
+	}
+
+	// without the email the user cannot finish, so this one fails the login;
+	// the code goes to the notifier rather than out as an event, which would
+	// be stored with the webhook deliveries
+	err = g.notifier.SendLoginCode(ctx, &entity.LoginCode{
+		UserID:    user.ID,
+		Email:     user.Email.String(),
+		Code:      code,
+		ExpiresAt: challenge.ExpiresAt,
+	})
+	if err != nil {
+		return domain_error.NewInternalError(fmt.Sprintf("failed to send login code: %s", err.Error()))
+	}
+
+	event := entity.NewSecurityEvent(user.ID, entity.SecurityEventUnusualLoginLocation, ip, userAgent)
+	event.Location = &location
+	g.securityEvents.Record(ctx, event)
+
+	return domain_error.New(
+		domain_error.ReasonLoginVerificationRequired,
+		domain_error.WithMetadata("challenge_id", challenge.ID),
+	)
+}
+
+// Verify checks the emailed code or a backup code of a challenge and
+// returns it, for its user to be logged in, recording the challenged
+// location as known. Each code is used once; wrong, expired and used codes
+// all fail with INVALID_VERIFICATION_CODE, and wrong backup codes count
+// toward the attempts of the challenge like wrong emailed codes. The
+// attempt is counted before the code is checked, so guesses sent in
+// parallel cannot get past maxLoginCodeAttempts.
+func (g *LoginGuard) Verify(ctx context.Context, params dto.VerifyLoginRequest) (*entity.LoginChallenge, error) {
+	invalid := domain_error.New(domain_error.ReasonInvalidVerificationCode)
+	if params.ChallengeID == "" || (params.Code == "") == (params.BackupCode == "") {
+		return nil, invalid
+	}
+
+	challenge, err := g.challengeRepo.ReserveAttempt(ctx, params.ChallengeID)
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if challenge.Attempts > maxLoginCodeAttempts {
+		if _, err := g.challengeRepo.DeleteChallenge(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, invalid
+	}
+
+	var valid bool
+	if params.BackupCode != "" {
+		valid, err = g.backupCodes.Consume(ctx, challenge.UserID, params.BackupCode)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		valid = subtle.ConstantTimeCompare([]byte(hashLoginCode(challenge.ID, params.Code)), []byte(challenge.CodeHash)) == 1
+	}
+	if !valid {
+		if challenge.Attempts >= maxLoginCodeAttempts {
+			if _, err := g.challengeRepo.DeleteChallenge(ctx, challenge.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, invalid
+	}
+
+	// only the caller that deletes the challenge may use it
+	deleted, err := g.challengeRepo.DeleteChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, invalid
+	}
+
+	location := entity.NewLoginLocation(challenge.UserID, challenge.IP, challenge.Location)
+	if err := g.locationRepo.SaveLoginLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	if params.BackupCode != "" {
+		g.securityEvents.Record(ctx, entity.NewSecurityEvent(challenge.UserID, entity.SecurityEventBackupCodeUsed, challenge.IP, params.UserAgent))
+	}
+
+	return challenge, nil
+}
+
+func hasCountry(locations []*entity.LoginLocation, country string) bool {
+	for _, location := range locations {
+		if location.Country == country {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newLoginCode returns a random 6-digit code.
+func newLoginCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashLoginCode binds the code to its challenge, so a hash is no use for
+// another challenge.
+func hashLoginCode(challengeID, code string) string {
+	sum := sha256.Sum256([]byte(challengeID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
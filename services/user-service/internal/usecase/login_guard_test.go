@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// fakeChallengeRepo keeps challenges in memory, counting attempts under a
+// lock as the Redis script does.
+type fakeChallengeRepo struct {
+	mu         sync.Mutex
+	challenges map[string]entity.LoginChallenge
+}
+
+func (r *fakeChallengeRepo) CreateChallenge(_ context.Context, challenge *entity.LoginChallenge, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[challenge.ID] = *challenge
+
+	return nil
+}
+
+func (r *fakeChallengeRepo) ReserveAttempt(_ context.Context, id string) (*entity.LoginChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return nil, domain_error.NewNotFoundError("login challenge not found")
+	}
+	challenge.Attempts++
+	r.challenges[id] = challenge
+
+	return &challenge, nil
+}
+
+func (r *fakeChallengeRepo) DeleteChallenge(_ context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.challenges[id]
+	delete(r.challenges, id)
+
+	return ok, nil
+}
+
+// fakeBackupCodeRepo counts the backup codes checked, none of which match.
+type fakeBackupCodeRepo struct {
+	repository.BackupCodeRepository
+
+	consumed atomic.Int32
+}
+
+func (r *fakeBackupCodeRepo) ConsumeBackupCode(context.Context, string, string) (bool, error) {
+	r.consumed.Add(1)
+	return false, nil
+}
+
+// fakeLocationRepo knows every user from Vietnam.
+type fakeLocationRepo struct {
+	repository.LoginLocationRepository
+}
+
+func (fakeLocationRepo) ListLoginLocations(_ context.Context, userID string) ([]*entity.LoginLocation, error) {
+	return []*entity.LoginLocation{{UserID: userID, Country: "VN"}}, nil
+}
+
+func (fakeLocationRepo) SaveLoginLocation(context.Context, *entity.LoginLocation) error {
+	return nil
+}
+
+// fixedLocator places every address in country.
+type fixedLocator struct {
+	country string
+}
+
+func (l fixedLocator) Locate(context.Context, string) (entity.Location, error) {
+	return entity.Location{Country: l.country}, nil
+}
+
+// recordedNotifier keeps the sent login codes.
+type recordedNotifier struct {
+	mu    sync.Mutex
+	codes []entity.LoginCode
+}
+
+func (n *recordedNotifier) SendLoginCode(_ context.Context, code *entity.LoginCode) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.codes = append(n.codes, *code)
+
+	return nil
+}
+
+func (n *recordedNotifier) SendMagicLink(context.Context, *entity.MagicLinkEmail) error {
+	return nil
+}
+
+type fakeSecurityEventRepo struct {
+	repository.SecurityEventRepository
+}
+
+func (fakeSecurityEventRepo) SaveSecurityEvent(context.Context, *entity.SecurityEvent) error {
+	return nil
+}
+
+// publishedEvents keeps the published events.
+type publishedEvents struct {
+	mu     sync.Mutex
+	events []*entity.Event
+}
+
+func (p *publishedEvents) Publish(_ context.Context, event *entity.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func newTestLoginGuard(t *testing.T, code string) (*LoginGuard, *fakeBackupCodeRepo) {
+	t.Helper()
+
+	challenges := &fakeChallengeRepo{challenges: map[string]entity.LoginChallenge{}}
+	challenge := &entity.LoginChallenge{
+		ID:       "challenge-1",
+		UserID:   "user-1",
+		CodeHash: hashLoginCode("challenge-1", code),
+		Location: entity.Location{Country: "VN"},
+	}
+	if err := challenges.CreateChallenge(context.Background(), challenge, time.Minute); err != nil {
+		t.Fatalf("CreateChallenge: %v", err)
+	}
+
+	backupCodes := &fakeBackupCodeRepo{}
+	guard := NewLoginGuard(nil, fakeLocationRepo{}, challenges, nil, nil,
+		NewBackupCodeUseCase(nil, backupCodes, nil), LoginRiskPolicy{CodeTTL: time.Minute})
+
+	return guard, backupCodes
+}
+
+// verifyConcurrently sends every request to Verify at once and returns how
+// many were let through.
+func verifyConcurrently(guard *LoginGuard, requests []dto.VerifyLoginRequest) int {
+	var (
+		wg        sync.WaitGroup
+		start     = make(chan struct{})
+		succeeded atomic.Int32
+	)
+	for _, req := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := guard.Verify(context.Background(), req); err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return int(succeeded.Load())
+}
+
+func TestLoginGuardCheckSendsCodeToNotifier(t *testing.T) {
+	ctx := context.Background()
+	challenges := &fakeChallengeRepo{challenges: map[string]entity.LoginChallenge{}}
+	notifier := &recordedNotifier{}
+	events := &publishedEvents{}
+	guard := NewLoginGuard(fixedLocator{country: "US"}, fakeLocationRepo{}, challenges, notifier,
+		NewSecurityEventUseCase(nil, fakeSecurityEventRepo{}, events), nil, LoginRiskPolicy{CodeTTL: time.Minute})
+
+	user := &entity.User{ID: "user-1", Email: sharedvo.NewEmail("john@example.com")}
+	err := guard.Check(ctx, user, "203.0.113.7", "test-agent")
+	if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonLoginVerificationRequired {
+		t.Fatalf("Check from a new country = %v, want %s", err, domain_error.ReasonLoginVerificationRequired)
+	}
+
+	if len(notifier.codes) != 1 || notifier.codes[0].Email != "john@example.com" || len(notifier.codes[0].Code) != 6 {
+		t.Fatalf("sent login codes %+v, want one 6-digit code to john@example.com", notifier.codes)
+	}
+	for _, event := range events.events {
+		if event.Type != entity.EventSecurityAlert {
+			t.Errorf("published a %s event, want only %s", event.Type, entity.EventSecurityAlert)
+		}
+	}
+
+	var challengeID string
+	for id := range challenges.challenges {
+		challengeID = id
+	}
+	if _, err := guard.Verify(ctx, dto.VerifyLoginRequest{ChallengeID: challengeID, Code: notifier.codes[0].Code}); err != nil {
+		t.Errorf("Verify of the sent code: %v", err)
+	}
+}
+
+func TestLoginGuardVerifyConcurrentGuesses(t *testing.T) {
+	t.Run("checks no more codes than the attempt limit", func(t *testing.T) {
+		guard, backupCodes := newTestLoginGuard(t, "123456")
+
+		requests := make([]dto.VerifyLoginRequest, 4*maxLoginCodeAttempts)
+		for i := range requests {
+			requests[i] = dto.VerifyLoginRequest{ChallengeID: "challenge-1", BackupCode: "wrong-code"}
+		}
+		if n := verifyConcurrently(guard, requests); n != 0 {
+			t.Fatalf("%d wrong backup codes were let through, want 0", n)
+		}
+		if n := backupCodes.consumed.Load(); n > maxLoginCodeAttempts {
+			t.Errorf("%d backup codes were checked, want at most %d", n, maxLoginCodeAttempts)
+		}
+
+		_, err := guard.Verify(context.Background(), dto.VerifyLoginRequest{ChallengeID: "challenge-1", Code: "123456"})
+		if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonInvalidVerificationCode {
+			t.Errorf("Verify of the right code after the limit = %v, want %s", err, domain_error.ReasonInvalidVerificationCode)
+		}
+	})
+
+	t.Run("lets the right code through once", func(t *testing.T) {
+		guard, _ := newTestLoginGuard(t, "123456")
+
+		requests := make([]dto.VerifyLoginRequest, maxLoginCodeAttempts)
+		for i := range requests {
+			requests[i] = dto.VerifyLoginRequest{ChallengeID: "challenge-1", Code: "123456"}
+		}
+		if n := verifyConcurrently(guard, requests); n != 1 {
+			t.Errorf("the right code was let through %d times, want once", n)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/auth"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/memory"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+type fakeMagicLinkRepo struct {
+	mu    sync.Mutex
+	links map[string]entity.MagicLink
+}
+
+func (r *fakeMagicLinkRepo) CreateMagicLink(_ context.Context, link *entity.MagicLink, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links[link.ID] = *link
+
+	return nil
+}
+
+func (r *fakeMagicLinkRepo) TakeMagicLink(_ context.Context, id string) (*entity.MagicLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	link, ok := r.links[id]
+	if !ok {
+		return nil, domain_error.NewNotFoundError("magic link not found")
+	}
+	delete(r.links, id)
+
+	return &link, nil
+}
+
+// sentLinks keeps the sign-in links sent through the notifier.
+type sentLinks struct {
+	mu    sync.Mutex
+	links []entity.MagicLinkEmail
+}
+
+func (s *sentLinks) SendLoginCode(context.Context, *entity.LoginCode) error {
+	return nil
+}
+
+func (s *sentLinks) SendMagicLink(_ context.Context, link *entity.MagicLinkEmail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = append(s.links, *link)
+
+	return nil
+}
+
+func TestRequestMagicLink(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	authService := auth.NewFakeService()
+	events := &recordedEvents{}
+	notifier := &sentLinks{}
+	security := usecase.NewSecurityEventUseCase(users, nil, events)
+	sso := usecase.NewSSOUseCase(users, noSSOConnections{}, nil, nil, nil, authService, events, security, usecase.SSOPolicy{})
+	magicLinks := usecase.NewMagicLinkUseCase(users, &fakeMagicLinkRepo{links: map[string]entity.MagicLink{}}, authService, notifier, security, sso,
+		usecase.MagicLinkPolicy{Secret: []byte("test-secret"), URL: "http://localhost/login/magic", TTL: time.Minute})
+
+	user, err := entity.NewUser("John", "Doe", "john@example.com", "0901234567", "Secret123!")
+	if err != nil {
+		t.Fatalf("NewUser: %v", err)
+	}
+	if _, err := users.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	if err := magicLinks.RequestMagicLink(ctx, dto.RequestMagicLinkRequest{Email: "john@example.com"}); err != nil {
+		t.Fatalf("RequestMagicLink: %v", err)
+	}
+	if len(notifier.links) != 1 || notifier.links[0].Email != "john@example.com" {
+		t.Fatalf("sent links %+v, want one to john@example.com", notifier.links)
+	}
+	if got := events.types(); len(got) != 0 {
+		t.Errorf("published %v, want no events carrying the link", got)
+	}
+
+	link, err := url.Parse(notifier.links[0].URL)
+	if err != nil {
+		t.Fatalf("failed to parse the sent link: %v", err)
+	}
+	tokens, err := magicLinks.ConsumeMagicLink(ctx, dto.ConsumeMagicLinkRequest{Token: link.Query().Get("token")})
+	if err != nil {
+		t.Fatalf("ConsumeMagicLink of the sent link: %v", err)
+	}
+	if claims, err := authService.ValidateToken(tokens.AccessToken, nil); err != nil || claims.UserID != user.ID {
+		t.Errorf("ConsumeMagicLink signed in %+v (%v), want user %s", claims, err, user.ID)
+	}
+}
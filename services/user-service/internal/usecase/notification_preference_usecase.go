@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	valueobject "github.com/phongloihong/go-shop/services/user-service/internal/domain/valueObject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+type NotificationPreferenceUseCase struct {
+	unitOfWork  repository.UserUnitOfWork
+	prefRepo    repository.NotificationPreferenceRepository
+	consentRepo repository.ConsentRepository
+}
+
+func NewNotificationPreferenceUseCase(unitOfWork repository.UserUnitOfWork, prefRepo repository.NotificationPreferenceRepository, consentRepo repository.ConsentRepository) *NotificationPreferenceUseCase {
+	return &NotificationPreferenceUseCase{
+		unitOfWork:  unitOfWork,
+		prefRepo:    prefRepo,
+		consentRepo: consentRepo,
+	}
+}
+
+// GetPreferences returns every channel/category combination for the user,
+// filling the ones never stored with their defaults.
+func (u *NotificationPreferenceUseCase) GetPreferences(ctx context.Context, userID string) ([]*entity.NotificationPreference, error) {
+	stored, err := u.prefRepo.GetPreferencesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := entity.DefaultNotificationPreferences(userID)
+	for _, pref := range ret {
+		for _, s := range stored {
+			if s.Channel == pref.Channel && s.Category == pref.Category {
+				pref.Enabled = s.Enabled
+				pref.UpdatedAt = s.UpdatedAt
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// UpdatePreferences stores all of params.Preferences or, when one is invalid
+// or a write fails, none of them.
+func (u *NotificationPreferenceUseCase) UpdatePreferences(ctx context.Context, params dto.UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreference, error) {
+	// validate everything before opening the transaction
+	prefs := make([]*entity.NotificationPreference, 0, len(params.Preferences))
+	for _, p := range params.Preferences {
+		pref, err := entity.NewNotificationPreference(params.UserID, p.Channel, p.Category, p.Enabled)
+		if err != nil {
+			return nil, domain_error.NewInvalidData(err.Error())
+		}
+
+		prefs = append(prefs, pref)
+	}
+
+	err := u.unitOfWork.Do(ctx, params.UserID, func(ctx context.Context) error {
+		for _, pref := range prefs {
+			if err := u.prefRepo.UpsertPreference(ctx, pref); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetPreferences(ctx, params.UserID)
+}
+
+// IsAllowed reports whether a notification of the given category may be sent
+// to the user over the given channel. Marketing also needs the user's
+// consent to marketing over the channel, whatever the preference says.
+func (u *NotificationPreferenceUseCase) IsAllowed(ctx context.Context, params dto.CheckNotificationAllowedRequest) (bool, error) {
+	check, err := entity.NewNotificationPreference(params.UserID, params.Channel, params.Category, false)
+	if err != nil {
+		return false, domain_error.NewInvalidData(err.Error())
+	}
+
+	if check.Category == valueobject.CategoryMarketing {
+		consents, err := u.consentRepo.ListConsents(ctx, params.UserID)
+		if err != nil {
+			return false, err
+		}
+		if !entity.NewConsentState(consents)[valueobject.MarketingConsent(check.Channel)] {
+			return false, nil
+		}
+	}
+
+	pref, err := u.prefRepo.GetPreference(ctx, params.UserID, params.Channel, params.Category)
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return check.Category.DefaultEnabled(), nil
+		}
+
+		return false, err
+	}
+
+	return pref.Enabled, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+)
+
+// EventPublishers hands each event to every publisher in turn, e.g. to the
+// webhooks and to the projections of the read models. One failing does not
+// stop the others.
+type EventPublishers []service.EventPublisher
+
+func (p EventPublishers) Publish(ctx context.Context, event *entity.Event) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ProfileProjection keeps the profile read model up to date from the
+// user.created, user.updated and user.deleted events.
+type ProfileProjection struct {
+	profiles repository.ProfileReadModel
+}
+
+func NewProfileProjection(profiles repository.ProfileReadModel) *ProfileProjection {
+	return &ProfileProjection{profiles: profiles}
+}
+
+func (p *ProfileProjection) Publish(ctx context.Context, event *entity.Event) error {
+	switch event.Type {
+	case entity.EventUserCreated, entity.EventUserUpdated:
+		data, ok := event.Data.(entity.UserEventData)
+		if !ok || data.User == nil {
+			return nil
+		}
+		return p.profiles.SaveProfile(ctx, data.User)
+	case entity.EventUserDeleted:
+		return p.profiles.DeleteProfile(ctx, event.Subject)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// RetentionPolicy sets how long each kind of personal data is kept before
+// PurgeExpired deletes it. Zero keeps that kind forever.
+type RetentionPolicy struct {
+	// SecurityEvents is the account audit log, by when each event happened.
+	SecurityEvents time.Duration
+	// LoginLocations is the login history, by when each country was last
+	// signed in from.
+	LoginLocations time.Duration
+}
+
+// retentionTarget is a kind of record PurgeExpired deletes by age.
+type retentionTarget struct {
+	name      string
+	retention time.Duration
+	count     func(ctx context.Context, before time.Time) (int64, error)
+	delete    func(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RetentionUseCase deletes personal data once it is past its retention
+// period. Users under a legal hold are exempt; see LegalHoldUseCase.
+type RetentionUseCase struct {
+	targets []retentionTarget
+}
+
+func NewRetentionUseCase(
+	securityEventRepo repository.SecurityEventRepository,
+	loginLocationRepo repository.LoginLocationRepository,
+	policy RetentionPolicy,
+) *RetentionUseCase {
+	return &RetentionUseCase{
+		targets: []retentionTarget{
+			{
+				name:      "security_events",
+				retention: policy.SecurityEvents,
+				count:     securityEventRepo.CountExpiredSecurityEvents,
+				delete:    securityEventRepo.DeleteExpiredSecurityEvents,
+			},
+			{
+				name:      "login_locations",
+				retention: policy.LoginLocations,
+				count:     loginLocationRepo.CountExpiredLoginLocations,
+				delete:    loginLocationRepo.DeleteExpiredLoginLocations,
+			},
+		},
+	}
+}
+
+// PurgeExpired deletes the records past their retention period, except
+// those of users under a legal hold, and reports how many of each kind it
+// deleted. With dryRun it only counts them. Kinds kept forever are left out
+// of the report.
+func (u *RetentionUseCase) PurgeExpired(ctx context.Context, dryRun bool) ([]dto.PurgeResult, error) {
+	now := time.Now()
+
+	results := make([]dto.PurgeResult, 0, len(u.targets))
+	for _, target := range u.targets {
+		if target.retention <= 0 {
+			continue
+		}
+
+		before := now.Add(-target.retention)
+		purge := target.delete
+		if dryRun {
+			purge = target.count
+		}
+
+		n, err := purge(ctx, before)
+		if err != nil {
+			return results, fmt.Errorf("purge %s: %w", target.name, err)
+		}
+		results = append(results, dto.PurgeResult{
+			Kind:   target.name,
+			Before: before,
+			Count:  n,
+			DryRun: dryRun,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const (
+	// maxSCIMPageSize bounds the count of SCIM list requests.
+	maxSCIMPageSize = 100
+	// scimTokenPrefix marks SCIM tokens, so leaked ones are easy to spot.
+	scimTokenPrefix = "scim_"
+)
+
+// ProvisionedUser is a user with its SCIM provisioning state.
+type ProvisionedUser struct {
+	User *entity.User
+	SCIM *entity.SCIMUser
+}
+
+// SCIMUseCase lets the IdP of an SSO connection provision, update and
+// deprovision the users of its domains, and keep groups of them. Every call
+// acts within one connection, the one the IdP's SCIM token belongs to.
+type SCIMUseCase struct {
+	userRepo    repository.UserRepository
+	userUseCase *UserUseCase
+	connRepo    repository.SSOConnectionRepository
+	scimRepo    repository.SCIMRepository
+	events      service.EventPublisher
+}
+
+// NewSCIMUseCase builds the use case; profile changes and deletions go
+// through userUseCase, so they publish the same events as any other.
+func NewSCIMUseCase(
+	userRepo repository.UserRepository,
+	userUseCase *UserUseCase,
+	connRepo repository.SSOConnectionRepository,
+	scimRepo repository.SCIMRepository,
+	events service.EventPublisher,
+) *SCIMUseCase {
+	return &SCIMUseCase{
+		userRepo:    userRepo,
+		userUseCase: userUseCase,
+		connRepo:    connRepo,
+		scimRepo:    scimRepo,
+		events:      events,
+	}
+}
+
+// Authenticate returns the connection of a SCIM bearer token.
+func (u *SCIMUseCase) Authenticate(ctx context.Context, token string) (*entity.SSOConnection, error) {
+	if token == "" {
+		return nil, domain_error.New(domain_error.ReasonInvalidAccessToken)
+	}
+
+	conn, err := u.connRepo.GetConnectionBySCIMToken(ctx, hashSCIMToken(token))
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return nil, domain_error.New(domain_error.ReasonInvalidAccessToken)
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// CreateUser provisions the user with the email params.UserName. A user who
+// registered before the organization moved to SSO is taken over rather than
+// duplicated.
+func (u *SCIMUseCase) CreateUser(ctx context.Context, conn *entity.SSOConnection, params dto.CreateSCIMUserRequest) (*ProvisionedUser, error) {
+	email := sharedvo.NewEmail(params.UserName)
+	if !slices.Contains(conn.Domains, email.Domain()) {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("userName", "userName must be an email in the organization's domains"))
+	}
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email.String())
+	switch {
+	case err == nil:
+		if _, err := u.scimRepo.GetUser(ctx, user.ID); err == nil {
+			return nil, domain_error.New(domain_error.ReasonAlreadyExists, domain_error.WithMessage(fmt.Sprintf("user %s is already provisioned", email.String())))
+		} else if !domain_error.IsNotFound(err) {
+			return nil, err
+		}
+		if user.FirstName != params.GivenName || user.LastName != params.FamilyName {
+			user, err = u.userUseCase.UpdateProfile(ctx, dto.UpdateProfileRequest{
+				UserID:    user.ID,
+				FirstName: &params.GivenName,
+				LastName:  &params.FamilyName,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	case domain_error.IsNotFound(err):
+		user, err = createSSOUser(ctx, u.userRepo, u.events, email.String(), params.GivenName, params.FamilyName)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	now := sharedvo.NewTime(utils.TimeNow())
+	scimUser := &entity.SCIMUser{
+		UserID:       user.ID,
+		ConnectionID: conn.ID,
+		ExternalID:   params.ExternalID,
+		Active:       params.Active,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := u.scimRepo.CreateUser(ctx, scimUser); err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedUser{User: user, SCIM: scimUser}, nil
+}
+
+// GetUser fails with USER_NOT_FOUND for users the connection did not
+// provision.
+func (u *SCIMUseCase) GetUser(ctx context.Context, conn *entity.SSOConnection, id string) (*ProvisionedUser, error) {
+	scimUser, err := u.scimUser(ctx, conn, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedUser{User: user, SCIM: scimUser}, nil
+}
+
+// ListUsers returns a page of the connection's users, with the number of
+// users matching params.Filter. The filter takes userName or externalId.
+func (u *SCIMUseCase) ListUsers(ctx context.Context, conn *entity.SSOConnection, params dto.ListSCIMResourcesRequest) ([]*ProvisionedUser, int64, error) {
+	if params.Filter != nil {
+		var (
+			user *ProvisionedUser
+			err  error
+		)
+		switch params.Filter.Attribute {
+		case "username":
+			user, err = u.getUserByEmail(ctx, conn, params.Filter.Value)
+		case "externalid":
+			user, err = u.getUserByExternalID(ctx, conn, params.Filter.Value)
+		default:
+			return nil, 0, unsupportedSCIMFilter(params.Filter)
+		}
+		return singleSCIMResult(user, err)
+	}
+
+	offset, limit := scimPage(params)
+	scimUsers, total, err := u.scimRepo.ListUsers(ctx, conn.ID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(scimUsers) == 0 {
+		return nil, total, nil
+	}
+
+	ids := make([]string, 0, len(scimUsers))
+	for _, scimUser := range scimUsers {
+		ids = append(ids, scimUser.UserID)
+	}
+	users, err := u.userRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[string]*entity.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	ret := make([]*ProvisionedUser, 0, len(scimUsers))
+	for _, scimUser := range scimUsers {
+		// deleted by an admin rather than the IdP
+		if user, ok := byID[scimUser.UserID]; ok {
+			ret = append(ret, &ProvisionedUser{User: user, SCIM: scimUser})
+		}
+	}
+
+	return ret, total, nil
+}
+
+// PatchUser applies the set fields of params. The email cannot change, as
+// it ties the user to the organization.
+func (u *SCIMUseCase) PatchUser(ctx context.Context, conn *entity.SSOConnection, id string, params dto.PatchSCIMUserRequest) (*ProvisionedUser, error) {
+	current, err := u.GetUser(ctx, conn, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.UserName != nil && sharedvo.NewEmail(*params.UserName) != current.User.Email {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("userName", "userName cannot be changed"))
+	}
+
+	if (params.GivenName != nil && *params.GivenName != current.User.FirstName) ||
+		(params.FamilyName != nil && *params.FamilyName != current.User.LastName) {
+		current.User, err = u.userUseCase.UpdateProfile(ctx, dto.UpdateProfileRequest{
+			UserID:    id,
+			FirstName: params.GivenName,
+			LastName:  params.FamilyName,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changed := false
+	if params.ExternalID != nil && *params.ExternalID != current.SCIM.ExternalID {
+		current.SCIM.ExternalID = *params.ExternalID
+		changed = true
+	}
+	if params.Active != nil && *params.Active != current.SCIM.Active {
+		current.SCIM.Active = *params.Active
+		changed = true
+	}
+	if changed {
+		current.SCIM.UpdatedAt = sharedvo.NewTime(utils.TimeNow())
+		if err := u.scimRepo.UpdateUser(ctx, current.SCIM); err != nil {
+			return nil, err
+		}
+	}
+
+	return current, nil
+}
+
+// DeleteUser deletes the user, for IdPs that deprovision by deleting rather
+// than deactivating. A user under a legal hold is deactivated instead, so
+// the IdP's deprovisioning still takes effect.
+func (u *SCIMUseCase) DeleteUser(ctx context.Context, conn *entity.SSOConnection, id string) error {
+	scimUser, err := u.scimUser(ctx, conn, id)
+	if err != nil {
+		return err
+	}
+
+	// an admin may have deleted the user already
+	err = u.userUseCase.DeleteUser(ctx, id)
+	if reason, _ := domain_error.ReasonOf(err); reason == domain_error.ReasonUserUnderLegalHold {
+		scimUser.Active = false
+		scimUser.UpdatedAt = sharedvo.NewTime(utils.TimeNow())
+		return u.scimRepo.UpdateUser(ctx, scimUser)
+	}
+	if err != nil && !domain_error.IsNotFound(err) {
+		return err
+	}
+
+	return u.scimRepo.DeleteUser(ctx, id)
+}
+
+func (u *SCIMUseCase) CreateGroup(ctx context.Context, conn *entity.SSOConnection, params dto.CreateSCIMGroupRequest) (*entity.SCIMGroup, error) {
+	members, err := u.checkMembers(ctx, conn, params.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := entity.NewSCIMGroup(conn.ID, params.DisplayName, params.ExternalID, members)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.scimRepo.CreateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (u *SCIMUseCase) GetGroup(ctx context.Context, conn *entity.SSOConnection, id string) (*entity.SCIMGroup, error) {
+	if uuid.Validate(id) != nil {
+		return nil, domain_error.NewNotFoundError(fmt.Sprintf("group %s not found", id))
+	}
+
+	return u.scimRepo.GetGroup(ctx, conn.ID, id)
+}
+
+// ListGroups returns a page of the connection's groups, with the number of
+// groups matching params.Filter. The filter takes displayName or externalId.
+func (u *SCIMUseCase) ListGroups(ctx context.Context, conn *entity.SSOConnection, params dto.ListSCIMResourcesRequest) ([]*entity.SCIMGroup, int64, error) {
+	if params.Filter != nil {
+		var (
+			group *entity.SCIMGroup
+			err   error
+		)
+		switch params.Filter.Attribute {
+		case "displayname":
+			group, err = u.scimRepo.GetGroupByDisplayName(ctx, conn.ID, params.Filter.Value)
+		case "externalid":
+			group, err = u.scimRepo.GetGroupByExternalID(ctx, conn.ID, params.Filter.Value)
+		default:
+			return nil, 0, unsupportedSCIMFilter(params.Filter)
+		}
+		return singleSCIMResult(group, err)
+	}
+
+	offset, limit := scimPage(params)
+	return u.scimRepo.ListGroups(ctx, conn.ID, offset, limit)
+}
+
+// PatchGroup renames the group and changes its members. Members can only
+// be users the connection provisioned.
+func (u *SCIMUseCase) PatchGroup(ctx context.Context, conn *entity.SSOConnection, id string, params dto.PatchSCIMGroupRequest) (*entity.SCIMGroup, error) {
+	group, err := u.GetGroup(ctx, conn, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.DisplayName != nil || params.ExternalID != nil {
+		if params.DisplayName != nil {
+			group.DisplayName = *params.DisplayName
+		}
+		if params.ExternalID != nil {
+			group.ExternalID = *params.ExternalID
+		}
+		if err := group.Validate(); err != nil {
+			return nil, err
+		}
+		group.UpdatedAt = sharedvo.NewTime(utils.TimeNow())
+		if err := u.scimRepo.UpdateGroup(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, op := range params.MemberOps {
+		switch op.Op {
+		case "add", "replace":
+			members, err := u.checkMembers(ctx, conn, op.UserIDs)
+			if err != nil {
+				return nil, err
+			}
+			if op.Op == "add" {
+				err = u.scimRepo.AddGroupMembers(ctx, group.ID, members)
+			} else {
+				err = u.scimRepo.ReplaceGroupMembers(ctx, group.ID, members)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case "remove":
+			// removing a user who is not a member is not an error
+			ids := slices.DeleteFunc(slices.Clone(op.UserIDs), func(id string) bool {
+				return uuid.Validate(id) != nil
+			})
+			if err := u.scimRepo.RemoveGroupMembers(ctx, group.ID, ids); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("Operations", "unsupported operation "+op.Op))
+		}
+	}
+
+	return u.scimRepo.GetGroup(ctx, conn.ID, group.ID)
+}
+
+func (u *SCIMUseCase) DeleteGroup(ctx context.Context, conn *entity.SSOConnection, id string) error {
+	notFound := domain_error.NewNotFoundError(fmt.Sprintf("group %s not found", id))
+	if uuid.Validate(id) != nil {
+		return notFound
+	}
+
+	found, err := u.scimRepo.DeleteGroup(ctx, conn.ID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound
+	}
+
+	return nil
+}
+
+// scimUser returns the provisioning state of a user of the connection.
+func (u *SCIMUseCase) scimUser(ctx context.Context, conn *entity.SSOConnection, id string) (*entity.SCIMUser, error) {
+	notFound := domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", id)))
+	if uuid.Validate(id) != nil {
+		return nil, notFound
+	}
+
+	scimUser, err := u.scimRepo.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if scimUser.ConnectionID != conn.ID {
+		return nil, notFound
+	}
+
+	return scimUser, nil
+}
+
+func (u *SCIMUseCase) getUserByEmail(ctx context.Context, conn *entity.SSOConnection, email string) (*ProvisionedUser, error) {
+	user, err := u.userRepo.GetUserByEmail(ctx, sharedvo.NewEmail(email).String())
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetUser(ctx, conn, user.ID)
+}
+
+func (u *SCIMUseCase) getUserByExternalID(ctx context.Context, conn *entity.SSOConnection, externalID string) (*ProvisionedUser, error) {
+	scimUser, err := u.scimRepo.GetUserByExternalID(ctx, conn.ID, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetUser(ctx, conn, scimUser.UserID)
+}
+
+// checkMembers returns ids without repeats, failing unless the connection
+// provisioned each of them.
+func (u *SCIMUseCase) checkMembers(ctx context.Context, conn *entity.SSOConnection, ids []string) ([]string, error) {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if uuid.Validate(id) != nil {
+			return nil, unknownSCIMMember(id)
+		}
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	known, err := u.scimRepo.ListUsersByIDs(ctx, conn.ID, unique)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range unique {
+		if !slices.ContainsFunc(known, func(user *entity.SCIMUser) bool { return user.UserID == id }) {
+			return nil, unknownSCIMMember(id)
+		}
+	}
+
+	return unique, nil
+}
+
+// hashSCIMToken returns what is stored of a SCIM token: its SHA-256, which
+// is enough for random tokens.
+func hashSCIMToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// scimPage returns the offset and limit of a SCIM list request.
+func scimPage(params dto.ListSCIMResourcesRequest) (int32, int32) {
+	offset := max(params.StartIndex-1, 0)
+	limit := params.Count
+	if limit < 0 || limit > maxSCIMPageSize {
+		limit = maxSCIMPageSize
+	}
+
+	return offset, limit
+}
+
+// singleSCIMResult is the result of a filtered list: the resource found, or
+// none.
+func singleSCIMResult[T any](resource *T, err error) ([]*T, int64, error) {
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	return []*T{resource}, 1, nil
+}
+
+func unsupportedSCIMFilter(filter *dto.SCIMFilter) error {
+	return domain_error.New(domain_error.ReasonInvalidFilter, domain_error.WithMessage(fmt.Sprintf("filtering on %s is not supported", filter.Attribute)))
+}
+
+func unknownSCIMMember(id string) error {
+	return domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("members", fmt.Sprintf("user %s is not provisioned by this organization", id)))
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/phongloihong/go-shop/pkg/pagination"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+type SecurityEventUseCase struct {
+	userRepo          repository.UserRepository
+	securityEventRepo repository.SecurityEventRepository
+	events            service.EventPublisher
+}
+
+// NewSecurityEventUseCase builds the use case; events receives a
+// user.security_alert event for every security event recorded.
+func NewSecurityEventUseCase(userRepo repository.UserRepository, securityEventRepo repository.SecurityEventRepository, events service.EventPublisher) *SecurityEventUseCase {
+	return &SecurityEventUseCase{
+		userRepo:          userRepo,
+		securityEventRepo: securityEventRepo,
+		events:            events,
+	}
+}
+
+// Record stores event and publishes it as a user.security_alert. It is
+// called once the change it describes is done, so a failure is logged
+// rather than failing that change.
+func (u *SecurityEventUseCase) Record(ctx context.Context, event *entity.SecurityEvent) {
+	if err := u.securityEventRepo.SaveSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("failed to record %s security event of user %s: %v", event.Kind, event.UserID, err)
+		return
+	}
+
+	publishEvent(ctx, u.events, entity.NewEvent(entity.EventSecurityAlert, event.UserID, event))
+}
+
+// RecordLogin records a new_device event when the user logs in with a user
+// agent they never logged in with. Logins without a user agent are not
+// tracked.
+func (u *SecurityEventUseCase) RecordLogin(ctx context.Context, userID, ip, userAgent string) {
+	event := entity.NewSecurityEvent(userID, entity.SecurityEventNewDevice, ip, userAgent)
+	if event.UserAgent == "" {
+		return
+	}
+
+	seen, err := u.securityEventRepo.HasUserAgent(ctx, userID, entity.SecurityEventNewDevice, event.UserAgent)
+	if err != nil {
+		log.Printf("failed to check the devices of user %s: %v", userID, err)
+		return
+	}
+	if seen {
+		return
+	}
+
+	u.Record(ctx, event)
+}
+
+// ListSecurityEvents returns a page of the user's security events, newest
+// first, failing with USER_NOT_FOUND for unknown users.
+func (u *SecurityEventUseCase) ListSecurityEvents(ctx context.Context, params dto.ListSecurityEventsRequest) (pagination.ListResponse[*entity.SecurityEvent], error) {
+	pageSize := pagination.PageSize(params.PageSize, pagination.DefaultPageSize, pagination.MaxPageSize)
+
+	var beforeID int64
+	if err := pagination.DecodeCursor(params.PageToken, &beforeID); err != nil {
+		return pagination.ListResponse[*entity.SecurityEvent]{}, err
+	}
+
+	if _, err := u.userRepo.GetUserByID(ctx, params.UserID); err != nil {
+		return pagination.ListResponse[*entity.SecurityEvent]{}, err
+	}
+
+	events, err := u.securityEventRepo.ListSecurityEvents(ctx, params.UserID, beforeID, pageSize+1)
+	if err != nil {
+		return pagination.ListResponse[*entity.SecurityEvent]{}, err
+	}
+
+	return pagination.NewListResponse(events, pageSize, func(event *entity.SecurityEvent) []any {
+		return []any{event.ID}
+	})
+}
@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"slices"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// SSOPolicy sets how sign-ins at identity providers run.
+type SSOPolicy struct {
+	// RedirectURL is the page IdPs send users back to, which finishes the
+	// sign-in with FinishSsoLogin. It must be registered at every IdP.
+	RedirectURL string
+	// StateTTL is how long a user has to sign in at the IdP.
+	StateTTL time.Duration
+}
+
+// SSOUseCase signs in the users of organizations at their own OpenID Connect
+// identity provider. An organization is a connection owning email domains;
+// users with an email in them sign in through the IdP only, and get an
+// account on their first sign-in unless the IdP provisions them over SCIM.
+type SSOUseCase struct {
+	userRepo    repository.UserRepository
+	connRepo    repository.SSOConnectionRepository
+	stateRepo   repository.SSOStateRepository
+	scimRepo    repository.SCIMRepository
+	idp         service.IdentityProvider
+	authService service.AuthService
+	events      service.EventPublisher
+	security    *SecurityEventUseCase
+	policy      SSOPolicy
+}
+
+func NewSSOUseCase(
+	userRepo repository.UserRepository,
+	connRepo repository.SSOConnectionRepository,
+	stateRepo repository.SSOStateRepository,
+	scimRepo repository.SCIMRepository,
+	idp service.IdentityProvider,
+	authService service.AuthService,
+	events service.EventPublisher,
+	security *SecurityEventUseCase,
+	policy SSOPolicy,
+) *SSOUseCase {
+	return &SSOUseCase{
+		userRepo:    userRepo,
+		connRepo:    connRepo,
+		stateRepo:   stateRepo,
+		scimRepo:    scimRepo,
+		idp:         idp,
+		authService: authService,
+		events:      events,
+		security:    security,
+		policy:      policy,
+	}
+}
+
+func (u *SSOUseCase) CreateConnection(ctx context.Context, params dto.CreateSSOConnectionRequest) (*entity.SSOConnection, error) {
+	conn, err := entity.NewSSOConnection(params.Name, params.Issuer, params.ClientID, params.ClientSecret, params.Domains)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.connRepo.CreateConnection(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (u *SSOUseCase) ListConnections(ctx context.Context) ([]*entity.SSOConnection, error) {
+	return u.connRepo.ListConnections(ctx)
+}
+
+// DeleteConnection removes the connection. Its users keep their accounts;
+// those created at their first sign-in have no password they know, and sign
+// in with a magic link instead.
+func (u *SSOUseCase) DeleteConnection(ctx context.Context, id string) error {
+	found, err := u.connRepo.DeleteConnection(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain_error.New(domain_error.ReasonSSOConnectionNotFound)
+	}
+
+	return nil
+}
+
+// CreateSCIMToken returns a new bearer token for the connection's IdP to
+// provision users over SCIM with, replacing the previous one. From then on
+// only users the IdP provisioned can sign in.
+func (u *SSOUseCase) CreateSCIMToken(ctx context.Context, id string) (string, error) {
+	secret, err := newSSOSecret()
+	if err != nil {
+		return "", err
+	}
+	token := scimTokenPrefix + secret
+
+	found, err := u.connRepo.SetSCIMToken(ctx, id, hashSCIMToken(token))
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", domain_error.New(domain_error.ReasonSSOConnectionNotFound)
+	}
+
+	return token, nil
+}
+
+// RequireSSO fails with SSO_REQUIRED if the email belongs to an organization
+// that signs in with SSO, for the other ways of signing in and registering.
+func (u *SSOUseCase) RequireSSO(ctx context.Context, email string) error {
+	conn, err := u.connRepo.GetConnectionByDomain(ctx, sharedvo.NewEmail(email).Domain())
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	return domain_error.New(domain_error.ReasonSSORequired, domain_error.WithMetadata("connection", conn.Name))
+}
+
+// StartLogin returns the IdP URL to send the user with the email to.
+func (u *SSOUseCase) StartLogin(ctx context.Context, params dto.StartSSOLoginRequest) (string, error) {
+	conn, err := u.connRepo.GetConnectionByDomain(ctx, sharedvo.NewEmail(params.Email).Domain())
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return "", domain_error.New(domain_error.ReasonSSOConnectionNotFound)
+		}
+		return "", err
+	}
+
+	nonce, err := newSSOSecret()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := newSSOSecret()
+	if err != nil {
+		return "", err
+	}
+	state := &entity.SSOState{
+		ID:           utils.NewUUID(),
+		ConnectionID: conn.ID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    sharedvo.NewTime(utils.TimeNow() + int64(u.policy.StateTTL.Seconds())),
+	}
+
+	authURL, err := u.idp.AuthorizationURL(ctx, conn, u.policy.RedirectURL, state)
+	if err != nil {
+		log.Printf("failed to start sign-in at SSO connection %s: %v", conn.ID, err)
+		return "", domain_error.New(domain_error.ReasonSSOLoginFailed)
+	}
+
+	if err := u.stateRepo.CreateState(ctx, state, u.policy.StateTTL); err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// FinishLogin signs in the user the IdP redirected back with state and
+// code. The IdP account is matched to a user by a previous sign-in, then by
+// email; failing both, a user is created for it.
+func (u *SSOUseCase) FinishLogin(ctx context.Context, params dto.FinishSSOLoginRequest) (*service.TokenPairs, error) {
+	failed := domain_error.New(domain_error.ReasonSSOLoginFailed)
+
+	state, err := u.stateRepo.TakeState(ctx, params.State)
+	if err != nil {
+		if domain_error.IsNotFound(err) {
+			return nil, failed
+		}
+		return nil, err
+	}
+
+	conn, err := u.connRepo.GetConnection(ctx, state.ConnectionID)
+	if err != nil {
+		// deleted since the sign-in started
+		if domain_error.IsNotFound(err) {
+			return nil, failed
+		}
+		return nil, err
+	}
+
+	claims, err := u.idp.Exchange(ctx, conn, u.policy.RedirectURL, params.Code, state.CodeVerifier)
+	if err != nil {
+		log.Printf("failed to finish sign-in at SSO connection %s: %v", conn.ID, err)
+		return nil, failed
+	}
+	if claims.Nonce != state.Nonce {
+		return nil, failed
+	}
+
+	// an IdP only speaks for the domains of its organization
+	email := sharedvo.NewEmail(claims.Email)
+	if !claims.EmailVerified || !slices.Contains(conn.Domains, email.Domain()) {
+		log.Printf("SSO connection %s signed in %q, which is not a verified email of its domains", conn.ID, claims.Email)
+		return nil, failed
+	}
+
+	user, err := u.resolveUser(ctx, conn, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !u.provisioned(ctx, conn, user) {
+		log.Printf("SSO connection %s signed in user %s, whom its IdP has not provisioned or has deactivated", conn.ID, user.ID)
+		return nil, failed
+	}
+
+	if err := u.connRepo.SaveIdentity(ctx, &entity.SSOIdentity{
+		ConnectionID: conn.ID,
+		Subject:      claims.Subject,
+		UserID:       user.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	ret, err := u.authService.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+	u.security.RecordLogin(ctx, user.ID, params.IP, params.UserAgent)
+
+	return ret, nil
+}
+
+// resolveUser returns the user of the IdP account, creating it on the
+// account's first sign-in if no user has its email and the IdP does not
+// provision users itself.
+func (u *SSOUseCase) resolveUser(ctx context.Context, conn *entity.SSOConnection, claims *entity.SSOClaims) (*entity.User, error) {
+	identity, err := u.connRepo.GetIdentity(ctx, conn.ID, claims.Subject)
+	if err != nil && !domain_error.IsNotFound(err) {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := u.userRepo.GetUserByID(ctx, identity.UserID)
+		if err == nil {
+			return user, nil
+		}
+		// the user was deleted, so the account is linked anew
+		if !domain_error.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	user, err := u.userRepo.GetUserByEmail(ctx, sharedvo.NewEmail(claims.Email).String())
+	if err == nil {
+		return user, nil
+	}
+	if !domain_error.IsNotFound(err) {
+		return nil, err
+	}
+
+	// an IdP that provisions its users has not provisioned this one
+	if conn.SCIMTokenHash != "" {
+		return nil, domain_error.New(domain_error.ReasonSSOLoginFailed)
+	}
+
+	return createSSOUser(ctx, u.userRepo, u.events, claims.Email, claims.GivenName, claims.FamilyName)
+}
+
+// provisioned reports whether user may sign in at conn: any user may, unless
+// the IdP provisions users over SCIM and has not provisioned this one or has
+// deactivated it.
+func (u *SSOUseCase) provisioned(ctx context.Context, conn *entity.SSOConnection, user *entity.User) bool {
+	if conn.SCIMTokenHash == "" {
+		return true
+	}
+
+	scimUser, err := u.scimRepo.GetUser(ctx, user.ID)
+	if err != nil {
+		if !domain_error.IsNotFound(err) {
+			log.Printf("failed to check provisioning of user %s: %v", user.ID, err)
+		}
+		return false
+	}
+
+	return scimUser.ConnectionID == conn.ID && scimUser.Active
+}
+
+// createSSOUser creates the user of an organization's IdP account, which has
+// no password of its own, and publishes user.created.
+func createSSOUser(ctx context.Context, userRepo repository.UserRepository, events service.EventPublisher, email, givenName, familyName string) (*entity.User, error) {
+	// this password is never told to anyone
+	password, err := newSSOSecret()
+	if err != nil {
+		return nil, err
+	}
+	newUser, err := entity.NewUser(givenName, familyName, email, "", password)
+	if err != nil {
+		return nil, err
+	}
+	user, err := userRepo.CreateUser(ctx, newUser)
+	if err != nil {
+		return nil, err
+	}
+	publishUserEvent(ctx, events, entity.EventUserCreated, user, entity.NewConsentState(nil))
+
+	return user, nil
+}
+
+// newSSOSecret returns 256 random bits, base64url encoded, for nonces and
+// PKCE code verifiers.
+func newSSOSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const tagRuleBatchSize = 500
+
+type TagUseCase struct {
+	userRepo repository.UserRepository
+	tagRepo  repository.UserTagRepository
+}
+
+func NewTagUseCase(userRepo repository.UserRepository, tagRepo repository.UserTagRepository) *TagUseCase {
+	return &TagUseCase{
+		userRepo: userRepo,
+		tagRepo:  tagRepo,
+	}
+}
+
+// GetTags returns the user's tags, failing with USER_NOT_FOUND for unknown
+// users.
+func (u *TagUseCase) GetTags(ctx context.Context, userID string) ([]*entity.UserTag, error) {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.tagRepo.ListTags(ctx, []string{userID})
+}
+
+// AddTags gives the user tags as admin tags, which the tag rules never
+// remove, and returns all the user's tags. Tags the user already has become
+// admin tags.
+func (u *TagUseCase) AddTags(ctx context.Context, userID string, tags []string) ([]*entity.UserTag, error) {
+	userTags := make([]*entity.UserTag, 0, len(tags))
+	for _, tag := range tags {
+		userTag, err := entity.NewUserTag(userID, tag, entity.TagSourceAdmin)
+		if err != nil {
+			return nil, err
+		}
+		userTags = append(userTags, userTag)
+	}
+
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, userTag := range userTags {
+		if err := u.tagRepo.SaveTag(ctx, userTag); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.tagRepo.ListTags(ctx, []string{userID})
+}
+
+// RemoveTags removes tags from the user whatever set them, and returns the
+// remaining ones. Tags the user does not have are ignored. A tag rule that
+// still matches the user sets its tag again on its next run.
+func (u *TagUseCase) RemoveTags(ctx context.Context, userID string, tags []string) ([]*entity.UserTag, error) {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if _, err := u.tagRepo.DeleteTag(ctx, userID, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.tagRepo.ListTags(ctx, []string{userID})
+}
+
+// ValidateTagRule checks rule's tag and filter, so a bad rule fails at
+// startup rather than on each run.
+func ValidateTagRule(rule dto.TagRule) error {
+	if err := entity.ValidateTag(rule.Tag); err != nil {
+		return err
+	}
+
+	_, err := tagRuleFilter(rule, time.Now())
+	return err
+}
+
+// ApplyTagRule tags every user matching rule and untags the ones it tagged
+// on earlier runs that no longer match. Admin tags are left alone.
+func (u *TagUseCase) ApplyTagRule(ctx context.Context, rule dto.TagRule) (*dto.ApplyTagRuleResult, error) {
+	start := time.Unix(utils.TimeNow(), 0)
+	where, err := tagRuleFilter(rule, start)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &dto.ApplyTagRuleResult{}
+	afterID := ""
+	for {
+		users, err := u.userRepo.ListUsersAfter(ctx, afterID, tagRuleBatchSize, where)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			userTag, err := entity.NewUserTag(user.ID, rule.Tag, entity.TagSourceRule)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.tagRepo.SaveTag(ctx, userTag); err != nil {
+				return nil, err
+			}
+			ret.Tagged++
+		}
+
+		if len(users) < tagRuleBatchSize {
+			break
+		}
+		afterID = users[len(users)-1].ID
+	}
+
+	// every tag still matching was saved at or after start
+	ret.Removed, err = u.tagRepo.DeleteStaleRuleTags(ctx, rule.Tag, start)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func tagRuleFilter(rule dto.TagRule, now time.Time) (filter.Expr, error) {
+	text := rule.Filter
+	if rule.InactiveFor > 0 {
+		inactive := fmt.Sprintf("update_time < %q", now.Add(-rule.InactiveFor).UTC().Format(time.RFC3339))
+		if text == "" {
+			text = inactive
+		} else {
+			text = "(" + text + ") AND " + inactive
+		}
+	}
+
+	return filter.Parse(text, repository.UserFilterSchema)
+}
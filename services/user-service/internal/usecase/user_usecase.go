@@ -2,26 +2,136 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/pkg/filter"
+	"github.com/phongloihong/go-shop/pkg/pagination"
+	"github.com/phongloihong/go-shop/pkg/valueobject"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
 	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/pkg/utils"
 	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
 )
 
+// maxBatchGetUsers bounds BatchGetUsers, like page sizes bound list methods.
+const maxBatchGetUsers = 100
+
+// unknownUserPassword is compared against on logins of unknown emails. It is
+// a bcrypt hash at the cost Password.Hash uses, of a password nobody sends.
+var unknownUserPassword = valueobject.NewPassword("$2a$10$u1EmUfb4R16L.V6gI5od3e4Z/v/OYiN2wJ1MuRTnv6i5FXBDrELHO")
+
+// BatchGetUserResult is the outcome for one ID of BatchGetUsers: the user, or
+// why it could not be returned.
+type BatchGetUserResult struct {
+	ID   string
+	User *entity.User
+	Err  error
+}
+
+// EmailPolicy sets which emails may register.
+type EmailPolicy struct {
+	// BlockedDomains are refused with their subdomains, e.g. disposable
+	// email providers.
+	BlockedDomains valueobject.DomainList
+	// RejectPlusAliases refuses an email whose DedupKey matches a registered
+	// one, e.g. "lan+2@example.com" when "lan@example.com" or
+	// "lan+1@example.com" has an account.
+	RejectPlusAliases bool
+}
+
 type UserUseCase struct {
 	userRepo    repository.UserRepository
+	profiles    repository.ProfileReadModel
+	consentRepo repository.ConsentRepository
+	legalHolds  repository.LegalHoldRepository
 	authService service.AuthService
+	events      service.EventPublisher
+	emailPolicy EmailPolicy
+	loginGuard  *LoginGuard
+	anomalies   *AuthAnomalyDetector
+	security    *SecurityEventUseCase
+	sso         *SSOUseCase
 }
 
-func NewUserUseCase(repo repository.UserRepository, authService service.AuthService) *UserUseCase {
+// NewUserUseCase builds the use case; events receives the user.created,
+// user.updated and user.deleted events of the users it changes, which carry
+// their consents from consentRepo, and profiles serves profile reads. It
+// is kept up to date from those events. Users under a hold in legalHolds
+// cannot be deleted. emailPolicy applies to registrations and loginGuard to
+// logins; anomalies watches both for attacks. Password changes and logins
+// from new devices are recorded in security. Emails of organizations in sso
+// can neither register nor log in with a password.
+func NewUserUseCase(
+	repo repository.UserRepository,
+	profiles repository.ProfileReadModel,
+	consentRepo repository.ConsentRepository,
+	legalHolds repository.LegalHoldRepository,
+	authService service.AuthService,
+	events service.EventPublisher,
+	emailPolicy EmailPolicy,
+	loginGuard *LoginGuard,
+	anomalies *AuthAnomalyDetector,
+	security *SecurityEventUseCase,
+	sso *SSOUseCase,
+) *UserUseCase {
 	return &UserUseCase{
 		userRepo:    repo,
+		profiles:    profiles,
+		consentRepo: consentRepo,
+		legalHolds:  legalHolds,
 		authService: authService,
+		events:      events,
+		emailPolicy: emailPolicy,
+		loginGuard:  loginGuard,
+		anomalies:   anomalies,
+		security:    security,
+		sso:         sso,
+	}
+}
+
+// CreateGuestToken issues a token identifying a new guest, returned with
+// its ID.
+func (u *UserUseCase) CreateGuestToken(ctx context.Context) (string, *service.TokenPairs, error) {
+	guestID := utils.NewUUID()
+	ret, err := u.authService.GenerateGuestToken(guestID)
+	if err != nil {
+		return "", nil, err
 	}
+
+	return guestID, ret, nil
 }
 
 func (u *UserUseCase) RegisterUser(ctx context.Context, params dto.RegisterRequest) (*entity.User, error) {
+	// check the guest token first, so a stale one fails the request before
+	// the account exists
+	var guestID string
+	if params.GuestToken != "" {
+		id, err := u.authService.ValidateGuestToken(params.GuestToken)
+		if err != nil {
+			return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("guest_token", "the guest token is invalid or expired"))
+		}
+		guestID = id
+	}
+
+	if u.emailPolicy.BlockedDomains.Contains(valueobject.NewEmail(params.Email)) {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("email", "disposable email addresses are not accepted"))
+	}
+
+	// accounts of SSO organizations are created at their first sign-in
+	if err := u.sso.RequireSSO(ctx, params.Email); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.anomalies.ScreenRegistration(ctx, params.IP, params.CaptchaToken); err != nil {
+		return nil, err
+	}
+
 	// Create entity
 	newUser, err := entity.NewUser(
 		params.FirstName,
@@ -34,22 +144,94 @@ func (u *UserUseCase) RegisterUser(ctx context.Context, params dto.RegisterReque
 		return nil, err
 	}
 
+	if u.emailPolicy.RejectPlusAliases {
+		exists, err := u.userRepo.EmailKeyExists(ctx, newUser.Email.DedupKey())
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, domain_error.New(
+				domain_error.ReasonEmailAlreadyExists,
+				domain_error.WithMessage(fmt.Sprintf("a user with an alias of %s already exists", newUser.Email.String())),
+				domain_error.WithFieldViolation("email", "an alias of this address is already registered"),
+			)
+		}
+	}
+
 	// save to database
 	ret, err := u.userRepo.CreateUser(ctx, newUser)
 	if err != nil {
 		return nil, err
 	}
+	u.anomalies.RecordRegistration(ctx)
+	// a new user has decided on nothing yet
+	publishUserEvent(ctx, u.events, entity.EventUserCreated, ret, entity.NewConsentState(nil))
+	if guestID != "" {
+		publishEvent(ctx, u.events, entity.NewEvent(entity.EventGuestUpgraded, ret.ID, entity.GuestUpgrade{
+			GuestID: guestID,
+			UserID:  ret.ID,
+		}))
+	}
 
 	return ret, nil
 }
 
 func (u *UserUseCase) Login(ctx context.Context, params dto.LoginRequest) (*service.TokenPairs, error) {
-	user, err := u.userRepo.GetUserByEmail(ctx, params.Email)
+	if err := u.sso.RequireSSO(ctx, params.Email); err != nil {
+		return nil, err
+	}
+
+	source, err := u.anomalies.ScreenLogin(ctx, params.IP, params.CaptchaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// unknown email and wrong password look the same to the caller
+	email := valueobject.NewEmail(params.Email).String()
+	user, err := getUserByEmail(ctx, u.userRepo, params.Email)
 	if err != nil {
+		if domain_error.IsNotFound(err) {
+			// spend as long as a wrong password would, so response times do
+			// not tell which emails have accounts
+			_ = unknownUserPassword.CompareHash(params.Password)
+			u.anomalies.RecordFailedLogin(ctx, source, email)
+			return nil, domain_error.New(domain_error.ReasonInvalidCredentials)
+		}
 		return nil, err
 	}
 
 	if err := user.Password.CompareHash(params.Password); err != nil {
+		u.anomalies.RecordFailedLogin(ctx, source, email)
+		return nil, domain_error.New(domain_error.ReasonInvalidCredentials)
+	}
+
+	if err := u.loginGuard.Check(ctx, user, params.IP, params.UserAgent); err != nil {
+		return nil, err
+	}
+
+	ret, err := u.authService.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+	u.security.RecordLogin(ctx, user.ID, params.IP, params.UserAgent)
+
+	return ret, nil
+}
+
+// VerifyLogin finishes a login that failed with LOGIN_VERIFICATION_REQUIRED,
+// given the code emailed to the user.
+func (u *UserUseCase) VerifyLogin(ctx context.Context, params dto.VerifyLoginRequest) (*service.TokenPairs, error) {
+	challenge, err := u.loginGuard.Verify(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		// deleted since the challenge was issued
+		if domain_error.IsNotFound(err) {
+			return nil, domain_error.New(domain_error.ReasonInvalidVerificationCode)
+		}
 		return nil, err
 	}
 
@@ -57,6 +239,321 @@ func (u *UserUseCase) Login(ctx context.Context, params dto.LoginRequest) (*serv
 	if err != nil {
 		return nil, err
 	}
+	u.security.RecordLogin(ctx, user.ID, challenge.IP, params.UserAgent)
+
+	return ret, nil
+}
+
+// GetProfile serves the user from the profile read model. Users it does not
+// hold yet, e.g. created before it existed or expired from it, are read
+// from the users table and added to it. The returned user has no password.
+func (u *UserUseCase) GetProfile(ctx context.Context, userID string) (*entity.User, error) {
+	profiles, missing, err := u.profiles.GetProfiles(ctx, []string{userID})
+	if err != nil {
+		// the read model is only a copy; the users table can still answer
+		log.Printf("failed to read profile of user %s: %v", userID, err)
+		return u.userRepo.GetUserByID(ctx, userID)
+	}
+	if len(profiles) > 0 {
+		return profiles[0], nil
+	}
+	if len(missing) == 0 {
+		return nil, domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", userID)))
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	u.saveProfiles(ctx, user)
+
+	return user, nil
+}
+
+// GetProfileForUpdate reads the user from the users table, bypassing the
+// profile read model, for callers that send the version back with an
+// update: the read model may lag, and a stale version would fail every
+// update until it caught up.
+func (u *UserUseCase) GetProfileForUpdate(ctx context.Context, userID string) (*entity.User, error) {
+	return u.userRepo.GetUserByID(ctx, userID)
+}
+
+// UpdateProfile applies the set fields of params to the user and returns the
+// updated user. Concurrent updates never overwrite each other: the write only
+// lands if the user is unchanged since it was read, and at
+// params.ExpectedVersion when that is set, failing with
+// USER_VERSION_MISMATCH otherwise.
+func (u *UserUseCase) UpdateProfile(ctx context.Context, params dto.UpdateProfileRequest) (*entity.User, error) {
+	user, err := u.userRepo.GetUserByID(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if params.ExpectedVersion != 0 && params.ExpectedVersion != user.Version {
+		return nil, versionMismatch(user.Version)
+	}
+	// read before the write, so a failure leaves the user unchanged
+	consents, err := u.consentRepo.ListConsents(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.FirstName != nil {
+		user.FirstName = *params.FirstName
+	}
+	if params.LastName != nil {
+		user.LastName = *params.LastName
+	}
+	if params.Phone != nil {
+		user.Phone = valueobject.NewPhone(*params.Phone)
+	}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	user.UpdatedAt = valueobject.NewTime(utils.TimeNow())
+
+	affected, err := u.userRepo.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	// deleted or updated by someone else between the read and the write
+	if affected == 0 {
+		current, err := u.userRepo.GetUserByID(ctx, params.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, versionMismatch(current.Version)
+	}
+	publishUserEvent(ctx, u.events, entity.EventUserUpdated, user, entity.NewConsentState(consents))
+
+	return user, nil
+}
+
+// DeleteUser deletes the user with their notification preferences, tags and
+// consents. It fails with USER_UNDER_LEGAL_HOLD while the user is under a
+// legal hold.
+func (u *UserUseCase) DeleteUser(ctx context.Context, id string) error {
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// the deletion would take the held data with it
+	if _, err := u.legalHolds.GetLegalHold(ctx, id); err == nil {
+		return domain_error.New(domain_error.ReasonUserUnderLegalHold, domain_error.WithMessage(fmt.Sprintf("user %s is under a legal hold", id)))
+	} else if !domain_error.IsNotFound(err) {
+		return err
+	}
+	// the deletion takes the consents with it
+	consents, err := u.consentRepo.ListConsents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := u.userRepo.DeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	// deleted by someone else meanwhile
+	if affected == 0 {
+		return domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", id)))
+	}
+	publishUserEvent(ctx, u.events, entity.EventUserDeleted, user, entity.NewConsentState(consents))
+
+	return nil
+}
+
+// publishUserEvent publishes an event of eventType carrying user and the
+// state of their consents.
+func publishUserEvent(ctx context.Context, events service.EventPublisher, eventType string, user *entity.User, consents entity.ConsentState) {
+	publishEvent(ctx, events, entity.NewEvent(eventType, user.ID, entity.UserEventData{
+		User:     user,
+		Consents: consents,
+	}))
+}
+
+// publishEvent publishes event about a change that is already stored. Users
+// may live on another shard than the webhook tables, so the two cannot share
+// a transaction; a failure to publish is logged rather than failing a change
+// that cannot be undone.
+func publishEvent(ctx context.Context, events service.EventPublisher, event *entity.Event) {
+	if err := events.Publish(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("failed to publish %s event %s of user %s: %v", event.Type, event.ID, event.Subject, err)
+	}
+}
+
+func versionMismatch(current int64) error {
+	return domain_error.New(
+		domain_error.ReasonUserVersionMismatch,
+		domain_error.WithFieldViolation("expected_version", fmt.Sprintf("the profile is at version %d", current)),
+	)
+}
+
+func (u *UserUseCase) ChangePassword(ctx context.Context, params dto.ChangePasswordRequest) error {
+	user, err := u.userRepo.GetUserByID(ctx, params.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := user.Password.CompareHash(params.OldPassword); err != nil {
+		return domain_error.New(domain_error.ReasonInvalidCredentials)
+	}
+
+	newPassword := valueobject.NewPassword(params.NewPassword)
+	if err := newPassword.Validate(); err != nil {
+		return domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("new_password", err.Error()))
+	}
+	hash, err := newPassword.Hash()
+	if err != nil {
+		return domain_error.NewInternalError(fmt.Sprintf("failed to hash password: %s", err.Error()))
+	}
+
+	if _, err := u.userRepo.ChangePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+	u.security.Record(ctx, entity.NewSecurityEvent(user.ID, entity.SecurityEventPasswordChanged, params.IP, params.UserAgent))
+
+	return nil
+}
+
+// GetPublicProfiles returns the profiles of the users among ids that exist.
+func (u *UserUseCase) GetPublicProfiles(ctx context.Context, ids []string) ([]*entity.UserPublicProfile, error) {
+	if len(ids) == 0 {
+		return []*entity.UserPublicProfile{}, nil
+	}
+
+	var opts []domain_error.Option
+	for i, id := range ids {
+		if err := uuid.Validate(id); err != nil {
+			opts = append(opts, domain_error.WithFieldViolation(fmt.Sprintf("ids[%d]", i), "must be a UUID"))
+		}
+	}
+	if len(opts) > 0 {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, opts...)
+	}
+
+	// each user once, as from the users table
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	users, missing, err := u.profiles.GetProfiles(ctx, ids)
+	if err != nil {
+		log.Printf("failed to read public profiles: %v", err)
+		return u.userRepo.GetPublicProfileByIds(ctx, ids)
+	}
+	if len(missing) > 0 {
+		stored, err := u.userRepo.GetUsersByIDs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		u.saveProfiles(ctx, stored...)
+		users = append(users, stored...)
+	}
+
+	ret := make([]*entity.UserPublicProfile, 0, len(users))
+	for _, user := range users {
+		ret = append(ret, entity.NewUserPublicProfile(user.ID, user.FirstName, user.LastName))
+	}
+
+	return ret, nil
+}
+
+// saveProfiles adds users read from the users table to the profile read
+// model. A failure only means they are read from the table again.
+func (u *UserUseCase) saveProfiles(ctx context.Context, users ...*entity.User) {
+	for _, user := range users {
+		if err := u.profiles.SaveProfile(ctx, user); err != nil {
+			log.Printf("failed to save profile of user %s: %v", user.ID, err)
+		}
+	}
+}
+
+// BatchGetUsers looks up several users in one call, returning one result per
+// id in request order. A malformed or unknown id fails only its own result,
+// with VALIDATION_FAILED or USER_NOT_FOUND; the returned error is for
+// problems with the whole request.
+func (u *UserUseCase) BatchGetUsers(ctx context.Context, ids []string) ([]BatchGetUserResult, error) {
+	if len(ids) == 0 || len(ids) > maxBatchGetUsers {
+		return nil, domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithFieldViolation("ids", fmt.Sprintf("must hold 1 to %d IDs", maxBatchGetUsers)))
+	}
+
+	ret := make([]BatchGetUserResult, len(ids))
+	// canonical forms of the valid ids, as the repository returns them
+	canonical := make([]string, len(ids))
+	valid := make([]string, 0, len(ids))
+	for i, id := range ids {
+		ret[i].ID = id
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			ret[i].Err = domain_error.New(domain_error.ReasonValidationFailed, domain_error.WithMessage(fmt.Sprintf("%q is not a UUID", id)))
+			continue
+		}
+		canonical[i] = parsed.String()
+		valid = append(valid, canonical[i])
+	}
+
+	users := make(map[string]*entity.User, len(valid))
+	if len(valid) > 0 {
+		found, err := u.userRepo.GetUsersByIDs(ctx, valid)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range found {
+			users[user.ID] = user
+		}
+	}
+
+	for i := range ret {
+		if ret[i].Err != nil {
+			continue
+		}
+		user, ok := users[canonical[i]]
+		if !ok {
+			ret[i].Err = domain_error.New(domain_error.ReasonUserNotFound, domain_error.WithMessage(fmt.Sprintf("user %s not found", ret[i].ID)))
+			continue
+		}
+		ret[i].User = user
+	}
 
 	return ret, nil
 }
+
+// ListUsers returns a page of the users matching params.Filter, in ID order.
+func (u *UserUseCase) ListUsers(ctx context.Context, params dto.ListUsersRequest) (pagination.ListResponse[*entity.User], error) {
+	pageSize := pagination.PageSize(params.PageSize, pagination.DefaultPageSize, pagination.MaxPageSize)
+
+	where, err := filter.Parse(params.Filter, repository.UserFilterSchema)
+	if err != nil {
+		return pagination.ListResponse[*entity.User]{}, err
+	}
+
+	var afterID string
+	if err := pagination.DecodeCursor(params.PageToken, &afterID); err != nil {
+		return pagination.ListResponse[*entity.User]{}, err
+	}
+
+	users, err := u.userRepo.ListUsersAfter(ctx, afterID, pageSize+1, where)
+	if err != nil {
+		return pagination.ListResponse[*entity.User]{}, err
+	}
+
+	return pagination.NewListResponse(users, pageSize, func(user *entity.User) []any {
+		return []any{user.ID}
+	})
+}
+
+// getUserByEmail finds the user who signs in as email. Emails are stored
+// lower-cased, except those that differ from another only in case, which
+// migration 000012 left as they were; their owners are found by the address
+// exactly as they type it, before anyone else's lower-cased one.
+func getUserByEmail(ctx context.Context, userRepo repository.UserRepository, email string) (*entity.User, error) {
+	exact := strings.TrimSpace(email)
+	normalized := valueobject.NewEmail(email).String()
+	if exact != normalized {
+		user, err := userRepo.GetUserByEmail(ctx, exact)
+		if !domain_error.IsNotFound(err) {
+			return user, err
+		}
+	}
+
+	return userRepo.GetUserByEmail(ctx, normalized)
+}
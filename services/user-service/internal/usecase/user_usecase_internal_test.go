@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"testing"
+
+	sharedvo "github.com/phongloihong/go-shop/pkg/valueobject"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Logins of unknown emails only take as long as wrong passwords if the dummy
+// hash costs what stored hashes do.
+func TestUnknownUserPasswordCostsLikeAStoredHash(t *testing.T) {
+	stored, err := sharedvo.NewPassword("Secret123!").Hash()
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	want, err := bcrypt.Cost([]byte(stored))
+	if err != nil {
+		t.Fatalf("Cost of a stored hash: %v", err)
+	}
+
+	got, err := bcrypt.Cost([]byte(unknownUserPassword))
+	if err != nil {
+		t.Fatalf("unknownUserPassword is not a bcrypt hash: %v", err)
+	}
+	if got != want {
+		t.Errorf("unknownUserPassword has cost %d, want %d", got, want)
+	}
+	if err := unknownUserPassword.CompareHash("Secret123!"); err == nil {
+		t.Error("unknownUserPassword matches a password")
+	}
+}
@@ -0,0 +1,229 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/auth"
+	"github.com/phongloihong/go-shop/services/user-service/internal/infrastructure/database/memory"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+// unknownLocator places no address, so logins are never challenged.
+type unknownLocator struct{}
+
+func (unknownLocator) Locate(context.Context, string) (entity.Location, error) {
+	return entity.Location{}, nil
+}
+
+// quietActivity raises no auth anomalies.
+type quietActivity struct {
+	repository.AuthActivityRepository
+}
+
+func (quietActivity) HasAnomaly(context.Context, ...string) (bool, error) {
+	return false, nil
+}
+
+// noSSOConnections has no SSO organization, so every email may use a
+// password.
+type noSSOConnections struct {
+	repository.SSOConnectionRepository
+}
+
+func (noSSOConnections) GetConnectionByDomain(context.Context, string) (*entity.SSOConnection, error) {
+	return nil, domain_error.NewNotFoundError("sso connection not found")
+}
+
+type noConsents struct {
+	repository.ConsentRepository
+}
+
+func (noConsents) ListConsents(context.Context, string) ([]*entity.Consent, error) {
+	return nil, nil
+}
+
+// recordedEvents keeps the published events.
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []*entity.Event
+}
+
+func (r *recordedEvents) Publish(_ context.Context, event *entity.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+func (r *recordedEvents) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	types := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// newTestUserUseCase builds a UserUseCase on the in-memory repository and
+// fake tokens, with login challenges, auth anomalies and SSO out of the way.
+func newTestUserUseCase(t *testing.T) (*usecase.UserUseCase, *auth.FakeService, *recordedEvents) {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	authService := auth.NewFakeService()
+	events := &recordedEvents{}
+	security := usecase.NewSecurityEventUseCase(users, nil, events)
+	loginGuard := usecase.NewLoginGuard(unknownLocator{}, nil, nil, nil, security, nil, usecase.LoginRiskPolicy{})
+	anomalies := usecase.NewAuthAnomalyDetector(quietActivity{}, unknownLocator{}, nil, events, usecase.AuthAnomalyPolicy{})
+	sso := usecase.NewSSOUseCase(users, noSSOConnections{}, nil, nil, nil, authService, events, security, usecase.SSOPolicy{})
+
+	return usecase.NewUserUseCase(users, nil, noConsents{}, nil, authService, events, usecase.EmailPolicy{},
+		loginGuard, anomalies, security, sso), authService, events
+}
+
+var testRegistration = dto.RegisterRequest{
+	FirstName: "John",
+	LastName:  "Doe",
+	Email:     "john@example.com",
+	Phone:     "0901234567",
+	Password:  "Secret123!",
+}
+
+func TestRegisterUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates the user and announces it", func(t *testing.T) {
+		users, _, events := newTestUserUseCase(t)
+
+		user, err := users.RegisterUser(ctx, testRegistration)
+		if err != nil {
+			t.Fatalf("RegisterUser: %v", err)
+		}
+		if user.ID == "" || user.Email.String() != "john@example.com" {
+			t.Errorf("RegisterUser = %+v, want a user with an ID and the email", user)
+		}
+		if got := events.types(); len(got) != 1 || got[0] != entity.EventUserCreated {
+			t.Fatalf("published %v, want one %s", got, entity.EventUserCreated)
+		}
+
+		payload, err := json.Marshal(events.events[0])
+		if err != nil {
+			t.Fatalf("failed to encode the event: %v", err)
+		}
+		var decoded struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			t.Fatalf("failed to decode the event: %v", err)
+		}
+		if decoded.Data["email"] != "john@example.com" {
+			t.Errorf("event data = %v, want the user's email", decoded.Data)
+		}
+		if _, ok := decoded.Data["phone"]; ok {
+			t.Errorf("event data = %v, want no phone number", decoded.Data)
+		}
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		users, _, _ := newTestUserUseCase(t)
+
+		if _, err := users.RegisterUser(ctx, testRegistration); err != nil {
+			t.Fatalf("RegisterUser: %v", err)
+		}
+		_, err := users.RegisterUser(ctx, testRegistration)
+		if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonEmailAlreadyExists {
+			t.Errorf("RegisterUser of a taken email = %v, want %s", err, domain_error.ReasonEmailAlreadyExists)
+		}
+	})
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	users, authService, _ := newTestUserUseCase(t)
+	user, err := users.RegisterUser(ctx, testRegistration)
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+
+	t.Run("issues tokens for the right password", func(t *testing.T) {
+		tokens, err := users.Login(ctx, dto.LoginRequest{Email: "john@example.com", Password: "Secret123!"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		claims, err := authService.ValidateToken(tokens.AccessToken, nil)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		if claims.UserID != user.ID {
+			t.Errorf("access token is for user %q, want %q", claims.UserID, user.ID)
+		}
+	})
+
+	for _, params := range []dto.LoginRequest{
+		{Email: "john@example.com", Password: "wrong"},
+		{Email: "jane@example.com", Password: "Secret123!"},
+	} {
+		t.Run("rejects "+params.Email+" with "+params.Password, func(t *testing.T) {
+			_, err := users.Login(ctx, params)
+			if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonInvalidCredentials {
+				t.Errorf("Login = %v, want %s", err, domain_error.ReasonInvalidCredentials)
+			}
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	name := "Johnny"
+
+	t.Run("updates at the expected version", func(t *testing.T) {
+		users, _, _ := newTestUserUseCase(t)
+		user, err := users.RegisterUser(ctx, testRegistration)
+		if err != nil {
+			t.Fatalf("RegisterUser: %v", err)
+		}
+
+		updated, err := users.UpdateProfile(ctx, dto.UpdateProfileRequest{UserID: user.ID, FirstName: &name, ExpectedVersion: user.Version})
+		if err != nil {
+			t.Fatalf("UpdateProfile: %v", err)
+		}
+		if updated.FirstName != name || updated.Version != user.Version+1 {
+			t.Errorf("UpdateProfile = %q at version %d, want %q at version %d", updated.FirstName, updated.Version, name, user.Version+1)
+		}
+	})
+
+	t.Run("rejects a stale expected version", func(t *testing.T) {
+		users, _, _ := newTestUserUseCase(t)
+		user, err := users.RegisterUser(ctx, testRegistration)
+		if err != nil {
+			t.Fatalf("RegisterUser: %v", err)
+		}
+		if _, err := users.UpdateProfile(ctx, dto.UpdateProfileRequest{UserID: user.ID, FirstName: &name}); err != nil {
+			t.Fatalf("UpdateProfile: %v", err)
+		}
+
+		stale := "Jack"
+		_, err = users.UpdateProfile(ctx, dto.UpdateProfileRequest{UserID: user.ID, FirstName: &stale, ExpectedVersion: user.Version})
+		if reason, _ := domain_error.ReasonOf(err); reason != domain_error.ReasonUserVersionMismatch {
+			t.Fatalf("UpdateProfile at a stale version = %v, want %s", err, domain_error.ReasonUserVersionMismatch)
+		}
+
+		current, err := users.GetProfileForUpdate(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetProfileForUpdate: %v", err)
+		}
+		if current.FirstName != name {
+			t.Errorf("first name after the rejected update = %q, want %q", current.FirstName, name)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	domain_error "github.com/phongloihong/go-shop/pkg/domain_errors"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/entity"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/repository"
+	"github.com/phongloihong/go-shop/services/user-service/internal/domain/service"
+	"github.com/phongloihong/go-shop/services/user-service/internal/usecase/dto"
+)
+
+const defaultDeliveryListLimit = 20
+
+type WebhookRetryPolicy struct {
+	MaxAttempts    int32
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// failed attempts: exponential growth capped at MaxBackoff, with up to 10%
+// jitter so failing endpoints aren't retried in lockstep.
+func (p WebhookRetryPolicy) Backoff(attempts int32) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := p.MaxBackoff
+	if attempts < 32 {
+		if d := p.InitialBackoff << (attempts - 1); d > 0 && d < p.MaxBackoff {
+			delay = d
+		}
+	}
+
+	return delay + time.Duration(rand.Int64N(int64(delay/10)+1))
+}
+
+type WebhookUseCase struct {
+	unitOfWork  repository.UnitOfWork
+	webhookRepo repository.WebhookRepository
+	sender      service.WebhookSender
+	retryPolicy WebhookRetryPolicy
+	region      string
+}
+
+// NewWebhookUseCase builds the use case; region, if not empty, is recorded
+// on events that do not name one.
+func NewWebhookUseCase(unitOfWork repository.UnitOfWork, webhookRepo repository.WebhookRepository, sender service.WebhookSender, retryPolicy WebhookRetryPolicy, region string) *WebhookUseCase {
+	return &WebhookUseCase{
+		unitOfWork:  unitOfWork,
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		retryPolicy: retryPolicy,
+		region:      region,
+	}
+}
+
+func (u *WebhookUseCase) CreateSubscription(ctx context.Context, params dto.CreateWebhookSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	sub, err := entity.NewWebhookSubscription(params.Owner, params.URL, params.Secret, params.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.webhookRepo.CreateSubscription(ctx, sub)
+}
+
+func (u *WebhookUseCase) ListSubscriptions(ctx context.Context, owner entity.WebhookOwner) ([]*entity.WebhookSubscription, error) {
+	return u.webhookRepo.ListSubscriptionsByOwner(ctx, owner)
+}
+
+func (u *WebhookUseCase) DeleteSubscription(ctx context.Context, id string, owner entity.WebhookOwner) error {
+	affected, err := u.webhookRepo.DeleteSubscription(ctx, id, owner)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain_error.New(domain_error.ReasonWebhookNotFound, domain_error.WithMessage(fmt.Sprintf("webhook subscription %s not found", id)))
+	}
+
+	return nil
+}
+
+func (u *WebhookUseCase) ListDeliveries(ctx context.Context, params dto.ListWebhookDeliveriesRequest) ([]*entity.WebhookDelivery, error) {
+	if _, err := u.getOwnedSubscription(ctx, params.SubscriptionID, params.Owner); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultDeliveryListLimit
+	}
+
+	return u.webhookRepo.ListDeliveriesBySubscription(ctx, params.SubscriptionID, limit)
+}
+
+// RetryDelivery requeues a dead-lettered delivery.
+func (u *WebhookUseCase) RetryDelivery(ctx context.Context, id string, owner entity.WebhookOwner) (*entity.WebhookDelivery, error) {
+	delivery, err := u.webhookRepo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.getOwnedSubscription(ctx, delivery.SubscriptionID, owner); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status != entity.DeliveryDead {
+		return nil, domain_error.New(
+			domain_error.ReasonDeliveryNotRetryable,
+			domain_error.WithMessage(fmt.Sprintf("webhook delivery %s is %s, only dead deliveries can be retried", id, delivery.Status)),
+		)
+	}
+
+	delivery.Requeue()
+	if err := u.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Publish queues a delivery of the event for every matching subscription,
+// either all of them or none. Called inside a unit of work, the deliveries
+// commit together with the caller's state change.
+func (u *WebhookUseCase) Publish(ctx context.Context, event *entity.Event) error {
+	subs, err := u.webhookRepo.ListActiveSubscriptionsForEvent(ctx, event.Type, event.Subject)
+	if err != nil {
+		return err
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	if event.Region == "" && u.region != "" {
+		stamped := *event
+		stamped.Region = u.region
+		event = &stamped
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return domain_error.NewInternalError(fmt.Sprintf("failed to encode event %s: %s", event.ID, err.Error()))
+	}
+
+	return u.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		for _, sub := range subs {
+			if err := u.webhookRepo.CreateDelivery(ctx, entity.NewWebhookDelivery(sub.ID, event, payload)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeliverDue sends up to batchSize due deliveries and records the outcome of
+// each attempt. It returns the number of deliveries attempted.
+func (u *WebhookUseCase) DeliverDue(ctx context.Context, batchSize int32, lease time.Duration) (int, error) {
+	deliveries, err := u.webhookRepo.ClaimDueDeliveries(ctx, batchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		u.attempt(ctx, delivery)
+
+		if err := u.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Printf("failed to record webhook delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// PruneDeliveries deletes succeeded and dead deliveries that finished more
+// than retention ago and returns how many it deleted.
+func (u *WebhookUseCase) PruneDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return u.webhookRepo.DeleteFinishedDeliveries(ctx, time.Now().Add(-retention))
+}
+
+func (u *WebhookUseCase) attempt(ctx context.Context, delivery *entity.WebhookDelivery) {
+	sub, err := u.webhookRepo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil || !sub.Active {
+		// nothing to deliver to anymore, dead-letter immediately
+		delivery.MarkFailed(0, "subscription is no longer active", 0, 0)
+		return
+	}
+
+	statusCode, err := u.sender.Send(ctx, sub, delivery)
+	if err != nil {
+		nextAttemptAt := time.Now().Add(u.retryPolicy.Backoff(delivery.Attempts + 1)).Unix()
+		delivery.MarkFailed(statusCode, err.Error(), u.retryPolicy.MaxAttempts, nextAttemptAt)
+		return
+	}
+
+	delivery.MarkSucceeded(statusCode)
+}
+
+func (u *WebhookUseCase) getOwnedSubscription(ctx context.Context, id string, owner entity.WebhookOwner) (*entity.WebhookSubscription, error) {
+	sub, err := u.webhookRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// don't reveal subscriptions owned by someone else
+	if sub.Owner != owner {
+		return nil, domain_error.New(domain_error.ReasonWebhookNotFound, domain_error.WithMessage(fmt.Sprintf("webhook subscription %s not found", id)))
+	}
+
+	return sub, nil
+}